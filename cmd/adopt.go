@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	adoptName string
+	adoptMove bool
+	adoptLink bool
+	adoptPath string
+)
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt <path>",
+	Short: "Register an existing clone in the workspace (--move, --link)",
+	Long: `Registers a git checkout you already have instead of cloning it again. The
+remote and default branch are read from the checkout.
+
+By default the checkout stays where it is. --move moves it into the workspace
+(where 'spark-cli use' would clone it, or --path); --link leaves it in place
+and symlinks it into the workspace instead.
+
+Examples:
+  spark-cli adopt ~/code/AppAPI
+  spark-cli adopt ~/code/AppAPI --move
+  spark-cli adopt ~/old/BusinessAPI --link --path backend/BusinessAPI`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if adoptMove && adoptLink {
+			return errs.New(errs.KindUsage, "--move and --link can't be combined")
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+
+		src, err := filepath.Abs(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid path: %w", err)
+		}
+		if resolved, err := filepath.EvalSymlinks(src); err == nil {
+			src = resolved
+		}
+		if !git.IsRepo(src) {
+			return errs.New(errs.KindUsage, "%s is not a git checkout", src)
+		}
+
+		remote := git.RemoteURL(src, "origin")
+		name := adoptName
+		if name == "" && remote != "" {
+			name = strings.TrimSuffix(git.RepoNameFromRemote(remote), ".git")
+		}
+		if name == "" {
+			name = filepath.Base(src)
+		}
+		for existing, repo := range ws.Repos {
+			if dir, err := filepath.EvalSymlinks(filepath.Join(wsPath, repo.Path)); err == nil && dir == src {
+				return errs.New(errs.KindUsage, "%s is already in the workspace as '%s'", src, existing)
+			}
+		}
+		if _, exists := ws.Repos[name]; exists {
+			return errs.New(errs.KindUsage, "workspace already has a repo named '%s' — pass --name", name)
+		}
+		if remote == "" {
+			ui.Warn.Println("No origin remote — sync will skip fetching this repo until one is added")
+		}
+
+		target := src
+		if adoptMove || adoptLink {
+			target, err = cloneTarget(wsPath, remote, name, adoptPath)
+			if err != nil {
+				return err
+			}
+			if _, err := os.Lstat(target); err == nil {
+				return errs.New(errs.KindUsage, "%s already exists", target)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+		}
+		switch {
+		case adoptMove:
+			if err := os.Rename(src, target); err != nil {
+				return fmt.Errorf("failed to move %s into the workspace (across disks? use --link): %w", src, err)
+			}
+			ui.OK.Printf("Moved %s → %s\n", src, target)
+		case adoptLink:
+			if err := os.Symlink(src, target); err != nil {
+				return fmt.Errorf("failed to link %s: %w", src, err)
+			}
+			fmt.Printf("🔗 Linked %s → %s\n", target, src)
+		}
+
+		if err := registerRepo(wsPath, name, remote, "", target); err != nil {
+			return err
+		}
+		branch := defaultBranch(target)
+		if ws.DefaultBranch != "" && branch != ws.DefaultBranch {
+			ws, err = workspace.Load(wsPath)
+			if err != nil {
+				return err
+			}
+			repo := ws.Repos[name]
+			repo.DefaultBranch = branch
+			ws.Repos[name] = repo
+			if err := workspace.Save(wsPath, ws); err != nil {
+				return err
+			}
+		}
+
+		ui.OK.Printf("Adopted '%s' (remote: %s, default branch: %s, on: %s)\n",
+			name, orDefault(remote, "none"), branch, orDefault(git.GetCurrentBranch(target), "-"))
+		if !isSubdir(wsPath, target) {
+			fmt.Println("  It lives outside the workspace — --move or --link keeps everything in one place")
+		}
+		return nil
+	},
+}
+
+func init() {
+	adoptCmd.Flags().StringVar(&adoptName, "name", "", "Repo name in the workspace (default: from the remote)")
+	adoptCmd.Flags().BoolVar(&adoptMove, "move", false, "Move the checkout into the workspace")
+	adoptCmd.Flags().BoolVar(&adoptLink, "link", false, "Symlink the checkout into the workspace")
+	adoptCmd.Flags().StringVar(&adoptPath, "path", "", "Where to move or link it (relative to the workspace; default: the clone layout)")
+	rootCmd.AddCommand(adoptCmd)
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var aliasDelete bool
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias [name [command...]]",
+	Short: "List, set, or delete command aliases (~/.spk/config.json)",
+	Long: `Aliases make frequent invocations short. When the first argument to
+spark-cli is an alias, it is replaced by the alias's arguments and anything
+after it is appended:
+
+  spark-cli alias b run build         # spark-cli b --profile → spark-cli run build --profile
+  spark-cli alias s workspace sync --env beta
+
+Aliases are split on whitespace, don't expand other aliases, and can't shadow a
+built-in command. They're stored under "aliases" in ~/.spk/config.json.
+
+Commands from before the reorganization and the sync.sh era (create workspace,
+sync beta, clone, ...) are translated the same way and still run, with a
+warning naming the current command.
+
+Examples:
+  spark-cli alias                     # list aliases
+  spark-cli alias b run build
+  spark-cli alias --delete b`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadGlobal()
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			if len(cfg.Aliases) == 0 {
+				fmt.Println(binname.Rewrite("No aliases — add one with 'spark-cli alias <name> <command...>'"))
+				return nil
+			}
+			names := make([]string, 0, len(cfg.Aliases))
+			for name := range cfg.Aliases {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf(binname.Rewrite("  %-12s spark-cli %s\n"), name, cfg.Aliases[name])
+			}
+			return nil
+		}
+
+		name := args[0]
+		if aliasDelete {
+			if _, ok := cfg.Aliases[name]; !ok {
+				return errs.New(errs.KindUsage, "no alias '%s'", name)
+			}
+			delete(cfg.Aliases, name)
+			if err := config.SaveGlobal(cfg); err != nil {
+				return err
+			}
+			ui.OK.Printf("Deleted alias '%s'\n", name)
+			return nil
+		}
+
+		if len(args) == 1 {
+			expansion, ok := cfg.Aliases[name]
+			if !ok {
+				return errs.New(errs.KindUsage, "no alias '%s'", name)
+			}
+			fmt.Printf(binname.Rewrite("%s = spark-cli %s\n"), name, expansion)
+			return nil
+		}
+		if strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t") {
+			return errs.New(errs.KindUsage, "invalid alias name %q", name)
+		}
+		if isBuiltinCommand(name) {
+			return errs.New(errs.KindUsage, "'%s' is a spark-cli command — pick another alias name", name)
+		}
+		if cfg.Aliases == nil {
+			cfg.Aliases = make(map[string]string)
+		}
+		cfg.Aliases[name] = strings.Join(args[1:], " ")
+		if err := config.SaveGlobal(cfg); err != nil {
+			return err
+		}
+		ui.OK.Printf(binname.Rewrite("%s = spark-cli %s\n"), name, cfg.Aliases[name])
+		return nil
+	},
+}
+
+// isBuiltinCommand reports whether name is a top-level command or one of its aliases
+func isBuiltinCommand(name string) bool {
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandAlias replaces a leading user alias in args with its expansion
+func expandAlias(args []string) []string {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isBuiltinCommand(args[0]) {
+		return args
+	}
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return args
+	}
+	expansion, ok := cfg.Aliases[args[0]]
+	if !ok {
+		return args
+	}
+	return append(strings.Fields(expansion), args[1:]...)
+}
+
+func init() {
+	aliasCmd.Flags().BoolVar(&aliasDelete, "delete", false, "Delete the named alias")
+	// Flags after the alias name belong to the expansion
+	aliasCmd.Flags().SetInterspersed(false)
+	rootCmd.AddCommand(aliasCmd)
+}
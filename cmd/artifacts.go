@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/buildcache"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var artifactsJSON bool
+
+var artifactsCmd = &cobra.Command{
+	Use:   "artifacts [repo...]",
+	Short: "List what a repo's build produced and whether it's older than the sources",
+	Long: `Lists the build outputs of a repo — Smithy codegen outputs (with the package
+name and version they'd link as), output dirs like dist/, and jar files — with
+their size, when they were last written, and whether a tracked source file
+changed after them.
+
+An output older than the sources is why a change isn't showing up in the repos
+that link it: rebuild with 'spark-cli build <repo>'. Output dirs are the repo's
+"cache_outputs" in workspace.json, else smithy/build and dist.
+
+With no repo, lists the repo you're in, or every cloned repo outside one.
+
+Examples:
+  spark-cli artifacts AppModel
+  spark-cli artifacts
+  spark-cli artifacts @models --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			if name, _ := detectCurrentRepo(wsPath, ws); name != "" {
+				args = []string{name}
+			}
+		}
+		names, err := expandRepoNames(ws, args)
+		if err != nil {
+			return err
+		}
+
+		var reports []repoArtifacts
+		for _, name := range names {
+			repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+			if _, err := os.Stat(repoDir); err != nil {
+				if len(args) > 0 {
+					ui.Skip.Printf("%s: not cloned\n", name)
+				}
+				continue
+			}
+			reports = append(reports, collectArtifacts(wsPath, ws, name))
+		}
+		if artifactsJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(reports)
+		}
+		for i, r := range reports {
+			if i > 0 {
+				fmt.Println()
+			}
+			printArtifacts(r)
+		}
+		return nil
+	},
+}
+
+// repoArtifacts is what a repo's build produced
+type repoArtifacts struct {
+	Repo          string     `json:"repo"`
+	SourceFile    string     `json:"source_file,omitempty"` // most recently changed tracked file
+	SourceChanged *time.Time `json:"source_changed,omitempty"`
+	Artifacts     []artifact `json:"artifacts"`
+}
+
+// artifact is one build output
+type artifact struct {
+	Kind    string    `json:"kind"` // codegen, dir, or jar
+	Path    string    `json:"path"` // relative to the repo
+	Package string    `json:"package,omitempty"`
+	Size    int64     `json:"size"`
+	BuiltAt time.Time `json:"built_at"`
+	Stale   bool      `json:"stale"` // a source changed after it was built
+}
+
+// collectArtifacts finds name's codegen outputs, output dirs, and jars
+func collectArtifacts(wsPath string, ws *workspace.Workspace, name string) repoArtifacts {
+	repo := ws.Repos[name]
+	repoDir := filepath.Join(wsPath, repo.Path)
+	r := repoArtifacts{Repo: name, Artifacts: []artifact{}}
+	source, changed, ok := buildcache.NewestSource(repoDir, repo.CacheOutputs)
+	if ok && !changed.IsZero() {
+		r.SourceFile, r.SourceChanged = source, &changed
+	}
+
+	add := func(kind, path, pkg string) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return
+		}
+		a := artifact{Kind: kind, Package: pkg, Size: info.Size(), BuiltAt: info.ModTime()}
+		if info.IsDir() {
+			a.Size, a.BuiltAt = dirSize(path), buildcache.NewestModTime(path)
+		}
+		a.Path, _ = filepath.Rel(repoDir, path)
+		a.Stale = r.SourceChanged != nil && !a.BuiltAt.IsZero() && r.SourceChanged.After(a.BuiltAt)
+		r.Artifacts = append(r.Artifacts, a)
+	}
+
+	jarDirs := []string{repoDir}
+	if dir := repoGradleDir(wsPath, ws, name); dir != "" && dir != repoDir {
+		jarDirs = append(jarDirs, dir)
+	}
+	codegenBase := filepath.Join(repoDir, npm.SmithyBuildBase)
+	entries, _ := os.ReadDir(codegenBase)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(codegenBase, e.Name())
+		add("codegen", dir, codegenPackage(dir))
+		jarDirs = append(jarDirs, dir)
+	}
+	for _, out := range buildcache.Outputs(repoDir, repo.CacheOutputs) {
+		// smithy/build is already listed, one codegen output at a time
+		if len(entries) > 0 && filepath.Clean(out) == filepath.Clean("smithy/build") {
+			continue
+		}
+		add("dir", filepath.Join(repoDir, out), "")
+	}
+	for _, dir := range jarDirs {
+		jars, _ := filepath.Glob(filepath.Join(dir, "build", "libs", "*.jar"))
+		for _, jar := range jars {
+			add("jar", jar, "")
+		}
+	}
+	return r
+}
+
+// codegenPackage returns "name@version" from a codegen output's package.json, or "" if it
+// has none
+func codegenPackage(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(data, &pkg) != nil || pkg.Name == "" {
+		return ""
+	}
+	if pkg.Version == "" {
+		return pkg.Name
+	}
+	return pkg.Name + "@" + pkg.Version
+}
+
+// printArtifacts prints a repo's artifacts, flagging the ones older than its sources
+func printArtifacts(r repoArtifacts) {
+	fmt.Print(r.Repo)
+	if r.SourceChanged != nil {
+		fmt.Printf("  (sources changed %s: %s)", formatAge(time.Since(*r.SourceChanged)), r.SourceFile)
+	}
+	fmt.Println()
+	if len(r.Artifacts) == 0 {
+		fmt.Printf(binname.Rewrite("  no build outputs — run 'spark-cli build %s'\n"), r.Repo)
+		return
+	}
+	stale := slices.ContainsFunc(r.Artifacts, func(a artifact) bool { return a.Stale })
+	for _, a := range r.Artifacts {
+		mark, note := ui.OK, ""
+		if a.Stale {
+			mark, note = ui.Warn, "  older than sources"
+		}
+		path := a.Path
+		if a.Kind == "codegen" {
+			path = filepath.Base(path) // the plugin name; they all live under npm.SmithyBuildBase
+		}
+		fmt.Printf("  %s %-8s %-35s %-35s %10s  built %s%s\n", mark, a.Kind, path, a.Package, formatBytes(a.Size), formatAge(time.Since(a.BuiltAt)), note)
+	}
+	if stale {
+		fmt.Printf(binname.Rewrite("  Rebuild with 'spark-cli build %s'\n"), r.Repo)
+	}
+}
+
+func init() {
+	artifactsCmd.Flags().BoolVar(&artifactsJSON, "json", false, "Print the artifacts as JSON")
+	rootCmd.AddCommand(artifactsCmd)
+}
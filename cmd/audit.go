@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/audit"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditRepos       []string
+	auditMaxSeverity string
+	auditJSON        bool
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Scan workspace repos for vulnerable dependencies",
+	Long: `Runs 'npm audit' in each Node repo (and osv-scanner, when installed, for
+Gradle/Maven and other lockfiles), then merges the results by package so a
+vulnerability shared by several repos is reported once with every repo listed.
+
+--max-severity fails the command when any finding is at or above the given
+severity (low, moderate, high, critical) — use it in CI or before a release.
+Findings the scanner gave no severity (common for osv-scanner) count as over it.
+
+Per-repo progress goes to stderr, so --json output can be piped.
+
+Examples:
+  spark-cli audit
+  spark-cli audit --max-severity high
+  spark-cli audit --repos AppAPI,AppModel --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if auditMaxSeverity != "" && audit.SeverityRank(auditMaxSeverity) < 0 {
+			return errs.New(errs.KindUsage, "invalid --max-severity %q — use one of %s", auditMaxSeverity, strings.Join(audit.Severities, ", "))
+		}
+
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, auditRepos)
+		if err != nil {
+			return err
+		}
+
+		osv := audit.OSVAvailable()
+		perRepo := make(map[string][]audit.Finding)
+		var failed int
+		for _, name := range names {
+			repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+			findings, scanner, err := auditRepo(repoDir, osv)
+			switch {
+			case err != nil:
+				ui.Fail.Fprintf(os.Stderr, "%-25s %v\n", name, err)
+				failed++
+			case scanner == "":
+				ui.Skip.Fprintf(os.Stderr, "%-25s nothing to scan (install osv-scanner for non-Node repos)\n", name)
+			default:
+				ui.OK.Fprintf(os.Stderr, "%-25s %s: %d vulnerable package(s)\n", name, scanner, len(findings))
+				perRepo[name] = findings
+			}
+		}
+
+		merged := audit.Merge(perRepo)
+		if auditJSON {
+			out, err := json.MarshalIndent(merged, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+		} else {
+			printAuditReport(merged)
+		}
+
+		if auditMaxSeverity != "" {
+			threshold := audit.SeverityRank(auditMaxSeverity)
+			var over, unknown int
+			for _, f := range merged {
+				switch rank := audit.SeverityRank(f.Severity); {
+				case rank < 0:
+					unknown++
+				case rank >= threshold:
+					over++
+				}
+			}
+			// A finding without a severity can't be shown to be under the threshold
+			switch {
+			case over > 0 && unknown > 0:
+				return errs.New(errs.KindCommandFailed, "%d package(s) at or above %s severity, %d more with unknown severity", over, auditMaxSeverity, unknown)
+			case over > 0:
+				return errs.New(errs.KindCommandFailed, "%d package(s) at or above %s severity", over, auditMaxSeverity)
+			case unknown > 0:
+				return errs.New(errs.KindCommandFailed, "%d package(s) with unknown severity — review them in the report above", unknown)
+			}
+		}
+		if failed > 0 {
+			return errs.New(errs.KindCommandFailed, "%d repo(s) could not be audited", failed)
+		}
+		return nil
+	},
+}
+
+// auditRepo scans one repo with npm audit (Node repos) or osv-scanner, returning which
+// scanner ran; an empty scanner means no supported scanner applies
+func auditRepo(repoDir string, osv bool) ([]audit.Finding, string, error) {
+	if fileExistsCheck(filepath.Join(repoDir, "package-lock.json")) {
+		findings, err := audit.NpmAudit(repoDir)
+		return findings, "npm audit", err
+	}
+	if osv {
+		findings, err := audit.OSVScan(repoDir)
+		return findings, "osv-scanner", err
+	}
+	return nil, "", nil
+}
+
+func printAuditReport(findings []audit.Finding) {
+	if len(findings) == 0 {
+		fmt.Println("\nNo known vulnerabilities")
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	var summary []string
+	for i := len(audit.Severities) - 1; i >= 0; i-- {
+		if n := counts[audit.Severities[i]]; n > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", n, audit.Severities[i]))
+		}
+	}
+	if n := counts["unknown"]; n > 0 {
+		summary = append(summary, fmt.Sprintf("%d unknown", n))
+	}
+	fmt.Printf("\n%d vulnerable package(s): %s\n\n", len(findings), strings.Join(summary, ", "))
+
+	for _, f := range findings {
+		fix := ""
+		if f.Fixable {
+			fix = " (fix available)"
+		}
+		fmt.Printf("  %-9s %s%s\n", f.Severity, f.Package, fix)
+		for _, t := range f.Titles {
+			fmt.Printf("            %s\n", t)
+		}
+		fmt.Printf("            repos: %s\n", strings.Join(f.Repos, ", "))
+	}
+}
+
+func init() {
+	auditCmd.Flags().StringSliceVar(&auditRepos, "repos", nil, "Limit to these repos (comma-separated)")
+	auditCmd.Flags().StringVar(&auditMaxSeverity, "max-severity", "", "Fail if any finding is at or above this severity (low, moderate, high, critical)")
+	auditCmd.Flags().BoolVar(&auditJSON, "json", false, "Print the merged findings as JSON")
+	rootCmd.AddCommand(auditCmd)
+}
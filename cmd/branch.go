@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	branchPruneRepos  []string
+	branchPruneDryRun bool
+	branchPruneYes    bool
+)
+
+// prunableBranch is a local branch branch prune would delete
+type prunableBranch struct {
+	repo    string
+	repoDir string
+	branch  string
+	reason  string
+}
+
+var branchCmd = &cobra.Command{
+	Use:   "branch",
+	Short: "Manage local branches across repos (prune)",
+}
+
+var branchPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete local branches whose upstream is gone or whose PR is merged",
+	Long: `Fetches each repo (pruning deleted remote branches), then deletes local
+branches that are done with:
+
+  - the upstream branch was deleted on the remote, or
+  - a merged GitHub PR was opened from the branch (needs a GitHub token;
+    catches squash merges where the branch was never deleted)
+
+The checked-out branch and the repo's default/target branch are never deleted,
+and neither is a branch with commits that were never pushed: its tip has to be
+the merged PR's head (or part of it), the upstream's last-known commit, or on
+some remote branch. Otherwise it's skipped with a warning — a reused name like
+"fix" with new work on it is kept. Branches are removed with 'git branch -D',
+since squash-merged branches aren't ancestors of the base.
+
+Examples:
+  spark-cli branch prune --dry-run
+  spark-cli branch prune --repos AppAPI,AppModel
+  spark-cli branch prune --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, branchPruneRepos)
+		if err != nil {
+			return err
+		}
+		token := resolveGitHubToken()
+		if token == "" {
+			ui.Warn.Println("No GitHub token — only pruning branches whose upstream is gone")
+		}
+
+		var found []prunableBranch
+		for _, name := range names {
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+			// the upstreams' commits before the fetch prunes them
+			lastUpstream := git.UpstreamTips(repoDir)
+			if err := git.FetchPruneQuiet(repoDir, "origin"); err != nil {
+				ui.Warn.Printf("%-25s fetch failed — using remote branches as of the last fetch\n", name)
+			}
+
+			keep := map[string]bool{
+				getTargetBranch(ws, &repo, repoDir): true,
+				defaultBranch(repoDir):              true,
+			}
+			current := git.GetCurrentBranch(repoDir)
+			gone := make(map[string]bool)
+			for _, b := range git.GoneBranches(repoDir) {
+				gone[b] = true
+			}
+			slug := git.GitHubSlug(git.RemoteURL(repoDir, "origin"))
+
+			for _, branch := range git.ListLocalBranches(repoDir) {
+				if keep[branch] {
+					continue
+				}
+				reason, pushed := "", ""
+				if gone[branch] {
+					reason, pushed = "upstream gone", lastUpstream[branch]
+				} else if token != "" && slug != "" {
+					pr, err := github.MergedPullRequest(token, slug, branch)
+					if err != nil {
+						ui.Warn.Printf("%-25s %s: couldn't check PRs: %v\n", name, branch, err)
+						continue
+					}
+					if pr != nil {
+						reason, pushed = fmt.Sprintf("PR #%d merged", pr.Number), pr.Head.SHA
+					}
+				}
+				if reason == "" {
+					continue
+				}
+				if !branchPushed(repoDir, branch, pushed) {
+					ui.Warn.Printf("%-25s %s (%s) has commits that were never pushed — not deleting\n", name, branch, reason)
+					continue
+				}
+				if branch == current {
+					ui.Skip.Printf("%-25s %s (%s) is checked out — switch off it to prune\n", name, branch, reason)
+					continue
+				}
+				found = append(found, prunableBranch{repo: name, repoDir: repoDir, branch: branch, reason: reason})
+			}
+		}
+
+		if len(found) == 0 {
+			ui.OK.Println("No stale branches")
+			return nil
+		}
+		for _, b := range found {
+			fmt.Printf("  %-25s %-40s %s\n", b.repo, b.branch, b.reason)
+		}
+		if branchPruneDryRun {
+			fmt.Printf("\n%d branch(es) would be deleted (dry run)\n", len(found))
+			return nil
+		}
+		if !branchPruneYes {
+			if prompt.NonInteractive {
+				return errs.New(errs.KindUsage, "%d branch(es) to delete — pass --yes to prune non-interactively", len(found))
+			}
+			if !prompt.Confirm(fmt.Sprintf("Delete %d branch(es)?", len(found)), false) {
+				fmt.Println("Aborted")
+				return nil
+			}
+		}
+
+		var failed int
+		for _, b := range found {
+			if err := git.DeleteBranch(b.repoDir, b.branch); err != nil {
+				ui.Fail.Printf("%-25s %v\n", b.repo, err)
+				failed++
+				continue
+			}
+			ui.OK.Printf("%-25s deleted %s\n", b.repo, b.branch)
+		}
+		if failed > 0 {
+			return errs.New(errs.KindCommandFailed, "%d branch(es) failed to delete", failed)
+		}
+		return nil
+	},
+}
+
+// branchPushed reports whether branch's local commits are all in pushed (the merged PR's
+// head or the upstream's last-known commit) or on some remote branch
+func branchPushed(repoDir, branch, pushed string) bool {
+	tip := git.BranchSHA(repoDir, branch)
+	if tip == "" {
+		return false
+	}
+	if pushed != "" && (tip == pushed || git.IsAncestor(repoDir, tip, pushed)) {
+		return true
+	}
+	return git.OnRemoteBranch(repoDir, tip)
+}
+
+func init() {
+	branchPruneCmd.Flags().StringSliceVar(&branchPruneRepos, "repos", nil, "Only prune these repos")
+	branchPruneCmd.Flags().BoolVar(&branchPruneDryRun, "dry-run", false, "List the branches that would be deleted")
+	branchPruneCmd.Flags().BoolVarP(&branchPruneYes, "yes", "y", false, "Don't ask for confirmation")
+	branchCmd.AddCommand(branchPruneCmd)
+	rootCmd.AddCommand(branchCmd)
+}
@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/buildcache"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/metrics"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/progress"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellquote"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	buildAll       bool
+	buildDeps      bool
+	buildNoCache   bool
+	buildPushCache bool
+	buildProfile   bool
+)
+
+var buildCmd = &cobra.Command{
+	Use:   "build [repo...]",
+	Short: "Build repos in dependency order, reusing the team build cache",
+	Long: `Builds the given repos (default: the repo you're in, otherwise pick from a
+list) after the repos they depend on. Uses the repo's build_command from workspace.json, otherwise the
+project's build script (npm run build, ./gradlew build, ...).
+
+Remote build cache: with "build_cache" set in workspace.json, each repo's
+artifacts are keyed by a hash of its committed sources plus its dependencies'
+keys. On a hit the artifacts are downloaded instead of rebuilt (e.g. Smithy
+codegen a teammate or CI already produced). Repos with uncommitted changes
+always build locally.
+
+  "build_cache": {"bucket": "spark-build-cache", "profile": "central", "push": false}
+
+profile may be a workspace environment or an AWS profile. Artifacts are the
+repo's cache_outputs (default: smithy/build, dist). Set "push": true (or pass
+--push-cache) to upload after building — typically only in CI.
+
+Build stops at the first repo that fails. With --keep-going it builds the rest,
+skipping repos that depend on a failed one, and lists every failure at the end;
+the exit code is non-zero if any repo failed.
+
+--summary-file writes the outcome as JSON — each repo's status (passed, failed,
+skipped), duration, and error — for CI job summaries and bots. Repos have the same
+shape as in 'spark-cli ci-run --json'.
+
+Examples:
+  spark-cli build                  # current repo
+  spark-cli build AppAPI --deps    # AppAPI and everything it depends on
+  spark-cli build --all
+  spark-cli build --all --no-cache
+  spark-cli build --all --profile  # print where the time went (install vs compile)
+  spark-cli build --all --keep-going --summary-file build-summary.json`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		report := newRunReport("build")
+		defer func() { err = report.finish(err) }()
+
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+
+		if buildProfile {
+			metrics.EnableProfile()
+			defer metrics.PrintProfile(os.Stdout)
+		}
+
+		names := args
+		switch {
+		case buildAll:
+			names = nil
+		case len(names) == 0:
+			current, _ := detectCurrentRepo(wsPath, ws)
+			if current != "" {
+				names = []string{current}
+				break
+			}
+			names, err = pickRepos(ws, "Build which repos?", "not inside a workspace repo — name repos to build or pass --all")
+			if err != nil {
+				return err
+			}
+		}
+
+		order, err := workspace.BuildOrder(wsPath, ws, names)
+		if err != nil {
+			return err
+		}
+		if names != nil && !buildDeps {
+			order = onlyRepos(order, names)
+		}
+
+		wsEnv := buildWorkspaceEnv(wsPath, ws)
+		remote, cacheOn := buildCacheRemote(ws)
+		push := cacheOn && (buildPushCache || ws.BuildCache.Push)
+		keys := make(map[string]string)
+		stop := stopOnFailure(true)
+		failures := &repoFailures{what: "build", kind: errs.KindBuildFailed}
+
+		for i, name := range order {
+			step := progress.Step(i+1, len(order))
+			if proc.Interrupted() {
+				return proc.Context().Err()
+			}
+			if stop && len(failures.failed) > 0 {
+				failures.skip(name, "stopped at the first failure (--keep-going builds the rest)")
+				report.skip(name, "stopped at the first failure")
+				continue
+			}
+			if dep := failedDependency(wsPath, ws, name, failures); dep != "" {
+				ui.Skip.Printf("%s%-25s not built — depends on %s\n", step, name, dep)
+				failures.skip(name, "depends on "+dep)
+				report.skip(name, "depends on "+dep)
+				continue
+			}
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+			if _, err := os.Stat(repoDir); os.IsNotExist(err) {
+				ui.Skip.Printf("%s%-25s not cloned\n", step, name)
+				report.skip(name, "not cloned")
+				continue
+			}
+
+			start := time.Now()
+			key := ""
+			if cacheOn {
+				key = buildCacheKey(wsPath, ws, name, repoDir, keys)
+				keys[name] = key
+			}
+
+			if key != "" && !buildNoCache {
+				endFetch := metrics.Span(name, "cache")
+				hit, err := buildcache.Fetch(remote, name, key, repoDir, repo.CacheOutputs)
+				endFetch()
+				if err != nil {
+					ui.Fail.Printf("%-25s cache fetch failed: %v — building locally\n", name, err)
+				} else if hit {
+					ui.OK.Printf("%s%-25s restored from build cache\n", step, name)
+					report.add(name, time.Since(start), "restored from build cache", nil)
+					continue
+				}
+			}
+
+			if err := buildRepo(wsPath, ws, name, wsEnv); err != nil {
+				ui.Fail.Printf("%s%-25s %v\n", step, name, err)
+				failures.add(name, err)
+				report.add(name, time.Since(start), "", err)
+				continue
+			}
+			report.add(name, time.Since(start), "", nil)
+			ui.OK.Printf("%s%-25s built (%s)\n", step, name, progress.Elapsed(time.Since(start)))
+
+			if key != "" && push {
+				outputs := buildcache.Outputs(repoDir, repo.CacheOutputs)
+				endPush := metrics.Span(name, "cache")
+				err := buildcache.Push(remote, name, key, repoDir, outputs)
+				endPush()
+				if err != nil {
+					ui.Fail.Printf("%-25s cache upload failed: %v\n", name, err)
+				} else {
+					ui.OK.Printf("%-25s uploaded to build cache\n", name)
+				}
+			}
+		}
+		failures.summarize()
+		return failures.err()
+	},
+}
+
+// failedDependency returns the dependency of name that failed to build or was left
+// out because of a failure, or "" if none was
+func failedDependency(wsPath string, ws *workspace.Workspace, name string, failures *repoFailures) string {
+	for _, dep := range workspace.Dependencies(wsPath, ws, name) {
+		if failures.has(dep) {
+			return dep
+		}
+	}
+	return ""
+}
+
+// buildRepo runs a repo's build_command if it has one, otherwise its build script
+func buildRepo(wsPath string, ws *workspace.Workspace, name string, wsEnv map[string]string) error {
+	repo := ws.Repos[name]
+	if repo.BuildCommand == "" {
+		return runRepoScript(wsPath, ws, name, "build", nil, wsEnv)
+	}
+
+	repoDir := filepath.Join(wsPath, repo.Path)
+	wsEnv = repo.ScopeEnv(wsEnv)
+	if detectProjectType(repoDir) == projectTypeNode {
+		if err := ensureNodeModules(wsPath, name, repoDir, wsEnv); err != nil {
+			return err
+		}
+	}
+
+	wsEnv, err := gradleEnv(wsPath, repo, repoDir, wsEnv)
+	if err != nil {
+		return err
+	}
+
+	command := repo.BuildCommand
+	if args := repo.ArgsFor("build", nil); len(args) > 0 {
+		command += " " + shellquote.Join(args)
+	}
+	fmt.Printf("=== %s: %s ===\n", name, command)
+	stop := metrics.Span(name, metrics.PhaseCompile)
+	err = metrics.Time(wsPath, name, "build", func() error {
+		return runShellCmdWithEnv(repoDir, command, nil, wsEnv)
+	})
+	stop()
+	if err != nil {
+		return errs.New(errs.KindBuildFailed, "%s: %s failed: %w", name, command, err)
+	}
+	return nil
+}
+
+// buildCacheRemote resolves workspace.json's build_cache; false if none is configured
+func buildCacheRemote(ws *workspace.Workspace) (buildcache.Remote, bool) {
+	c := ws.BuildCache
+	if c == nil || c.Bucket == "" {
+		return buildcache.Remote{}, false
+	}
+	r := buildcache.Remote{
+		Bucket:  c.Bucket,
+		Prefix:  c.Prefix,
+		Profile: c.Profile,
+		Region:  c.Region,
+	}
+	if _, ok := ws.Environments[c.Profile]; ok {
+		profile, region := ws.ResolveEnv(c.Profile)
+		r.Profile = profile
+		if r.Region == "" {
+			r.Region = region
+		}
+	}
+	return r, true
+}
+
+// buildCacheKey keys a repo from its sources and its dependencies' keys. It returns ""
+// (build locally, don't upload) when the repo or any dependency can't be keyed.
+func buildCacheKey(wsPath string, ws *workspace.Workspace, name, repoDir string, keys map[string]string) string {
+	if err := buildcache.CheckOutputs(ws.Repos[name].CacheOutputs); err != nil {
+		fmt.Printf("  %s: %v — skipping build cache\n", name, err)
+		return ""
+	}
+	var depKeys []string
+	for _, dep := range workspace.Dependencies(wsPath, ws, name) {
+		k, ok := keys[dep]
+		if !ok {
+			// Dependency not part of this build — key it now
+			depDir := filepath.Join(wsPath, ws.Repos[dep].Path)
+			k = buildCacheKey(wsPath, ws, dep, depDir, keys)
+			keys[dep] = k
+		}
+		if k == "" {
+			return ""
+		}
+		depKeys = append(depKeys, k)
+	}
+
+	key, err := buildcache.Key(repoDir, depKeys)
+	if errors.Is(err, buildcache.ErrDirty) {
+		fmt.Printf("  %s has uncommitted changes — skipping build cache\n", name)
+		return ""
+	}
+	if err != nil {
+		return ""
+	}
+	return key
+}
+
+// onlyRepos filters order down to names, keeping order
+func onlyRepos(order, names []string) []string {
+	want := make(map[string]bool, len(names))
+	for _, n := range names {
+		want[n] = true
+	}
+	var out []string
+	for _, n := range order {
+		if want[n] {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func init() {
+	buildCmd.Flags().BoolVar(&buildAll, "all", false, "Build every repo in the workspace")
+	buildCmd.Flags().BoolVar(&buildDeps, "deps", false, "Also build the repos the named repos depend on")
+	buildCmd.Flags().BoolVar(&buildNoCache, "no-cache", false, "Don't download artifacts from the build cache")
+	buildCmd.Flags().BoolVar(&buildPushCache, "push-cache", false, "Upload built artifacts to the build cache")
+	buildCmd.Flags().BoolVar(&buildProfile, "profile", false, "Print a per-repo breakdown of time spent installing and compiling")
+	addFailurePolicyFlags(buildCmd, true)
+	addSummaryFileFlag(buildCmd)
+	rootCmd.AddCommand(buildCmd)
+}
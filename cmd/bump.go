@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bumpRepos    []string
+	bumpNoTest   bool
+	bumpDraft    bool
+	bumpWatch    bool
+	bumpInterval time.Duration
+)
+
+var bumpCmd = &cobra.Command{
+	Use:   "bump <package>@<version> | <package> --watch",
+	Short: "Open PRs bumping a package in every repo that depends on it",
+	Long: `For each workspace repo whose package.json depends on the package (or only
+--repos), bump branches from the repo's default branch, installs the version
+(updating package.json and the lockfile), runs the repo's tests, and pushes
+the branch and opens a PR. A repo whose tests fail gets no PR; its branch is
+deleted. Repos already on the version are skipped.
+
+--watch waits for new GitHub releases of the repo that publishes the package
+(the model a spk.config.json "consumes" it from) and bumps to each one once the
+registry has it, until interrupted.
+
+Examples:
+  spark-cli bump @spark-rewards/sra-sdk@1.4.0
+  spark-cli bump @spark-rewards/sra-sdk@1.4.0 --repos AppAPI,Web --draft
+  spark-cli bump @spark-rewards/sra-sdk --watch`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := expandRepoNames(ws, bumpRepos)
+		if err != nil {
+			return err
+		}
+		opts := bumpOptions{token: resolveGitHubToken(), draft: bumpDraft, test: !bumpNoTest, via: "spark-cli bump"}
+
+		pkg, version := splitPackageVersion(args[0])
+		if bumpWatch {
+			if version != "" {
+				return errs.New(errs.KindUsage, "--watch bumps to each new release — name the package without a version")
+			}
+			return watchReleases(wsPath, ws, pkg, names, opts)
+		}
+		if version == "" {
+			return errs.New(errs.KindUsage, "name the version to bump to, e.g. %s@1.2.3 (or --watch)", pkg)
+		}
+		return bumpConsumers(wsPath, ws, pkg, version, names, opts)
+	},
+}
+
+// bumpOptions controls how openBumpPR bumps a consumer
+type bumpOptions struct {
+	token string // GitHub token to open the PR with; without one the branch is only pushed
+	draft bool
+	test  bool   // run the consumer's tests on the bump before pushing it
+	via   string // the command opening the PR, named in its description
+}
+
+// errBumpCurrent means the consumer is already on the version
+var errBumpCurrent = errors.New("already on that version")
+
+// bumpConsumers opens a bump PR in each of names that depends on pkg
+func bumpConsumers(wsPath string, ws *workspace.Workspace, pkg, version string, names []string, opts bumpOptions) error {
+	var found, failed int
+	for _, name := range names {
+		repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+		spec := packageDependency(repoDir, pkg)
+		if spec == "" {
+			continue
+		}
+		found++
+		if strings.TrimLeft(spec, "^~=") == version {
+			ui.Skip.Printf("%-25s already on %s\n", name, spec)
+			continue
+		}
+		fmt.Printf("=== %s: %s %s → %s ===\n", name, pkg, spec, version)
+		url, err := openBumpPR(wsPath, ws, name, pkg, version, opts)
+		switch {
+		case errors.Is(err, errBumpCurrent):
+			ui.Skip.Printf("%-25s already on %s\n", name, version)
+		case err != nil:
+			ui.Fail.Printf("%-25s %v\n", name, err)
+			failed++
+		default:
+			ui.OK.Printf("%-25s %s\n", name, orDefault(url, "pushed (open the PR manually — no GitHub remote or token)"))
+		}
+	}
+	if found == 0 {
+		fmt.Printf("No repo depends on %s\n", pkg)
+	}
+	if failed > 0 {
+		return errs.New(errs.KindCommandFailed, "%d bump(s) failed", failed)
+	}
+	return nil
+}
+
+// watchReleases bumps names to each new release of the repo that publishes pkg, once the
+// registry has it, until interrupted
+func watchReleases(wsPath string, ws *workspace.Workspace, pkg string, names []string, opts bumpOptions) error {
+	model := ""
+	for _, name := range names {
+		for _, c := range consumedModels(wsPath, ws, name) {
+			if c.Package == pkg {
+				model = c.Model
+			}
+		}
+	}
+	if model == "" {
+		return errs.New(errs.KindUsage, "no repo's spk.config.json consumes %s, so the repo that releases it is unknown", pkg)
+	}
+	modelDir := filepath.Join(wsPath, ws.Repos[model].Path)
+	slug := git.GitHubSlug(git.RemoteURL(modelDir, "origin"))
+	if slug == "" {
+		return errs.New(errs.KindUsage, "%s's origin is not a GitHub remote — can't watch its releases", model)
+	}
+	if opts.token == "" {
+		return errs.New(errs.KindAuthExpired, "no GitHub token — set GITHUB_TOKEN or run 'gh auth login'")
+	}
+
+	last := ""
+	if rel, err := github.LatestRelease(opts.token, slug); err == nil {
+		last = rel.TagName
+	}
+	fmt.Printf("Watching %s releases for %s (latest: %s) — Ctrl-C to stop\n", slug, pkg, orDefault(last, "none"))
+	env := []string{"GITHUB_TOKEN=" + opts.token}
+	for {
+		select {
+		case <-proc.Context().Done():
+			return nil
+		case <-time.After(bumpInterval):
+		}
+		rel, err := github.LatestRelease(opts.token, slug)
+		if err != nil {
+			fmt.Printf("  %s couldn't check releases: %v\n", ui.Warn, err)
+			continue
+		}
+		if rel.TagName == last {
+			continue
+		}
+		version := strings.TrimPrefix(rel.TagName, "v")
+		if !npm.HasVersion(modelDir, pkg, version, env) {
+			fmt.Printf("  %s released, waiting for %s@%s in the registry...\n", rel.TagName, pkg, version)
+			continue
+		}
+		fmt.Printf("\n%s %s — bumping\n", slug, rel.TagName)
+		if err := bumpConsumers(wsPath, ws, pkg, version, names, opts); err != nil {
+			ui.Fail.Printf("%v\n", err)
+		}
+		last = rel.TagName
+	}
+}
+
+// splitPackageVersion splits "pkg@1.2.3" (or "@scope/pkg@1.2.3"); version is "" if absent
+func splitPackageVersion(arg string) (pkg, version string) {
+	if i := strings.LastIndexByte(arg, '@'); i > 0 {
+		return arg[:i], arg[i+1:]
+	}
+	return arg, ""
+}
+
+// packageDependency returns the version spec repoDir's package.json requires pkg at, or ""
+func packageDependency(repoDir, pkg string) string {
+	data, err := os.ReadFile(filepath.Join(repoDir, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var p struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if json.Unmarshal(data, &p) != nil {
+		return ""
+	}
+	if spec, ok := p.Dependencies[pkg]; ok {
+		return spec
+	}
+	return p.DevDependencies[pkg]
+}
+
+// branchUnsafe matches what can't go in a branch name segment
+var branchUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// openBumpPR bumps pkg to version in consumer on a new branch from its default branch,
+// pushes it, and opens a PR. It returns the PR's URL, or "" when there's no GitHub remote
+// or token to open one with. The consumer is left on the branch it was on.
+func openBumpPR(wsPath string, ws *workspace.Workspace, consumer, pkg, version string, opts bumpOptions) (string, error) {
+	repo := ws.Repos[consumer]
+	repoDir := filepath.Join(wsPath, repo.Path)
+	if git.IsDirty(repoDir) {
+		return "", errs.New(errs.KindRepoDirty, "uncommitted changes — commit or 'spark-cli stash push' first")
+	}
+	base := getTargetBranch(ws, &repo, repoDir)
+	if err := git.FetchQuiet(repoDir, "origin"); err != nil {
+		return "", fmt.Errorf("fetch failed: %w", err)
+	}
+	branch := fmt.Sprintf("bump/%s-%s", strings.Trim(branchUnsafe.ReplaceAllString(pkg, "-"), "-"), version)
+	if git.BranchExists(repoDir, branch) {
+		return "", fmt.Errorf("%s already exists — delete it to bump again", branch)
+	}
+
+	original := git.GetCurrentBranch(repoDir)
+	if err := git.CreateBranchFrom(repoDir, branch, "origin/"+base); err != nil {
+		return "", err
+	}
+	err := commitBump(wsPath, ws, consumer, repoDir, pkg, version, opts.test)
+	if err == nil {
+		err = git.Push(repoDir, "origin", branch, true)
+	}
+	git.RestoreBranchQuiet(repoDir, original)
+	if err != nil {
+		git.DeleteBranch(repoDir, branch)
+		return "", err
+	}
+
+	slug := git.GitHubSlug(git.RemoteURL(repoDir, "origin"))
+	if slug == "" || opts.token == "" {
+		return "", nil
+	}
+	body := fmt.Sprintf("Bumps %s to %s.", pkg, version)
+	if opts.test {
+		body += " The repo's tests passed on the bump locally."
+	}
+	pr, err := github.CreatePullRequest(opts.token, slug, github.NewPullRequest{
+		Title: fmt.Sprintf("chore(deps): bump %s to %s", pkg, version),
+		Head:  branch,
+		Base:  base,
+		Body:  fmt.Sprintf("%s\n\nOpened by `%s`.", body, opts.via),
+		Draft: opts.draft,
+	})
+	if err != nil {
+		return "", fmt.Errorf("pushed %s, but opening the PR failed: %w", branch, err)
+	}
+	return pr.HTMLURL, nil
+}
+
+// commitBump installs pkg@version in the checked-out consumer, updating package.json and
+// the lockfile, optionally runs its tests, and commits the change
+func commitBump(wsPath string, ws *workspace.Workspace, consumer, repoDir, pkg, version string, test bool) error {
+	command := fmt.Sprintf("npm install %s@%s", pkg, version)
+	if manager, _ := npm.PackageManager(repoDir); manager == "pnpm" {
+		command = fmt.Sprintf("pnpm add %s@%s", pkg, version)
+	}
+	wsEnv := buildWorkspaceEnv(wsPath, ws)
+	repo := ws.Repos[consumer]
+	if err := runSyncCmd(repoDir, command, &repo, wsEnv); err != nil {
+		return fmt.Errorf("%s failed: %w", command, err)
+	}
+	if !git.IsDirty(repoDir) {
+		return errBumpCurrent
+	}
+	if test {
+		if err := testRepo(wsPath, ws, consumer, wsEnv); err != nil {
+			return fmt.Errorf("tests failed on %s@%s — no PR opened: %w", pkg, version, err)
+		}
+	}
+	if err := git.AddAll(repoDir); err != nil {
+		return err
+	}
+	return git.Commit(repoDir, fmt.Sprintf("chore(deps): bump %s to %s", pkg, version))
+}
+
+func init() {
+	bumpCmd.Flags().StringSliceVar(&bumpRepos, "repos", nil, "Only bump these repos")
+	bumpCmd.Flags().BoolVar(&bumpNoTest, "no-test", false, "Open the PRs without running the repos' tests first")
+	bumpCmd.Flags().BoolVar(&bumpDraft, "draft", false, "Open the PRs as drafts")
+	bumpCmd.Flags().BoolVar(&bumpWatch, "watch", false, "Bump to each new GitHub release of the package's repo, until interrupted")
+	bumpCmd.Flags().DurationVar(&bumpInterval, "interval", 5*time.Minute, "How often --watch checks for releases")
+	rootCmd.AddCommand(bumpCmd)
+}
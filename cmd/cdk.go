@@ -7,6 +7,10 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/settings"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -27,11 +31,24 @@ var cdkCmd = &cobra.Command{
 from the current repo (if it contains cdk.json) or from CorePipeline (or any
 workspace repo that contains cdk.json). Passes all arguments through to cdk.
 
+Global flags (--workspace, --timeout, --no-login, ...) work as for any command;
+--timeout 30m aborts cdk and its children after that long. -v, --trace, and
+--no-color are passed on to cdk as well.
+
 A --profile / -p flag is available to select an AWS account:
   pipeline  →  AWS_PROFILE=openclaw-pipeline
   beta      →  AWS_PROFILE=openclaw-beta
   prod      →  AWS_PROFILE=openclaw-prod
 
+--env selects a named environment from workspace.json instead, e.g.
+  "environments": {"beta": {"profile": "beta", "account": "050451385382", "region": "us-east-1"}}
+which sets AWS_PROFILE and AWS_REGION and pins the expected account. With
+neither, cdk uses the profile of the active environment ('spark-cli switch-env').
+
+Before deploy/destroy/bootstrap, the STS account of the selected profile is checked
+against the expected account for the env (beta/prod/central); a mismatch aborts.
+Pass --skip-account-check to bypass.
+
 AWS_DEFAULT_OUTPUT=json is always injected. Workspace env (GITHUB_TOKEN etc.)
 is also injected so cdk synth can resolve private npm packages.
 
@@ -39,30 +56,19 @@ Examples:
   spark-cli cdk list
   spark-cli cdk --profile pipeline list
   spark-cli cdk -p beta deploy PipelineStack/beta/SomeStack
+  spark-cli cdk --env beta deploy SomeStack
   spark-cli cdk diff
   spark-cli cdk synth`,
 	Args:               cobra.ArbitraryArgs,
 	DisableFlagParsing: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// --- Parse --profile / -p from args manually (before forwarding to cdk) ---
-		profileShort := ""
-		var cdkArgs []string
-
-		for i := 0; i < len(args); i++ {
-			arg := args[i]
-			switch {
-			case arg == "--profile" || arg == "-p":
-				if i+1 < len(args) {
-					profileShort = args[i+1]
-					i++ // skip value
-				}
-			case strings.HasPrefix(arg, "--profile="):
-				profileShort = strings.TrimPrefix(arg, "--profile=")
-			case strings.HasPrefix(arg, "-p="):
-				profileShort = strings.TrimPrefix(arg, "-p=")
-			default:
-				cdkArgs = append(cdkArgs, arg)
-			}
+		opts, err := parseCDKArgs(args)
+		if err != nil {
+			return err
+		}
+		// Flag parsing is disabled for cdk, so the global flags are applied here
+		if err := applyRootFlags(); err != nil {
+			return err
 		}
 
 		// --- Load workspace ---
@@ -76,29 +82,38 @@ Examples:
 			return err
 		}
 
-		// --- Resolve AWS profile ---
-		awsProfileEnvVal := ""
+		target, err := resolveCDKTarget(ws, opts)
+		if err != nil {
+			return err
+		}
 
-		if profileShort != "" {
-			mapped, ok := profileMap[profileShort]
-			if !ok {
-				return fmt.Errorf("unknown profile %q — valid options: pipeline, beta, prod", profileShort)
+		if target.profile != "" {
+			fmt.Printf("Using AWS profile: %s\n", target.profile)
+			if target.env == "prod" {
+				ui.Warn.Println("Using PROD profile — be careful!")
 			}
-			awsProfileEnvVal = mapped
-		} else if ws.AWSProfile != "" {
-			// Fall back to workspace default
-			awsProfileEnvVal = ws.AWSProfile
 		}
 
-		if awsProfileEnvVal != "" {
-			fmt.Printf("Using AWS profile: %s\n", awsProfileEnvVal)
-			if profileShort == "prod" {
-				fmt.Println("⚠️  Using PROD profile — be careful!")
+		// --- Account guard for commands that change AWS resources ---
+		if cdkMutates(opts.args) && !opts.skipAccountCheck {
+			env := target.env
+			if env == "" {
+				env = activeEnv(ws, "")
+			}
+			var err error
+			switch {
+			case target.expectedAccount != "":
+				err = aws.VerifyAccountID(target.profile, env, target.expectedAccount)
+			case env != "":
+				err = aws.VerifyAccount(target.profile, env)
+			}
+			if err != nil {
+				return fmt.Errorf("%w (override with --skip-account-check)", err)
 			}
 		}
 
 		// --- Find CDK repo dir ---
-		cdkDir, err := findCDKRepoDir(wsPath, ws)
+		cdkDir, cdkRepo, err := findCDKRepoDir(wsPath, ws)
 		if err != nil {
 			return err
 		}
@@ -116,28 +131,17 @@ Examples:
 				envMap[e[:idx]] = e[idx+1:]
 			}
 		}
-
-		// Overlay workspace env (GITHUB_TOKEN, .env, workspace.json env)
-		wsEnv := buildWorkspaceEnv(wsPath, ws)
-		for k, v := range wsEnv {
+		for k, v := range cdkEnv(wsPath, ws, cdkRepo, target) {
 			envMap[k] = v
 		}
 
-		// Always inject AWS_DEFAULT_OUTPUT=json (uppercase JSON in config breaks CLI)
-		envMap["AWS_DEFAULT_OUTPUT"] = "json"
-
-		// Inject AWS_PROFILE if resolved
-		if awsProfileEnvVal != "" {
-			envMap["AWS_PROFILE"] = awsProfileEnvVal
-		}
-
 		// Flatten env map back to slice
 		var env []string
 		for k, v := range envMap {
 			env = append(env, fmt.Sprintf("%s=%s", k, v))
 		}
 
-		c := exec.Command(cdkPath, cdkArgs...)
+		c := proc.Foreground(cdkPath, opts.args...)
 		c.Dir = cdkDir
 		c.Stdin = os.Stdin
 		c.Stdout = os.Stdout
@@ -145,6 +149,9 @@ Examples:
 		c.Env = env
 
 		if err := c.Run(); err != nil {
+			if proc.Interrupted() {
+				return err
+			}
 			if exit, ok := err.(*exec.ExitError); ok {
 				os.Exit(exit.ExitCode())
 			}
@@ -154,12 +161,136 @@ Examples:
 	},
 }
 
-// findCDKRepoDir returns the repo directory that contains cdk.json.
+// cdkOptions are the spark-cli flags mixed into the arguments forwarded to cdk
+type cdkOptions struct {
+	profile          string // --profile / -p: a workspace environment or built-in short name
+	env              string // --env
+	skipAccountCheck bool
+	args             []string // everything else, passed to cdk
+}
+
+// parseCDKArgs separates spark-cli's flags from cdk's (flag parsing is disabled for cdk)
+func parseCDKArgs(args []string) (cdkOptions, error) {
+	var opts cdkOptions
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--profile" || arg == "-p":
+			if i+1 < len(args) {
+				opts.profile = args[i+1]
+				i++ // skip value
+			}
+		case strings.HasPrefix(arg, "--profile="):
+			opts.profile = strings.TrimPrefix(arg, "--profile=")
+		case strings.HasPrefix(arg, "-p="):
+			opts.profile = strings.TrimPrefix(arg, "-p=")
+		case arg == "--env":
+			if i+1 < len(args) {
+				opts.env = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--env="):
+			opts.env = strings.TrimPrefix(arg, "--env=")
+		case arg == "--skip-account-check":
+			opts.skipAccountCheck = true
+		default:
+			// Global flags (-w, --timeout, --no-login, ...) are spark-cli's; the ones cdk
+			// also has are passed on too
+			n, err := takeRootFlag(args, i)
+			if err != nil {
+				return opts, err
+			}
+			if n == 0 || cdkSharesFlag(arg) {
+				opts.args = append(opts.args, arg)
+			}
+			if n > 1 {
+				i += n - 1 // skip value
+			}
+		}
+	}
+	return opts, nil
+}
+
+// cdkSharesFlag reports whether a global flag is one cdk understands as well (-v,
+// --trace, --no-color)
+func cdkSharesFlag(arg string) bool {
+	switch arg {
+	case "--verbose", "--trace", "--no-color":
+		return true
+	}
+	return strings.HasPrefix(arg, "-v") && strings.Trim(arg[1:], "v") == ""
+}
+
+// cdkTarget is the AWS account cdk runs against
+type cdkTarget struct {
+	profile         string
+	region          string
+	env             string
+	expectedAccount string
+}
+
+// resolveCDKTarget picks the AWS profile for cdk. --env selects a named environment from
+// workspace.json; --profile accepts either a workspace environment name or one of the
+// built-in short names; otherwise the active environment's profile is used.
+func resolveCDKTarget(ws *workspace.Workspace, opts cdkOptions) (cdkTarget, error) {
+	t := cdkTarget{env: opts.env}
+	if t.env == "" && opts.profile != "" {
+		if _, ok := ws.Environments[opts.profile]; ok {
+			t.env = opts.profile
+		}
+	}
+
+	switch {
+	case t.env != "":
+		def, ok := ws.Environments[t.env]
+		if !ok {
+			return t, fmt.Errorf("unknown env %q — define it under \"environments\" in workspace.json", t.env)
+		}
+		t.profile, t.region = ws.ResolveEnv(t.env)
+		t.expectedAccount = def.Account
+	case opts.profile != "":
+		mapped, ok := profileMap[opts.profile]
+		if !ok {
+			return t, fmt.Errorf("unknown profile %q — valid options: pipeline, beta, prod", opts.profile)
+		}
+		t.profile = mapped
+		t.env = opts.profile
+	default:
+		// Fall back to the active environment's profile, so it follows switch-env
+		if profile, region := settings.Load(ws).AWS(activeEnv(ws, "")); profile != "" {
+			t.profile, t.region = profile, region
+		}
+	}
+	return t, nil
+}
+
+// cdkEnv returns the variables cdk gets on top of the process environment: the workspace
+// env (GITHUB_TOKEN, .env, workspace.json env) the CDK repo allows, and the resolved AWS
+// profile and region
+func cdkEnv(wsPath string, ws *workspace.Workspace, repo workspace.RepoDef, t cdkTarget) map[string]string {
+	env := make(map[string]string)
+	for k, v := range repo.ScopeEnv(buildWorkspaceEnv(wsPath, ws)) {
+		env[k] = v
+	}
+
+	// Always inject AWS_DEFAULT_OUTPUT=json (uppercase JSON in config breaks CLI)
+	env["AWS_DEFAULT_OUTPUT"] = "json"
+
+	if t.profile != "" {
+		env["AWS_PROFILE"] = t.profile
+	}
+	if t.region != "" {
+		env["AWS_REGION"] = t.region
+	}
+	return env
+}
+
+// findCDKRepoDir returns the repo directory that contains cdk.json, and its definition.
 // Prefers the repo containing the current working dir; otherwise the first workspace repo with cdk.json (e.g. CorePipeline).
-func findCDKRepoDir(wsPath string, ws *workspace.Workspace) (string, error) {
+func findCDKRepoDir(wsPath string, ws *workspace.Workspace) (string, workspace.RepoDef, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
-		return "", err
+		return "", workspace.RepoDef{}, err
 	}
 
 	// If cwd is inside a repo that has cdk.json, use it.
@@ -168,7 +299,7 @@ func findCDKRepoDir(wsPath string, ws *workspace.Workspace) (string, error) {
 		absRepo, _ := filepath.Abs(repoDir)
 		if cwd == absRepo || isSubdir(absRepo, cwd) {
 			if hasCDK(repoDir) {
-				return repoDir, nil
+				return repoDir, repo, nil
 			}
 			break
 		}
@@ -178,11 +309,29 @@ func findCDKRepoDir(wsPath string, ws *workspace.Workspace) (string, error) {
 	for _, repo := range ws.Repos {
 		repoDir := filepath.Join(wsPath, repo.Path)
 		if hasCDK(repoDir) {
-			return repoDir, nil
+			return repoDir, repo, nil
 		}
 	}
 
-	return "", fmt.Errorf("no CDK app (cdk.json) found in workspace — run from CorePipeline or add cdk.json to a repo")
+	return "", workspace.RepoDef{}, fmt.Errorf("no CDK app (cdk.json) found in workspace — run from CorePipeline or add cdk.json to a repo")
+}
+
+// cdkMutatingCommands are cdk subcommands that create, change, or delete AWS resources
+var cdkMutatingCommands = map[string]bool{
+	"deploy":    true,
+	"destroy":   true,
+	"bootstrap": true,
+}
+
+// cdkMutates reports whether the forwarded cdk args run a mutating subcommand. Any
+// positional arg is checked since global cdk flags (e.g. -c key=val) may come first.
+func cdkMutates(cdkArgs []string) bool {
+	for _, a := range cdkArgs {
+		if cdkMutatingCommands[a] {
+			return true
+		}
+	}
+	return false
 }
 
 func hasCDK(dir string) bool {
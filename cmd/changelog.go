@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// unlabeledGroup is the heading for PRs without labels
+const unlabeledGroup = "Other"
+
+var (
+	changelogSince  string
+	changelogUntil  string
+	changelogRepos  []string
+	changelogOutput string
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Generate Markdown release notes from merged PRs across repos",
+	Long: `Collects the PRs merged in a date range across workspace repos, grouped by
+repo and then by label, and prints Markdown for release notes or sprint reviews.
+
+Repos without a GitHub remote (or when the API is unreachable) fall back to the
+commit log of their default branch.
+
+Examples:
+  spark-cli changelog --since 2024-05-01
+  spark-cli changelog --since 2024-05-01 --until 2024-05-14 --repos AppAPI,AppModel
+  spark-cli changelog --since 2024-05-01 -o sprint-42.md`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if changelogSince == "" {
+			return errs.New(errs.KindUsage, "--since is required (e.g. --since 2024-05-01)")
+		}
+		for _, d := range []string{changelogSince, changelogUntil} {
+			if d == "" {
+				continue
+			}
+			if _, err := time.Parse("2006-01-02", d); err != nil {
+				return errs.New(errs.KindUsage, "invalid date %q — use YYYY-MM-DD", d)
+			}
+		}
+
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, changelogRepos)
+		if err != nil {
+			return err
+		}
+
+		token := resolveGitHubToken()
+		var b strings.Builder
+		rangeText := "since " + changelogSince
+		if changelogUntil != "" {
+			rangeText = fmt.Sprintf("%s to %s", changelogSince, changelogUntil)
+		}
+		fmt.Fprintf(&b, "# Changelog (%s)\n", rangeText)
+
+		var total int
+		for _, name := range names {
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+
+			section, n, err := changelogSection(repoDir, getTargetBranch(ws, &repo, repoDir), token)
+			if err != nil {
+				ui.Fail.Fprintf(os.Stderr, "%s: %v\n", name, err)
+				continue
+			}
+			if n == 0 {
+				continue
+			}
+			total += n
+			fmt.Fprintf(&b, "\n## %s\n%s", name, section)
+		}
+		if total == 0 {
+			b.WriteString("\nNo changes.\n")
+		}
+
+		if changelogOutput != "" {
+			if err := os.WriteFile(changelogOutput, []byte(b.String()), 0644); err != nil {
+				return err
+			}
+			ui.OK.Printf("Wrote %d entries to %s\n", total, changelogOutput)
+			return nil
+		}
+		fmt.Print(b.String())
+		return nil
+	},
+}
+
+// changelogSection renders one repo's entries, preferring merged PRs grouped by label and
+// falling back to the default branch's commit log
+func changelogSection(repoDir, branch, token string) (string, int, error) {
+	var b strings.Builder
+
+	slug := git.GitHubSlug(git.RemoteURL(repoDir, "origin"))
+	if slug != "" && token != "" {
+		prs, err := github.SearchMergedPRs(token, slug, changelogSince, changelogUntil)
+		if err == nil {
+			groups := make(map[string][]github.PullRequest)
+			for _, pr := range prs {
+				label := unlabeledGroup
+				if len(pr.Labels) > 0 {
+					label = pr.Labels[0].Name
+				}
+				groups[label] = append(groups[label], pr)
+			}
+			for _, label := range sortedLabels(groups) {
+				fmt.Fprintf(&b, "\n### %s\n", label)
+				for _, pr := range groups[label] {
+					fmt.Fprintf(&b, "- %s ([#%d](%s)) @%s\n", pr.Title, pr.Number, pr.HTMLURL, pr.User.Login)
+				}
+			}
+			return b.String(), len(prs), nil
+		}
+		fmt.Fprintf(os.Stderr, "  %s: %v — using commit log\n", slug, err)
+	}
+
+	until := changelogUntil
+	if until != "" {
+		until += " 23:59:59" // git's --until is exclusive of the day otherwise
+	}
+	commits, err := git.LogSubjects(repoDir, "origin/"+branch, changelogSince, until)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(commits) > 0 {
+		b.WriteString("\n")
+	}
+	for _, c := range commits {
+		fmt.Fprintf(&b, "- %s\n", c)
+	}
+	return b.String(), len(commits), nil
+}
+
+// sortedLabels orders label groups alphabetically with unlabeled PRs last
+func sortedLabels(groups map[string][]github.PullRequest) []string {
+	labels := make([]string, 0, len(groups))
+	for l := range groups {
+		if l != unlabeledGroup {
+			labels = append(labels, l)
+		}
+	}
+	sort.Strings(labels)
+	if _, ok := groups[unlabeledGroup]; ok {
+		labels = append(labels, unlabeledGroup)
+	}
+	return labels
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogSince, "since", "", "Start date, inclusive (YYYY-MM-DD)")
+	changelogCmd.Flags().StringVar(&changelogUntil, "until", "", "End date, inclusive (YYYY-MM-DD; default: today)")
+	changelogCmd.Flags().StringSliceVar(&changelogRepos, "repos", nil, "Limit to these repos (comma-separated)")
+	changelogCmd.Flags().StringVarP(&changelogOutput, "output", "o", "", "Write the Markdown to a file instead of stdout")
+	rootCmd.AddCommand(changelogCmd)
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// ciMode runs commands in a plain (non-login) shell and skips gh/SSO lookups; set by ci-run
+var ciMode bool
+
+var (
+	ciAffected bool
+	ciBase     string
+	ciRepos    []string
+	ciEnv      []string
+	ciJSON     bool
+)
+
+var ciRunCmd = &cobra.Command{
+	Use:   "ci-run <script>",
+	Short: "Run a script across repos in dependency order, for CI (non-interactive)",
+	Long: `Runs a script (build, test, lint, ...) in every repo in dependency order, the
+same ordering spark-cli uses locally, tuned for GitHub Actions:
+
+  - no login shells: commands run in a plain shell with the runner's PATH
+  - no prompts, no gh auth or SSO lookups
+  - explicit env: the runner environment, workspace .env/workspace.json env, and
+    any --env KEY=VALUE pairs — nothing else is injected
+  - installs are frozen (npm ci / pnpm --frozen-lockfile)
+  - every repo runs; the exit code is non-zero if any failed
+  - --json prints a machine-readable summary as the only output on stdout (the
+    repos' output and progress go to stderr); a Markdown summary is appended to
+    $GITHUB_STEP_SUMMARY when it is set
+
+--affected limits the run to repos with commits since --base plus every repo
+that depends on them.
+
+Examples:
+  spark-cli ci-run build
+  spark-cli ci-run test --affected --base origin/main
+  spark-cli ci-run build --repos AppModel,AppAPI --env NODE_ENV=test --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		script := args[0]
+		ciMode = true
+		prompt.NonInteractive = true
+		frozenInstall = true
+
+		// With --json stdout carries only the summary; progress and the repos' own
+		// output go to stderr
+		summaryOut := os.Stdout
+		if ciJSON {
+			os.Stdout = os.Stderr
+			defer func() { os.Stdout = summaryOut }()
+		}
+
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+
+		names, err := selectRepos(wsPath, ws, ciRepos)
+		if err != nil {
+			return err
+		}
+		if ciAffected {
+			names = affectedRepos(wsPath, ws, names, ciBase)
+			if len(names) == 0 {
+				fmt.Printf("No repos changed since %s\n", ciBase)
+				return writeCISummary(summaryOut, script, nil)
+			}
+		}
+
+		order, err := workspace.BuildOrder(wsPath, ws, names)
+		if err != nil {
+			return err
+		}
+		order = onlyRepos(order, names)
+
+		wsEnv := buildWorkspaceEnv(wsPath, ws)
+		for _, kv := range ciEnv {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok || k == "" {
+				return errs.New(errs.KindUsage, "invalid --env %q — use KEY=VALUE", kv)
+			}
+			wsEnv[k] = v
+		}
+
+		var results []ciResult
+		for _, name := range order {
+			if proc.Interrupted() {
+				results = append(results, ciResult{Repo: name, Status: "skipped", Error: "interrupted"})
+				continue
+			}
+			start := time.Now()
+			var runErr error
+			if script == "build" {
+				runErr = buildRepo(wsPath, ws, name, wsEnv)
+			} else {
+				runErr = runRepoScript(wsPath, ws, name, script, nil, wsEnv)
+			}
+			r := ciResult{Repo: name, Status: "passed", DurationMs: time.Since(start).Milliseconds()}
+			if runErr != nil {
+				r.Status = "failed"
+				r.Error = runErr.Error()
+			}
+			results = append(results, r)
+		}
+
+		if err := writeCISummary(summaryOut, script, results); err != nil {
+			return err
+		}
+
+		var failed []string
+		for _, r := range results {
+			if r.Status == "failed" {
+				failed = append(failed, r.Repo)
+			}
+		}
+		if len(failed) > 0 {
+			kind := errs.KindCommandFailed
+			if script == "build" {
+				kind = errs.KindBuildFailed
+			}
+			return errs.New(kind, "%s failed in %d of %d repos: %s", script, len(failed), len(results), strings.Join(failed, ", "))
+		}
+		return nil
+	},
+}
+
+// ciResult is one repo's entry in the ci-run summary
+type ciResult struct {
+	Repo       string `json:"repo"`
+	Status     string `json:"status"` // passed, failed, skipped
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+	Message    string `json:"message,omitempty"` // why it was skipped, or what else happened
+}
+
+// affectedRepos returns the repos among names with commits since base, plus everything
+// in the workspace that depends on them. A repo whose base can't be resolved counts as
+// affected so nothing is silently skipped.
+func affectedRepos(wsPath string, ws *workspace.Workspace, names []string, base string) []string {
+	var changed []string
+	for _, name := range names {
+		repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+		files, err := git.ChangedFiles(repoDir, base)
+		if err != nil {
+			fmt.Printf("  %s: %v — treating as affected\n", name, err)
+			changed = append(changed, name)
+			continue
+		}
+		if len(files) > 0 {
+			changed = append(changed, name)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	cloned := make(map[string]bool, len(names))
+	for _, n := range names {
+		cloned[n] = true
+	}
+	var affected []string
+	for _, n := range workspace.Dependents(wsPath, ws, changed) {
+		if cloned[n] {
+			affected = append(affected, n)
+		}
+	}
+	fmt.Printf("Affected since %s: %s\n", base, strings.Join(affected, ", "))
+	return affected
+}
+
+// writeCISummary prints the per-repo table (or JSON with --json) to w and appends a
+// Markdown table to $GITHUB_STEP_SUMMARY when running in GitHub Actions
+func writeCISummary(w io.Writer, script string, results []ciResult) error {
+	if ciJSON {
+		out, err := json.MarshalIndent(struct {
+			Script  string     `json:"script"`
+			Results []ciResult `json:"results"`
+		}{script, results}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(out))
+	} else {
+		fmt.Fprintln(w)
+		for _, r := range results {
+			icon := ui.OK
+			switch r.Status {
+			case "failed":
+				icon = ui.Fail
+			case "skipped":
+				icon = ui.Skip
+			}
+			fmt.Fprintf(w, "%s %-25s %-8s %s\n", icon, r.Repo, r.Status, formatDuration(time.Duration(r.DurationMs)*time.Millisecond))
+		}
+	}
+
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write step summary: %w", err)
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, binname.Rewrite("### spark-cli ci-run %s\n\n"), script)
+	if len(results) == 0 {
+		fmt.Fprintln(f, "No affected repos.")
+		return nil
+	}
+	fmt.Fprintln(f, "| Repo | Status | Duration |")
+	fmt.Fprintln(f, "|---|---|---|")
+	for _, r := range results {
+		fmt.Fprintf(f, "| %s | %s | %s |\n", r.Repo, r.Status, formatDuration(time.Duration(r.DurationMs)*time.Millisecond))
+	}
+	fmt.Fprintln(f)
+	return nil
+}
+
+func init() {
+	ciRunCmd.Flags().BoolVar(&ciAffected, "affected", false, "Only run repos changed since --base and their dependents")
+	ciRunCmd.Flags().StringVar(&ciBase, "base", "origin/main", "Base ref for --affected")
+	ciRunCmd.Flags().StringSliceVar(&ciRepos, "repos", nil, "Limit to these repos (comma-separated)")
+	ciRunCmd.Flags().StringArrayVar(&ciEnv, "env", nil, "Extra KEY=VALUE to inject (repeatable)")
+	ciRunCmd.Flags().BoolVar(&ciJSON, "json", false, "Print the summary as JSON")
+	rootCmd.AddCommand(ciRunCmd)
+}
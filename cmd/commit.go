@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	commitMessage string
+	commitRepos   []string
+	commitTicket  string
+	commitYes     bool
+)
+
+var commitCmd = &cobra.Command{
+	Use:   "commit -m <message>",
+	Short: "Commit changes in every dirty repo with the same message",
+	Long: `Stages and commits all changes (including untracked files) in each selected
+dirty repo using one shared message. Shows a per-repo diff summary and asks for
+confirmation first.
+
+--ticket prefixes the message with an issue reference ("SPK-123: message");
+it defaults to the active ticket from 'spark-cli work start'.
+
+Examples:
+  spark-cli commit -m "Add loyalty tier field"
+  spark-cli commit -m "Add loyalty tier field" --repos AppModel,AppAPI --ticket SPK-123
+  spark-cli commit -m "Bump SDK" -y`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if strings.TrimSpace(commitMessage) == "" {
+			return fmt.Errorf("a commit message is required (-m)")
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+
+		ticket := commitTicket
+		if ticket == "" {
+			if st, err := state.Load(wsPath); err == nil && st.Work != nil {
+				ticket = st.Work.Ticket
+			}
+		}
+		message := commitMessage
+		if ticket != "" && !strings.HasPrefix(message, ticket) {
+			message = fmt.Sprintf("%s: %s", ticket, commitMessage)
+		}
+		names, err := selectRepos(wsPath, ws, commitRepos)
+		if err != nil {
+			return err
+		}
+
+		var dirty []string
+		for _, name := range names {
+			repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+			if !git.IsDirty(repoDir) {
+				continue
+			}
+			dirty = append(dirty, name)
+
+			fmt.Printf("=== %s (%s) ===\n", name, git.GetCurrentBranch(repoDir))
+			if stat := git.DiffStat(repoDir); stat != "" {
+				fmt.Println(stat)
+			}
+			if untracked := git.UntrackedFiles(repoDir); len(untracked) > 0 {
+				fmt.Printf(" %d untracked file(s): %s\n", len(untracked), strings.Join(untracked, ", "))
+			}
+			fmt.Println()
+		}
+
+		if len(dirty) == 0 {
+			fmt.Println("No dirty repos — nothing to commit")
+			return nil
+		}
+
+		fmt.Printf("Message: %s\n", message)
+		if !commitYes && !prompt.Confirm(fmt.Sprintf("Commit %d repo(s)?", len(dirty)), false) {
+			fmt.Println("Aborted")
+			return nil
+		}
+
+		var failed int
+		for _, name := range dirty {
+			repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+			if err := git.AddAll(repoDir); err != nil {
+				ui.Fail.Printf("%-25s git add failed: %v\n", name, err)
+				failed++
+				continue
+			}
+			if err := git.Commit(repoDir, message); err != nil {
+				ui.Fail.Printf("%-25s %v\n", name, err)
+				failed++
+				continue
+			}
+			ui.OK.Printf("%-25s committed\n", name)
+		}
+
+		if failed > 0 {
+			return errs.New(errs.KindCommandFailed, "%d repo(s) failed to commit", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	commitCmd.Flags().StringVarP(&commitMessage, "message", "m", "", "Commit message (required)")
+	commitCmd.Flags().StringSliceVar(&commitRepos, "repos", nil, "Only commit these repos (default: all dirty repos)")
+	commitCmd.Flags().StringVar(&commitTicket, "ticket", "", "Issue/ticket reference to prefix the message with (e.g. SPK-123)")
+	commitCmd.Flags().BoolVarP(&commitYes, "yes", "y", false, "Skip the confirmation prompt")
+	rootCmd.AddCommand(commitCmd)
+}
@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/settings"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configEffective bool
+	configGlobal    bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show and change settings (get | set | list | edit)",
+	Long: `Settings are resolved from several layers; the first one that has a value wins:
+
+  1. command-line flags (e.g. sync --env prod)
+  2. SPK_* environment variables (SPK_ENV, SPK_AWS_PROFILE, SPK_CONCURRENCY, ...)
+  3. the workspace's .spk/workspace.json
+  4. the global ~/.spk/config.json
+  5. built-in defaults
+
+Settings:
+  aws_profile   AWS profile for SSM, CDK, and whoami (an "environments" entry
+                for the resolved env overrides the workspace's aws_profile)
+  aws_region    AWS region (default us-east-1; same environments rule)
+  env           environment whose SSM parameters fill .env (default beta)
+  github_org    GitHub org repos are cloned from (default Spark-Rewards)
+  link_mode     symlink (default) to use models built in the workspace, or
+                published to always use the published packages ('spark-cli
+                link enable/disable' overrides it per consumer)
+  concurrency   repos fetched or inspected in parallel by sync, list, and
+                workspace (default 8)
+  nudge_behind  commits a repo's default branch can fall behind origin before
+                other commands suggest a sync, at most once a day (default 20;
+                off to never)
+
+~/.spk/config.json also takes "notify": "bell", "desktop", or "both" rings the
+terminal bell and/or shows a desktop notification with the outcome of builds,
+tests, and syncs that run longer than "notify_after_seconds" (default 30).
+
+Examples:
+  spark-cli config list --effective     # every value and where it came from
+  spark-cli config get env
+  spark-cli config set concurrency 4
+  spark-cli config set github_org my-fork --global
+  spark-cli config set link_mode ""     # unset, falling back to the global value
+  spark-cli config edit                 # edit workspace.json in $EDITOR, checked on save`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a setting's resolved value",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, ws, err := findWorkspace()
+		if err != nil {
+			return err
+		}
+		v, err := settings.Load(ws).Get(args[0])
+		if err != nil {
+			return err
+		}
+		if configEffective {
+			fmt.Printf("%s (%s)\n", orDefault(v.Value, "(not set)"), describeSource(v))
+			return nil
+		}
+		fmt.Println(v.Value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a setting in workspace.json (or ~/.spk/config.json with --global)",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+		wsPath, ws, err := findWorkspace()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.LoadGlobal()
+		if err != nil {
+			return err
+		}
+		if err := settings.Set(ws, cfg, key, value, configGlobal); err != nil {
+			return err
+		}
+
+		where := "~/.spk/config.json"
+		if configGlobal {
+			err = config.SaveGlobal(cfg)
+		} else {
+			where = "workspace.json"
+			err = workspace.Save(wsPath, ws)
+		}
+		if err != nil {
+			return err
+		}
+		v, _ := settings.Load(ws).Get(key)
+		if value == "" {
+			ui.OK.Printf("Unset %s in %s — now %q from %s\n", key, where, v.Value, describeSource(v))
+			return nil
+		}
+		ui.OK.Printf("%s = %s in %s\n", key, value, where)
+		// A higher layer may still win
+		if v.Value != value {
+			ui.Warn.Printf("%s resolves to %q from %s\n", key, v.Value, describeSource(v))
+		}
+		return nil
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List settings stored in the workspace and global config",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, ws, err := findWorkspace()
+		if err != nil {
+			return err
+		}
+		r := settings.Load(ws)
+
+		if configEffective {
+			fmt.Printf("%-14s %-25s %s\n", "KEY", "VALUE", "SOURCE")
+			for _, v := range r.All() {
+				fmt.Printf("%-14s %-25s %s\n", v.Key, orDefault(v.Value, "(not set)"), describeSource(v))
+			}
+			return nil
+		}
+
+		stored := false
+		for _, v := range r.All() {
+			if v.Source == settings.SourceWorkspace || v.Source == settings.SourceGlobal {
+				fmt.Printf("%-14s %-25s %s\n", v.Key, v.Value, v.Source)
+				stored = true
+			}
+		}
+		if !stored {
+			fmt.Println(binname.Rewrite("No settings stored — 'spark-cli config list --effective' shows the defaults in use"))
+		}
+		return nil
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open workspace.json (or ~/.spk/config.json with --global) in $EDITOR",
+	Long: `Opens the workspace manifest, or the global config with --global, in $VISUAL or
+$EDITOR (default vi) and checks it when the editor exits. The file must be valid
+JSON with no unknown fields, settings must have valid values, and in
+workspace.json every repo needs its own path and dependencies must name
+workspace repos without forming a cycle.
+
+Nothing is written until the edit passes; on a mistake you can reopen the
+editor to fix it or discard the changes.
+
+Examples:
+  spark-cli config edit
+  spark-cli config edit --global
+  EDITOR="code --wait" spark-cli config edit`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var path string
+		var check func(data []byte) error
+		if configGlobal {
+			var err error
+			if path, err = config.GlobalConfigPath(); err != nil {
+				return err
+			}
+			check = func(data []byte) error {
+				var cfg config.GlobalConfig
+				if err := decodeStrict(data, &cfg); err != nil {
+					return err
+				}
+				return settings.Validate(nil, &cfg)
+			}
+		} else {
+			wsPath, _, err := loadWorkspace()
+			if err != nil {
+				return err
+			}
+			path = workspace.ManifestPath(wsPath)
+			check = func(data []byte) error {
+				var ws workspace.Workspace
+				if err := decodeStrict(data, &ws); err != nil {
+					return err
+				}
+				if err := workspace.Validate(wsPath, &ws); err != nil {
+					return err
+				}
+				return settings.Validate(&ws, nil)
+			}
+		}
+
+		original, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			original = []byte("{}\n")
+		} else if err != nil {
+			return err
+		}
+		saved, err := editValidated(path, original, check)
+		if err != nil {
+			return err
+		}
+		if saved {
+			ui.OK.Printf("Saved %s\n", path)
+		} else {
+			fmt.Println("No changes")
+		}
+		return nil
+	},
+}
+
+// editValidated opens a copy of original in the user's editor until check accepts it,
+// then writes it to path. It reports false when the file was left unchanged.
+func editValidated(path string, original []byte, check func([]byte) error) (bool, error) {
+	tmp, err := os.CreateTemp("", "spk-*"+filepath.Ext(path))
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(original)
+	tmp.Close()
+	if err != nil {
+		return false, err
+	}
+
+	editor := strings.Fields(orDefault(os.Getenv("VISUAL"), orDefault(os.Getenv("EDITOR"), "vi")))
+	for {
+		c := proc.Foreground(editor[0], append(editor[1:], tmp.Name())...)
+		c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := c.Run(); err != nil {
+			return false, fmt.Errorf("editor %s failed: %w", editor[0], err)
+		}
+		edited, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return false, err
+		}
+		if bytes.Equal(edited, original) {
+			return false, nil
+		}
+		if err := check(edited); err != nil {
+			ui.Fail.Printf("%s: %v\n", filepath.Base(path), err)
+			if !prompt.NonInteractive && prompt.Confirm("Edit again?", true) {
+				continue
+			}
+			return false, errs.New(errs.KindUsage, "changes to %s discarded: %v", path, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return false, err
+		}
+		return true, os.WriteFile(path, edited, 0644)
+	}
+}
+
+// decodeStrict parses JSON into v, rejecting unknown fields (usually typos)
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
+
+// describeSource renders where a resolved value came from, e.g. "workspace: ssm_env_path"
+func describeSource(v settings.Value) string {
+	if v.Origin == "" {
+		return string(v.Source)
+	}
+	return fmt.Sprintf("%s: %s", v.Source, v.Origin)
+}
+
+// findWorkspace loads the current workspace, or returns nil outside one
+func findWorkspace() (string, *workspace.Workspace, error) {
+	wsPath, err := workspace.Find()
+	if err != nil {
+		if errs.KindOf(err) == errs.KindWorkspaceNotFound {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+	ws, err := workspace.Load(wsPath)
+	if err != nil {
+		return "", nil, err
+	}
+	return wsPath, ws, nil
+}
+
+// currentSettings resolves settings for the current workspace, if there is one
+func currentSettings() *settings.Resolver {
+	_, ws, _ := findWorkspace()
+	return settings.Load(ws)
+}
+
+// activeEnv returns the env named by flag, else the resolved env setting
+func activeEnv(ws *workspace.Workspace, flag string) string {
+	return settings.Load(ws).SetFlag(settings.Env, flag).String(settings.Env)
+}
+
+func init() {
+	configGetCmd.Flags().BoolVar(&configEffective, "effective", false, "Also print where the value came from")
+	configListCmd.Flags().BoolVar(&configEffective, "effective", false, "List every setting's resolved value and its source")
+	configSetCmd.Flags().BoolVar(&configGlobal, "global", false, "Store in ~/.spk/config.json instead of workspace.json")
+	configEditCmd.Flags().BoolVar(&configGlobal, "global", false, "Edit ~/.spk/config.json instead of workspace.json")
+	configCmd.AddCommand(configGetCmd, configSetCmd, configListCmd, configEditCmd)
+	rootCmd.AddCommand(configCmd)
+}
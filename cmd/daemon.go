@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/daemon"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/notify"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/settings"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// daemonMinInterval keeps the daemon from hammering the git remotes
+	daemonMinInterval = time.Minute
+	// daemonEnvRefreshEvery is how old the .env may get before the daemon refreshes it
+	daemonEnvRefreshEvery = 24 * time.Hour
+	// daemonStopWait is how long stop waits for the daemon to exit
+	daemonStopWait = 5 * time.Second
+)
+
+var (
+	daemonInterval time.Duration
+	daemonQuiet    bool
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run periodic fetches and env refreshes in the background (start | status | stop)",
+	Long: `The daemon keeps a workspace fresh while you work. Every interval (default
+15m) it:
+
+  - fetches every repo from origin — it never rebases or touches working trees
+  - refreshes the workspace .env from SSM once it is a day old, and one last
+    time before the AWS SSO session expires, so it stays usable until you log
+    in again (it never starts a login itself)
+  - notifies you when a repo's default branch falls nudge_behind commits
+    behind origin (see 'spark-cli outdated'), or the AWS session is expiring
+
+Notifications are desktop notifications; --quiet only logs them. There is one
+daemon per workspace; it records itself in .spk/daemon.json and logs to
+.spk/logs/daemon.log.
+
+Editor integrations can query the workspace and trigger builds over the
+daemon's JSON-RPC socket, .spk/daemon.sock — see 'spark-cli daemon call --help'.
+
+Examples:
+  spark-cli daemon start
+  spark-cli daemon start --interval 5m
+  spark-cli daemon status
+  spark-cli daemon stop`,
+}
+
+var daemonStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the workspace's background daemon",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, _, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		if daemonInterval < daemonMinInterval {
+			return errs.New(errs.KindUsage, "--interval must be at least %s", daemonMinInterval)
+		}
+		if s := daemon.Running(wsPath); s != nil {
+			return errs.New(errs.KindUsage, "the daemon is already running (pid %d) — 'spark-cli daemon stop' first", s.PID)
+		}
+
+		logPath := daemon.LogPath(wsPath)
+		if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+			return err
+		}
+		logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		defer logFile.Close()
+
+		self, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		runArgs := []string{"--workspace", wsPath, "daemon", "run", "--interval", daemonInterval.String()}
+		if daemonQuiet {
+			runArgs = append(runArgs, "--quiet")
+		}
+		// Not internal/proc: the daemon must outlive this command and its process group
+		child := exec.Command(self, runArgs...)
+		child.Dir = wsPath
+		child.Stdout, child.Stderr = logFile, logFile
+		child.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+		if err := child.Start(); err != nil {
+			return fmt.Errorf("failed to start the daemon: %w", err)
+		}
+		pid := child.Process.Pid
+		child.Process.Release()
+
+		// Wait for it to record itself, so a daemon that dies at startup is reported here
+		deadline := time.Now().Add(daemonStopWait)
+		for time.Now().Before(deadline) {
+			if s := daemon.Running(wsPath); s != nil && s.PID == pid {
+				ui.OK.Printf("Daemon started (pid %d, every %s)\n", pid, daemonInterval)
+				fmt.Printf("  Logs: %s\n", logPath)
+				return nil
+			}
+			if !daemon.Alive(pid) {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		return errs.New(errs.KindCommandFailed, "the daemon didn't start — see %s", logPath)
+	},
+}
+
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the daemon is running and what it last did",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, _, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		s := daemon.Running(wsPath)
+		if s == nil {
+			fmt.Println(binname.Rewrite("Daemon not running — 'spark-cli daemon start' to start it"))
+			return nil
+		}
+		ui.OK.Printf("Daemon running (pid %d, every %s)\n", s.PID, s.Interval)
+		fmt.Printf("  Started:  %s\n", describeTimestamp(s.StartedAt))
+		fmt.Printf("  Last run: %s\n", describeTimestamp(s.LastRun))
+		if s.LastError != "" {
+			ui.Warn.Printf("Last run had a problem: %s\n", s.LastError)
+		}
+		fmt.Printf("  Socket:   %s\n", s.Socket)
+		fmt.Printf("  Logs:     %s\n", daemon.LogPath(wsPath))
+		return nil
+	},
+}
+
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the workspace's daemon",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, _, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		s := daemon.Running(wsPath)
+		if s == nil {
+			fmt.Println("Daemon not running")
+			return nil
+		}
+		if err := syscall.Kill(s.PID, syscall.SIGTERM); err != nil {
+			return fmt.Errorf("failed to stop the daemon (pid %d): %w", s.PID, err)
+		}
+		deadline := time.Now().Add(daemonStopWait)
+		for daemon.Alive(s.PID) && time.Now().Before(deadline) {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if daemon.Alive(s.PID) {
+			return errs.New(errs.KindCommandFailed, "the daemon (pid %d) didn't exit within %s", s.PID, daemonStopWait)
+		}
+		daemon.Remove(wsPath, s.PID)
+		ui.OK.Printf("Daemon stopped (pid %d)\n", s.PID)
+		return nil
+	},
+}
+
+// daemonRunCmd is the daemon itself, started in the background by daemon start
+var daemonRunCmd = &cobra.Command{
+	Use:    "run",
+	Short:  "Run the daemon in the foreground",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, _, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		if daemonInterval < daemonMinInterval {
+			return errs.New(errs.KindUsage, "--interval must be at least %s", daemonMinInterval)
+		}
+		// Nobody is there to answer a prompt or finish a browser login
+		prompt.NonInteractive = true
+		aws.DisableLogin(true)
+
+		d := &daemonRun{wsPath: wsPath, notified: make(map[string]int)}
+		status := &daemon.Status{
+			PID:       os.Getpid(),
+			StartedAt: time.Now().UTC().Format(time.RFC3339),
+			Interval:  daemonInterval.String(),
+			Socket:    daemon.SocketPath(wsPath),
+		}
+		go func() {
+			if err := daemon.Serve(proc.Context(), status.Socket, d.rpcHandlers()); err != nil {
+				d.logf("JSON-RPC socket failed: %v", err)
+			}
+		}()
+		if err := daemon.Save(wsPath, status); err != nil {
+			return err
+		}
+		defer daemon.Remove(wsPath, status.PID)
+
+		d.logf("started (pid %d, every %s, socket %s)", status.PID, daemonInterval, status.Socket)
+		ticker := time.NewTicker(daemonInterval)
+		defer ticker.Stop()
+		for {
+			status.LastError = d.tick()
+			status.LastRun = time.Now().UTC().Format(time.RFC3339)
+			daemon.Save(wsPath, status)
+			select {
+			case <-ticker.C:
+			case <-proc.Context().Done():
+				d.logf("stopped")
+				return nil
+			}
+		}
+	},
+}
+
+// daemonRun is the daemon's memory between runs
+type daemonRun struct {
+	wsPath string
+	// notified maps a repo to how far behind it was when the daemon last said so
+	notified map[string]int
+	// expiryNoticed is the SSO expiry the daemon last warned about
+	expiryNoticed time.Time
+	// building is held while a build.run request runs
+	building sync.Mutex
+}
+
+// tick does one round of fetching, env refresh, and staleness checks, returning the
+// first problem it hit ("" if none)
+func (d *daemonRun) tick() string {
+	var problem string
+	fail := func(format string, a ...any) {
+		msg := fmt.Sprintf(format, a...)
+		d.logf("%s", msg)
+		if problem == "" {
+			problem = msg
+		}
+	}
+
+	ws, err := workspace.Load(d.wsPath)
+	if err != nil {
+		fail("can't load workspace: %v", err)
+		return problem
+	}
+	names, _ := expandRepoNames(ws, nil)
+
+	// Fetch only: rebasing would change working trees under the developer's feet
+	failed := make([]bool, len(names))
+	forEachRepo(ws, names, func(i int, name string) {
+		repoDir := filepath.Join(d.wsPath, ws.Repos[name].Path)
+		if git.IsRepo(repoDir) {
+			failed[i] = git.FetchQuiet(repoDir, "origin") != nil
+		}
+	})
+	var fetchFailed []string
+	for i, name := range names {
+		if failed[i] {
+			fetchFailed = append(fetchFailed, name)
+		}
+	}
+	if len(fetchFailed) > 0 {
+		fail("fetch failed for %s", strings.Join(fetchFailed, ", "))
+	} else {
+		d.logf("fetched %d repo(s)", len(names))
+	}
+
+	if msg := d.refreshEnv(ws); msg != "" {
+		fail("%s", msg)
+	}
+	d.checkStaleness(ws, names)
+	return problem
+}
+
+// refreshEnv rewrites the workspace .env from SSM when it's a day old, or for the last
+// time before the SSO session expires. It returns a problem, if there was one.
+func (d *daemonRun) refreshEnv(ws *workspace.Workspace) string {
+	env := activeEnv(ws, "")
+	if ws.LocalEndpoint(env) != "" {
+		return ""
+	}
+	profile, _ := settings.Load(ws).AWS(env)
+	expires, sso := aws.SSOExpiry(profile)
+	if sso && time.Until(expires) <= 0 {
+		if !expires.Equal(d.expiryNoticed) {
+			d.expiryNoticed = expires
+			d.notify(fmt.Sprintf("AWS session for %s expired — run 'aws sso login --profile %s'", env, orDefault(profile, "default")))
+		}
+		return ""
+	}
+
+	var written time.Time
+	if st, err := state.Load(d.wsPath); err == nil && st.EnvRefresh != nil && st.EnvRefresh.Env == env {
+		written, _ = time.Parse(time.RFC3339, st.EnvRefresh.WrittenAt)
+	}
+	expiring := sso && time.Until(expires) < 2*daemonInterval
+	if !expiring && time.Since(written) < daemonEnvRefreshEvery {
+		return ""
+	}
+	if expiring && written.After(expires.Add(-2*daemonInterval)) {
+		// Already refreshed for this session's last stretch
+		return ""
+	}
+
+	envVars, err := fetchEnvVars(ws, env, false)
+	if err != nil {
+		return fmt.Sprintf("env refresh from %s failed: %v", env, err)
+	}
+	if err := writeWorkspaceEnv(d.wsPath, ws, env, envVars); err != nil {
+		return fmt.Sprintf("writing .env failed: %v", err)
+	}
+	d.logf("refreshed .env from %s (%d variables)", env, len(envVars))
+	if expiring && !expires.Equal(d.expiryNoticed) {
+		d.expiryNoticed = expires
+		d.notify(fmt.Sprintf("AWS session for %s expires at %s — .env refreshed; run 'aws sso login' to keep it current",
+			env, expires.Local().Format("15:04")))
+	}
+	return ""
+}
+
+// checkStaleness notifies about repos nudge_behind or more commits behind origin, once
+// per repo until it catches up or falls another nudge_behind commits behind
+func (d *daemonRun) checkStaleness(ws *workspace.Workspace, names []string) {
+	threshold := settings.Load(ws).Int(settings.NudgeBehind)
+	if threshold <= 0 {
+		return
+	}
+	for _, r := range checkOutdated(d.wsPath, ws, names) {
+		if !r.known || r.behind < threshold {
+			delete(d.notified, r.name)
+			continue
+		}
+		if last, ok := d.notified[r.name]; ok && r.behind < last+threshold {
+			continue
+		}
+		d.notified[r.name] = r.behind
+		d.notify(fmt.Sprintf(binname.Rewrite("%s is %d commits behind origin/%s — run 'spark-cli workspace sync'"), r.name, r.behind, r.target))
+	}
+}
+
+// notify logs msg and, unless --quiet, shows it as a desktop notification
+func (d *daemonRun) notify(msg string) {
+	d.logf("notify: %s", msg)
+	if !daemonQuiet {
+		notify.Send(notify.MethodDesktop, "spark-cli", msg)
+	}
+}
+
+// logf writes a timestamped line to the daemon log (the daemon's stdout)
+func (d *daemonRun) logf(format string, a ...any) {
+	fmt.Printf("%s %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, a...))
+}
+
+// describeTimestamp renders an RFC3339 time as an age, or "never"
+func describeTimestamp(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return "never"
+	}
+	return formatAge(time.Since(t))
+}
+
+func init() {
+	for _, c := range []*cobra.Command{daemonStartCmd, daemonRunCmd} {
+		c.Flags().DurationVar(&daemonInterval, "interval", 15*time.Minute, "How often to fetch and check (at least 1m)")
+		c.Flags().BoolVar(&daemonQuiet, "quiet", false, "Only log notifications instead of showing them on the desktop")
+	}
+	daemonCmd.AddCommand(daemonStartCmd, daemonStatusCmd, daemonStopCmd, daemonRunCmd)
+	rootCmd.AddCommand(daemonCmd)
+}
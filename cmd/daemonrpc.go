@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/daemon"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/spkconfig"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var daemonCallCmd = &cobra.Command{
+	Use:   "call <method> [params-json]",
+	Short: "Call one of the daemon's JSON-RPC methods and print the result",
+	Long: `The daemon answers JSON-RPC 2.0 on the unix socket .spk/daemon.sock, one
+request per line, so editor integrations can query the workspace without
+starting spark-cli for every question. call sends one request, for trying
+methods out and for scripts.
+
+Methods:
+  workspace.info      name, path, active env, repos, and the daemon's status
+  repos.list          each repo's branch, dirty state, and commits behind
+                      origin (params: {"repos": [...]} to limit)
+  links.status        each consumed model: package, and whether the consumer
+                      links the workspace build or uses the published package
+  graph.buildOrder    build order and each repo's dependencies
+  build.run           build repos like 'spark-cli build' and return when done
+                      (params: {"repos": [...], "deps": true} or {"all": true});
+                      output goes to .spk/logs/rpc-build.log
+
+Example request:
+  {"jsonrpc": "2.0", "id": 1, "method": "repos.list", "params": {"repos": ["AppAPI"]}}
+
+Examples:
+  spark-cli daemon call workspace.info
+  spark-cli daemon call build.run '{"repos": ["AppAPI"], "deps": true}'`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, _, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		if daemon.Running(wsPath) == nil {
+			return errs.New(errs.KindUsage, "the daemon isn't running — 'spark-cli daemon start' first")
+		}
+		var params any
+		if len(args) == 2 {
+			var raw json.RawMessage
+			if err := json.Unmarshal([]byte(args[1]), &raw); err != nil {
+				return errs.New(errs.KindUsage, "params must be JSON: %v", err)
+			}
+			params = raw
+		}
+		result, err := daemon.Call(daemon.SocketPath(wsPath), args[0], params)
+		if err != nil {
+			return errs.New(errs.KindCommandFailed, "%s: %w", args[0], err)
+		}
+		var out bytes.Buffer
+		if json.Indent(&out, result, "", "  ") != nil {
+			out.Write(result)
+		}
+		fmt.Println(out.String())
+		return nil
+	},
+}
+
+// rpcHandlers are the JSON-RPC methods the daemon serves
+func (d *daemonRun) rpcHandlers() map[string]daemon.Handler {
+	return map[string]daemon.Handler{
+		"workspace.info":   d.rpcWorkspaceInfo,
+		"repos.list":       d.rpcReposList,
+		"links.status":     d.rpcLinksStatus,
+		"graph.buildOrder": d.rpcBuildOrder,
+		"build.run":        d.rpcBuildRun,
+	}
+}
+
+// rpcParams decodes a method's params into v; absent params leave v as is
+func rpcParams(params json.RawMessage, v any) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return daemon.InvalidParams("invalid params: %v", err)
+	}
+	return nil
+}
+
+func (d *daemonRun) rpcWorkspaceInfo(params json.RawMessage) (any, error) {
+	ws, err := workspace.Load(d.wsPath)
+	if err != nil {
+		return nil, err
+	}
+	names, _ := expandRepoNames(ws, nil)
+	status, _ := daemon.Load(d.wsPath)
+	return map[string]any{
+		"name":   ws.Name,
+		"path":   d.wsPath,
+		"env":    activeEnv(ws, ""),
+		"repos":  names,
+		"daemon": status,
+	}, nil
+}
+
+// rpcRepo is one repos.list entry
+type rpcRepo struct {
+	Name          string `json:"name"`
+	Path          string `json:"path"`
+	Cloned        bool   `json:"cloned"`
+	Branch        string `json:"branch,omitempty"`
+	Dirty         bool   `json:"dirty"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+	// Behind is how far the local default branch trails origin, as of the last fetch
+	Behind int `json:"behind"`
+}
+
+func (d *daemonRun) rpcReposList(params json.RawMessage) (any, error) {
+	var p struct {
+		Repos []string `json:"repos"`
+	}
+	if err := rpcParams(params, &p); err != nil {
+		return nil, err
+	}
+	ws, err := workspace.Load(d.wsPath)
+	if err != nil {
+		return nil, err
+	}
+	names, err := expandRepoNames(ws, p.Repos)
+	if err != nil {
+		return nil, daemon.InvalidParams("%v", err)
+	}
+
+	repos := make([]rpcRepo, len(names))
+	outdated := checkOutdated(d.wsPath, ws, names)
+	forEachRepo(ws, names, func(i int, name string) {
+		r := rpcRepo{Name: name, Path: ws.Repos[name].Path}
+		repoDir := filepath.Join(d.wsPath, r.Path)
+		if _, err := os.Stat(repoDir); err == nil {
+			r.Cloned = true
+		}
+		if snap, err := git.TakeSnapshot(repoDir); r.Cloned && err == nil {
+			r.Branch, r.Dirty = snap.Branch, snap.Dirty
+		}
+		if o := outdated[i]; o.known {
+			r.DefaultBranch, r.Behind = o.target, o.behind
+		}
+		repos[i] = r
+	})
+	return repos, nil
+}
+
+// rpcLink is one links.status entry
+type rpcLink struct {
+	Consumer string `json:"consumer"`
+	Model    string `json:"model"`
+	Package  string `json:"package"`
+	Codegen  string `json:"codegen,omitempty"`
+	// Linked is true when node_modules points at the model's workspace build
+	Linked bool `json:"linked"`
+}
+
+func (d *daemonRun) rpcLinksStatus(params json.RawMessage) (any, error) {
+	ws, err := workspace.Load(d.wsPath)
+	if err != nil {
+		return nil, err
+	}
+	names, _ := expandRepoNames(ws, nil)
+	links := []rpcLink{}
+	for _, name := range names {
+		repoDir := filepath.Join(d.wsPath, ws.Repos[name].Path)
+		cfg, err := spkconfig.Load(repoDir)
+		if err != nil || cfg == nil {
+			continue
+		}
+		for _, c := range cfg.Consumes {
+			links = append(links, rpcLink{
+				Consumer: name,
+				Model:    c.Model,
+				Package:  c.Package,
+				Codegen:  c.Codegen,
+				Linked:   consumerLinked(d.wsPath, ws, name, c),
+			})
+		}
+	}
+	return links, nil
+}
+
+func (d *daemonRun) rpcBuildOrder(params json.RawMessage) (any, error) {
+	ws, err := workspace.Load(d.wsPath)
+	if err != nil {
+		return nil, err
+	}
+	order, err := workspace.BuildOrder(d.wsPath, ws, nil)
+	if err != nil {
+		return nil, err
+	}
+	deps := make(map[string][]string, len(order))
+	for _, name := range order {
+		deps[name] = append([]string{}, workspace.Dependencies(d.wsPath, ws, name)...)
+	}
+	return map[string]any{"order": order, "dependencies": deps}, nil
+}
+
+// rpcBuildLog is where build.run writes the build's output, inside .spk/logs
+const rpcBuildLog = "rpc-build.log"
+
+func (d *daemonRun) rpcBuildRun(params json.RawMessage) (any, error) {
+	var p struct {
+		Repos []string `json:"repos"`
+		Deps  bool     `json:"deps"`
+		All   bool     `json:"all"`
+	}
+	if err := rpcParams(params, &p); err != nil {
+		return nil, err
+	}
+	if len(p.Repos) == 0 && !p.All {
+		return nil, daemon.InvalidParams(`name "repos" to build, or pass "all": true`)
+	}
+	ws, err := workspace.Load(d.wsPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range p.Repos {
+		if _, ok := ws.Repos[name]; !ok {
+			return nil, daemon.InvalidParams("unknown repo %q", name)
+		}
+	}
+	if !d.building.TryLock() {
+		return nil, errors.New("a build is already running")
+	}
+	defer d.building.Unlock()
+
+	// A child spark-cli, so the build gets its own flags and can't take the daemon down
+	self, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"--workspace", d.wsPath, "--no-interactive", "--no-color", "build"}
+	if p.Deps {
+		args = append(args, "--deps")
+	}
+	if p.All {
+		args = append(args, "--all")
+	} else {
+		args = append(append(args, "--"), p.Repos...)
+	}
+
+	logPath := filepath.Join(filepath.Dir(daemon.LogPath(d.wsPath)), rpcBuildLog)
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return nil, err
+	}
+	defer logFile.Close()
+
+	d.logf("build.run: spark-cli %s", strings.Join(args[4:], " "))
+	start := time.Now()
+	c := proc.Command(self, args...)
+	c.Dir = d.wsPath
+	c.Stdout, c.Stderr = logFile, logFile
+	err = c.Run()
+	exitCode := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return nil, err
+	}
+	d.logf("build.run: exit %d after %s", exitCode, time.Since(start).Round(time.Millisecond))
+	return map[string]any{
+		"ok":          exitCode == 0,
+		"exit_code":   exitCode,
+		"duration_ms": time.Since(start).Milliseconds(),
+		"log":         logPath,
+	}, nil
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonCallCmd)
+}
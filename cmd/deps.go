@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/spkconfig"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	depsPackage string
+	depsCodegen string
+)
+
+var depsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Show or edit the dependency graph (add | remove)",
+	Long: `With no subcommand, prints what each repo depends on and the resulting build
+order. A repo's dependencies are its "dependencies" in workspace.json plus the
+models its spk.config.json consumes.
+
+Examples:
+  spark-cli deps
+  spark-cli deps add AppAPI AppModel
+  spark-cli deps remove AppAPI AppModel`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		order, err := workspace.BuildOrder(wsPath, ws, nil)
+		if err != nil {
+			return err
+		}
+		for _, name := range order {
+			deps := workspace.Dependencies(wsPath, ws, name)
+			if len(deps) == 0 {
+				fmt.Printf("  %s\n", name)
+				continue
+			}
+			fmt.Printf("  %-25s ← %s\n", name, strings.Join(deps, ", "))
+		}
+		fmt.Println()
+		printBuildOrder(order)
+		return nil
+	},
+}
+
+var depsAddCmd = &cobra.Command{
+	Use:   "add <repo> <dependency>",
+	Short: "Make a repo depend on another, refusing cycles",
+	Long: `Adds dependency to the repo's "dependencies" in workspace.json. When the
+dependency is a Smithy model, the repo's spk.config.json also gets a consumes
+entry, so both files describe the same graph; the package name is read from the
+model's build output or given with --package.
+
+Nothing is written if the new edge would create a cycle.
+
+Examples:
+  spark-cli deps add AppAPI AppModel
+  spark-cli deps add MobileApp AppModel --package @spark-rewards/app-client --codegen typescript-client-codegen`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, dep := args[0], args[1]
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		repo, depDef, err := depsEdge(ws, name, dep)
+		if err != nil {
+			return err
+		}
+		for _, d := range workspace.Dependencies(wsPath, ws, name) {
+			if d == dep {
+				return errs.New(errs.KindUsage, "%s already depends on %s", name, dep)
+			}
+		}
+
+		repo.Dependencies = append(repo.Dependencies, dep)
+		ws.Repos[name] = repo
+		if err := workspace.Validate(wsPath, ws); err != nil {
+			return errs.New(errs.KindUsage, "not added: %v", err)
+		}
+
+		repoDir := filepath.Join(wsPath, repo.Path)
+		depDir := filepath.Join(wsPath, depDef.Path)
+		pkg := depsPackage
+		if pkg == "" && isModelRepo(depDir, depDef) {
+			pkg = modelPackageName(depDir, depsCodegen)
+		}
+		if pkg != "" {
+			if _, err := os.Stat(repoDir); err != nil {
+				return fmt.Errorf("%s isn't cloned, so its %s can't be updated: %w", name, spkconfig.ConfigFilename, err)
+			}
+			entry := spkconfig.ConsumesEntry{Model: dep, Package: pkg, Codegen: depsCodegen}
+			if err := spkconfig.AddConsumes(repoDir, entry); err != nil {
+				return fmt.Errorf("failed to update %s: %w", spkconfig.ConfigFilename, err)
+			}
+		}
+		if err := workspace.Save(wsPath, ws); err != nil {
+			return err
+		}
+
+		ui.OK.Printf("%s now depends on %s\n", name, dep)
+		if pkg != "" {
+			ui.OK.Printf("%s/%s consumes %s (%s)\n", repo.Path, spkconfig.ConfigFilename, pkg, depsCodegen)
+		} else if isModelRepo(depDir, depDef) {
+			ui.Warn.Printf("%s is a model but has no %s build — pass --package to record it in %s\n", dep, depsCodegen, spkconfig.ConfigFilename)
+		}
+		return printWorkspaceBuildOrder(wsPath, ws)
+	},
+}
+
+var depsRemoveCmd = &cobra.Command{
+	Use:   "remove <repo> <dependency>",
+	Short: "Remove a dependency from workspace.json and spk.config.json",
+	Long: `Removes dependency from the repo's "dependencies" in workspace.json and any
+consumes entries for it in the repo's spk.config.json, so the edge is gone from
+the graph.
+
+Example:
+  spark-cli deps remove AppAPI AppModel`,
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, dep := args[0], args[1]
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		repo, _, err := depsEdge(ws, name, dep)
+		if err != nil {
+			return err
+		}
+
+		declared := false
+		var kept []string
+		for _, d := range repo.Dependencies {
+			if d == dep {
+				declared = true
+				continue
+			}
+			kept = append(kept, d)
+		}
+		repoDir := filepath.Join(wsPath, repo.Path)
+		consumed, err := spkconfig.RemoveConsumes(repoDir, dep)
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", spkconfig.ConfigFilename, err)
+		}
+		if !declared && !consumed {
+			return errs.New(errs.KindUsage, "%s doesn't depend on %s", name, dep)
+		}
+		if declared {
+			repo.Dependencies = kept
+			ws.Repos[name] = repo
+			if err := workspace.Save(wsPath, ws); err != nil {
+				return err
+			}
+		}
+
+		ui.OK.Printf("%s no longer depends on %s\n", name, dep)
+		if consumed {
+			ui.OK.Printf("Removed %s from %s/%s\n", dep, repo.Path, spkconfig.ConfigFilename)
+		}
+		return printWorkspaceBuildOrder(wsPath, ws)
+	},
+}
+
+// depsEdge looks up both ends of a dependency edge
+func depsEdge(ws *workspace.Workspace, name, dep string) (workspace.RepoDef, workspace.RepoDef, error) {
+	repo, ok := ws.Repos[name]
+	if !ok {
+		return repo, repo, errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
+	}
+	depDef, ok := ws.Repos[dep]
+	if !ok {
+		return repo, depDef, errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", dep)
+	}
+	if name == dep {
+		return repo, depDef, errs.New(errs.KindUsage, "a repo can't depend on itself")
+	}
+	return repo, depDef, nil
+}
+
+// isModelRepo reports whether the repo is a Smithy model
+func isModelRepo(dir string, repo workspace.RepoDef) bool {
+	return repo.ModelFor != "" || fileExistsCheck(filepath.Join(dir, "smithy"))
+}
+
+// modelPackageName returns the npm package name of a model's codegen output, or "" if
+// it hasn't been built
+func modelPackageName(modelDir, codegen string) string {
+	data, err := os.ReadFile(filepath.Join(npm.BuildOutputDirForCodegen(modelDir, codegen), "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+	return pkg.Name
+}
+
+// printWorkspaceBuildOrder prints the whole workspace's build order
+func printWorkspaceBuildOrder(wsPath string, ws *workspace.Workspace) error {
+	order, err := workspace.BuildOrder(wsPath, ws, nil)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	printBuildOrder(order)
+	return nil
+}
+
+func printBuildOrder(order []string) {
+	fmt.Printf("Build order: %s\n", strings.Join(order, " → "))
+}
+
+func init() {
+	depsAddCmd.Flags().StringVar(&depsPackage, "package", "", "npm package the repo consumes from the model (default: read from the model's build)")
+	depsAddCmd.Flags().StringVar(&depsCodegen, "codegen", filepath.Base(npm.SmithyBuildPath), "Model codegen the repo consumes")
+	depsCmd.AddCommand(depsAddCmd, depsRemoveCmd)
+	rootCmd.AddCommand(depsCmd)
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/devcontainer"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellquote"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// postCreateScript runs once when the container is created
+const postCreateScript = "post-create.sh"
+
+var (
+	devcontainerImage string
+	devcontainerForce bool
+)
+
+var devcontainerCmd = &cobra.Command{
+	Use:   "devcontainer",
+	Short: "Dev container setup for the whole workspace (generate)",
+}
+
+var devcontainerGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Write .devcontainer/ for opening the workspace in Codespaces or a container",
+	Long: `Writes .devcontainer/devcontainer.json and a post-create script at the
+workspace root so the workspace opens in Codespaces or a local dev container
+the same way it works locally:
+
+  - tool versions: Node from .nvmrc/.node-version/.tool-versions, Java from
+    .java-version/.sdkmanrc (Gradle repos), Go from go.mod — the newest pinned
+    version across repos wins
+  - AWS CLI, GitHub CLI, and Docker-in-Docker when a repo has a Dockerfile
+  - env: workspace.json env as remoteEnv; workspace .env keys as recommended
+    Codespaces secrets (values are never written)
+  - ports from each repo's "docker.ports" are forwarded
+  - the post-create script clones missing repos over https and installs
+    dependencies in build order
+
+Re-run after adding repos. A hand-written devcontainer.json is left alone unless
+--force is given.
+
+Examples:
+  spark-cli devcontainer generate
+  spark-cli devcontainer generate --image mcr.microsoft.com/devcontainers/typescript-node:20`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		dir := filepath.Join(wsPath, devcontainer.Dir)
+		configPath := filepath.Join(dir, "devcontainer.json")
+		if fileExistsCheck(configPath) && !devcontainer.IsGenerated(configPath) && !devcontainerForce {
+			return errs.New(errs.KindUsage, "%s wasn't generated by spark-cli — use --force to replace it", configPath)
+		}
+
+		order, err := workspace.BuildOrder(wsPath, ws, nil)
+		if err != nil {
+			return err
+		}
+
+		var tools devcontainer.Tools
+		var ports []int
+		for _, name := range order {
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+			switch detectProjectType(repoDir) {
+			case projectTypeNode:
+				tools.Node = devcontainer.Newer(tools.Node, orDefault(devcontainer.NodeVersion(repoDir), devcontainer.DefaultNode))
+			case projectTypeGradle:
+				tools.Java = devcontainer.Newer(tools.Java, orDefault(devcontainer.JavaVersion(repoDir), devcontainer.DefaultJava))
+			case projectTypeGo:
+				tools.Go = devcontainer.Newer(tools.Go, orDefault(devcontainer.GoVersion(repoDir), devcontainer.DefaultGo))
+			}
+			if dockerfile := filepath.Join(repoDir, "Dockerfile"); fileExistsCheck(dockerfile) || repo.Docker != nil {
+				tools.Docker = true
+			}
+			if repo.Docker != nil {
+				for _, p := range repo.Docker.Ports {
+					host, _, _ := strings.Cut(p, ":")
+					if n, err := strconv.Atoi(host); err == nil {
+						ports = append(ports, n)
+					}
+				}
+			}
+		}
+		sort.Ints(ports)
+
+		config := devcontainer.NewConfig(ws.Name, devcontainerImage, tools)
+		config.WorkspaceFolder = "/workspaces/" + filepath.Base(wsPath)
+		config.RemoteEnv = ws.Env
+		config.ForwardPorts = ports
+		config.PostCreateCommand = "sh " + devcontainer.Dir + "/" + postCreateScript
+		config.Customizations = map[string]any{
+			"vscode": map[string]any{"extensions": devcontainerExtensions(tools)},
+		}
+		if dotEnv, err := workspace.ReadGlobalEnv(wsPath); err == nil && len(dotEnv) > 0 {
+			config.Secrets = make(map[string]devcontainer.Secret)
+			for k := range dotEnv {
+				if _, inline := ws.Env[k]; !inline {
+					config.Secrets[k] = devcontainer.Secret{Description: "From the workspace .env (spark-cli workspace sync --env)"}
+				}
+			}
+		}
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := config.Write(configPath); err != nil {
+			return err
+		}
+		scriptPath := filepath.Join(dir, postCreateScript)
+		if err := os.WriteFile(scriptPath, []byte(devcontainerPostCreate(wsPath, ws, order)), 0755); err != nil {
+			return err
+		}
+
+		ui.OK.Printf("Wrote %s\n", configPath)
+		ui.OK.Printf("Wrote %s\n", scriptPath)
+		var summary []string
+		for _, t := range []struct{ name, version string }{{"node", tools.Node}, {"java", tools.Java}, {"go", tools.Go}} {
+			if t.version != "" {
+				summary = append(summary, t.name+" "+t.version)
+			}
+		}
+		if tools.Docker {
+			summary = append(summary, "docker")
+		}
+		fmt.Printf("  Tools: %s\n", strings.Join(summary, ", "))
+		if len(config.Secrets) > 0 {
+			fmt.Printf("  Add %d Codespaces secret(s) for the .env keys (see \"secrets\" in devcontainer.json)\n", len(config.Secrets))
+		}
+		return nil
+	},
+}
+
+// devcontainerPostCreate renders the script that clones missing repos and installs
+// dependencies in build order
+func devcontainerPostCreate(wsPath string, ws *workspace.Workspace, order []string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n# Generated by spark-cli devcontainer generate — regenerate instead of editing\n")
+	b.WriteString("cd \"$(dirname \"$0\")/..\" || exit 1\n\n")
+	b.WriteString("clone() { [ -d \"$2/.git\" ] || git clone \"$1\" \"$2\"; }\n")
+	for _, name := range order {
+		repo := ws.Repos[name]
+		remote := repo.Remote
+		if slug := git.GitHubSlug(remote); slug != "" {
+			remote = "https://github.com/" + slug + ".git" // the container has gh auth, not ssh keys
+		}
+		fmt.Fprintf(&b, "clone %s %s\n", shellquote.Quote(remote), shellquote.Quote(repo.Path))
+	}
+
+	b.WriteString("\n")
+	for _, name := range order {
+		repo := ws.Repos[name]
+		repoDir := filepath.Join(wsPath, repo.Path)
+		var install string
+		switch detectProjectType(repoDir) {
+		case projectTypeNode:
+			install = "{ npm ci || npm install; }"
+		case projectTypeGradle:
+			install = "./gradlew --no-daemon dependencies > /dev/null"
+		case projectTypeGo:
+			install = "go mod download"
+		default:
+			continue
+		}
+		fmt.Fprintf(&b, "(cd %s && %s) || echo \"✗ %s: install failed\"\n", shellquote.Quote(repo.Path), install, name)
+	}
+	b.WriteString("\n[ -f .env ] || echo \"No workspace .env — add Codespaces secrets or run 'spark-cli workspace sync --env beta'\"\n")
+	return b.String()
+}
+
+func devcontainerExtensions(tools devcontainer.Tools) []string {
+	var ext []string
+	if tools.Node != "" {
+		ext = append(ext, "dbaeumer.vscode-eslint", "esbenp.prettier-vscode")
+	}
+	if tools.Java != "" {
+		ext = append(ext, "vscjava.vscode-java-pack", "vscjava.vscode-gradle")
+	}
+	if tools.Go != "" {
+		ext = append(ext, "golang.go")
+	}
+	if tools.Docker {
+		ext = append(ext, "ms-azuretools.vscode-docker")
+	}
+	return ext
+}
+
+func init() {
+	devcontainerGenerateCmd.Flags().StringVar(&devcontainerImage, "image", "mcr.microsoft.com/devcontainers/base:ubuntu", "Base image")
+	devcontainerGenerateCmd.Flags().BoolVar(&devcontainerForce, "force", false, "Replace a devcontainer.json spark-cli didn't generate")
+	devcontainerCmd.AddCommand(devcontainerGenerateCmd)
+	rootCmd.AddCommand(devcontainerCmd)
+}
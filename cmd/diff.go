@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffStat  bool
+	diffRepos []string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show every repo's changes against its default branch",
+	Long: `Shows one combined diff of the whole cross-repo change set: for each repo,
+everything on the current branch plus uncommitted changes, compared with the
+point where it forked from origin/<default branch>. Untracked files are listed
+after each repo's diff.
+
+Examples:
+  spark-cli diff
+  spark-cli diff --stat
+  spark-cli diff --repos AppModel,AppAPI | less -R`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, diffRepos)
+		if err != nil {
+			return err
+		}
+
+		var changed int
+		for _, name := range names {
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+			base := getTargetBranch(ws, &repo, repoDir)
+
+			forkPoint := git.MergeBase(repoDir, "HEAD", "origin/"+base)
+			if forkPoint == "" {
+				ui.Skip.Printf("%s: no common history with origin/%s\n", name, base)
+				continue
+			}
+			untracked := git.UntrackedFiles(repoDir)
+			if !git.HasDiff(repoDir, forkPoint) && len(untracked) == 0 {
+				continue
+			}
+			changed++
+
+			fmt.Printf("=== %s (%s vs origin/%s) ===\n", name, git.GetCurrentBranch(repoDir), base)
+			if err := git.DiffTo(repoDir, forkPoint, diffStat, os.Stdout); err != nil {
+				ui.Fail.Printf("git diff failed: %v\n", err)
+			}
+			if len(untracked) > 0 {
+				fmt.Printf(" %d untracked file(s):\n", len(untracked))
+				for _, f := range untracked {
+					fmt.Printf("   %s\n", f)
+				}
+			}
+			fmt.Println()
+		}
+
+		if changed == 0 {
+			fmt.Println("No changes against default branches")
+		} else {
+			fmt.Printf("%d repo(s) with changes\n", changed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffStat, "stat", false, "Show a per-file summary instead of the full diff")
+	diffCmd.Flags().StringSliceVar(&diffRepos, "repos", nil, "Limit to these repos (comma-separated)")
+	rootCmd.AddCommand(diffCmd)
+}
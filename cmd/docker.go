@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/docker"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellquote"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// composeNameRe matches characters compose doesn't allow in project names
+var composeNameRe = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+var dockerBuildFirst bool
+
+var dockerCmd = &cobra.Command{
+	Use:   "docker",
+	Short: "Build and run repo images with the workspace env (build | run | compose)",
+	Long: `Builds and runs each repo's Dockerfile with the workspace env, and brings up
+multi-repo stacks defined in workspace.json.
+
+Images are tagged <image>:<git sha> and <image>:<env> (the active workspace env).
+Per-repo settings live under "docker" in workspace.json:
+
+  "docker": {
+    "image":   "app-api",
+    "ports":   ["3000:3000"],
+    "secrets": ["GITHUB_TOKEN"]
+  }
+
+Examples:
+  spark-cli docker build AppAPI
+  spark-cli docker run AppAPI
+  spark-cli docker compose api up -d`,
+}
+
+var dockerBuildCmd = &cobra.Command{
+	Use:   "build [repo]",
+	Short: "Build a repo's image, tagged with its git SHA and the workspace env",
+	Long: `Builds a repo's Dockerfile (the current repo if none is given).
+
+Workspace env vars are passed as --build-arg for every ARG the Dockerfile
+declares, plus GIT_SHA and APP_ENV. Keys listed in "docker.secrets" are passed
+as BuildKit secrets instead (RUN --mount=type=secret,id=KEY), so they never end
+up in an image layer.
+
+Examples:
+  spark-cli docker build
+  spark-cli docker build AppAPI`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := docker.CheckCLI(); err != nil {
+			return err
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		name, err := dockerRepo(wsPath, ws, args)
+		if err != nil {
+			return err
+		}
+		_, err = dockerBuild(wsPath, ws, name, buildWorkspaceEnv(wsPath, ws))
+		return err
+	},
+}
+
+var dockerRunCmd = &cobra.Command{
+	Use:   "run [repo] [-- command...]",
+	Short: "Run a repo's image with the workspace env and its ports published",
+	Long: `Runs a repo's image (the current repo if none is given) with every workspace
+env var and the ports from "docker.ports". The image for the current commit is
+built first if it doesn't exist yet; --build always rebuilds.
+
+Examples:
+  spark-cli docker run AppAPI
+  spark-cli docker run AppAPI --build
+  spark-cli docker run AppAPI -- npm run migrate`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := docker.CheckCLI(); err != nil {
+			return err
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+
+		repoArgs, command := args, []string(nil)
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			repoArgs, command = args[:dash], args[dash:]
+		}
+		if len(repoArgs) > 1 {
+			return errs.New(errs.KindUsage, "expected at most one repo, got %s", strings.Join(repoArgs, ", "))
+		}
+		name, err := dockerRepo(wsPath, ws, repoArgs)
+		if err != nil {
+			return err
+		}
+		repo := ws.Repos[name]
+		wsEnv := repo.ScopeEnv(buildWorkspaceEnv(wsPath, ws))
+
+		ref, err := dockerImageRef(wsPath, ws, name)
+		if err != nil {
+			return err
+		}
+		if dockerBuildFirst || proc.Command("docker", "image", "inspect", ref).Run() != nil {
+			if ref, err = dockerBuild(wsPath, ws, name, wsEnv); err != nil {
+				return err
+			}
+		}
+
+		parts := []string{"docker", "run", "--rm", "-it"}
+		if def := ws.Repos[name].Docker; def != nil {
+			for _, p := range def.Ports {
+				parts = append(parts, "-p", p)
+			}
+		}
+		for _, k := range sortedKeys(wsEnv) {
+			parts = append(parts, "-e", k) // value comes from the environment, not argv
+		}
+		parts = append(parts, ref)
+		for _, c := range command {
+			parts = append(parts, shellquote.Quote(c))
+		}
+
+		fmt.Printf("=== %s: docker run %s ===\n", name, ref)
+		return errs.Wrap(errs.KindCommandFailed, runShellCmdWithEnv(wsPath, strings.Join(parts, " "), &repo, wsEnv))
+	},
+}
+
+var dockerComposeCmd = &cobra.Command{
+	Use:   "compose <stack> [compose args...]",
+	Short: "Bring up a multi-repo stack defined in workspace.json",
+	Long: `Generates a compose file for a stack — a named group of repos under "stacks"
+in workspace.json — and runs docker compose on it (default: up --build).
+
+  "stacks": {
+    "api": ["AppAPI", "AppModel"]
+  }
+
+Each repo becomes a service built from its Dockerfile, with its "docker.ports",
+every workspace env var, and depends_on from the workspace dependency graph.
+The compose file is written to .spk/compose/<stack>.json; env values are passed
+through the environment and never written to it.
+
+Examples:
+  spark-cli docker compose api
+  spark-cli docker compose api up -d
+  spark-cli docker compose api logs -f
+  spark-cli docker compose api down`,
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+			return cmd.Help()
+		}
+		if err := docker.CheckCLI(); err != nil {
+			return err
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+
+		stack := args[0]
+		repos, ok := ws.Stacks[stack]
+		if !ok {
+			var names []string
+			for s := range ws.Stacks {
+				names = append(names, s)
+			}
+			sort.Strings(names)
+			if len(names) == 0 {
+				return errs.New(errs.KindUsage, "no stacks defined — add a \"stacks\" map to workspace.json (see 'spark-cli docker compose --help')")
+			}
+			return errs.New(errs.KindUsage, "unknown stack %q — defined: %s", stack, strings.Join(names, ", "))
+		}
+		wsEnv := buildWorkspaceEnv(wsPath, ws)
+
+		file, err := writeStackCompose(wsPath, ws, stack, repos, wsEnv)
+		if err != nil {
+			return err
+		}
+
+		composeArgs := args[1:]
+		if len(composeArgs) == 0 {
+			composeArgs = []string{"up", "--build"}
+		}
+		quoted := make([]string, len(composeArgs))
+		for i, a := range composeArgs {
+			quoted[i] = shellquote.Quote(a)
+		}
+		command := fmt.Sprintf("docker compose -f %s %s", shellquote.Quote(file), strings.Join(quoted, " "))
+		fmt.Printf("=== %s: docker compose %s ===\n", stack, strings.Join(composeArgs, " "))
+		return errs.Wrap(errs.KindCommandFailed, runShellCmdWithEnv(wsPath, command, nil, wsEnv))
+	},
+}
+
+// dockerRepo resolves the repo argument, defaulting to the current repo
+func dockerRepo(wsPath string, ws *workspace.Workspace, args []string) (string, error) {
+	if len(args) == 1 {
+		if _, ok := ws.Repos[args[0]]; !ok {
+			return "", errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", args[0])
+		}
+		return args[0], nil
+	}
+	name, _ := detectCurrentRepo(wsPath, ws)
+	if name == "" {
+		return "", errs.New(errs.KindUsage, "not inside a workspace repo — name the repo")
+	}
+	return name, nil
+}
+
+// dockerImage returns the image name and Dockerfile path for a repo
+func dockerImage(wsPath string, ws *workspace.Workspace, name string) (image, dockerfile string) {
+	repo := ws.Repos[name]
+	image, dockerfile = docker.ImageName(name), "Dockerfile"
+	if repo.Docker != nil {
+		if repo.Docker.Image != "" {
+			image = repo.Docker.Image
+		}
+		if repo.Docker.Dockerfile != "" {
+			dockerfile = repo.Docker.Dockerfile
+		}
+	}
+	return image, filepath.Join(wsPath, repo.Path, dockerfile)
+}
+
+// dockerImageRef is <image>:<sha>, with -dirty appended for uncommitted changes
+func dockerImageRef(wsPath string, ws *workspace.Workspace, name string) (string, error) {
+	repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+	sha, err := git.ShortSHA(repoDir)
+	if err != nil {
+		return "", err
+	}
+	if git.IsDirty(repoDir) {
+		sha += "-dirty"
+	}
+	image, _ := dockerImage(wsPath, ws, name)
+	return image + ":" + sha, nil
+}
+
+// dockerBuild builds and tags a repo's image and returns the SHA-tagged reference
+func dockerBuild(wsPath string, ws *workspace.Workspace, name string, wsEnv map[string]string) (string, error) {
+	repo := ws.Repos[name]
+	repoDir := filepath.Join(wsPath, repo.Path)
+	image, dockerfile := dockerImage(wsPath, ws, name)
+	if !fileExistsCheck(dockerfile) {
+		return "", errs.New(errs.KindUsage, "%s has no %s", name, filepath.Base(dockerfile))
+	}
+	ref, err := dockerImageRef(wsPath, ws, name)
+	if err != nil {
+		return "", err
+	}
+	envTag := image + ":" + activeEnv(ws, "")
+
+	env := make(map[string]string, len(wsEnv)+3)
+	for k, v := range repo.ScopeEnv(wsEnv) {
+		env[k] = v
+	}
+	env["GIT_SHA"] = strings.TrimSuffix(ref[strings.LastIndex(ref, ":")+1:], "-dirty")
+	if env["APP_ENV"] == "" {
+		env["APP_ENV"] = activeEnv(ws, "")
+	}
+	env["DOCKER_BUILDKIT"] = "1"
+
+	secrets := make(map[string]bool)
+	parts := []string{"docker", "build", "-f", shellquote.Quote(dockerfile), "-t", ref, "-t", envTag}
+	if repo.Docker != nil {
+		for _, s := range repo.Docker.Secrets {
+			secrets[s] = true
+			parts = append(parts, "--secret", fmt.Sprintf("id=%s,env=%s", s, s))
+		}
+	}
+	declared, err := docker.DeclaredArgs(dockerfile)
+	if err != nil {
+		return "", err
+	}
+	for _, arg := range declared {
+		if _, ok := env[arg]; ok && !secrets[arg] {
+			parts = append(parts, "--build-arg", arg) // value comes from the environment
+		}
+	}
+	parts = append(parts, shellquote.Quote(repoDir))
+
+	fmt.Printf("=== %s: docker build -t %s -t %s ===\n", name, ref, envTag)
+	if err := runShellCmdWithEnv(repoDir, strings.Join(parts, " "), nil, env); err != nil {
+		return "", errs.New(errs.KindBuildFailed, "%s: docker build failed: %w", name, err)
+	}
+	ui.OK.Printf("Built %s (also tagged %s)\n", ref, envTag)
+	return ref, nil
+}
+
+// writeStackCompose generates .spk/compose/<stack>.json and returns its path
+func writeStackCompose(wsPath string, ws *workspace.Workspace, stack string, repos []string, wsEnv map[string]string) (string, error) {
+	inStack := make(map[string]bool)
+	for _, r := range repos {
+		if _, ok := ws.Repos[r]; !ok {
+			return "", errs.New(errs.KindRepoNotFound, "stack %s: repo '%s' not found in workspace", stack, r)
+		}
+		inStack[r] = true
+	}
+
+	services := make(map[string]docker.Service)
+	for _, name := range repos {
+		image, dockerfile := dockerImage(wsPath, ws, name)
+		if !fileExistsCheck(dockerfile) {
+			return "", errs.New(errs.KindUsage, "stack %s: %s has no %s", stack, name, filepath.Base(dockerfile))
+		}
+		svc := docker.Service{
+			Build: docker.ServiceBuild{
+				Context:    filepath.Join(wsPath, ws.Repos[name].Path),
+				Dockerfile: dockerfile,
+			},
+			Image:       image + ":" + activeEnv(ws, ""),
+			Environment: sortedKeys(ws.Repos[name].ScopeEnv(wsEnv)),
+		}
+		if def := ws.Repos[name].Docker; def != nil {
+			svc.Ports = def.Ports
+		}
+		for _, dep := range workspace.Dependencies(wsPath, ws, name) {
+			if inStack[dep] {
+				svc.DependsOn = append(svc.DependsOn, docker.ImageName(dep))
+			}
+		}
+		services[docker.ImageName(name)] = svc
+	}
+
+	dir := filepath.Join(workspace.SparkDir(wsPath), "compose")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, stack+".json")
+	project := composeNameRe.ReplaceAllString(strings.ToLower(ws.Name+"-"+stack), "-")
+	if err := docker.WriteCompose(path, project, services); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	dockerRunCmd.Flags().BoolVar(&dockerBuildFirst, "build", false, "Rebuild the image before running")
+	dockerCmd.AddCommand(dockerBuildCmd)
+	dockerCmd.AddCommand(dockerRunCmd)
+	dockerCmd.AddCommand(dockerComposeCmd)
+	rootCmd.AddCommand(dockerCmd)
+}
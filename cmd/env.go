@@ -0,0 +1,463 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/envfile"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/spkconfig"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var envShowValues bool
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Show workspace environment (link | validate | template | -h)",
+	Long: `Shows the variables in the workspace .env file. Values are masked, since they
+include tokens and API keys; --show-values prints them.
+
+Examples:
+  spark-cli env
+  spark-cli env --show-values
+  spark-cli env link
+  spark-cli env validate
+  spark-cli env template`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, _, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		vars, err := workspace.ReadGlobalEnv(wsPath)
+		if err != nil {
+			return err
+		}
+		if len(vars) == 0 {
+			fmt.Println(binname.Rewrite("Workspace .env is empty — run 'spark-cli workspace sync --env beta'"))
+			return nil
+		}
+		keys := make([]string, 0, len(vars))
+		for k := range vars {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := vars[k]
+			if !envShowValues && v != "" {
+				v = "****"
+			}
+			fmt.Printf("%s=%s\n", k, v)
+		}
+		return nil
+	},
+}
+
+var envLinkCmd = &cobra.Command{
+	Use:   "link",
+	Short: "Symlink each repo's env file to the workspace .env",
+	Long: `Symlinks the workspace .env into every repo under the file name its framework
+reads:
+
+  Next.js        .env.local   generated, not linked (see below)
+  React Native   .env         generated, not linked (see below)
+  CDK            .env
+  other          .env
+
+Next.js repos get a generated .env.local holding only the keys the app declares
+in its "env_keys" (names or patterns; default NEXT_PUBLIC_*) instead of every
+workspace variable. NEXT_PUBLIC_<KEY> values come from <KEY> (or a
+known alias, e.g. NEXT_PUBLIC_USERPOOL_ID from BUSINESS_USERPOOL_ID).
+
+React Native repos get a generated .env the same way, since react-native-config
+compiles every key in it into the app. Without "env_keys" it holds only
+client-side config: APP_ENV, AWS_REGION, the Cognito pool and client IDs,
+*_API_URL, *_PUBLIC_KEY, and GOOGLE_MAPS_KEY.
+
+A repo with "env_allowlist" or "env_denylist" gets a generated file as well,
+holding only the variables the lists let through (see 'spark-cli run --help').
+
+Generated files are refreshed whenever the workspace .env is.
+
+Existing real files and files tracked by git are never replaced.
+
+Example:
+  spark-cli env link`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, nil)
+		if err != nil {
+			return err
+		}
+
+		globalEnv := workspace.GlobalEnvPath(wsPath)
+		if _, err := os.Stat(globalEnv); os.IsNotExist(err) {
+			return fmt.Errorf("no workspace .env yet — run 'spark-cli workspace sync --env beta' first")
+		}
+
+		vars, err := workspace.ReadGlobalEnv(wsPath)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+			fw := envfile.Detect(repoDir)
+			for _, file := range envfile.FileNames(fw) {
+				var status string
+				if envfile.Generates(fw) || repo.ScopesEnv() {
+					status = generateEnvFile(repoDir, file, fw, repo, vars)
+				} else {
+					status = linkEnvFile(repoDir, file, globalEnv)
+				}
+				fmt.Printf("%-25s %-12s %-11s %s\n", name, fw, file, status)
+			}
+		}
+		return nil
+	},
+}
+
+var envValidateRepos []string
+
+var envValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check that each repo's required env vars are set",
+	Long: `Checks the variables each repo declares in its spk.config.json against the
+workspace env the repo's commands get, and reports every missing or empty one
+with where it should come from (the SSM parameter, the services registry, or
+workspace.json) — instead of the app failing at runtime on an undefined value.
+
+  spk.config.json:
+  { "required_env": ["USERPOOL_ID", "APP_API_URL", "STRIPE_PUBLIC_KEY"] }
+
+'spark-cli run start' runs the same check for the repo first.
+
+Examples:
+  spark-cli env validate
+  spark-cli env validate --repos AppAPI,Web`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, envValidateRepos)
+		if err != nil {
+			return err
+		}
+
+		wsEnv := buildWorkspaceEnv(wsPath, ws)
+		var checked, failed int
+		for _, name := range names {
+			problems, err := validateRepoEnv(wsPath, ws, name, wsEnv)
+			switch {
+			case err != nil:
+				ui.Fail.Printf("%-25s %v\n", name, err)
+				failed++
+			case problems == nil:
+				continue
+			case len(problems) == 0:
+				ui.OK.Printf("%-25s all required variables set\n", name)
+			default:
+				ui.Fail.Printf("%-25s %d problem(s)\n", name, len(problems))
+				printEnvProblems(problems)
+				failed++
+			}
+			checked++
+		}
+
+		if checked == 0 {
+			fmt.Printf("No repo declares \"required_env\" in %s\n", spkconfig.ConfigFilename)
+			return nil
+		}
+		if failed > 0 {
+			return errs.New(errs.KindCommandFailed, "%d repo(s) are missing required env — fix the sources above, then 'spark-cli workspace sync'", failed)
+		}
+		return nil
+	},
+}
+
+var (
+	envTemplateRepos []string
+	envTemplateCheck bool
+	envTemplateForce bool
+)
+
+var envTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Write each repo's .env.example from its required_env",
+	Long: `Writes a .env.example into every repo that declares "required_env" in its
+spk.config.json: the variable names, in order, without values. Rerun it after
+changing required_env so the template never drifts from what the repo needs and
+the workspace provides ('spark-cli env validate' checks the values).
+
+Templates are meant to be committed. Only ones spark-cli wrote are rewritten; a
+hand-written .env.example is left alone unless --force. --check writes nothing
+and fails if any template is missing or out of date (for CI).
+
+Examples:
+  spark-cli env template
+  spark-cli env template --repos AppAPI,Web
+  spark-cli env template --check`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, envTemplateRepos)
+		if err != nil {
+			return err
+		}
+
+		var declared, stale int
+		for _, name := range names {
+			repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+			cfg, err := spkconfig.Load(repoDir)
+			if err != nil {
+				ui.Fail.Printf("%-25s invalid %s: %v\n", name, spkconfig.ConfigFilename, err)
+				stale++
+				continue
+			}
+			if cfg == nil || len(cfg.RequiredEnv) == 0 {
+				continue
+			}
+			declared++
+
+			path := filepath.Join(repoDir, envfile.TemplateName)
+			want := envfile.Template(cfg.RequiredEnv)
+			current, readErr := os.ReadFile(path)
+			switch {
+			case readErr == nil && string(current) == want:
+				ui.OK.Printf("%-25s %s up to date\n", name, envfile.TemplateName)
+				continue
+			case readErr == nil && !envfile.IsTemplate(path) && !envTemplateForce:
+				ui.Skip.Printf("%-25s %s is hand-written — left alone (--force replaces it)\n", name, envfile.TemplateName)
+				continue
+			case envTemplateCheck:
+				what := "out of date"
+				if readErr != nil {
+					what = "missing"
+				}
+				ui.Fail.Printf("%-25s %s %s\n", name, envfile.TemplateName, what)
+				stale++
+				continue
+			}
+			if err := os.WriteFile(path, []byte(want), 0644); err != nil {
+				ui.Fail.Printf("%-25s %v\n", name, err)
+				stale++
+				continue
+			}
+			ui.OK.Printf("%-25s %s written (%d variable(s))\n", name, envfile.TemplateName, strings.Count(want, "=\n"))
+		}
+
+		if declared == 0 {
+			fmt.Printf("No repo declares \"required_env\" in %s\n", spkconfig.ConfigFilename)
+			return nil
+		}
+		if stale > 0 && envTemplateCheck {
+			return errs.New(errs.KindCommandFailed, "%d template(s) missing or out of date — run 'spark-cli env template'", stale)
+		}
+		if stale > 0 {
+			return errs.New(errs.KindCommandFailed, "%d template(s) not written", stale)
+		}
+		return nil
+	},
+}
+
+// envProblem is a required variable a repo won't get
+type envProblem struct {
+	Key     string
+	Problem string
+	Source  string
+}
+
+// validateRepoEnv checks a repo's required_env against the env its commands get. It returns
+// nil problems when the repo declares none.
+func validateRepoEnv(wsPath string, ws *workspace.Workspace, name string, wsEnv map[string]string) ([]envProblem, error) {
+	repo := ws.Repos[name]
+	cfg, err := spkconfig.Load(filepath.Join(wsPath, repo.Path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", spkconfig.ConfigFilename, err)
+	}
+	if cfg == nil || len(cfg.RequiredEnv) == 0 {
+		return nil, nil
+	}
+
+	scoped := repo.ScopeEnv(wsEnv)
+	problems := []envProblem{}
+	for _, key := range cfg.RequiredEnv {
+		v, ok := scoped[key]
+		switch {
+		case !ok && wsEnv[key] != "":
+			problems = append(problems, envProblem{key, "blocked", "excluded by the repo's env_allowlist/env_denylist in workspace.json"})
+		case !ok && os.Getenv(key) != "":
+			// Exported in the shell; commands inherit it
+		case !ok:
+			problems = append(problems, envProblem{key, "missing", envSource(ws, key)})
+		case strings.TrimSpace(v) == "":
+			problems = append(problems, envProblem{key, "empty", envSource(ws, key)})
+		}
+	}
+	return problems, nil
+}
+
+// envSource describes where spark-cli gets a workspace variable from
+func envSource(ws *workspace.Workspace, key string) string {
+	if _, ok := ws.Env[key]; ok {
+		return "set in workspace.json \"env\""
+	}
+	for _, suffix := range workspaceSSMParams(ws) {
+		if ssmEnvKey(ws, suffix) == key {
+			return fmt.Sprintf("SSM parameter /app/%s/%s", activeEnv(ws, ""), suffix)
+		}
+	}
+	for service := range ws.Services {
+		if ws.ServiceVar(service) == key {
+			return fmt.Sprintf("services.%s in workspace.json (needs a URL for %s)", service, activeEnv(ws, ""))
+		}
+	}
+	return "not provided by spark-cli — add it to workspace.json \"env\""
+}
+
+func printEnvProblems(problems []envProblem) {
+	for _, p := range problems {
+		fmt.Printf("    %-28s %-8s %s\n", p.Key, p.Problem, p.Source)
+	}
+}
+
+// writeWorkspaceEnv writes the workspace .env fetched for env and regenerates the repo env
+// files derived from it (ones spark-cli generated before, or that still link to the
+// workspace .env)
+func writeWorkspaceEnv(wsPath string, ws *workspace.Workspace, env string, envVars map[string]string) error {
+	if err := workspace.WriteGlobalEnv(wsPath, envVars); err != nil {
+		return err
+	}
+	state.Update(wsPath, func(s *state.State) error {
+		s.EnvRefresh = &state.EnvRefresh{Env: env, WrittenAt: time.Now().UTC().Format(time.RFC3339)}
+		return nil
+	})
+	current, _ := workspace.ReadGlobalEnv(wsPath)
+	// Leaving a local (LocalStack) env must not leave clients pointed at it
+	if envVars["AWS_ENDPOINT_URL"] == "" && current["AWS_ENDPOINT_URL"] != "" {
+		if err := workspace.UnsetGlobalEnv(wsPath, localEndpointEnv(current["AWS_ENDPOINT_URL"])); err != nil {
+			return err
+		}
+	}
+	// Nor keep another env's URL for a service that has none in this one
+	stale := make(map[string]string)
+	for name, def := range ws.Services {
+		key := ws.ServiceVar(name)
+		if _, ok := envVars[key]; ok {
+			continue
+		}
+		for _, u := range def.URLs {
+			if current[key] == u {
+				stale[key] = u
+			}
+		}
+	}
+	if err := workspace.UnsetGlobalEnv(wsPath, stale); err != nil {
+		return err
+	}
+	return regenerateRepoEnvFiles(wsPath, ws)
+}
+
+// regenerateRepoEnvFiles rewrites the repo env files derived from the workspace .env
+func regenerateRepoEnvFiles(wsPath string, ws *workspace.Workspace) error {
+	vars, err := workspace.ReadGlobalEnv(wsPath)
+	if err != nil {
+		return err
+	}
+	for _, repo := range ws.Repos {
+		repoDir := filepath.Join(wsPath, repo.Path)
+		fw := envfile.Detect(repoDir)
+		if !envfile.Generates(fw) && !repo.ScopesEnv() {
+			continue
+		}
+		for _, file := range envfile.FileNames(fw) {
+			path := filepath.Join(repoDir, file)
+			if info, err := os.Lstat(path); err == nil && (info.Mode()&os.ModeSymlink != 0 || envfile.IsGenerated(path)) {
+				generateEnvFile(repoDir, file, fw, repo, vars)
+			}
+		}
+	}
+	return nil
+}
+
+// generateEnvFile writes repoDir/file with only the variables the repo declares (and its
+// env_allowlist/env_denylist let through) and returns a status line. Hand-written and
+// git-tracked files are never replaced.
+func generateEnvFile(repoDir, file string, fw envfile.Framework, repo workspace.RepoDef, vars map[string]string) string {
+	path := filepath.Join(repoDir, file)
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink == 0 && !envfile.IsGenerated(path) {
+		return ui.Skip.String() + " real file exists — left alone"
+	}
+	if git.IsTracked(repoDir, file) {
+		return ui.Skip.String() + " tracked by git — left alone"
+	}
+	generated := repo.ScopeEnv(vars)
+	if envfile.Generates(fw) {
+		generated = envfile.Generate(fw, generated, repo.EnvKeys)
+	}
+	if err := envfile.WriteGenerated(path, generated); err != nil {
+		return fmt.Sprintf("%s %v", ui.Fail, err)
+	}
+	return fmt.Sprintf("%s generated (%d variables)", ui.OK, len(generated))
+}
+
+// linkEnvFile points repoDir/file at the workspace .env and returns a status line
+func linkEnvFile(repoDir, file, globalEnv string) string {
+	linkPath := filepath.Join(repoDir, file)
+	target, err := filepath.Rel(repoDir, globalEnv)
+	if err != nil {
+		target = globalEnv
+	}
+
+	if info, err := os.Lstat(linkPath); err == nil {
+		if info.Mode()&os.ModeSymlink == 0 {
+			return ui.Skip.String() + " real file exists — left alone"
+		}
+		if current, err := os.Readlink(linkPath); err == nil && current == target {
+			return ui.OK.String() + " already linked"
+		}
+		if err := os.Remove(linkPath); err != nil {
+			return fmt.Sprintf("%s %v", ui.Fail, err)
+		}
+	}
+
+	if git.IsTracked(repoDir, file) {
+		return ui.Skip.String() + " tracked by git — left alone"
+	}
+
+	if err := os.Symlink(target, linkPath); err != nil {
+		return fmt.Sprintf("%s %v", ui.Fail, err)
+	}
+	return "🔗 linked"
+}
+
+func init() {
+	envCmd.Flags().BoolVar(&envShowValues, "show-values", false, "Print values instead of masking them")
+	envValidateCmd.Flags().StringSliceVar(&envValidateRepos, "repos", nil, "Only check these repos")
+	envCmd.AddCommand(envLinkCmd)
+	envCmd.AddCommand(envValidateCmd)
+	envTemplateCmd.Flags().StringSliceVar(&envTemplateRepos, "repos", nil, "Only these repos")
+	envTemplateCmd.Flags().BoolVar(&envTemplateCheck, "check", false, "Write nothing; fail if a template is missing or out of date")
+	envTemplateCmd.Flags().BoolVar(&envTemplateForce, "force", false, "Replace hand-written templates too")
+	envCmd.AddCommand(envTemplateCmd)
+	rootCmd.AddCommand(envCmd)
+}
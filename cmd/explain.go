@@ -0,0 +1,409 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/settings"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellquote"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <run | build | test | sync | cdk> [args...]",
+	Short: "Show what run, build, test, sync, or cdk would execute, without running it",
+	Long: `Prints what a command would do — the repo it resolves to, the directory, the
+full shell command, and the environment variables it adds or changes — without
+running anything. Use it to find out why spark-cli did something unexpected.
+
+Values of variables that look like secrets (TOKEN, SECRET, PASSWORD, KEY) are
+hidden.
+
+Examples:
+  spark-cli explain run build          # from inside a repo
+  spark-cli explain build AppAPI --deps
+  spark-cli explain test --all
+  spark-cli explain sync --install
+  spark-cli explain cdk --env beta deploy SomeStack`,
+}
+
+var explainRunCmd = &cobra.Command{
+	Use:                "run [script] [args...]",
+	Short:              "Explain 'spark-cli run'",
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+			return cmd.Help()
+		}
+		if args[0] == "--" {
+			args = args[1:]
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		wsEnv := buildSyncEnv(wsPath, ws)
+
+		repoName, _ := detectCurrentRepo(wsPath, ws)
+		if repoName == "" {
+			command := strings.Join(args, " ")
+			printExplained("run: "+command, wsPath, command, wsEnv)
+			return nil
+		}
+		return explainRepoCommand(wsPath, ws, repoName, args[0], args[1:], "", wsEnv)
+	},
+}
+
+var explainBuildCmd = &cobra.Command{
+	Use:   "build [repo...]",
+	Short: "Explain 'spark-cli build'",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return explainRepoScripts(args, "build", buildAll, buildDeps)
+	},
+}
+
+var explainTestCmd = &cobra.Command{
+	Use:   "test [repo...]",
+	Short: "Explain 'spark-cli test'",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return explainRepoScripts(args, "test", testAll, false)
+	},
+}
+
+var explainSyncCmd = &cobra.Command{
+	Use:   "sync [repo-name]",
+	Short: "Explain 'spark-cli workspace sync'",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := expandRepoNames(ws, args)
+		if err != nil {
+			return err
+		}
+		var wsEnv map[string]string
+		if syncInstall {
+			wsEnv = buildSyncEnv(wsPath, ws)
+		}
+		for _, name := range names {
+			explainSyncRepo(wsPath, ws, name, wsEnv)
+		}
+
+		if syncEnv != "" {
+			env := syncEnvName(ws)
+			fmt.Printf("Then: refresh %s from SSM /app/%s/...\n", workspace.GlobalEnvPath(wsPath), env)
+			if endpoint := ws.LocalEndpoint(env); endpoint != "" {
+				fmt.Printf("  LocalStack: %s\n", endpoint)
+			} else {
+				profile, region := settings.Load(ws).AWS(env)
+				fmt.Printf("  AWS profile: %s, region: %s\n", orDefault(profile, "default"), region)
+			}
+		}
+		return nil
+	},
+}
+
+var explainCDKCmd = &cobra.Command{
+	Use:                "cdk [cdk-args...]",
+	Short:              "Explain 'spark-cli cdk'",
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+			return cmd.Help()
+		}
+		opts, err := parseCDKArgs(args)
+		if err != nil {
+			return err
+		}
+		if err := applyRootFlags(); err != nil {
+			return err
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		target, err := resolveCDKTarget(ws, opts)
+		if err != nil {
+			return err
+		}
+		cdkDir, cdkRepo, err := findCDKRepoDir(wsPath, ws)
+		if err != nil {
+			return err
+		}
+
+		command := strings.Join(append([]string{"cdk"}, opts.args...), " ")
+		var notes []string
+		if cdkMutates(opts.args) {
+			switch {
+			case opts.skipAccountCheck:
+				notes = append(notes, "account check skipped (--skip-account-check)")
+			case target.expectedAccount != "":
+				notes = append(notes, fmt.Sprintf("first checks profile %s is account %s", orDefault(target.profile, "default"), target.expectedAccount))
+			case orDefault(target.env, activeEnv(ws, "")) != "":
+				notes = append(notes, fmt.Sprintf("first checks profile %s is the %s account", orDefault(target.profile, "default"), orDefault(target.env, activeEnv(ws, ""))))
+			}
+		}
+		env := cdkEnv(wsPath, ws, cdkRepo, target)
+		printExplainedArgv("cdk: "+strings.Join(opts.args, " "), cdkDir, command, env, notes...)
+		return nil
+	},
+}
+
+// explainRepoScripts explains build or test for the repos they would select
+func explainRepoScripts(args []string, script string, all, deps bool) error {
+	wsPath, ws, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+	names := args
+	switch {
+	case all:
+		names = nil
+	case len(names) == 0:
+		current, _ := detectCurrentRepo(wsPath, ws)
+		if current == "" {
+			return errs.New(errs.KindUsage, "not inside a workspace repo — name repos or pass --all")
+		}
+		names = []string{current}
+	}
+
+	// Builds run in dependency order; tests run in the order given
+	order := names
+	if script == "build" {
+		if order, err = workspace.BuildOrder(wsPath, ws, names); err != nil {
+			return err
+		}
+		if names != nil && !deps {
+			order = onlyRepos(order, names)
+		}
+	} else if all {
+		if order, err = selectRepos(wsPath, ws, nil); err != nil {
+			return err
+		}
+	}
+
+	wsEnv := buildSyncEnv(wsPath, ws)
+	for _, name := range order {
+		repo := ws.Repos[name]
+		configured := repo.BuildCommand
+		if script == "test" {
+			configured = repo.TestCommand
+		}
+		if err := explainRepoCommand(wsPath, ws, name, script, nil, configured, wsEnv); err != nil {
+			fmt.Printf("%s: %v\n\n", name, err)
+		}
+	}
+	return nil
+}
+
+// explainRepoCommand explains running script (or the configured command in its place) in
+// a repo, the way runRepoScript, buildRepo, and testRepo resolve it
+func explainRepoCommand(wsPath string, ws *workspace.Workspace, name, script string, extraArgs []string, configured string, wsEnv map[string]string) error {
+	repo, ok := ws.Repos[name]
+	if !ok {
+		return errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
+	}
+	repoDir := filepath.Join(wsPath, repo.Path)
+	if _, err := os.Stat(repoDir); os.IsNotExist(err) {
+		return fmt.Errorf("repo directory %s does not exist", repoDir)
+	}
+
+	projType := detectProjectType(repoDir)
+	command := configured
+	if command != "" {
+		if args := repo.ArgsFor(script, nil); len(args) > 0 {
+			command += " " + shellquote.Join(args)
+		}
+	} else {
+		command = buildCommand(repoDir, projType, script, repo.ArgsFor(script, extraArgs))
+		if command == "" {
+			return fmt.Errorf("script '%s' not available in %s", script, name)
+		}
+	}
+
+	var notes []string
+	if configured != "" {
+		notes = append(notes, fmt.Sprintf("%s_command from workspace.json", script))
+	}
+	if projType == projectTypeNode {
+		if reason := npmInstallNeeded(wsPath, name, repoDir); reason != "" {
+			install, err := npm.InstallCommand(repoDir, installOptions(wsPath))
+			if err != nil {
+				install = err.Error()
+			}
+			notes = append(notes, fmt.Sprintf("first runs %s (%s)", install, reason))
+		}
+	}
+	g := resolveGradleEnv(wsPath, repo, repoDir, repo.ScopeEnv(wsEnv))
+	if len(g.localProperties) > 0 {
+		keys := make([]string, 0, len(g.localProperties))
+		for k := range g.localProperties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		rel, _ := filepath.Rel(wsPath, filepath.Join(g.dir, "local.properties"))
+		notes = append(notes, fmt.Sprintf("writes %s: %s", rel, strings.Join(keys, ", ")))
+	}
+	if len(g.missing) > 0 {
+		notes = append(notes, "gradle keys not in workspace env, skipped: "+strings.Join(g.missing, ", "))
+	}
+	if len(repo.EnvAllowlist) > 0 || len(repo.EnvDenylist) > 0 {
+		notes = append(notes, "env narrowed by the repo's env_allowlist/env_denylist")
+	}
+
+	printExplained(name+": "+command, repoDir, command, g.env, notes...)
+	return nil
+}
+
+// explainSyncRepo lists the git commands sync runs in a repo, and the install with --install
+func explainSyncRepo(wsPath string, ws *workspace.Workspace, name string, wsEnv map[string]string) {
+	repo := ws.Repos[name]
+	repoDir := filepath.Join(wsPath, repo.Path)
+	if _, err := os.Stat(repoDir); os.IsNotExist(err) {
+		fmt.Printf("%s: skipped (not cloned)\n\n", name)
+		return
+	}
+	fmt.Printf("%s\n", name)
+	fmt.Printf("  Directory: %s\n", repoDir)
+	if !git.IsRepo(repoDir) {
+		fmt.Printf("  Skipped:   not a git repo\n\n")
+		return
+	}
+
+	current := git.GetCurrentBranch(repoDir)
+	target, onto := syncTargetBranch(wsPath, ws, name, &repo, repoDir)
+	def := ""
+	if onto {
+		def = getTargetBranch(ws, &repo, repoDir)
+	}
+	upstream := repo.SyncRemote() + "/" + target
+	fmt.Println("  git fetch origin")
+	if repo.Upstream != "" {
+		fmt.Printf("  git fetch %s          (%s)\n", workspace.UpstreamRemote, repo.Upstream)
+	}
+	switch {
+	case git.IsDirty(repoDir):
+		fmt.Printf("  Skipped:   dirty working tree (nothing below runs)\n\n")
+		return
+	case syncNoRebase:
+		fmt.Printf("  git pull             (on %s)\n", current)
+	default:
+		if onto && current == def {
+			fmt.Printf("  Skipped:   rebasing %s, the default branch, onto %s\n", current, upstream)
+		} else {
+			fmt.Printf("  git rebase %s     (on %s)\n", upstream, current)
+		}
+		others := syncOtherBranches(repoDir, current, target, def, onto)
+		for _, branch := range others {
+			fmt.Printf("  git checkout %s && git rebase %s\n", branch, upstream)
+		}
+		if len(others) > 0 {
+			fmt.Printf("  git checkout %s\n", current)
+		}
+		if git.HasSubmodules(repoDir) {
+			fmt.Println("  git submodule update --init --recursive")
+		}
+		if git.UsesLFS(repoDir) && git.LFSInstalled() {
+			fmt.Println("  git lfs pull")
+		}
+	}
+
+	if wsEnv != nil && fileExistsCheck(filepath.Join(repoDir, "package.json")) {
+		if command, err := npm.InstallCommand(repoDir, installOptions(wsPath)); err == nil {
+			reason := npmInstallNeeded(wsPath, name, repoDir)
+			if reason == "" {
+				reason = "if the lockfile changes"
+			}
+			fmt.Printf("  %s    (%s)\n", command, reason)
+		}
+	}
+	fmt.Println()
+}
+
+// printExplained prints a command as it would run through the user's shell
+func printExplained(title, dir, command string, env map[string]string, notes ...string) {
+	shell, args := shellCommand(command)
+	argv := shell
+	for _, a := range args {
+		if a == command {
+			a = shellquote.Quote(a)
+		}
+		argv += " " + a
+	}
+	printExplainedArgv(title, dir, argv, env, notes...)
+}
+
+func printExplainedArgv(title, dir, argv string, env map[string]string, notes ...string) {
+	fmt.Println(title)
+	fmt.Printf("  Directory: %s\n", dir)
+	fmt.Printf("  Runs:      %s\n", argv)
+	for _, note := range notes {
+		fmt.Printf("  Note:      %s\n", note)
+	}
+	printEnvDiff(env)
+	fmt.Println()
+}
+
+// secretKeyRe matches variable names whose values explain won't print
+var secretKeyRe = regexp.MustCompile(`(?i)TOKEN|SECRET|PASSWORD|PASSWD|KEY|CREDENTIAL`)
+
+// printEnvDiff lists the variables env adds to, or changes in, spark-cli's own environment
+func printEnvDiff(env map[string]string) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	added, changed := 0, 0
+	for _, k := range keys {
+		v := env[k]
+		cur, set := os.LookupEnv(k)
+		if set && cur == v {
+			continue
+		}
+		shown := v
+		if secretKeyRe.MatchString(k) {
+			shown = "(hidden)"
+		}
+		if set {
+			changed++
+			lines = append(lines, fmt.Sprintf("    ~ %s=%s", k, shown))
+		} else {
+			added++
+			lines = append(lines, fmt.Sprintf("    + %s=%s", k, shown))
+		}
+	}
+	if len(lines) == 0 {
+		fmt.Println("  Env:       unchanged")
+		return
+	}
+	fmt.Printf("  Env:       %d added, %d changed\n", added, changed)
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+}
+
+func init() {
+	explainBuildCmd.Flags().BoolVar(&buildAll, "all", false, "Explain building every repo")
+	explainBuildCmd.Flags().BoolVar(&buildDeps, "deps", false, "Include the repos they depend on")
+	explainTestCmd.Flags().BoolVar(&testAll, "all", false, "Explain testing every cloned repo")
+	explainSyncCmd.Flags().StringVar(&syncBranch, "branch", "", "Target branch (default: the repo's default branch)")
+	explainSyncCmd.Flags().BoolVar(&syncNoRebase, "no-rebase", false, "Explain git pull instead of rebase")
+	explainSyncCmd.Flags().StringVar(&syncEnv, "env", "", "Also explain refreshing .env from this SSM environment")
+	explainSyncCmd.Flags().BoolVarP(&syncInstall, "install", "i", false, "Also explain dependency installs")
+	explainSyncCmd.Flags().BoolVar(&frozenInstall, "frozen", false, "With --install, explain frozen installs")
+	explainCmd.AddCommand(explainRunCmd, explainBuildCmd, explainTestCmd, explainSyncCmd, explainCDKCmd)
+	rootCmd.AddCommand(explainCmd)
+}
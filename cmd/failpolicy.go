@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// --fail-fast and --keep-going, shared by the multi-repo commands (build, test, sync)
+var (
+	failFast  bool
+	keepGoing bool
+)
+
+// addFailurePolicyFlags registers --fail-fast and --keep-going on cmd; stopByDefault says
+// which applies when neither is given
+func addFailurePolicyFlags(cmd *cobra.Command, stopByDefault bool) {
+	fast, going := "Stop at the first repo that fails", "Run every repo even after one fails"
+	if stopByDefault {
+		fast += " (default)"
+	} else {
+		going += " (default)"
+	}
+	cmd.Flags().BoolVar(&failFast, "fail-fast", false, fast)
+	cmd.Flags().BoolVar(&keepGoing, "keep-going", false, going)
+	cmd.MarkFlagsMutuallyExclusive("fail-fast", "keep-going")
+}
+
+// stopOnFailure reports whether a multi-repo command stops at its first failing repo
+func stopOnFailure(stopByDefault bool) bool {
+	switch {
+	case failFast:
+		return true
+	case keepGoing:
+		return false
+	}
+	return stopByDefault
+}
+
+// repoFailures collects the repos a multi-repo command failed on, and the ones it
+// didn't run because of them, for the summary it ends with
+type repoFailures struct {
+	what   string    // what failed, e.g. "build" or "tests"
+	kind   errs.Kind // exit kind when the first failure isn't tagged
+	failed []repoFailure
+	notRun []repoFailure
+}
+
+type repoFailure struct {
+	repo   string
+	reason string
+	kind   errs.Kind
+}
+
+// add records that repo failed with err
+func (f *repoFailures) add(repo string, err error) {
+	f.failed = append(f.failed, repoFailure{repo, err.Error(), errs.KindOf(errs.Wrap(f.kind, err))})
+}
+
+// skip records that repo wasn't run, and why
+func (f *repoFailures) skip(repo, why string) {
+	f.notRun = append(f.notRun, repoFailure{repo: repo, reason: why})
+}
+
+// has reports whether repo failed or wasn't run because of a failure
+func (f *repoFailures) has(repo string) bool {
+	is := func(r repoFailure) bool { return r.repo == repo }
+	return slices.ContainsFunc(f.failed, is) || slices.ContainsFunc(f.notRun, is)
+}
+
+// summarize prints the failed and not-run repos, if any
+func (f *repoFailures) summarize() {
+	if len(f.failed) == 0 {
+		return
+	}
+	fmt.Printf("\n%d repo(s) failed", len(f.failed))
+	if len(f.notRun) > 0 {
+		fmt.Printf(", %d not run", len(f.notRun))
+	}
+	fmt.Println(":")
+	for _, r := range f.failed {
+		fmt.Printf("  %s %-25s %s\n", ui.Fail, r.repo, r.reason)
+	}
+	for _, r := range f.notRun {
+		fmt.Printf("  %s %-25s not run — %s\n", ui.Skip, r.repo, r.reason)
+	}
+}
+
+// err returns the command's error: one naming every failed repo, with the exit kind
+// of the first failure. It returns nil when nothing failed.
+func (f *repoFailures) err() error {
+	if len(f.failed) == 0 {
+		return nil
+	}
+	names := make([]string, len(f.failed))
+	for i, r := range f.failed {
+		names[i] = r.repo
+	}
+	return errs.New(f.failed[0].kind, "%s failed in %s", f.what, strings.Join(names, ", "))
+}
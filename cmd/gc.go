@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/gradle"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/metrics"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gcDays        int
+	gcNodeModules int
+	gcDryRun      bool
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Prune old logs, caches, and metrics, broken links, and (opt-in) idle node_modules",
+	Long: `Frees space spark-cli's own files take up in the workspace:
+
+  logs      files in .spk/logs not written for --days
+  cache     files in .spk/cache not written for --days, and cached Gradle
+            tasks of repos no longer in the workspace
+  metrics   .spk/metrics history entries older than --days
+  links     node_modules symlinks whose target is gone, and Gradle link
+            substitutions whose consumer or model build is gone
+
+--node-modules N also deletes node_modules in repos untouched for N days: no
+commit, checkout, uncommitted change, or spark-cli build/test/run in that time.
+They're reinstalled the next time the repo is built. Off by default.
+
+Reports the space reclaimed; --dry-run only lists what would go.
+
+Examples:
+  spark-cli gc
+  spark-cli gc --dry-run
+  spark-cli gc --days 7 --node-modules 30`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if gcDays < 1 {
+			return errs.New(errs.KindUsage, "--days must be at least 1")
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		g := &gcRun{wsPath: wsPath, dryRun: gcDryRun, cutoff: time.Now().AddDate(0, 0, -gcDays)}
+		sparkDir := workspace.SparkDir(wsPath)
+		// Read before the metrics are pruned: it's what tells idle repos apart
+		active := lastActivityByRepo(wsPath)
+
+		g.section = "Logs"
+		g.pruneOld(filepath.Join(sparkDir, "logs"))
+
+		g.section = "Cache"
+		g.pruneOld(filepath.Join(sparkDir, "cache"))
+		taskCaches, _ := filepath.Glob(filepath.Join(sparkDir, "cache", "gradle-tasks", "*.json"))
+		for _, path := range taskCaches {
+			if _, ok := ws.Repos[strings.TrimSuffix(filepath.Base(path), ".json")]; !ok {
+				g.remove(path, "tasks of removed repo")
+			}
+		}
+
+		g.section = "Metrics"
+		if err := g.pruneMetrics(wsPath); err != nil {
+			return err
+		}
+
+		g.section = "Links"
+		names, _ := expandRepoNames(ws, nil)
+		for _, name := range names {
+			g.pruneBrokenSymlinks(filepath.Join(wsPath, ws.Repos[name].Path, "node_modules"))
+		}
+		pruned, err := gradle.PruneLinks(g.dryRun)
+		if err != nil {
+			return fmt.Errorf("failed to prune Gradle links: %w", err)
+		}
+		sort.Strings(pruned)
+		for _, p := range pruned {
+			g.report(p, "Gradle link to a build that's gone", 0)
+		}
+
+		if gcNodeModules > 0 {
+			g.section = "node_modules"
+			idleCutoff := time.Now().AddDate(0, 0, -gcNodeModules)
+			for _, name := range names {
+				repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+				dir := filepath.Join(repoDir, "node_modules")
+				if _, err := os.Stat(dir); err != nil {
+					continue
+				}
+				if last := repoLastTouched(repoDir, active[name]); last.After(idleCutoff) {
+					continue
+				}
+				g.remove(dir, fmt.Sprintf("%s untouched for %d+ days", name, gcNodeModules))
+			}
+		}
+
+		if g.removed > 0 {
+			fmt.Println()
+		}
+		switch {
+		case g.removed == 0:
+			ui.OK.Println("Nothing to clean up")
+		case g.dryRun:
+			fmt.Printf("Would remove %d item(s), reclaiming %s — run without --dry-run to clean up\n", g.removed, formatBytes(g.reclaimed))
+		default:
+			ui.OK.Printf("Removed %d item(s), reclaimed %s\n", g.removed, formatBytes(g.reclaimed))
+		}
+		return nil
+	},
+}
+
+// gcRun removes (or, dry-run, lists) what gc finds and tallies the space reclaimed
+type gcRun struct {
+	wsPath    string
+	section   string // printed above its first item
+	shown     string
+	dryRun    bool
+	cutoff    time.Time
+	removed   int
+	reclaimed int64
+}
+
+// report prints one thing gc removes and counts it
+func (g *gcRun) report(what, why string, size int64) {
+	if g.shown != g.section {
+		fmt.Println(g.section)
+		g.shown = g.section
+	}
+	g.removed++
+	g.reclaimed += size
+	sizeNote := ""
+	if size > 0 {
+		sizeNote = ", " + formatBytes(size)
+	}
+	fmt.Printf("  - %s (%s%s)\n", what, why, sizeNote)
+}
+
+// remove deletes path, file or dir, unless dry-run
+func (g *gcRun) remove(path, why string) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return
+	}
+	size := info.Size()
+	if info.IsDir() {
+		size = dirSize(path)
+	}
+	if !g.dryRun {
+		if err := os.RemoveAll(path); err != nil {
+			fmt.Printf("  %s %s: %v\n", ui.Warn, path, err)
+			return
+		}
+	}
+	if rel, err := filepath.Rel(g.wsPath, path); err == nil && filepath.IsLocal(rel) {
+		path = rel
+	}
+	g.report(path, why, size)
+}
+
+// pruneOld removes the files under dir last written before the cutoff, then any dirs
+// that leaves empty
+func (g *gcRun) pruneOld(dir string) {
+	var dirs []string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().Before(g.cutoff) {
+			g.remove(path, "last written "+formatAge(time.Since(info.ModTime())))
+		}
+		return nil
+	})
+	if g.dryRun {
+		return
+	}
+	// Deepest first, so a parent empties once its children are gone
+	for i := len(dirs) - 1; i >= 0; i-- {
+		os.Remove(dirs[i])
+	}
+}
+
+// pruneMetrics drops metrics history entries older than the cutoff
+func (g *gcRun) pruneMetrics(wsPath string) error {
+	path := metrics.HistoryPath(wsPath)
+	before := fileSize(path)
+	var dropped int
+	if g.dryRun {
+		entries, err := metrics.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if t, err := time.Parse(time.RFC3339, e.Time); err == nil && t.Before(g.cutoff) {
+				dropped++
+			}
+		}
+	} else {
+		var err error
+		if dropped, err = metrics.Prune(wsPath, g.cutoff); err != nil {
+			return err
+		}
+	}
+	if dropped == 0 {
+		return nil
+	}
+	saved := int64(0)
+	if !g.dryRun {
+		saved = before - fileSize(path)
+	}
+	g.report(fmt.Sprintf("%d history line(s)", dropped), fmt.Sprintf("older than %d days", gcDays), saved)
+	return nil
+}
+
+// pruneBrokenSymlinks removes the symlinks directly in nodeModules (or in its @scope
+// dirs) whose target no longer exists
+func (g *gcRun) pruneBrokenSymlinks(nodeModules string) {
+	dirs := []string{nodeModules}
+	scopes, _ := filepath.Glob(filepath.Join(nodeModules, "@*"))
+	dirs = append(dirs, scopes...)
+	for _, dir := range dirs {
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			if e.Type()&os.ModeSymlink == 0 {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				g.remove(path, "symlink to a build that's gone")
+			}
+		}
+	}
+}
+
+// lastActivityByRepo returns when spark-cli last built, tested, or ran something in each repo
+func lastActivityByRepo(wsPath string) map[string]time.Time {
+	entries, _ := metrics.Load(wsPath)
+	last := make(map[string]time.Time)
+	for _, e := range entries {
+		if t, err := time.Parse(time.RFC3339, e.Time); err == nil && t.After(last[e.Repo]) {
+			last[e.Repo] = t
+		}
+	}
+	return last
+}
+
+// repoLastTouched returns when the repo was last worked in: its latest commit, checkout
+// (.git/HEAD), or spark-cli activity. A repo with uncommitted changes counts as touched now.
+func repoLastTouched(repoDir string, activity time.Time) time.Time {
+	if git.IsDirty(repoDir) {
+		return time.Now()
+	}
+	last := activity
+	if t, err := git.LastCommitTime(repoDir); err == nil && t.After(last) {
+		last = t
+	}
+	if info, err := os.Stat(filepath.Join(repoDir, ".git", "HEAD")); err == nil && info.ModTime().After(last) {
+		last = info.ModTime()
+	}
+	return last
+}
+
+// fileSize returns the size of the file at path, or 0 if it can't be read
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+func init() {
+	gcCmd.Flags().IntVar(&gcDays, "days", 30, "Prune logs, caches, and metrics older than this many days")
+	gcCmd.Flags().IntVar(&gcNodeModules, "node-modules", 0, "Also delete node_modules in repos untouched for this many days (0 = never)")
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "List what would be removed without removing it")
+	rootCmd.AddCommand(gcCmd)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/gradle"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+)
+
+// gradleEnv returns wsEnv plus the ORG_GRADLE_PROJECT_* properties for a repo's Gradle
+// build, and writes its local.properties keys. Repos without a Gradle build get wsEnv back.
+func gradleEnv(wsPath string, repo workspace.RepoDef, repoDir string, wsEnv map[string]string) (map[string]string, error) {
+	g := resolveGradleEnv(wsPath, repo, repoDir, wsEnv)
+	if g.dir == "" {
+		return wsEnv, nil
+	}
+	if g.localProperties != nil {
+		if err := gradle.WriteLocalProperties(g.dir, g.localProperties); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", gradle.LocalPropertiesFile, err)
+		}
+	}
+	if len(g.missing) > 0 {
+		fmt.Printf("  gradle: not in workspace env, skipped: %s\n", strings.Join(g.missing, ", "))
+	}
+	return g.env, nil
+}
+
+// gradleSetup is what a repo's Gradle build is given, resolved without writing anything
+type gradleSetup struct {
+	dir             string            // Gradle project dir; "" when the repo has no Gradle build
+	env             map[string]string // wsEnv plus ORG_GRADLE_PROJECT_* properties
+	localProperties map[string]string // local.properties keys to write; nil when there are none
+	missing         []string          // workspace env keys the gradle block names but aren't set
+}
+
+func resolveGradleEnv(wsPath string, repo workspace.RepoDef, repoDir string, wsEnv map[string]string) gradleSetup {
+	var def workspace.GradleDef
+	if repo.Gradle != nil {
+		def = *repo.Gradle
+	}
+	g := gradleSetup{dir: gradle.ProjectDir(repoDir, def.Dir), env: wsEnv}
+	if g.dir == "" {
+		return g
+	}
+
+	lookup := func(key string) (string, bool) {
+		v, ok := wsEnv[key]
+		if !ok {
+			v, ok = os.LookupEnv(key)
+		}
+		return resolveGradlePath(wsPath, v), ok
+	}
+
+	g.env = make(map[string]string, len(wsEnv)*2)
+	for k, v := range wsEnv {
+		g.env[k] = v
+	}
+	if def.Properties == nil {
+		for k := range wsEnv {
+			v, _ := lookup(k)
+			g.env[gradle.PropertyEnv(k)] = v
+		}
+	}
+	for prop, key := range def.Properties {
+		if v, ok := lookup(key); ok {
+			g.env[gradle.PropertyEnv(prop)] = v
+		} else {
+			g.missing = append(g.missing, key)
+		}
+	}
+
+	if len(def.LocalProperties) > 0 {
+		g.localProperties = make(map[string]string)
+		for prop, key := range def.LocalProperties {
+			if v, ok := lookup(key); ok {
+				g.localProperties[prop] = v
+			} else {
+				g.missing = append(g.missing, key)
+			}
+		}
+	}
+	sort.Strings(g.missing)
+	return g
+}
+
+// resolveGradlePath makes ./ and ~/ paths (keystores, SDK dirs) absolute so they work from
+// whichever directory Gradle runs in
+func resolveGradlePath(wsPath, v string) string {
+	switch {
+	case strings.HasPrefix(v, "./"):
+		return filepath.Join(wsPath, v)
+	case strings.HasPrefix(v, "~/"):
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, v[2:])
+		}
+	}
+	return v
+}
+
+// repoGradleDir returns a workspace repo's Gradle root, or "" when it has no Gradle build
+func repoGradleDir(wsPath string, ws *workspace.Workspace, name string) string {
+	repo := ws.Repos[name]
+	var dir string
+	if repo.Gradle != nil {
+		dir = repo.Gradle.Dir
+	}
+	return gradle.ProjectDir(filepath.Join(wsPath, repo.Path), dir)
+}
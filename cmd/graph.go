@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/spkconfig"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// graphExportVersion is bumped whenever the export's shape changes incompatibly
+const graphExportVersion = 1
+
+var graphExportFormat string
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the workspace's build graph for other tools (export)",
+	Long: `The graph spark-cli builds, links, and orders repos by, for tooling outside
+spark-cli — CI pipelines, docs, dashboards. 'spark-cli deps' shows the same graph
+for people.
+
+Examples:
+  spark-cli graph export --format json
+  spark-cli graph export > graph.json`,
+}
+
+var graphExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the build graph's nodes and edges as JSON",
+	Long: `Prints the workspace's build graph as JSON.
+
+Nodes:
+  repo      a workspace repo: path, remote, default branch, tags, whether it is
+            a Smithy model, and whether it is cloned
+  codegen   a model's codegen output: the model, the codegen, its directory
+            relative to the workspace, and whether it has been built
+  package   an npm package a repo consumes from a model
+
+Edges (from → to):
+  builds-before   dependency → dependent; "declared_in" says whether the edge
+                  comes from workspace.json, spk.config.json, or both
+  generates       model repo → codegen output
+  provides        codegen output → package
+  consumes        repo → package, with "linked" true when node_modules points
+                  at the workspace build instead of the published package
+
+"build_order" is the order 'spark-cli build --all' uses. Node ids are
+"<type>:<name>", e.g. "repo:AppAPI" or "package:@spark-rewards/app-client".
+
+Examples:
+  spark-cli graph export --format json
+  spark-cli graph export | jq '.edges[] | select(.type == "consumes")'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if graphExportFormat != "json" {
+			return errs.New(errs.KindUsage, "unsupported format '%s' (supported: json)", graphExportFormat)
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		graph, err := buildGraphExport(wsPath, ws)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(graph)
+	},
+}
+
+// graphExport is the document 'graph export' prints
+type graphExport struct {
+	Version    int         `json:"version"`
+	Workspace  string      `json:"workspace"`
+	BuildOrder []string    `json:"build_order"`
+	Nodes      []graphNode `json:"nodes"`
+	Edges      []graphEdge `json:"edges"`
+}
+
+// graphNode is a repo, codegen output, or package; fields that don't apply to its
+// type are left out
+type graphNode struct {
+	ID            string   `json:"id"`
+	Type          string   `json:"type"`
+	Name          string   `json:"name"`
+	Path          string   `json:"path,omitempty"`
+	Remote        string   `json:"remote,omitempty"`
+	DefaultBranch string   `json:"default_branch,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Model         bool     `json:"model,omitempty"`
+	Cloned        *bool    `json:"cloned,omitempty"`
+	Repo          string   `json:"repo,omitempty"`
+	Codegen       string   `json:"codegen,omitempty"`
+	Built         *bool    `json:"built,omitempty"`
+}
+
+// graphEdge points from → to; DeclaredIn is set on builds-before, Linked on consumes
+type graphEdge struct {
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Type       string   `json:"type"`
+	DeclaredIn []string `json:"declared_in,omitempty"`
+	Linked     *bool    `json:"linked,omitempty"`
+}
+
+// buildGraphExport collects the workspace's graph from workspace.json and each repo's
+// spk.config.json
+func buildGraphExport(wsPath string, ws *workspace.Workspace) (*graphExport, error) {
+	order, err := workspace.BuildOrder(wsPath, ws, nil)
+	if err != nil {
+		return nil, err
+	}
+	names, _ := expandRepoNames(ws, nil)
+
+	g := &graphExport{Version: graphExportVersion, Workspace: ws.Name, BuildOrder: order}
+	seen := make(map[string]bool)
+	addNode := func(n graphNode) {
+		if !seen[n.ID] {
+			seen[n.ID] = true
+			g.Nodes = append(g.Nodes, n)
+		}
+	}
+
+	for _, name := range names {
+		repo := ws.Repos[name]
+		dir := filepath.Join(wsPath, repo.Path)
+		cloned := fileExistsCheck(dir)
+		addNode(graphNode{
+			ID:            "repo:" + name,
+			Type:          "repo",
+			Name:          name,
+			Path:          repo.Path,
+			Remote:        repo.Remote,
+			DefaultBranch: getTargetBranch(ws, &repo, dir),
+			Tags:          repo.Tags,
+			Model:         isModelRepo(dir, repo),
+			Cloned:        &cloned,
+		})
+	}
+
+	for _, name := range names {
+		repo := ws.Repos[name]
+		dir := filepath.Join(wsPath, repo.Path)
+
+		declared := make(map[string][]string)
+		for _, dep := range repo.Dependencies {
+			declared[dep] = append(declared[dep], workspace.ManifestFile)
+		}
+		cfg, err := spkconfig.Load(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", name, spkconfig.ConfigFilename, err)
+		}
+		var consumes []spkconfig.ConsumesEntry
+		if cfg != nil {
+			consumes = cfg.Consumes
+		}
+		for _, c := range consumes {
+			if d := declared[c.Model]; len(d) == 0 || d[len(d)-1] != spkconfig.ConfigFilename {
+				declared[c.Model] = append(d, spkconfig.ConfigFilename)
+			}
+		}
+
+		for _, dep := range workspace.Dependencies(wsPath, ws, name) {
+			g.Edges = append(g.Edges, graphEdge{From: "repo:" + dep, To: "repo:" + name, Type: "builds-before", DeclaredIn: declared[dep]})
+		}
+
+		for _, c := range consumes {
+			model, ok := ws.Repos[c.Model]
+			if !ok || c.Package == "" {
+				continue
+			}
+			codegen := orDefault(c.Codegen, filepath.Base(npm.SmithyBuildPath))
+			modelDir := filepath.Join(wsPath, model.Path)
+			outDir := npm.BuildOutputDirForCodegen(modelDir, codegen)
+			relOut, err := filepath.Rel(wsPath, outDir)
+			if err != nil {
+				relOut = outDir
+			}
+			built := npm.IsBuiltForCodegen(modelDir, codegen)
+			linked := consumerLinked(wsPath, ws, name, c)
+
+			codegenID := "codegen:" + c.Model + "/" + codegen
+			packageID := "package:" + c.Package
+			addNode(graphNode{ID: codegenID, Type: "codegen", Name: codegen, Repo: c.Model, Codegen: codegen, Path: relOut, Built: &built})
+			addNode(graphNode{ID: packageID, Type: "package", Name: c.Package})
+			g.Edges = append(g.Edges,
+				graphEdge{From: "repo:" + c.Model, To: codegenID, Type: "generates"},
+				graphEdge{From: codegenID, To: packageID, Type: "provides"},
+				graphEdge{From: "repo:" + name, To: packageID, Type: "consumes", Linked: &linked},
+			)
+		}
+	}
+
+	g.Edges = dedupeGraphEdges(g.Edges)
+	return g, nil
+}
+
+// dedupeGraphEdges drops repeats (two consumers of one package each add its generates
+// and provides edges) and sorts edges so exports diff cleanly
+func dedupeGraphEdges(edges []graphEdge) []graphEdge {
+	seen := make(map[string]bool)
+	out := make([]graphEdge, 0, len(edges))
+	for _, e := range edges {
+		key := e.Type + " " + e.From + " " + e.To
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, e)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Type != out[j].Type {
+			return out[i].Type < out[j].Type
+		}
+		if out[i].From != out[j].From {
+			return out[i].From < out[j].From
+		}
+		return out[i].To < out[j].To
+	})
+	return out
+}
+
+func init() {
+	graphExportCmd.Flags().StringVar(&graphExportFormat, "format", "json", "Output format (json)")
+	graphCmd.AddCommand(graphExportCmd)
+	rootCmd.AddCommand(graphCmd)
+}
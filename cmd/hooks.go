@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// hookMarker identifies hook files spark-cli owns and may overwrite
+const hookMarker = "# Managed by spark-cli"
+
+var (
+	hooksRepos []string
+	hooksForce bool
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Install the workspace's git hooks in every repo (install)",
+	Long: `Keeps git hooks consistent across repos. Hooks are defined once in
+workspace.json as shell scripts keyed by hook name:
+
+  "hooks": {
+    "pre-commit": "npx --no-install lint-staged && gitleaks protect --staged",
+    "commit-msg": "grep -qE '^[A-Z]+-[0-9]+: ' \"$1\" || { echo 'commit message must start with a ticket (SPK-123: ...)'; exit 1; }",
+    "pre-push":   "npm test --if-present"
+  }
+
+'hooks install' writes them into each repo's hooks directory. Hooks that
+spark-cli didn't write are left alone unless --force is given, and hooks it
+wrote that are no longer in workspace.json are removed. Installed hooks are
+refreshed on every 'spark-cli workspace sync' (sync never adds new ones).
+
+Examples:
+  spark-cli hooks             # show which repos have the hooks installed
+  spark-cli hooks install
+  spark-cli hooks install --repos AppAPI --force`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		if len(ws.Hooks) == 0 {
+			fmt.Println("No hooks defined — add a \"hooks\" map to workspace.json (see 'spark-cli hooks --help')")
+			return nil
+		}
+		if err := checkHookNames(ws.Hooks); err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, hooksRepos)
+		if err != nil {
+			return err
+		}
+		hooks := sortedHookNames(ws.Hooks)
+		for _, name := range names {
+			dir, err := git.HooksDir(filepath.Join(wsPath, ws.Repos[name].Path))
+			if err != nil {
+				continue
+			}
+			var parts []string
+			for _, h := range hooks {
+				parts = append(parts, fmt.Sprintf("%s %s", hookStatusIcon(filepath.Join(dir, h), ws.Hooks[h]), h))
+			}
+			fmt.Printf("%-25s %s\n", name, strings.Join(parts, "  "))
+		}
+		return nil
+	},
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Write the workspace hooks into every repo",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		if len(ws.Hooks) == 0 {
+			return errs.New(errs.KindUsage, "no hooks defined — add a \"hooks\" map to workspace.json")
+		}
+		if err := checkHookNames(ws.Hooks); err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, hooksRepos)
+		if err != nil {
+			return err
+		}
+
+		var failed int
+		for _, name := range names {
+			installed, skipped, removed, err := installHooks(wsPath, ws, name, hooksForce, false)
+			switch {
+			case err != nil:
+				ui.Fail.Printf("%-25s %v\n", name, err)
+				failed++
+			case len(skipped) > 0:
+				ui.Skip.Printf("%-25s kept existing %s (use --force to replace)\n", name, strings.Join(skipped, ", "))
+			default:
+				ui.OK.Printf("%-25s %s\n", name, strings.Join(installed, ", "))
+			}
+			if len(removed) > 0 {
+				fmt.Printf("%-27s removed %s (no longer in workspace.json)\n", "", strings.Join(removed, ", "))
+			}
+		}
+		if failed > 0 {
+			return errs.New(errs.KindCommandFailed, "%d repo(s) failed", failed)
+		}
+		return nil
+	},
+}
+
+// installHooks writes the workspace hooks into one repo and removes the managed hooks
+// workspace.json no longer defines. Unmanaged hooks are skipped unless force is set;
+// with onlyManaged, only hooks spark-cli already manages are rewritten. Returns the
+// hooks written, skipped, and removed.
+func installHooks(wsPath string, ws *workspace.Workspace, name string, force, onlyManaged bool) (installed, skipped, removed []string, err error) {
+	if err := checkHookNames(ws.Hooks); err != nil {
+		return nil, nil, nil, err
+	}
+	dir, err := git.HooksDir(filepath.Join(wsPath, ws.Repos[name].Path))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, hook := range sortedHookNames(ws.Hooks) {
+		path := filepath.Join(dir, hook)
+		managed := isManagedHook(path)
+		if onlyManaged && !managed {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil && !force && !managed {
+			skipped = append(skipped, hook)
+			continue
+		}
+		if err := os.WriteFile(path, []byte(hookScript(ws.Hooks[hook])), 0755); err != nil {
+			return installed, skipped, removed, err
+		}
+		installed = append(installed, hook)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return installed, skipped, removed, err
+	}
+	for _, e := range entries {
+		if _, defined := ws.Hooks[e.Name()]; defined || e.IsDir() || !isManagedHook(filepath.Join(dir, e.Name())) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return installed, skipped, removed, err
+		}
+		removed = append(removed, e.Name())
+	}
+	return installed, skipped, removed, nil
+}
+
+// refreshHooks rewrites already-managed hooks after a sync so repos track workspace.json,
+// and removes the ones it no longer defines. Repos without managed hooks are untouched.
+func refreshHooks(wsPath string, ws *workspace.Workspace, names []string) {
+	for _, name := range names {
+		if _, _, _, err := installHooks(wsPath, ws, name, false, true); err != nil {
+			ui.Warn.Printf("%s: failed to refresh git hooks: %v\n", name, err)
+		}
+	}
+}
+
+// checkHookNames rejects hook names that aren't plain file names, so none can be
+// written outside the hooks directory
+func checkHookNames(hooks map[string]string) error {
+	for h := range hooks {
+		if h == "" || h == "." || h == ".." || strings.ContainsAny(h, `/\`) {
+			return errs.New(errs.KindUsage, "invalid hook name %q in workspace.json — use a git hook name like pre-commit", h)
+		}
+	}
+	return nil
+}
+
+// isManagedHook reports whether the hook file at path was written by spark-cli
+func isManagedHook(path string) bool {
+	existing, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(existing), hookMarker)
+}
+
+func hookScript(body string) string {
+	return fmt.Sprintf("#!/bin/sh\n%s (spark-cli hooks install) — edit \"hooks\" in workspace.json instead\n%s\n", hookMarker, body)
+}
+
+// hookStatusIcon is ✓ when the installed hook matches workspace.json, ✗ when missing or
+// stale, and ⏭ when an unmanaged hook is in the way
+func hookStatusIcon(path, body string) ui.Status {
+	existing, err := os.ReadFile(path)
+	switch {
+	case err != nil:
+		return ui.Fail
+	case !strings.Contains(string(existing), hookMarker):
+		return ui.Skip
+	case string(existing) != hookScript(body):
+		return ui.Fail
+	}
+	return ui.OK
+}
+
+func sortedHookNames(hooks map[string]string) []string {
+	names := make([]string, 0, len(hooks))
+	for h := range hooks {
+		names = append(names, h)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	hooksCmd.PersistentFlags().StringSliceVar(&hooksRepos, "repos", nil, "Limit to these repos (comma-separated)")
+	hooksInstallCmd.Flags().BoolVar(&hooksForce, "force", false, "Replace hooks spark-cli didn't install")
+	hooksCmd.AddCommand(hooksInstallCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
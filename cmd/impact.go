@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var impactJSON bool
+
+var impactCmd = &cobra.Command{
+	Use:   "impact <repo>",
+	Short: "List everything downstream of a repo and the order to roll a change out",
+	Long: `Walks the dependency graph from a repo (typically a model) to everything a
+change to it reaches: direct consumers, their consumers, and so on, plus the CDK
+stacks that deploy them.
+
+Repos are grouped into waves: every repo comes in a later wave than the affected
+repos it depends on, so releasing wave by wave never ships a consumer before
+what it consumes. The graph is the same one 'spark-cli deps' shows
+("dependencies" in workspace.json plus spk.config.json "consumes"). Stacks come
+from each repo's "cdk_stacks" in workspace.json:
+
+  "AppAPI": { ..., "cdk_stacks": ["PipelineStack/beta/AppAPIStack"] }
+
+Examples:
+  spark-cli impact AppModel
+  spark-cli impact AppModel --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		name := args[0]
+		if _, ok := ws.Repos[name]; !ok {
+			return errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
+		}
+		report, err := computeImpact(wsPath, ws, name)
+		if err != nil {
+			return err
+		}
+		if impactJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(report)
+		}
+		printImpact(report)
+		return nil
+	},
+}
+
+// impactReport is what a change to Repo reaches, in rollout order
+type impactReport struct {
+	Repo     string        `json:"repo"`
+	Affected []impactEntry `json:"affected"` // Repo first, then by wave
+	Stacks   []string      `json:"stacks"`   // in deploy order
+}
+
+// impactEntry is one affected repo
+type impactEntry struct {
+	Repo string `json:"repo"`
+	// Wave 0 is the changed repo; a repo's wave is one past the latest wave among the
+	// affected repos it depends on
+	Wave     int      `json:"wave"`
+	Via      []string `json:"via,omitempty"`      // affected repos it depends on
+	Packages []string `json:"packages,omitempty"` // packages it consumes from them
+	Stacks   []string `json:"stacks,omitempty"`
+}
+
+// computeImpact finds every repo that transitively depends on name and assigns rollout
+// waves
+func computeImpact(wsPath string, ws *workspace.Workspace, name string) (*impactReport, error) {
+	dependents := workspace.Dependents(wsPath, ws, []string{name})
+	affected := make(map[string]bool)
+	for _, n := range dependents {
+		affected[n] = true
+	}
+	order, err := workspace.BuildOrder(wsPath, ws, dependents)
+	if err != nil {
+		return nil, errs.New(errs.KindUsage, "%v", err)
+	}
+
+	report := &impactReport{Repo: name, Affected: []impactEntry{}, Stacks: []string{}}
+	wave := make(map[string]int)
+	for _, n := range order {
+		if !affected[n] {
+			continue
+		}
+		e := impactEntry{Repo: n, Stacks: ws.Repos[n].CDKStacks}
+		if n != name {
+			for _, dep := range workspace.Dependencies(wsPath, ws, n) {
+				if !affected[dep] {
+					continue
+				}
+				e.Via = append(e.Via, dep)
+				e.Wave = max(e.Wave, wave[dep]+1)
+			}
+			for _, c := range consumedModels(wsPath, ws, n) {
+				if slices.Contains(e.Via, c.Model) {
+					e.Packages = append(e.Packages, c.Package)
+				}
+			}
+		}
+		wave[n] = e.Wave
+		report.Affected = append(report.Affected, e)
+	}
+	sort.SliceStable(report.Affected, func(i, j int) bool {
+		a, b := report.Affected[i], report.Affected[j]
+		if a.Wave != b.Wave {
+			return a.Wave < b.Wave
+		}
+		return a.Repo < b.Repo
+	})
+	for _, e := range report.Affected {
+		for _, s := range e.Stacks {
+			if !slices.Contains(report.Stacks, s) {
+				report.Stacks = append(report.Stacks, s)
+			}
+		}
+	}
+	return report, nil
+}
+
+// printImpact prints the affected repos wave by wave, then the rollout and deploy order
+func printImpact(r *impactReport) {
+	if len(r.Affected) <= 1 {
+		fmt.Printf("Nothing in the workspace depends on %s\n", r.Repo)
+		if len(r.Stacks) > 0 {
+			fmt.Printf("Stacks to deploy: %s\n", strings.Join(r.Stacks, ", "))
+		}
+		return
+	}
+
+	fmt.Printf("A change to %s affects %d repo(s):\n", r.Repo, len(r.Affected)-1)
+	var waves [][]string
+	for _, e := range r.Affected {
+		if e.Wave == len(waves) {
+			waves = append(waves, nil)
+			if e.Wave > 0 {
+				fmt.Printf("\n  Wave %d\n", e.Wave)
+			}
+		}
+		waves[e.Wave] = append(waves[e.Wave], e.Repo)
+		if e.Wave == 0 {
+			continue
+		}
+		via := strings.Join(e.Via, ", ")
+		if len(e.Packages) > 0 {
+			via += " (consumes " + strings.Join(e.Packages, ", ") + ")"
+		}
+		fmt.Printf("    %-25s ← %s\n", e.Repo, via)
+		if len(e.Stacks) > 0 {
+			fmt.Printf("    %-25s   stacks: %s\n", "", strings.Join(e.Stacks, ", "))
+		}
+	}
+
+	parts := make([]string, len(waves))
+	for i, w := range waves {
+		parts[i] = strings.Join(w, ", ")
+	}
+	fmt.Printf("\nRollout order: %s\n", strings.Join(parts, " → "))
+	if len(r.Stacks) > 0 {
+		fmt.Printf("Stacks to deploy: %s\n", strings.Join(r.Stacks, " → "))
+	} else {
+		fmt.Println("No stacks mapped — add \"cdk_stacks\" to the affected repos in workspace.json")
+	}
+}
+
+func init() {
+	impactCmd.Flags().BoolVar(&impactJSON, "json", false, "Print the report as JSON")
+	rootCmd.AddCommand(impactCmd)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/devcontainer"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/retry"
+)
+
+// installTailLines is how much of an unrecognized failure's output is shown when the
+// install ran quietly
+const installTailLines = 10
+
+// stopInstallRetries lets an install be retried only when its output shows a network
+// failure; anything else (a rejected token, a peer-dep conflict, the wrong Node, a failing
+// postinstall) fails the same way when run again
+func stopInstallRetries(err error, out *bytes.Buffer) error {
+	if err == nil {
+		return nil
+	}
+	if d := npm.Diagnose(out.String()); d != nil && d.Transient {
+		return err
+	}
+	return retry.Permanent(err)
+}
+
+// explainInstallFailure prints the likely cause of a failed install and how to fix it.
+// Output the user didn't see (quiet installs) is shown in part when the cause isn't
+// recognized.
+func explainInstallFailure(w io.Writer, repoDir, output string, quiet bool) {
+	repo := filepath.Base(repoDir)
+	d := npm.Diagnose(output)
+	if d == nil {
+		if quiet {
+			for _, line := range npm.LastLines(output, installTailLines) {
+				fmt.Fprintf(w, "  │ %s\n", line)
+			}
+		}
+		return
+	}
+
+	var cause, fix string
+	switch d.Kind {
+	case npm.FailureAuth:
+		cause = "the registry rejected the token (401/403) — it may have expired or lack read:packages"
+		fix = "'spark-cli npm auth', then 'gh auth refresh -s read:packages' if it still fails"
+	case npm.FailureLockfile:
+		_, lockfile := npm.PackageManager(repoDir)
+		cause = lockfile + " is out of sync with package.json"
+		fix = fmt.Sprintf("run 'npm install' in %s and commit the updated %s", repo, lockfile)
+	case npm.FailurePeerDeps:
+		cause = "conflicting peer dependencies"
+		fix = fmt.Sprintf("align the versions in %s/package.json, or 'npm install --legacy-peer-deps' in %s", repo, repo)
+	case npm.FailureEngine:
+		cause = "this Node version doesn't satisfy the packages' engines"
+		if current := nodeVersion(); current != "" {
+			cause = "Node " + current + " doesn't satisfy the packages' engines"
+		}
+		fix = "switch Node and retry"
+		if pinned := devcontainer.NodeVersion(repoDir); pinned != "" {
+			fix = fmt.Sprintf("'nvm use' in %s (pinned to %s) and retry", repo, pinned)
+		} else if wanted := enginesNode(repoDir); wanted != "" {
+			fix = fmt.Sprintf("switch to Node %s (package.json engines) and retry", wanted)
+		}
+	case npm.FailureNetwork:
+		cause = "couldn't reach the registry"
+		fix = "check your connection or VPN, then retry"
+	}
+	fmt.Fprintf(w, "  cause: %s\n", cause)
+	if d.Detail != "" {
+		fmt.Fprintf(w, "         %s\n", d.Detail)
+	}
+	fmt.Fprintf(w, "  fix:   %s\n", fix)
+}
+
+// nodeVersion returns the active `node --version`, or "" if node can't be run
+func nodeVersion() string {
+	out, err := proc.Command("node", "--version").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// enginesNode returns package.json's engines.node range
+func enginesNode(repoDir string) string {
+	data, err := os.ReadFile(filepath.Join(repoDir, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Engines map[string]string `json:"engines"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+	return pkg.Engines["node"]
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+)
+
+// renamedCommand is an old invocation that still runs: args starting with old are
+// rewritten to the current command, with a deprecation warning. translate, if set,
+// builds the new args from the ones after old instead of appending them to now.
+type renamedCommand struct {
+	old       []string
+	now       []string
+	translate func(rest []string) []string
+}
+
+// renamedCommands are the commands that moved, and what they're called now
+var renamedCommands = []renamedCommand{
+	{old: []string{"create", "workspace"}, now: []string{"workspace", "create"}},
+	{old: []string{"init"}, now: []string{"workspace", "create"}},
+	{old: []string{"switch"}, now: []string{"workspace", "switch"}},
+	{old: []string{"configure"}, now: []string{"workspace", "configure"}},
+	{old: []string{"clone"}, now: []string{"use"}},
+	{old: []string{"sync"}, now: []string{"workspace", "sync"}, translate: translateLegacySync},
+}
+
+// legacyHint is an old invocation that can't be translated safely; it fails with a
+// suggestion instead of running something else
+type legacyHint struct {
+	old     []string
+	suggest string
+}
+
+// legacyHints are the shell-script-era invocations people still type
+var legacyHints = []legacyHint{
+	{old: []string{"sync.sh"}, suggest: "spark-cli workspace sync --env <env>"},
+	{old: []string{"./sync.sh"}, suggest: "spark-cli workspace sync --env <env>"},
+	{old: []string{"create"}, suggest: "spark-cli workspace create <path>"},
+	{old: []string{"install"}, suggest: "spark-cli workspace sync --install"},
+	{old: []string{"update"}, suggest: "spark-cli workspace sync --update"},
+	{old: []string{"pull"}, suggest: "spark-cli workspace sync"},
+}
+
+// upgradeLegacyArgs rewrites args that use a renamed command to the current one,
+// warning on stderr, and fails with a "did you mean" for old invocations it can't
+// translate. Current commands and user aliases (already expanded) are left alone.
+func upgradeLegacyArgs(args []string) ([]string, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isBuiltinCommand(args[0]) {
+		return args, nil
+	}
+	for _, r := range renamedCommands {
+		if !hasArgsPrefix(args, r.old) {
+			continue
+		}
+		rest := args[len(r.old):]
+		upgraded := append(slices.Clone(r.now), rest...)
+		if r.translate != nil {
+			upgraded = r.translate(rest)
+		}
+		ui.Warn.Fprintf(os.Stderr, binname.Rewrite("'spark-cli %s' is deprecated — running 'spark-cli %s'\n"), strings.Join(args, " "), strings.Join(upgraded, " "))
+		return upgraded, nil
+	}
+	for _, h := range legacyHints {
+		if hasArgsPrefix(args, h.old) {
+			return nil, errs.New(errs.KindUsage, "'spark-cli %s' is no longer a command — did you mean '%s'?", strings.Join(h.old, " "), h.suggest)
+		}
+	}
+	return args, nil
+}
+
+// translateLegacySync maps sync.sh's arguments onto workspace sync: sync.sh took the
+// environment to pull .env from ("sync beta"), where workspace sync takes a repo
+func translateLegacySync(rest []string) []string {
+	upgraded := []string{"workspace", "sync"}
+	if len(rest) > 0 && isLegacyEnvName(rest[0]) {
+		return append(append(upgraded, "--env", rest[0]), rest[1:]...)
+	}
+	return append(upgraded, rest...)
+}
+
+// isLegacyEnvName reports whether name is an environment rather than a repo: one in the
+// workspace's "environments", or a known AWS account name, that no repo is called
+func isLegacyEnvName(name string) bool {
+	if wsPath, err := workspace.Find(); err == nil {
+		if ws, err := workspace.Load(wsPath); err == nil {
+			if _, ok := ws.Repos[name]; ok {
+				return false
+			}
+			if _, ok := ws.Environments[name]; ok {
+				return true
+			}
+		}
+	}
+	return slices.ContainsFunc(aws.KnownSSOAccounts, func(a aws.SSOAccount) bool { return a.Name == name })
+}
+
+// hasArgsPrefix reports whether args start with prefix
+func hasArgsPrefix(args, prefix []string) bool {
+	return len(args) >= len(prefix) && slices.Equal(args[:len(prefix)], prefix)
+}
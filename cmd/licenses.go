@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/licenses"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	licensesRepos  []string
+	licensesFormat string
+	licensesOutput string
+)
+
+var licensesCmd = &cobra.Command{
+	Use:   "licenses",
+	Short: "Report the licenses of production dependencies across repos",
+	Long: `Collects the license of every production dependency in the workspace's Node
+and Gradle repos and prints one merged report, e.g. for app-store compliance.
+
+  Node:   read from package-lock.json (dev dependencies excluded), falling back
+          to the installed package.json
+  Gradle: runs './gradlew generateLicenseReport' and reads its JSON report —
+          the repo must apply com.github.jk1.dependency-license-report with
+          the JsonReportRenderer
+
+Dependencies shared by several repos are listed once with every repo that ships
+them. Progress goes to stderr so the report can be piped.
+
+Examples:
+  spark-cli licenses
+  spark-cli licenses --repos MobileApp --format json -o licenses.json
+  spark-cli licenses -o THIRD_PARTY_LICENSES.md`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if licensesFormat != "markdown" && licensesFormat != "json" {
+			return errs.New(errs.KindUsage, "invalid --format %q — use markdown or json", licensesFormat)
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, licensesRepos)
+		if err != nil {
+			return err
+		}
+		wsEnv := buildWorkspaceEnv(wsPath, ws)
+
+		perRepo := make(map[string][]licenses.Dependency)
+		var failed int
+		for _, name := range names {
+			repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+			var deps []licenses.Dependency
+			switch detectProjectType(repoDir) {
+			case projectTypeNode:
+				deps, err = licenses.Node(repoDir)
+			case projectTypeGradle:
+				repo := ws.Repos[name]
+				if err = runSyncCmd(repoDir, "./gradlew generateLicenseReport", &repo, wsEnv); err != nil {
+					err = fmt.Errorf("./gradlew generateLicenseReport failed: %w", err)
+					break
+				}
+				deps, err = licenses.Gradle(repoDir)
+			default:
+				ui.Skip.Fprintf(os.Stderr, "%-25s not a Node or Gradle repo\n", name)
+				continue
+			}
+			if err != nil {
+				ui.Fail.Fprintf(os.Stderr, "%-25s %v\n", name, err)
+				failed++
+				continue
+			}
+			ui.OK.Fprintf(os.Stderr, "%-25s %d dependencies\n", name, len(deps))
+			perRepo[name] = deps
+		}
+
+		merged := licenses.Merge(perRepo)
+		var report []byte
+		if licensesFormat == "json" {
+			if report, err = json.MarshalIndent(merged, "", "  "); err != nil {
+				return err
+			}
+			report = append(report, '\n')
+		} else {
+			report = []byte(licensesMarkdown(merged))
+		}
+
+		if licensesOutput != "" {
+			if err := os.WriteFile(licensesOutput, report, 0644); err != nil {
+				return err
+			}
+			ui.OK.Fprintf(os.Stderr, "Wrote %d dependencies to %s\n", len(merged), licensesOutput)
+		} else {
+			os.Stdout.Write(report)
+		}
+
+		if failed > 0 {
+			return errs.New(errs.KindCommandFailed, "%d repo(s) missing from the report", failed)
+		}
+		return nil
+	},
+}
+
+// licensesMarkdown renders a per-license summary followed by the full dependency table
+func licensesMarkdown(deps []licenses.Dependency) string {
+	var b strings.Builder
+	b.WriteString("# Third-party licenses\n\n")
+	if len(deps) == 0 {
+		b.WriteString("No dependencies found.\n")
+		return b.String()
+	}
+
+	counts := make(map[string]int)
+	for _, d := range deps {
+		counts[d.License]++
+	}
+	ids := make([]string, 0, len(counts))
+	for l := range counts {
+		ids = append(ids, l)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if counts[ids[i]] != counts[ids[j]] {
+			return counts[ids[i]] > counts[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	b.WriteString("| License | Packages |\n|---|---|\n")
+	for _, l := range ids {
+		fmt.Fprintf(&b, "| %s | %d |\n", l, counts[l])
+	}
+
+	b.WriteString("\n| Package | Version | License | Repos |\n|---|---|---|---|\n")
+	for _, d := range deps {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", d.Name, d.Version, d.License, strings.Join(d.Repos, ", "))
+	}
+	return b.String()
+}
+
+func init() {
+	licensesCmd.Flags().StringSliceVar(&licensesRepos, "repos", nil, "Limit to these repos (comma-separated)")
+	licensesCmd.Flags().StringVar(&licensesFormat, "format", "markdown", "Report format: markdown or json")
+	licensesCmd.Flags().StringVarP(&licensesOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	rootCmd.AddCommand(licensesCmd)
+}
@@ -0,0 +1,446 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/buildcache"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/gradle"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/settings"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/spkconfig"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var linkFresh bool
+
+var linkCmd = &cobra.Command{
+	Use:   "link",
+	Short: "Choose per consumer whether it links local model builds (enable | disable)",
+	Long: `With no subcommand, lists each consumer's models and whether it links the
+model built in the workspace or uses the published package.
+
+link_mode (see 'spark-cli config') decides for every consumer at once. enable
+and disable override it for one consumer, so e.g. AppAPI can keep the published
+SDK while MobileApp uses your local model. The choice is kept in .spk/state.json
+and respected wherever spark-cli links models (sync --rebuild-linked).
+
+Each link records the model's commit and build time; 'spark-cli link status'
+shows them and warns when the model has moved on since.
+
+A "consumes" package written as a Maven module ("group:artifact") is linked on
+the Gradle side: an init script spark-cli keeps in ~/.gradle/init.d substitutes
+the module with an included build of the model (its codegen output when that is
+a Gradle build, else the model repo's Gradle build), for that consumer only and
+whichever way Gradle is run. Disabling the link restores the published version.
+
+Examples:
+  spark-cli link
+  spark-cli link disable AppAPI       # back to the published package
+  spark-cli link enable MobileApp     # link the workspace's model build
+  spark-cli link reset AppAPI         # follow link_mode again`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printLinkStatus()
+	},
+}
+
+var linkStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show each consumer's links and the model commit they were built from",
+	Long: `Lists each consumer's models: whether it links the model built in the
+workspace, and for links, the model commit and build time recorded when the link
+was made. A link whose model has moved to another commit since is flagged — the
+consumer is running code that no longer matches the model's source.
+
+Examples:
+  spark-cli link status`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printLinkStatus()
+	},
+}
+
+var linkEnableCmd = &cobra.Command{
+	Use:   "enable <consumer>...",
+	Short: "Link a consumer to the models built in the workspace",
+	Long: `Records that the consumer links models built in the workspace and symlinks
+(or, for Maven modules, substitutes in Gradle) each model it consumes that has
+been built. Models that haven't been built are
+linked the next time spark-cli links them.
+
+A model build older than a tracked source file in the model repo is linked
+with a warning, since the consumer won't see those changes; --fresh rebuilds
+the model first instead.
+
+Examples:
+  spark-cli link enable MobileApp
+  spark-cli link enable MobileApp --fresh`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setConsumerLinks(args, true)
+	},
+}
+
+var linkDisableCmd = &cobra.Command{
+	Use:   "disable <consumer>...",
+	Short: "Make a consumer use the published packages",
+	Long: `Records that the consumer uses the published packages and removes its
+node_modules symlinks and Gradle substitutions for workspace models. Run 'npm
+install' in the repo (or 'spark-cli workspace sync --install') to restore the
+published npm packages; Gradle resolves the published version on its next build.
+
+Examples:
+  spark-cli link disable AppAPI`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setConsumerLinks(args, false)
+	},
+}
+
+var linkResetCmd = &cobra.Command{
+	Use:   "reset <consumer>...",
+	Short: "Drop a consumer's enable/disable so it follows link_mode",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		if err := checkConsumers(wsPath, ws, args); err != nil {
+			return err
+		}
+		err = state.Update(wsPath, func(s *state.State) error {
+			for _, name := range args {
+				delete(s.Links, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		mode := settings.Load(ws).String(settings.LinkMode)
+		for _, name := range args {
+			ui.OK.Printf("%s follows link_mode (%s)\n", name, mode)
+		}
+		return nil
+	},
+}
+
+// linkEnabled reports whether consumer should link models built in the workspace, and
+// why: its own enable/disable, else link_mode
+func linkEnabled(ws *workspace.Workspace, st *state.State, consumer string) (bool, string) {
+	if enabled, ok := st.Links[consumer]; ok {
+		if enabled {
+			return true, "link enable"
+		}
+		return false, "link disable"
+	}
+	mode := settings.Load(ws).String(settings.LinkMode)
+	return mode != settings.LinkPublished, "link_mode " + mode
+}
+
+// consumedModels returns the workspace models a repo consumes
+func consumedModels(wsPath string, ws *workspace.Workspace, name string) []spkconfig.ConsumesEntry {
+	cfg, err := spkconfig.Load(filepath.Join(wsPath, ws.Repos[name].Path))
+	if err != nil || cfg == nil {
+		return nil
+	}
+	var out []spkconfig.ConsumesEntry
+	for _, c := range cfg.Consumes {
+		if _, ok := ws.Repos[c.Model]; ok && c.Package != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// checkConsumers makes sure every name is a workspace repo that consumes a model
+func checkConsumers(wsPath string, ws *workspace.Workspace, names []string) error {
+	for _, name := range names {
+		if _, ok := ws.Repos[name]; !ok {
+			return errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
+		}
+		if len(consumedModels(wsPath, ws, name)) == 0 {
+			return errs.New(errs.KindUsage, "%s doesn't consume a workspace model (no \"consumes\" in its %s)", name, spkconfig.ConfigFilename)
+		}
+	}
+	return nil
+}
+
+// setConsumerLinks records the consumers' choice and links or unlinks them to match
+func setConsumerLinks(names []string, enabled bool) error {
+	wsPath, ws, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+	if err := checkConsumers(wsPath, ws, names); err != nil {
+		return err
+	}
+	err = state.Update(wsPath, func(s *state.State) error {
+		if s.Links == nil {
+			s.Links = make(map[string]bool)
+		}
+		for _, name := range names {
+			s.Links[name] = enabled
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	rebuilt := make(map[string]bool)
+	for _, name := range names {
+		usesNpm := false
+		for _, c := range consumedModels(wsPath, ws, name) {
+			if !enabled {
+				if !consumerLinked(wsPath, ws, name, c) {
+					continue
+				}
+				if err := unlinkModel(wsPath, ws, name, c); err != nil {
+					ui.Fail.Printf("%s → %s: %v\n", name, c.Package, err)
+					failed++
+					continue
+				}
+				usesNpm = usesNpm || !gradle.IsModule(c.Package)
+				fmt.Printf("  unlinked %s from %s\n", c.Package, name)
+				continue
+			}
+			if _, built := modelBuildDir(wsPath, ws, c); !built {
+				ui.Skip.Printf(binname.Rewrite("%s → %s: %s isn't built yet — 'spark-cli build %s'\n"), name, c.Package, c.Model, c.Model)
+				continue
+			}
+			if source, stale := modelBuildStale(wsPath, ws, c); stale && !rebuilt[c.Model] {
+				if !linkFresh {
+					fmt.Printf("  %s %s's build is older than %s — linking it anyway; --fresh rebuilds it first\n", ui.Warn, c.Model, source)
+				} else {
+					if err := buildRepo(wsPath, ws, c.Model, buildSyncEnv(wsPath, ws)); err != nil {
+						ui.Fail.Printf("%s → %s: rebuilding %s failed: %v\n", name, c.Package, c.Model, err)
+						failed++
+						continue
+					}
+					rebuilt[c.Model] = true
+				}
+			}
+			pin, err := linkModel(wsPath, ws, name, c)
+			if err != nil {
+				ui.Fail.Printf("%s → %s: %v\n", name, c.Package, err)
+				failed++
+				continue
+			}
+			fmt.Printf("  🔗 %s → %s (%s @ %s)\n", name, c.Package, c.Model, shortCommit(pin.Commit))
+		}
+		switch {
+		case enabled:
+			ui.OK.Printf("%s links models built in the workspace\n", name)
+		case usesNpm:
+			ui.OK.Printf("%s uses published packages — 'npm install' in %s restores them\n", name, ws.Repos[name].Path)
+		default:
+			ui.OK.Printf("%s uses published packages\n", name)
+		}
+	}
+	if failed > 0 {
+		return errs.New(errs.KindCommandFailed, "%d link(s) failed", failed)
+	}
+	return nil
+}
+
+// printLinkStatus lists each consumer's models, how it uses them, and what its links
+// were built from
+func printLinkStatus() error {
+	wsPath, ws, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+	st, err := state.Load(wsPath)
+	if err != nil {
+		return err
+	}
+	names, _ := expandRepoNames(ws, nil)
+	found := false
+	for _, name := range names {
+		consumes := consumedModels(wsPath, ws, name)
+		if len(consumes) == 0 {
+			continue
+		}
+		found = true
+		enabled, why := linkEnabled(ws, st, name)
+		mode := "published"
+		if enabled {
+			mode = "link"
+		}
+		fmt.Printf("%s  (%s, %s)\n", name, mode, why)
+		for _, c := range consumes {
+			if !consumerLinked(wsPath, ws, name, c) {
+				fmt.Printf("  %-40s published\n", c.Package)
+				continue
+			}
+			pin, ok := st.LinkPins[name][c.Package]
+			if !ok {
+				fmt.Printf(binname.Rewrite("  %-40s 🔗 local %s (build not recorded — relink with 'spark-cli link enable %s')\n"), c.Package, c.Model, name)
+				continue
+			}
+			built := ""
+			if t, err := time.Parse(time.RFC3339, pin.BuiltAt); err == nil {
+				built = ", built " + formatAge(time.Since(t))
+			}
+			if sub, ok := gradle.LinkedTo(repoGradleDir(wsPath, ws, name), c.Package); ok && sub.Version != "" {
+				fmt.Printf("  %-40s 📦 mavenLocal %s (publish-local of %s)\n", c.Package, sub.Version, c.Model)
+			} else {
+				fmt.Printf("  %-40s 🔗 local %s @ %s%s\n", c.Package, c.Model, shortCommit(pin.Commit), built)
+			}
+			if head, moved := linkPinMoved(wsPath, ws, pin); moved {
+				fmt.Printf(binname.Rewrite("  %s %s is at %s now — rebuild it and relink: 'spark-cli build %s && spark-cli link enable %s'\n"), ui.Warn, c.Model, shortCommit(head), c.Model, name)
+			} else if source, stale := modelBuildStale(wsPath, ws, c); stale {
+				fmt.Printf(binname.Rewrite("  %s %s's build is older than %s — relink a fresh build: 'spark-cli link enable %s --fresh'\n"), ui.Warn, c.Model, source, name)
+			}
+		}
+	}
+	if !found {
+		fmt.Println("No repo consumes a workspace model (spk.config.json \"consumes\")")
+	}
+	return nil
+}
+
+// modelBuildDir returns the model build c links to and whether it's ready to link. npm
+// packages link the codegen output; Maven modules ("group:artifact") include the codegen
+// output when it's a Gradle build, else the model's own Gradle build, which Gradle builds
+// when the consumer needs it.
+func modelBuildDir(wsPath string, ws *workspace.Workspace, c spkconfig.ConsumesEntry) (string, bool) {
+	modelDir := filepath.Join(wsPath, ws.Repos[c.Model].Path)
+	if !gradle.IsModule(c.Package) {
+		codegen := orDefault(c.Codegen, filepath.Base(npm.SmithyBuildPath))
+		return npm.BuildOutputDirForCodegen(modelDir, codegen), npm.IsBuiltForCodegen(modelDir, codegen)
+	}
+	if c.Codegen != "" {
+		if dir := npm.BuildOutputDirForCodegen(modelDir, c.Codegen); gradle.IsProject(dir) {
+			return dir, true
+		}
+	}
+	if dir := repoGradleDir(wsPath, ws, c.Model); dir != "" {
+		return dir, true
+	}
+	return "", false
+}
+
+// modelBuildStale reports whether a tracked source file of c's model changed after the
+// build c links to, and which file. A model's own Gradle build is never stale: Gradle
+// builds it from source when the consumer needs it.
+func modelBuildStale(wsPath string, ws *workspace.Workspace, c spkconfig.ConsumesEntry) (string, bool) {
+	buildDir, built := modelBuildDir(wsPath, ws, c)
+	if !built || buildDir == repoGradleDir(wsPath, ws, c.Model) {
+		return "", false
+	}
+	builtAt := buildcache.NewestModTime(buildDir)
+	model := ws.Repos[c.Model]
+	source, changed, ok := buildcache.NewestSource(filepath.Join(wsPath, model.Path), model.CacheOutputs)
+	if !ok || builtAt.IsZero() || !changed.After(builtAt) {
+		return "", false
+	}
+	return source, true
+}
+
+// consumerLinked reports whether consumer uses the workspace's build of c: a
+// node_modules symlink for npm packages, a Gradle substitution for Maven modules
+func consumerLinked(wsPath string, ws *workspace.Workspace, consumer string, c spkconfig.ConsumesEntry) bool {
+	if gradle.IsModule(c.Package) {
+		dir := repoGradleDir(wsPath, ws, consumer)
+		return dir != "" && gradle.IsLinked(dir, c.Package)
+	}
+	return npm.IsLinked(filepath.Join(wsPath, ws.Repos[consumer].Path), c.Package)
+}
+
+// linkModel points consumer's dependency on c at the model's local build (a node_modules
+// symlink, or a Gradle substitution for Maven modules) and records the model commit and
+// build it points at
+func linkModel(wsPath string, ws *workspace.Workspace, consumer string, c spkconfig.ConsumesEntry) (state.LinkPin, error) {
+	modelDir := filepath.Join(wsPath, ws.Repos[c.Model].Path)
+	buildDir, built := modelBuildDir(wsPath, ws, c)
+	if !built {
+		return state.LinkPin{}, fmt.Errorf("%s isn't built yet", c.Model)
+	}
+	pin := state.LinkPin{Model: c.Model, LinkedAt: time.Now().UTC().Format(time.RFC3339)}
+	if gradle.IsModule(c.Package) {
+		consumerDir := repoGradleDir(wsPath, ws, consumer)
+		if consumerDir == "" {
+			return state.LinkPin{}, fmt.Errorf("%s has no Gradle build", consumer)
+		}
+		if err := gradle.Link(consumerDir, c.Package, buildDir); err != nil {
+			return state.LinkPin{}, err
+		}
+	} else {
+		if err := npm.DirectLink(filepath.Join(wsPath, ws.Repos[consumer].Path), c.Package, buildDir); err != nil {
+			return state.LinkPin{}, err
+		}
+		if info, err := os.Stat(filepath.Join(buildDir, "package.json")); err == nil {
+			pin.BuiltAt = info.ModTime().UTC().Format(time.RFC3339)
+		}
+	}
+
+	pin.Commit, _ = git.HeadSHA(modelDir)
+	err := state.Update(wsPath, func(s *state.State) error {
+		s.SetLinkPin(consumer, c.Package, pin)
+		return nil
+	})
+	return pin, err
+}
+
+// unlinkModel removes consumer's link to c and forgets what it pointed at
+func unlinkModel(wsPath string, ws *workspace.Workspace, consumer string, c spkconfig.ConsumesEntry) error {
+	var err error
+	if gradle.IsModule(c.Package) {
+		if dir := repoGradleDir(wsPath, ws, consumer); dir != "" {
+			err = gradle.Unlink(dir, c.Package)
+		}
+	} else {
+		err = npm.Unlink(filepath.Join(wsPath, ws.Repos[consumer].Path), c.Package)
+	}
+	if err != nil {
+		return err
+	}
+	return state.Update(wsPath, func(s *state.State) error {
+		s.RemoveLinkPin(consumer, c.Package)
+		return nil
+	})
+}
+
+// linkPinMoved returns the model's HEAD and whether it differs from the commit the link
+// was made at
+func linkPinMoved(wsPath string, ws *workspace.Workspace, pin state.LinkPin) (string, bool) {
+	model, ok := ws.Repos[pin.Model]
+	if !ok || pin.Commit == "" {
+		return "", false
+	}
+	head, err := git.HeadSHA(filepath.Join(wsPath, model.Path))
+	if err != nil {
+		return "", false
+	}
+	return head, head != pin.Commit
+}
+
+// shortCommit abbreviates a commit SHA for display
+func shortCommit(sha string) string {
+	if sha == "" {
+		return "unknown commit"
+	}
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func init() {
+	linkEnableCmd.Flags().BoolVar(&linkFresh, "fresh", false, "Rebuild models whose build is older than their sources before linking")
+	linkCmd.AddCommand(linkStatusCmd, linkEnableCmd, linkDisableCmd, linkResetCmd)
+	rootCmd.AddCommand(linkCmd)
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/buildcache"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/metrics"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// listBehindWarn is how many commits behind the default branch a repo can fall before
+// list flags it
+const listBehindWarn = 20
+
+var listRepos []string
+
+// repoHealth is one row of the list table
+type repoHealth struct {
+	name      string
+	branch    string
+	committed string
+	synced    string
+	behind    string
+	deps      string
+	build     string
+	problems  int
+	// node is set for Node repos, whose DEPS column checkRepoDeps fills in
+	node bool
+}
+
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List repos with what needs attention (behind, stale deps/builds)",
+	Long: `Lists the workspace repos with, for each:
+
+  BRANCH   the checked-out branch
+  COMMIT   age of the last commit on it
+  SYNCED   when 'spark-cli sync' last succeeded for it
+  BEHIND   commits the default branch is ahead (as of the last fetch);
+           flagged at ` + strconv.Itoa(listBehindWarn) + ` or more
+  DEPS     whether node_modules is missing or older than the lockfile
+  BUILD    whether a tracked source file changed after the newest build
+           output (cache_outputs, default smithy/build and dist)
+
+Rows marked ⚠ need attention — usually 'spark-cli sync' or 'spark-cli build'.
+
+Examples:
+  spark-cli list
+  spark-cli ls --repos AppAPI,AppModel`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := expandRepoNames(ws, listRepos)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			fmt.Println(binname.Rewrite("No repos — run 'spark-cli use <repo>' to add one"))
+			return nil
+		}
+
+		lastSync := lastSuccessByRepo(wsPath, "sync")
+		fmt.Printf("  %-20s %-28s %-10s %-10s %-8s %-24s %s\n", "REPO", "BRANCH", "COMMIT", "SYNCED", "BEHIND", "DEPS", "BUILD")
+		rows := make([]repoHealth, len(names))
+		forEachRepo(ws, names, func(i int, name string) {
+			rows[i] = checkRepoHealth(wsPath, ws, name, lastSync[name])
+		})
+		attention := 0
+		for _, r := range rows {
+			checkRepoDeps(wsPath, ws, &r)
+			mark := ui.OK
+			if r.problems > 0 {
+				mark = ui.Warn
+				attention++
+			}
+			fmt.Printf("%s %-20s %-28s %-10s %-10s %s %s %s\n", mark, r.name, r.branch, r.committed, r.synced,
+				ui.Pad(r.behind, 8), ui.Pad(r.deps, 24), r.build)
+		}
+		if attention > 0 {
+			fmt.Printf("\n%d repo(s) need attention\n", attention)
+		}
+		return nil
+	},
+}
+
+// checkRepoHealth gathers the list columns for one repo except DEPS. It only reads, so
+// repos can be checked in parallel.
+func checkRepoHealth(wsPath string, ws *workspace.Workspace, name string, synced time.Time) repoHealth {
+	repo := ws.Repos[name]
+	repoDir := filepath.Join(wsPath, repo.Path)
+	r := repoHealth{name: name, branch: "-", committed: "-", synced: "never", behind: "-", deps: "-", build: "-"}
+	if !synced.IsZero() {
+		r.synced = formatAge(time.Since(synced))
+	}
+	if _, err := os.Stat(repoDir); os.IsNotExist(err) {
+		r.branch = "missing"
+		r.problems++
+		return r
+	}
+	if !git.IsRepo(repoDir) {
+		r.branch = "(not git)"
+		return r
+	}
+
+	snap, err := git.TakeSnapshot(repoDir)
+	if err != nil {
+		r.branch = "(unreadable)"
+		r.problems++
+		return r
+	}
+	r.branch = orDefault(snap.Branch, "(detached)")
+
+	// One for-each-ref answers both the commit age and whether origin has the target
+	target := getTargetBranch(ws, &repo, repoDir)
+	local, remote := "refs/heads/"+snap.Branch, "refs/remotes/origin/"+target
+	times := git.RefTimes(repoDir, local, remote)
+	if t, ok := times[local]; ok {
+		r.committed = formatAge(time.Since(t))
+	} else if t, err := git.LastCommitTime(repoDir); err == nil {
+		r.committed = formatAge(time.Since(t))
+	}
+	if _, ok := times[remote]; ok {
+		behind := snap.Behind
+		if snap.Upstream != "origin/"+target {
+			_, behind = git.AheadBehind(repoDir, "HEAD", "origin/"+target)
+		}
+		r.behind = strconv.Itoa(behind)
+		if behind >= listBehindWarn {
+			r.behind = ui.Warn.String() + " " + r.behind
+			r.problems++
+		}
+	}
+	r.node = detectProjectType(repoDir) == projectTypeNode
+
+	if stale, ok := buildcache.Stale(repoDir, repo.CacheOutputs); ok {
+		r.build = "fresh"
+		if stale {
+			r.build = ui.Warn.String() + " stale"
+			r.problems++
+		}
+	}
+	return r
+}
+
+// checkRepoDeps fills in the DEPS column of a Node repo. npmInstallNeeded may update
+// .spk/state.json, so this runs for one repo at a time.
+func checkRepoDeps(wsPath string, ws *workspace.Workspace, r *repoHealth) {
+	if !r.node {
+		return
+	}
+	r.deps = "ok"
+	if reason := npmInstallNeeded(wsPath, r.name, filepath.Join(wsPath, ws.Repos[r.name].Path)); reason != "" {
+		r.deps = ui.Warn.String() + " " + reason
+		r.problems++
+	}
+}
+
+// lastSuccessByRepo returns when op last succeeded for each repo, from the metrics log
+func lastSuccessByRepo(wsPath, op string) map[string]time.Time {
+	entries, _ := metrics.Load(wsPath)
+	last := make(map[string]time.Time)
+	for _, e := range entries {
+		if e.Op != op || !e.OK {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, e.Time); err == nil && t.After(last[e.Repo]) {
+			last[e.Repo] = t
+		}
+	}
+	return last
+}
+
+func init() {
+	listCmd.Flags().StringSliceVar(&listRepos, "repos", nil, "Only list these repos")
+	rootCmd.AddCommand(listCmd)
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/docker"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// localStackImage is the LocalStack community image
+const localStackImage = "localstack/localstack"
+
+// localStackReadyTimeout bounds how long `local up` waits for the health endpoint
+const localStackReadyTimeout = 90 * time.Second
+
+var (
+	localServices []string
+	localSeed     string
+)
+
+var localCmd = &cobra.Command{
+	Use:   "local",
+	Short: "Run AWS services locally with LocalStack (up | down)",
+	Long: `Manages a LocalStack container so APIs can be developed without touching the
+beta account.
+
+'local up' starts LocalStack and adds a "local" environment to workspace.json:
+
+  "environments": {
+    "local": { "type": "local", "endpoint": "http://localhost:4566" }
+  }
+
+Switching to it ('spark-cli switch-env local') reads SSM parameters from
+LocalStack and writes the endpoints into the workspace .env, so SDK clients
+(SSM, DynamoDB, Lambda, ...) talk to LocalStack:
+
+  AWS_ENDPOINT_URL, AWS_ENDPOINT_URL_SSM, AWS_ENDPOINT_URL_DYNAMODB,
+  AWS_ENDPOINT_URL_LAMBDA, AWS_ACCESS_KEY_ID=test, AWS_SECRET_ACCESS_KEY=test
+
+Examples:
+  spark-cli local              # is LocalStack running?
+  spark-cli local up --seed beta
+  spark-cli switch-env local
+  spark-cli local down`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		name := localStackContainer(ws)
+		envName, endpoint := localEnv(ws)
+		if !containerRunning(name) {
+			fmt.Println(binname.Rewrite("LocalStack is not running — start it with 'spark-cli local up'"))
+			return nil
+		}
+		ui.OK.Printf("LocalStack running (%s) at %s\n", name, endpoint)
+		if services, err := localStackHealth(endpoint); err == nil {
+			fmt.Printf("  Services: %s\n", strings.Join(services, ", "))
+		}
+		if envName != "" && activeEnv(ws, "") != envName {
+			fmt.Printf(binname.Rewrite("  Workspace env is %s — 'spark-cli switch-env %s' to use LocalStack\n"), activeEnv(ws, ""), envName)
+		}
+		return nil
+	},
+}
+
+var localUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Start LocalStack and register the local environment",
+	Long: `Starts the LocalStack container (or reuses a running one), waits until it's
+healthy, and adds a "local" environment to workspace.json if there isn't one.
+
+--seed copies the workspace's SSM parameters from a real environment into
+LocalStack so 'switch-env local' yields a working .env.
+
+Examples:
+  spark-cli local up
+  spark-cli local up --seed beta
+  spark-cli local up --services ssm,dynamodb,lambda,s3,sqs`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := docker.CheckCLI(); err != nil {
+			return err
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+
+		envName, endpoint := localEnv(ws)
+		if envName == "" {
+			envName, endpoint = workspace.EnvTypeLocal, workspace.DefaultLocalEndpoint
+			if ws.Environments == nil {
+				ws.Environments = make(map[string]workspace.EnvironmentDef)
+			}
+			ws.Environments[envName] = workspace.EnvironmentDef{Type: workspace.EnvTypeLocal, Region: ws.AWSRegion}
+			if err := workspace.Save(wsPath, ws); err != nil {
+				return err
+			}
+			ui.OK.Printf("Added environment %q to workspace.json\n", envName)
+		}
+
+		name := localStackContainer(ws)
+		switch {
+		case containerRunning(name):
+			fmt.Printf("LocalStack already running (%s)\n", name)
+		case proc.Command("docker", "start", name).Run() == nil:
+			fmt.Printf("Restarted %s\n", name)
+		default:
+			port := "4566"
+			if u, err := url.Parse(endpoint); err == nil && u.Port() != "" {
+				port = u.Port()
+			}
+			out, err := proc.Command("docker", "run", "-d", "--name", name,
+				"-p", port+":4566",
+				"-e", "SERVICES="+strings.Join(localServices, ","),
+				"-v", "/var/run/docker.sock:/var/run/docker.sock", // Lambda runs functions in sibling containers
+				localStackImage).CombinedOutput()
+			if err != nil {
+				return errs.New(errs.KindCommandFailed, "failed to start LocalStack: %s", strings.TrimSpace(string(out)))
+			}
+			fmt.Printf("Started %s\n", name)
+		}
+
+		fmt.Printf("Waiting for LocalStack at %s...\n", endpoint)
+		services, err := waitForLocalStack(endpoint)
+		if err != nil {
+			return err
+		}
+		ui.OK.Printf("LocalStack ready: %s\n", strings.Join(services, ", "))
+
+		if localSeed != "" {
+			if err := seedLocalStack(ws, localSeed, envName, endpoint); err != nil {
+				return err
+			}
+		}
+
+		if activeEnv(ws, "") != envName {
+			fmt.Printf(binname.Rewrite("\nNext: spark-cli switch-env %s\n"), envName)
+		}
+		return nil
+	},
+}
+
+var localDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Stop and remove the LocalStack container",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := docker.CheckCLI(); err != nil {
+			return err
+		}
+		_, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		name := localStackContainer(ws)
+		if out, err := proc.Command("docker", "rm", "-f", name).CombinedOutput(); err != nil {
+			if strings.Contains(string(out), "No such container") {
+				fmt.Println("LocalStack is not running")
+				return nil
+			}
+			return errs.New(errs.KindCommandFailed, "failed to remove %s: %s", name, strings.TrimSpace(string(out)))
+		}
+		ui.OK.Printf("Removed %s\n", name)
+		if ws.LocalEndpoint(activeEnv(ws, "")) != "" {
+			fmt.Println(binname.Rewrite("  The workspace env still points at LocalStack — 'spark-cli switch-env beta' to go back"))
+		}
+		return nil
+	},
+}
+
+// localEnv returns the workspace's local environment and its endpoint, or "" if none
+func localEnv(ws *workspace.Workspace) (string, string) {
+	var names []string
+	for name := range ws.Environments {
+		if ws.LocalEndpoint(name) != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", workspace.DefaultLocalEndpoint
+	}
+	sort.Strings(names)
+	return names[0], ws.LocalEndpoint(names[0])
+}
+
+// localEndpointEnv points AWS SDK clients and the CLI at LocalStack
+func localEndpointEnv(endpoint string) map[string]string {
+	return map[string]string{
+		"AWS_ENDPOINT_URL":          endpoint,
+		"AWS_ENDPOINT_URL_SSM":      endpoint,
+		"AWS_ENDPOINT_URL_DYNAMODB": endpoint,
+		"AWS_ENDPOINT_URL_LAMBDA":   endpoint,
+		"AWS_ACCESS_KEY_ID":         "test",
+		"AWS_SECRET_ACCESS_KEY":     "test",
+	}
+}
+
+func localStackContainer(ws *workspace.Workspace) string {
+	return "spark-localstack-" + composeNameRe.ReplaceAllString(strings.ToLower(ws.Name), "-")
+}
+
+func containerRunning(name string) bool {
+	out, err := proc.Command("docker", "inspect", "-f", "{{.State.Running}}", name).Output()
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// localStackHealth returns the services LocalStack reports as available or running
+func localStackHealth(endpoint string) ([]string, error) {
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get(strings.TrimSuffix(endpoint, "/") + "/_localstack/health")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var health struct {
+		Services map[string]string `json:"services"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return nil, err
+	}
+	var up []string
+	for svc, status := range health.Services {
+		if status == "available" || status == "running" {
+			up = append(up, svc)
+		}
+	}
+	sort.Strings(up)
+	return up, nil
+}
+
+func waitForLocalStack(endpoint string) ([]string, error) {
+	deadline := time.Now().Add(localStackReadyTimeout)
+	for {
+		if services, err := localStackHealth(endpoint); err == nil {
+			return services, nil
+		}
+		if proc.Interrupted() {
+			return nil, proc.Context().Err()
+		}
+		if time.Now().After(deadline) {
+			return nil, errs.New(errs.KindTimeout, "LocalStack didn't become healthy within %s — check 'docker logs'", localStackReadyTimeout)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// seedLocalStack copies the workspace's SSM parameters from a real environment into
+// LocalStack under the local environment's prefix
+func seedLocalStack(ws *workspace.Workspace, from, localName, endpoint string) error {
+	if ws.LocalEndpoint(from) != "" {
+		return errs.New(errs.KindUsage, "--seed needs a real AWS environment, not %s", from)
+	}
+	profile, region := ws.ResolveEnv(from)
+	if err := aws.GetCallerIdentityQuiet(profile); err != nil {
+		if err := aws.SSOLogin(profile); err != nil {
+			return errs.New(errs.KindAuthExpired, "AWS login failed: %w", err)
+		}
+	}
+	suffixes := workspaceSSMParams(ws)
+	params, err := github.FetchMultipleFromSSM(profile, from, region, suffixes)
+	if err != nil {
+		return err
+	}
+	_, localRegion := ws.ResolveEnv(localName)
+	for _, suffix := range suffixes {
+		v, ok := params[suffix]
+		if !ok {
+			continue
+		}
+		if err := github.PutSSMParameterAt(endpoint, localRegion, fmt.Sprintf("/app/%s/%s", localName, suffix), v); err != nil {
+			return err
+		}
+	}
+	ui.OK.Printf("Seeded %d parameter(s) from %s into /app/%s/\n", len(params), from, localName)
+	return nil
+}
+
+func init() {
+	localUpCmd.Flags().StringSliceVar(&localServices, "services", []string{"ssm", "dynamodb", "lambda", "s3"}, "LocalStack services to start")
+	localUpCmd.Flags().StringVar(&localSeed, "seed", "", "Copy SSM parameters from this environment into LocalStack")
+	localCmd.AddCommand(localUpCmd)
+	localCmd.AddCommand(localDownCmd)
+	rootCmd.AddCommand(localCmd)
+}
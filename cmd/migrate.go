@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/legacy"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateDryRun bool
+	migrateForce  bool
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move setups from before spark-cli into the workspace (legacy)",
+}
+
+var migrateLegacyCmd = &cobra.Command{
+	Use:   "legacy <script>...",
+	Short: "Import a sync.sh or .env template's SSM parameters, profiles, and exports into workspace.json",
+	Long: `Reads the sync.sh-style scripts and .env templates a team used before spark-cli
+and records what they set up in workspace.json, so 'spark-cli workspace sync'
+writes the same .env:
+
+  aws ssm get-parameter(s)   parameters under /app/<env>/ become "ssm_params"
+                             (name → env key: the variable a $(...) assigns it
+                             to, otherwise the name itself)
+  --profile / --region,      the AWS profile and region of each environment
+  AWS_PROFILE=, case $ENV    become "environments"; without one, the workspace's
+                             aws_profile and aws_region
+  export KEY=value           literal values become "env"
+
+In a .env template (any file that isn't a .sh or #! script) every KEY=value line
+counts as an export. Variables the script sets, and defaults like ${1:-beta},
+are expanded. What can't be translated — values built at run time, parameters
+outside /app/<env>/ — is listed instead of guessed at.
+
+Values already in workspace.json are kept unless --force. --dry-run shows the
+changes without saving them.
+
+Examples:
+  spark-cli migrate legacy ../old-setup/sync.sh
+  spark-cli migrate legacy sync.sh .env.template --dry-run`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		m := &migration{ws: ws, force: migrateForce}
+		for _, path := range args {
+			script, err := legacy.Parse(path)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			fmt.Println(path)
+			m.apply(script)
+		}
+
+		fmt.Println()
+		switch {
+		case m.changed == 0:
+			ui.OK.Println("Nothing to import — workspace.json already has it all")
+			return nil
+		case migrateDryRun:
+			fmt.Printf("Would make %d change(s) to workspace.json — run without --dry-run to save\n", m.changed)
+			return nil
+		}
+		if err := saveValidatedWorkspace(wsPath, ws); err != nil {
+			return err
+		}
+		ui.OK.Printf("Saved %d change(s) to workspace.json\n", m.changed)
+		fmt.Println(binname.Rewrite("Write the .env with 'spark-cli workspace sync --env <env>'"))
+		return nil
+	},
+}
+
+// migration merges legacy scripts into a workspace manifest, printing each change
+type migration struct {
+	ws      *workspace.Workspace
+	force   bool
+	changed int
+}
+
+// apply merges what script sets up into the manifest
+func (m *migration) apply(script *legacy.Script) {
+	for _, name := range sortedKeys(script.Params) {
+		key := script.Params[name]
+		current := ssmEnvKey(m.ws, name)
+		switch {
+		case slices.Contains(ssmParamSuffixes, name):
+			if current == key {
+				ui.Skip.Printf("/app/<env>/%s already read as %s\n", name, current)
+			} else {
+				ui.Skip.Printf("/app/<env>/%s already read as %s (the script exported %s)\n", name, current, key)
+			}
+		case m.ws.SSMParams[name] == key:
+			ui.Skip.Printf("/app/<env>/%s already read as %s\n", name, key)
+		case m.ws.SSMParams[name] != "" && !m.force:
+			ui.Skip.Printf("/app/<env>/%s kept as %s (the script exported %s; --force replaces it)\n", name, current, key)
+		default:
+			if m.ws.SSMParams == nil {
+				m.ws.SSMParams = make(map[string]string)
+			}
+			m.ws.SSMParams[name] = key
+			m.changed++
+			ui.OK.Printf("ssm_params: /app/<env>/%s → %s\n", name, key)
+		}
+	}
+
+	for _, name := range sortedKeys(script.Environments) {
+		e := script.Environments[name]
+		def := m.ws.Environments[name]
+		profile := m.setField("environments."+name+".profile", &def.Profile, e.Profile)
+		region := m.setField("environments."+name+".region", &def.Region, e.Region)
+		if profile || region {
+			if m.ws.Environments == nil {
+				m.ws.Environments = make(map[string]workspace.EnvironmentDef)
+			}
+			m.ws.Environments[name] = def
+		}
+	}
+	m.setField("aws_profile", &m.ws.AWSProfile, script.Profile)
+	m.setField("aws_region", &m.ws.AWSRegion, script.Region)
+
+	for _, key := range sortedKeys(script.Env) {
+		value := m.ws.Env[key]
+		if m.setField("env."+key, &value, script.Env[key]) {
+			if m.ws.Env == nil {
+				m.ws.Env = make(map[string]string)
+			}
+			m.ws.Env[key] = value
+		}
+	}
+
+	for _, s := range script.Skipped {
+		ui.Warn.Printf("not imported: %s\n", s)
+	}
+}
+
+// setField sets *field to value, unless value is empty or the same, or the field is
+// already set and not --force. It reports whether the field changed.
+func (m *migration) setField(name string, field *string, value string) bool {
+	switch {
+	case value == "" || *field == value:
+		return false
+	case *field != "" && !m.force:
+		ui.Skip.Printf("%s kept as %s (the script used %s; --force replaces it)\n", name, *field, value)
+		return false
+	}
+	*field = value
+	m.changed++
+	ui.OK.Printf("%s = %s\n", name, value)
+	return true
+}
+
+func init() {
+	migrateLegacyCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show the changes without saving them")
+	migrateLegacyCmd.Flags().BoolVar(&migrateForce, "force", false, "Replace values workspace.json already has")
+	migrateCmd.AddCommand(migrateLegacyCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,414 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/envfile"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/mobile"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellquote"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// metroWatchFoldersEnv lists linked local SDK directories (path-list separated) for
+// metro.config.js to add to watchFolders
+const metroWatchFoldersEnv = "SPARK_METRO_WATCH_FOLDERS"
+
+var (
+	mobileRepo       string
+	mobileDevice     string
+	mobileClean      bool
+	mobileNoMetro    bool
+	mobileResetCache bool
+	mobileDeep       bool
+	mobileDryRun     bool
+)
+
+var mobileCmd = &cobra.Command{
+	Use:   "mobile",
+	Short: "React Native workflows for the mobile app (run | reset)",
+	Long: `Wraps the React Native / Expo steps the mobile app needs before it can launch.
+
+The mobile repo is the one you're in, the one given with --repo, or the only
+React Native repo in the workspace.
+
+Examples:
+  spark-cli mobile run ios
+  spark-cli mobile run ios --device "iPhone 15"
+  spark-cli mobile run android --clean
+  spark-cli mobile reset`,
+}
+
+var mobileRunCmd = &cobra.Command{
+	Use:   "run <ios|android>",
+	Short: "Install pods, start metro, and launch the app on a simulator or device",
+	Long: `Prepares and launches the mobile app:
+
+  1. writes the repo's .env with only the keys in its "env_keys" — react-native-config
+     compiles it into the app, so backend secrets stay out (see 'spark-cli env link --help')
+  2. installs node_modules if package-lock.json changed
+  3. ios: runs pod install when Podfile.lock is out of sync with Pods/
+     android: passes workspace env to Gradle (see "gradle" in
+     'spark-cli run --help') and runs ./gradlew clean first with --clean
+  4. starts metro in the background (logs in .spk/logs/metro.log) unless it's
+     already running, passing linked local SDKs in $SPARK_METRO_WATCH_FOLDERS
+     so metro.config.js can add them to watchFolders
+  5. launches the app on --device, the booted simulator/device, or one you pick
+
+Metro keeps running after the app launches; press Ctrl-C to stop it.
+
+Examples:
+  spark-cli mobile run ios
+  spark-cli mobile run ios --device "iPhone 15 Pro" --reset-cache
+  spark-cli mobile run android --clean --no-metro`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"ios", "android"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		platform := args[0]
+		if platform != "ios" && platform != "android" {
+			return errs.New(errs.KindUsage, "unknown platform %q — use ios or android", platform)
+		}
+		if platform == "ios" && runtime.GOOS != "darwin" {
+			return errs.New(errs.KindUsage, "iOS builds need macOS and Xcode")
+		}
+
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		name, repoDir, err := resolveMobileRepo(wsPath, ws)
+		if err != nil {
+			return err
+		}
+		wsEnv := ws.Repos[name].ScopeEnv(buildWorkspaceEnv(wsPath, ws))
+		expo := mobile.IsExpo(repoDir)
+
+		// --- Env, node_modules, native deps ---
+		if fileExistsCheck(workspace.GlobalEnvPath(wsPath)) {
+			vars, err := workspace.ReadGlobalEnv(wsPath)
+			if err != nil {
+				return err
+			}
+			fmt.Printf(".env: %s\n", generateEnvFile(repoDir, ".env", envfile.FrameworkReactNative, ws.Repos[name], vars))
+		}
+		if err := ensureNodeModules(wsPath, name, repoDir, wsEnv); err != nil {
+			return err
+		}
+
+		switch platform {
+		case "ios":
+			iosDir := filepath.Join(repoDir, "ios")
+			if needed, reason := mobile.PodInstallNeeded(iosDir); needed {
+				command := mobile.PodInstallCommand(repoDir)
+				fmt.Printf("%s — running %s...\n", reason, command)
+				if err := runShellCmdWithEnv(iosDir, command, nil, wsEnv); err != nil {
+					return errs.New(errs.KindBuildFailed, "%s failed: %w", command, err)
+				}
+			}
+		case "android":
+			if wsEnv, err = gradleEnv(wsPath, ws.Repos[name], repoDir, wsEnv); err != nil {
+				return err
+			}
+			if mobileClean {
+				fmt.Println("Running ./gradlew clean...")
+				if err := runShellCmdWithEnv(filepath.Join(repoDir, "android"), "./gradlew clean", nil, wsEnv); err != nil {
+					return errs.New(errs.KindBuildFailed, "./gradlew clean failed: %w", err)
+				}
+			}
+		}
+
+		// --- Metro ---
+		if linked := mobile.LinkedPackageDirs(repoDir); len(linked) > 0 {
+			wsEnv[metroWatchFoldersEnv] = strings.Join(linked, string(os.PathListSeparator))
+			fmt.Printf("🔗 Linked SDKs for metro: %s\n", strings.Join(linked, ", "))
+		}
+		var metro *metroProcess
+		if !mobileNoMetro {
+			if mobile.MetroRunning(mobile.MetroPort) {
+				fmt.Printf("Metro already running on :%d\n", mobile.MetroPort)
+				if mobileResetCache {
+					fmt.Println("  --reset-cache ignored — restart metro yourself to clear its cache")
+				}
+			} else {
+				if metro, err = startMetro(wsPath, repoDir, expo, wsEnv); err != nil {
+					return err
+				}
+				defer metro.stop()
+			}
+		}
+
+		// --- Launch ---
+		device, err := pickMobileDevice(platform)
+		if err != nil {
+			return err
+		}
+		command := mobileLaunchCommand(platform, expo, device)
+		fmt.Printf("=== %s: %s ===\n", name, command)
+		if err := runShellCmdWithEnv(repoDir, command, nil, wsEnv); err != nil {
+			return errs.New(errs.KindBuildFailed, "%s failed: %w", command, err)
+		}
+
+		if metro != nil {
+			fmt.Println()
+			ui.OK.Printf("App launched — metro is running (logs: %s). Press Ctrl-C to stop.\n", metro.logPath)
+			metro.wait()
+		}
+		return nil
+	},
+}
+
+var mobileResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Clear metro, watchman, and build caches for the mobile app",
+	Long: `Clears everything that goes stale between React Native builds in one step:
+
+  - metro and haste-map caches in $TMPDIR
+  - the repo's watchman watch
+  - node_modules/.cache, .expo, ios/build, android/build, android/app/build
+  - the app's Xcode DerivedData
+
+--deep also removes ios/Pods and android/.gradle (restored by the next
+'spark-cli mobile run'). Stop metro before resetting.
+
+Examples:
+  spark-cli mobile reset
+  spark-cli mobile reset --deep
+  spark-cli mobile reset --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		name, repoDir, err := resolveMobileRepo(wsPath, ws)
+		if err != nil {
+			return err
+		}
+		if mobile.MetroRunning(mobile.MetroPort) {
+			ui.Warn.Printf("Metro is running on :%d — restart it after the reset\n", mobile.MetroPort)
+		}
+
+		dirs := mobile.CacheDirs(repoDir, mobileDeep)
+		var failed int
+		for _, dir := range dirs {
+			if mobileDryRun {
+				fmt.Printf("  would remove %s\n", dir)
+				continue
+			}
+			if err := os.RemoveAll(dir); err != nil {
+				ui.Fail.Printf("%s: %v\n", dir, err)
+				failed++
+				continue
+			}
+			ui.OK.Printf("Removed %s\n", dir)
+		}
+
+		if mobileDryRun {
+			fmt.Printf("  would run watchman watch-del %s\n", repoDir)
+		} else if ok, err := mobile.ResetWatchman(repoDir); err != nil {
+			ui.Fail.Printf("%v\n", err)
+			failed++
+		} else if ok {
+			ui.OK.Println("Cleared watchman watch")
+		}
+
+		if failed > 0 {
+			return errs.New(errs.KindCommandFailed, "%d cache(s) could not be cleared", failed)
+		}
+		if !mobileDryRun {
+			fmt.Println()
+			ui.OK.Printf(binname.Rewrite("%s reset — next: spark-cli mobile run ios|android\n"), name)
+		}
+		return nil
+	},
+}
+
+// resolveMobileRepo picks the repo from --repo, the current directory, or the workspace's
+// only React Native repo
+func resolveMobileRepo(wsPath string, ws *workspace.Workspace) (string, string, error) {
+	if mobileRepo != "" {
+		repo, ok := ws.Repos[mobileRepo]
+		if !ok {
+			return "", "", errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", mobileRepo)
+		}
+		return mobileRepo, filepath.Join(wsPath, repo.Path), nil
+	}
+	if name, dir := detectCurrentRepo(wsPath, ws); name != "" && envfile.Detect(dir) == envfile.FrameworkReactNative {
+		return name, dir, nil
+	}
+
+	var candidates []string
+	for name, repo := range ws.Repos {
+		if envfile.Detect(filepath.Join(wsPath, repo.Path)) == envfile.FrameworkReactNative {
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Strings(candidates)
+	switch len(candidates) {
+	case 0:
+		return "", "", errs.New(errs.KindRepoNotFound, "no React Native repo in this workspace")
+	case 1:
+		return candidates[0], filepath.Join(wsPath, ws.Repos[candidates[0]].Path), nil
+	}
+	return "", "", errs.New(errs.KindUsage, "several React Native repos (%s) — pick one with --repo", strings.Join(candidates, ", "))
+}
+
+// pickMobileDevice returns --device, the only booted simulator/device, or one chosen
+// interactively. An empty result lets the React Native CLI use its default.
+func pickMobileDevice(platform string) (mobile.Device, error) {
+	if mobileDevice != "" {
+		return mobile.Device{Name: mobileDevice}, nil
+	}
+	list := mobile.Simulators
+	if platform == "android" {
+		list = mobile.AndroidDevices
+	}
+	devices, err := list()
+	if err != nil || len(devices) == 0 {
+		return mobile.Device{}, nil
+	}
+
+	var booted []mobile.Device
+	for _, d := range devices {
+		if d.Booted {
+			booted = append(booted, d)
+		}
+	}
+	if len(booted) == 1 {
+		return booted[0], nil
+	}
+	if prompt.NonInteractive {
+		return mobile.Device{}, nil
+	}
+
+	options := make([]string, len(devices))
+	for i, d := range devices {
+		options[i] = d.Name
+		if d.Booted && platform == "ios" {
+			options[i] += " (booted)"
+		}
+	}
+	i, err := prompt.Select("Launch on:", options)
+	if err != nil {
+		return mobile.Device{}, errs.Wrap(errs.KindUsage, err)
+	}
+	return devices[i], nil
+}
+
+// mobileLaunchCommand builds the run-ios/run-android (or expo run:*) invocation. Metro
+// is managed by spark-cli, so the CLI is told not to start its own.
+func mobileLaunchCommand(platform string, expo bool, device mobile.Device) string {
+	var parts []string
+	if expo {
+		parts = []string{"npx", "expo", "run:" + platform, "--no-bundler"}
+		if device.Name != "" {
+			parts = append(parts, "--device", shellquote.Quote(device.Name))
+		}
+		return strings.Join(parts, " ")
+	}
+
+	parts = []string{"npx", "react-native", "run-" + platform, "--no-packager"}
+	switch {
+	case platform == "ios" && device.ID != "":
+		parts = append(parts, "--udid", device.ID)
+	case platform == "ios" && device.Name != "":
+		parts = append(parts, "--simulator", shellquote.Quote(device.Name))
+	case device.ID != "":
+		parts = append(parts, "--deviceId", device.ID)
+	case device.Name != "":
+		parts = append(parts, "--deviceId", shellquote.Quote(device.Name))
+	}
+	return strings.Join(parts, " ")
+}
+
+// metroProcess is a metro bundler started by spark-cli and stopped when it exits
+type metroProcess struct {
+	done    chan error
+	logPath string
+}
+
+// startMetro launches the bundler in the background, logging to .spk/logs/metro.log
+func startMetro(wsPath, repoDir string, expo bool, wsEnv map[string]string) (*metroProcess, error) {
+	logDir := filepath.Join(workspace.SparkDir(wsPath), "logs")
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return nil, err
+	}
+	logPath := filepath.Join(logDir, "metro.log")
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return nil, err
+	}
+
+	command := "npx react-native start"
+	if expo {
+		command = "npx expo start --dev-client"
+	}
+	if mobileResetCache {
+		if expo {
+			command += " --clear"
+		} else {
+			command += " --reset-cache"
+		}
+	}
+
+	shell, args := shellCommand(command)
+	cmd := proc.Command(shell, args...)
+	cmd.Dir = repoDir
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.Env = os.Environ()
+	for k, v := range wsEnv {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return nil, fmt.Errorf("failed to start metro: %w", err)
+	}
+	ui.OK.Printf("Metro starting on :%d (logs: %s)\n", mobile.MetroPort, logPath)
+
+	m := &metroProcess{done: make(chan error, 1), logPath: logPath}
+	go func() {
+		m.done <- cmd.Wait()
+		logFile.Close()
+	}()
+	return m, nil
+}
+
+// wait blocks until metro exits or spark-cli is interrupted
+func (m *metroProcess) wait() {
+	select {
+	case <-m.done:
+	case <-proc.Context().Done():
+	}
+}
+
+// stop waits for metro to exit after an interrupt (the shared context kills its process
+// group); if spark-cli is returning early for another reason, metro is left running
+func (m *metroProcess) stop() {
+	if proc.Interrupted() {
+		<-m.done
+	}
+}
+
+func init() {
+	mobileCmd.PersistentFlags().StringVar(&mobileRepo, "repo", "", "Mobile app repo (default: current or only React Native repo)")
+	mobileRunCmd.Flags().StringVar(&mobileDevice, "device", "", "Simulator name or device ID to launch on")
+	mobileRunCmd.Flags().BoolVar(&mobileClean, "clean", false, "Run ./gradlew clean before an Android build")
+	mobileRunCmd.Flags().BoolVar(&mobileNoMetro, "no-metro", false, "Don't start metro")
+	mobileRunCmd.Flags().BoolVar(&mobileResetCache, "reset-cache", false, "Start metro with a clean cache")
+	mobileResetCmd.Flags().BoolVar(&mobileDeep, "deep", false, "Also remove ios/Pods and android/.gradle")
+	mobileResetCmd.Flags().BoolVar(&mobileDryRun, "dry-run", false, "List what would be removed")
+	mobileCmd.AddCommand(mobileRunCmd)
+	mobileCmd.AddCommand(mobileResetCmd)
+	rootCmd.AddCommand(mobileCmd)
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	npmAuthUser  bool
+	npmAuthCheck bool
+)
+
+var npmCmd = &cobra.Command{
+	Use:   "npm",
+	Short: "npm registry helpers (auth)",
+}
+
+var npmAuthCmd = &cobra.Command{
+	Use:   "auth [repo...]",
+	Short: "Configure .npmrc for @spark-rewards packages on GitHub Packages and check it works",
+	Long: `Installing @spark-rewards packages needs an .npmrc that routes the scope to
+GitHub Packages and sends a token. auth writes both lines, keeping anything else
+in the file, then checks them by fetching one of the repo's @spark-rewards
+packages the way an install would.
+
+Each Node repo's .npmrc gets the token as ${GITHUB_TOKEN}, which npm reads from
+the environment — spark-cli passes the resolved token (GITHUB_TOKEN, .env, or
+gh auth) to installs, and the file stays safe to commit. --user writes the
+token itself into ~/.npmrc (mode 0600) for npm run outside spark-cli.
+
+Installs that fail with 401/403 run this for the repo automatically and retry
+once. If the check still fails, the token probably lacks the read:packages
+scope: 'gh auth refresh -s read:packages'.
+
+Examples:
+  spark-cli npm auth                 # every Node repo in the workspace
+  spark-cli npm auth AppAPI Web
+  spark-cli npm auth --user          # ~/.npmrc
+  spark-cli npm auth --check         # only check, write nothing`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token := resolveGitHubToken()
+		if token == "" {
+			return errs.New(errs.KindAuthExpired, "no GitHub token — set GITHUB_TOKEN or run 'gh auth login'")
+		}
+		if err := npm.CheckNPM(); err != nil {
+			return err
+		}
+		tokenEnv := []string{"GITHUB_TOKEN=" + token}
+
+		if npmAuthUser {
+			if len(args) > 0 {
+				return errs.New(errs.KindUsage, "--user configures ~/.npmrc and takes no repos")
+			}
+			path, err := npm.UserNpmrcPath()
+			if err != nil {
+				return err
+			}
+			if !npmAuthCheck {
+				if err := writeNpmAuth(path, "~/"+npm.NpmrcName, token); err != nil {
+					return err
+				}
+			}
+			fmt.Printf("%-25s ", "~/"+npm.NpmrcName)
+			if err := npm.CheckAuth(filepath.Dir(path), npm.GitHubPackagesRegistry, "", nil); err != nil {
+				ui.Fail.Printf("%v\n", err)
+				return npmAuthError(err, 1)
+			}
+			ui.OK.Println("authenticated")
+			return nil
+		}
+
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := npmAuthRepos(wsPath, ws, args)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			fmt.Println("No cloned Node repos to configure")
+			return nil
+		}
+
+		var failed int
+		var checkErr error
+		for _, name := range names {
+			repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+			if !npmAuthCheck {
+				if err := writeNpmAuth(filepath.Join(repoDir, npm.NpmrcName), name, npm.TokenRef); err != nil {
+					failed++
+					continue
+				}
+			}
+			fmt.Printf("%-25s ", name)
+			if err := npm.CheckAuth(repoDir, npm.GitHubPackagesRegistry, firstSparkPackage(repoDir), tokenEnv); err != nil {
+				ui.Fail.Printf("%v\n", err)
+				failed++
+				if checkErr == nil || errors.Is(err, npm.ErrAuthRejected) {
+					checkErr = err
+				}
+				continue
+			}
+			ui.OK.Println("authenticated")
+		}
+		if failed > 0 {
+			return npmAuthError(checkErr, failed)
+		}
+		return nil
+	},
+}
+
+// npmAuthError explains failed checks: a rejected token usually lacks a scope, anything
+// else (network, writing .npmrc) is reported as is
+func npmAuthError(err error, failed int) error {
+	if errors.Is(err, npm.ErrAuthRejected) {
+		return errs.New(errs.KindAuthExpired, "GitHub Packages rejected the token — check it has read:packages ('gh auth refresh -s read:packages')")
+	}
+	return errs.New(errs.KindCommandFailed, "%d npm auth check(s) failed", failed)
+}
+
+// npmAuthRepos returns the named repos, or every cloned repo with a package.json
+func npmAuthRepos(wsPath string, ws *workspace.Workspace, args []string) ([]string, error) {
+	if len(args) > 0 {
+		for _, name := range args {
+			if _, ok := ws.Repos[name]; !ok {
+				return nil, errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
+			}
+		}
+		return args, nil
+	}
+	names, _ := expandRepoNames(ws, nil)
+	var node []string
+	for _, name := range names {
+		if fileExistsCheck(filepath.Join(wsPath, ws.Repos[name].Path, "package.json")) {
+			node = append(node, name)
+		}
+	}
+	return node, nil
+}
+
+// writeNpmAuth configures the .npmrc at path and reports what it did under label
+func writeNpmAuth(path, label, token string) error {
+	changed, err := npm.ConfigureAuth(path, npm.GitHubPackagesRegistry, token)
+	if err != nil {
+		ui.Fail.Printf("%-25s %v\n", label, err)
+		return err
+	}
+	if changed {
+		fmt.Printf("%-25s %s → %s\n", label, npm.Scope, npm.GitHubPackagesRegistry)
+	}
+	return nil
+}
+
+// firstSparkPackage returns one @spark-rewards dependency of the repo, or "" if it has none
+func firstSparkPackage(repoDir string) string {
+	if pkgs := findSparkPackages(repoDir); len(pkgs) > 0 {
+		return pkgs[0]
+	}
+	return ""
+}
+
+// recoverNpmAuth runs after an install failed with 401/403: it configures the repo's
+// .npmrc and makes sure wsEnv carries the token. It reports whether anything changed,
+// i.e. whether retrying the install is worth it.
+func recoverNpmAuth(repoDir string, wsEnv map[string]string) bool {
+	fmt.Fprintf(os.Stderr, "%s The registry rejected the install (401/403) — configuring npm auth...\n", ui.Warn)
+	changed := false
+	if wsEnv != nil && wsEnv["GITHUB_TOKEN"] == "" {
+		token := resolveGitHubToken()
+		if token == "" {
+			fmt.Fprintln(os.Stderr, binname.Rewrite("  no GitHub token — set GITHUB_TOKEN or run 'gh auth login', then 'spark-cli npm auth'"))
+			return false
+		}
+		wsEnv["GITHUB_TOKEN"] = token
+		changed = true
+	}
+	wrote, err := npm.ConfigureAuth(filepath.Join(repoDir, npm.NpmrcName), npm.GitHubPackagesRegistry, npm.TokenRef)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  couldn't update %s: %v\n", npm.NpmrcName, err)
+		return changed
+	}
+	if wrote {
+		fmt.Fprintf(os.Stderr, "  %s/%s: %s → %s\n", filepath.Base(repoDir), npm.NpmrcName, npm.Scope, npm.GitHubPackagesRegistry)
+	}
+	if !wrote && !changed {
+		fmt.Fprintln(os.Stderr, "  .npmrc is already set up — the token likely lacks read:packages ('gh auth refresh -s read:packages')")
+	}
+	return wrote || changed
+}
+
+func init() {
+	npmAuthCmd.Flags().BoolVar(&npmAuthUser, "user", false, "Configure ~/.npmrc with the token instead of the repos")
+	npmAuthCmd.Flags().BoolVar(&npmAuthCheck, "check", false, "Only check that installs can authenticate; write nothing")
+	npmCmd.AddCommand(npmAuthCmd)
+	rootCmd.AddCommand(npmCmd)
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/progress"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// onboardRecommendedRepos are preselected in the onboarding repo picker
+var onboardRecommendedRepos = map[string]bool{
+	"AppModel":        true,
+	"AppAPI":          true,
+	"MobileApp":       true,
+	"BusinessModel":   true,
+	"BusinessAPI":     true,
+	"BusinessWebsite": true,
+}
+
+var onboardCmd = &cobra.Command{
+	Use:   "onboard",
+	Short: "Guided first-day setup: workspace, AWS SSO, repos, env, install, build",
+	Long: `Walks a new engineer through the whole first-day setup:
+
+  1. Create (or reuse) a workspace
+  2. Configure AWS SSO and pick the workspace profile (over SSH or without a
+     display, login uses the device-code flow: open the printed URL on any
+     device and enter the code)
+  3. Choose repos to clone from the GitHub org
+  4. Sync the workspace .env from SSM
+  5. Install dependencies
+  6. Run a first build in dependency order
+
+Every step can be skipped; re-running onboard is safe.
+
+Example:
+  spark-cli onboard`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		const steps = 6
+		step := func(n int, title string) {
+			fmt.Printf("\n━━ Step %d/%d: %s ━━\n", n, steps, title)
+		}
+
+		// 1. Workspace
+		step(1, "Workspace")
+		wsPath, err := onboardWorkspace()
+		if err != nil {
+			return err
+		}
+
+		// 2. AWS SSO
+		step(2, "AWS SSO")
+		if err := onboardAWS(wsPath); err != nil {
+			fmt.Printf("Skipping AWS setup: %v\n", err)
+		}
+
+		// 3. Repos
+		step(3, "Repositories")
+		cloned, err := onboardRepos(wsPath)
+		if err != nil {
+			fmt.Printf("Skipping repo selection: %v\n", err)
+		}
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		// 4. Env
+		step(4, "Environment")
+		env := activeEnv(ws, "")
+		if prompt.Confirm(fmt.Sprintf("Fetch .env from SSM (%s)?", env), true) {
+			envVars, err := fetchEnvVars(ws, env, true)
+			if err != nil {
+				fmt.Printf("Skipping env sync: %v\n", err)
+			} else if err := writeWorkspaceEnv(wsPath, ws, env, envVars); err != nil {
+				fmt.Printf("Failed to write .env: %v\n", err)
+			} else {
+				ui.OK.Printf("Wrote %d variables to %s\n", len(envVars), workspace.GlobalEnvPath(wsPath))
+			}
+		}
+
+		wsEnv := buildWorkspaceEnv(wsPath, ws)
+
+		// 5. Install
+		step(5, "Dependencies")
+		if len(cloned) > 0 && prompt.Confirm("Install dependencies for the selected repos?", true) {
+			for _, name := range cloned {
+				repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+				if detectProjectType(repoDir) != projectTypeNode {
+					continue
+				}
+				fmt.Printf("=== %s ===\n", name)
+				if err := ensureNodeModules(wsPath, name, repoDir, wsEnv); err != nil {
+					ui.Fail.Printf("%s: %v\n", name, err)
+				}
+			}
+		}
+
+		// 6. Build
+		step(6, "First build")
+		if len(cloned) > 0 && prompt.Confirm("Build the selected repos (dependencies first)?", true) {
+			order, err := workspace.BuildOrder(wsPath, ws, cloned)
+			if err != nil {
+				return err
+			}
+			for _, name := range order {
+				repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+				command := buildCommand(repoDir, detectProjectType(repoDir), "build", nil)
+				if command == "" {
+					continue
+				}
+				fmt.Printf("=== %s: %s ===\n", name, command)
+				repo := ws.Repos[name]
+				if err := runShellCmdWithEnv(repoDir, command, &repo, wsEnv); err != nil {
+					ui.Fail.Printf("%s build failed: %v\n", name, err)
+				}
+			}
+		}
+
+		fmt.Println("\nYou're set up. Next:")
+		fmt.Printf("  cd %s\n", wsPath)
+		fmt.Println(binname.Rewrite("  spark-cli workspace          # overview"))
+		fmt.Println(binname.Rewrite("  spark-cli whoami             # check identities"))
+		return nil
+	},
+}
+
+// onboardWorkspace returns the current workspace, or creates one at a prompted path
+func onboardWorkspace() (string, error) {
+	if wsPath, err := workspace.Find(); err == nil {
+		fmt.Printf("Using workspace at %s\n", wsPath)
+		return wsPath, nil
+	}
+
+	home, _ := os.UserHomeDir()
+	target := prompt.Input("Workspace directory", filepath.Join(home, "SparkRewards"))
+	absPath, err := filepath.Abs(target)
+	if err != nil {
+		return "", fmt.Errorf("invalid path: %w", err)
+	}
+	if _, err := os.Stat(workspace.ManifestPath(absPath)); err == nil {
+		fmt.Printf("Using existing workspace at %s\n", absPath)
+		return absPath, nil
+	}
+	if err := os.MkdirAll(absPath, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	ws, err := workspace.Create(absPath, filepath.Base(absPath), "", "")
+	if err != nil {
+		return "", err
+	}
+	if err := workspace.GenerateVSCodeWorkspace(absPath); err != nil {
+		fmt.Printf("Warning: failed to create VS Code workspace: %v\n", err)
+	}
+	ui.OK.Printf("Workspace '%s' created at %s\n", ws.Name, absPath)
+	return absPath, nil
+}
+
+// onboardAWS makes sure an SSO profile exists, selects it for the workspace, and logs in
+func onboardAWS(wsPath string) error {
+	if err := aws.CheckCLI(); err != nil {
+		return err
+	}
+	if len(aws.GetSSOProfiles()) == 0 {
+		aws.ShowSSOSetupInstructions()
+		if !prompt.Confirm("Run 'aws configure sso' now?", true) {
+			return fmt.Errorf("no SSO profiles configured")
+		}
+		if err := aws.RunConfigureSSO(); err != nil {
+			return err
+		}
+	}
+	profile, err := aws.PromptProfileSelection()
+	if err != nil {
+		return err
+	}
+	return runWorkspaceConfigureProfileAt(wsPath, profile)
+}
+
+// onboardRepos lets the user pick org repos to clone and returns the names cloned
+func onboardRepos(wsPath string) ([]string, error) {
+	org := githubOrg()
+	token := resolveGitHubToken()
+	repos, err := github.ListOrgRepos(token, org)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []github.Repo
+	for _, r := range repos {
+		if !r.Archived {
+			candidates = append(candidates, r)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+
+	labels := make([]string, len(candidates))
+	preselected := make([]bool, len(candidates))
+	for i, r := range candidates {
+		labels[i] = fmt.Sprintf("%-25s %s", r.Name, r.Description)
+		preselected[i] = onboardRecommendedRepos[r.Name]
+	}
+
+	picked, err := prompt.MultiSelect(fmt.Sprintf("Repositories in %s (* = recommended):", org), labels, preselected)
+	if err != nil {
+		return nil, err
+	}
+
+	var cloned []string
+	for n, i := range picked {
+		fmt.Print(progress.Step(n+1, len(picked)))
+		name, err := cloneRepo(wsPath, org+"/"+candidates[i].Name)
+		if err != nil {
+			ui.Fail.Printf("%s: %v\n", candidates[i].Name, err)
+			continue
+		}
+		cloned = append(cloned, name)
+	}
+	return cloned, nil
+}
+
+func init() {
+	rootCmd.AddCommand(onboardCmd)
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/progress"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/settings"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	outdatedFetch bool
+	outdatedRepos []string
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Show how far each repo's default branch is behind origin",
+	Long: `For each repo, shows how many commits origin's default branch has that the
+local one doesn't (as of the last fetch; --fetch fetches first) and when
+'spark-cli workspace sync' last succeeded for it. A repo without a local copy of
+its default branch is compared through the branch it has checked out.
+
+Repos at least nudge_behind commits behind (default 20) are flagged, and other
+commands print a one-line reminder about them at most once a day. Change the
+threshold with 'spark-cli config set nudge_behind <n>', or set it to off.
+
+Examples:
+  spark-cli outdated
+  spark-cli outdated --fetch
+  spark-cli outdated --repos AppAPI,AppModel
+  spark-cli config set nudge_behind off`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := expandRepoNames(ws, outdatedRepos)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			fmt.Println(binname.Rewrite("No repos — run 'spark-cli use <repo>' to add one"))
+			return nil
+		}
+
+		if outdatedFetch {
+			spin := progress.Start(fmt.Sprintf("Fetching %d repos", len(names)))
+			forEachRepo(ws, names, func(_ int, name string) {
+				repo := ws.Repos[name]
+				repoDir := filepath.Join(wsPath, repo.Path)
+				if git.IsRepo(repoDir) {
+					git.FetchQuiet(repoDir, "origin")
+					if repo.Upstream != "" {
+						fetchUpstream(repoDir, repo)
+					}
+				}
+			})
+			spin.Stop(nil)
+		}
+
+		threshold := settings.Load(ws).Int(settings.NudgeBehind)
+		lastSync := lastSuccessByRepo(wsPath, "sync")
+		rows := checkOutdated(wsPath, ws, names)
+
+		fmt.Printf("  %-25s %-28s %-8s %s\n", "REPO", "BRANCH", "BEHIND", "LAST SYNC")
+		var flagged []outdatedRepo
+		for _, r := range rows {
+			mark, branch, behind := ui.OK, "-", "-"
+			switch {
+			case !r.known:
+				mark = ui.Skip
+			case threshold > 0 && r.behind >= threshold:
+				mark = ui.Warn
+				flagged = append(flagged, r)
+				fallthrough
+			default:
+				branch = r.branch
+				behind = strconv.Itoa(r.behind)
+			}
+			fmt.Printf("%s %-25s %-28s %-8s %s\n", mark, r.name, branch, behind, sinceOrNever(lastSync[r.name]))
+		}
+
+		if len(flagged) > 0 {
+			fmt.Println()
+			for _, r := range flagged {
+				fmt.Printf("%s is %d commits behind origin/%s\n", r.name, r.behind, r.target)
+			}
+			fmt.Println(binname.Rewrite("Run 'spark-cli workspace sync' to catch up"))
+		}
+		return nil
+	},
+}
+
+// outdatedRepo is how far one repo's local default branch trails origin
+type outdatedRepo struct {
+	name   string
+	branch string // local branch compared: the default branch, or the checked-out one
+	target string // default branch on origin
+	behind int
+	// known is false when the repo isn't cloned or origin has no default branch
+	known bool
+}
+
+// checkOutdated compares each repo with origin as of its last fetch, in parallel
+func checkOutdated(wsPath string, ws *workspace.Workspace, names []string) []outdatedRepo {
+	rows := make([]outdatedRepo, len(names))
+	forEachRepo(ws, names, func(i int, name string) {
+		repo := ws.Repos[name]
+		repoDir := filepath.Join(wsPath, repo.Path)
+		r := outdatedRepo{name: name}
+		if !git.IsRepo(repoDir) {
+			rows[i] = r
+			return
+		}
+		r.target = getTargetBranch(ws, &repo, repoDir)
+		if !git.RemoteBranchExists(repoDir, repo.SyncRemote(), r.target) {
+			rows[i] = r
+			return
+		}
+		local := r.target
+		r.branch = r.target
+		if !git.BranchExists(repoDir, r.target) {
+			local = "HEAD"
+			r.branch = orDefault(git.GetCurrentBranch(repoDir), "(detached)")
+		}
+		_, r.behind = git.AheadBehind(repoDir, local, repo.SyncRemote()+"/"+r.target)
+		r.known = true
+		rows[i] = r
+	})
+	return rows
+}
+
+// outdatedCheckEvery is how often other commands may check for repos far behind origin
+const outdatedCheckEvery = 24 * time.Hour
+
+// outdatedQuiet are the commands that already report how far repos are behind, or
+// fix it, so they don't nudge
+var outdatedQuiet = map[string]bool{"outdated": true, "sync": true, "list": true, "workspace": true}
+
+// nudgeOutdated prints a reminder on stderr when a repo's default branch has fallen
+// nudge_behind or more commits behind origin. It checks at most once a day, and only
+// for interactive use.
+func nudgeOutdated(cmd *cobra.Command) {
+	if outdatedQuiet[cmd.Name()] || ciMode || prompt.NonInteractive || !ui.IsTerminal(os.Stderr) {
+		return
+	}
+	wsPath, ws, err := findWorkspace()
+	if err != nil || ws == nil || len(ws.Repos) == 0 {
+		return
+	}
+	threshold := settings.Load(ws).Int(settings.NudgeBehind)
+	if threshold <= 0 {
+		return
+	}
+	st, err := state.Load(wsPath)
+	if err != nil {
+		return
+	}
+	if t, err := time.Parse(time.RFC3339, st.OutdatedCheckedAt); err == nil && time.Since(t) < outdatedCheckEvery {
+		return
+	}
+	state.Update(wsPath, func(s *state.State) error {
+		s.OutdatedCheckedAt = time.Now().UTC().Format(time.RFC3339)
+		return nil
+	})
+
+	names, err := expandRepoNames(ws, nil)
+	if err != nil {
+		return
+	}
+	var worst outdatedRepo
+	var count int
+	for _, r := range checkOutdated(wsPath, ws, names) {
+		if r.known && r.behind >= threshold {
+			count++
+			if r.behind > worst.behind {
+				worst = r
+			}
+		}
+	}
+	if count == 0 {
+		return
+	}
+	msg := fmt.Sprintf("%s is %d commits behind origin/%s", worst.name, worst.behind, worst.target)
+	if count > 1 {
+		msg += fmt.Sprintf(" (and %d more repo(s))", count-1)
+	}
+	fmt.Fprintln(os.Stderr)
+	ui.Warn.Fprintf(os.Stderr, binname.Rewrite("%s — 'spark-cli outdated' for details, 'spark-cli workspace sync' to catch up\n"), msg)
+}
+
+func init() {
+	outdatedCmd.Flags().BoolVar(&outdatedFetch, "fetch", false, "Fetch every repo first instead of using the last fetch")
+	outdatedCmd.Flags().StringSliceVar(&outdatedRepos, "repos", nil, "Only check these repos")
+	rootCmd.AddCommand(outdatedCmd)
+}
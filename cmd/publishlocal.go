@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/gradle"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/spkconfig"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	publishLocalCodegen string
+	publishLocalRevert  bool
+)
+
+// codegenAliases are the short names --codegen accepts for Smithy codegen plugins
+var codegenAliases = map[string]string{
+	"java": "java-client-codegen",
+}
+
+var publishLocalCmd = &cobra.Command{
+	Use:   "publish-local <model>",
+	Short: "Publish a model's JVM codegen to mavenLocal and point Gradle consumers at it",
+	Long: `Builds the model (running its Smithy codegen), publishes the codegen output to
+mavenLocal with a marker version (0.0.0-spk-local-<commit>), and makes each
+Gradle repo that consumes one of its Maven modules resolve that version.
+
+Consumers are the repos whose spk.config.json "consumes" the model with a Maven
+module as the package ("group:artifact"); consumers with 'spark-cli link
+disable' are left alone. Their build files aren't touched: the same init script
+'spark-cli link' uses (~/.gradle/init.d) adds mavenLocal and pins the version.
+The codegen output is published when it is a Gradle build, else the model
+repo's Gradle build is.
+
+--revert puts the consumers back on the published version and deletes the
+marker versions from mavenLocal.
+
+Examples:
+  spark-cli publish-local AppModel --codegen java
+  spark-cli publish-local AppModel --revert`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		model := args[0]
+		repo, ok := ws.Repos[model]
+		if !ok {
+			return errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", model)
+		}
+		modelDir := filepath.Join(wsPath, repo.Path)
+		if !isModelRepo(modelDir, repo) {
+			return errs.New(errs.KindUsage, "%s isn't a model repo", model)
+		}
+		if publishLocalRevert {
+			return revertPublishLocal(wsPath, ws, model)
+		}
+		return publishLocal(wsPath, ws, model, modelDir)
+	},
+}
+
+// publishLocal builds model, publishes its JVM codegen to mavenLocal, and pins the
+// Gradle consumers of its modules to the published version
+func publishLocal(wsPath string, ws *workspace.Workspace, model, modelDir string) error {
+	wsEnv := buildSyncEnv(wsPath, ws)
+	if err := buildRepo(wsPath, ws, model, wsEnv); err != nil {
+		return err
+	}
+
+	codegen := publishLocalCodegen
+	if alias, ok := codegenAliases[codegen]; ok {
+		codegen = alias
+	}
+	dir := npm.BuildOutputDirForCodegen(modelDir, codegen)
+	if !gradle.IsProject(dir) {
+		if dir = repoGradleDir(wsPath, ws, model); dir == "" {
+			return errs.New(errs.KindUsage, "nothing to publish: %s has no %s output with a Gradle build, and %s has no Gradle build of its own", model, codegen, model)
+		}
+	}
+
+	commit, _ := git.HeadSHA(modelDir)
+	version := gradle.LocalVersion(commit)
+	env, err := gradleEnv(wsPath, ws.Repos[model], modelDir, ws.Repos[model].ScopeEnv(wsEnv))
+	if err != nil {
+		return err
+	}
+	command := gradle.PublishLocalCommand(dir, modelDir, version)
+	fmt.Printf("=== %s: %s ===\n", model, command)
+	if err := runShellCmdWithEnv(dir, command, nil, env); err != nil {
+		return errs.New(errs.KindBuildFailed, "%s: publishToMavenLocal failed: %w", model, err)
+	}
+	ui.OK.Printf("Published %s %s to mavenLocal\n", model, version)
+
+	st, err := state.Load(wsPath)
+	if err != nil {
+		return err
+	}
+	consumers := mavenConsumers(wsPath, ws, model)
+	if len(consumers) == 0 {
+		fmt.Printf("No repo consumes a Maven module of %s — add it to a repo's %s \"consumes\" as \"group:artifact\"\n", model, spkconfig.ConfigFilename)
+		return nil
+	}
+	var failed int
+	for _, l := range consumers {
+		if enabled, why := linkEnabled(ws, st, l.consumer); !enabled {
+			ui.Skip.Printf("%s → %s: left on the published version (%s)\n", l.consumer, l.entry.Package, why)
+			continue
+		}
+		consumerDir := repoGradleDir(wsPath, ws, l.consumer)
+		if consumerDir == "" {
+			ui.Skip.Printf("%s → %s: %s has no Gradle build\n", l.consumer, l.entry.Package, l.consumer)
+			continue
+		}
+		if !gradle.InMavenLocal(l.entry.Package, version) {
+			ui.Fail.Printf("%s → %s: not in mavenLocal at %s — does the codegen publish that group and artifact?\n", l.consumer, l.entry.Package, version)
+			failed++
+			continue
+		}
+		if err := gradle.LinkVersion(consumerDir, l.entry.Package, version); err != nil {
+			ui.Fail.Printf("%s → %s: %v\n", l.consumer, l.entry.Package, err)
+			failed++
+			continue
+		}
+		now := time.Now().UTC().Format(time.RFC3339)
+		err := state.Update(wsPath, func(s *state.State) error {
+			s.SetLinkPin(l.consumer, l.entry.Package, state.LinkPin{Model: model, Commit: commit, BuiltAt: now, LinkedAt: now})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  📦 %s → %s %s (mavenLocal)\n", l.consumer, l.entry.Package, version)
+	}
+	if failed > 0 {
+		return errs.New(errs.KindCommandFailed, "%d consumer(s) not pointed at %s", failed, version)
+	}
+	fmt.Printf(binname.Rewrite("\nRevert with 'spark-cli publish-local %s --revert'\n"), model)
+	return nil
+}
+
+// revertPublishLocal puts model's Gradle consumers back on the published version and
+// removes the marker versions from mavenLocal
+func revertPublishLocal(wsPath string, ws *workspace.Workspace, model string) error {
+	var modules []string
+	for _, l := range mavenConsumers(wsPath, ws, model) {
+		if !slices.Contains(modules, l.entry.Package) {
+			modules = append(modules, l.entry.Package)
+		}
+		dir := repoGradleDir(wsPath, ws, l.consumer)
+		if sub, ok := gradle.LinkedTo(dir, l.entry.Package); dir == "" || !ok || sub.Version == "" {
+			continue
+		}
+		if err := unlinkModel(wsPath, ws, l.consumer, l.entry); err != nil {
+			return err
+		}
+		fmt.Printf("  %s uses the published %s again\n", l.consumer, l.entry.Package)
+	}
+	for _, module := range modules {
+		removed, err := gradle.RemoveLocalVersions(module)
+		if err != nil {
+			return err
+		}
+		for _, v := range removed {
+			fmt.Printf("  removed %s:%s from mavenLocal\n", module, v)
+		}
+	}
+	ui.OK.Printf("Reverted publish-local of %s\n", model)
+	return nil
+}
+
+// modelConsumer is a repo's "consumes" entry for a model
+type modelConsumer struct {
+	consumer string
+	entry    spkconfig.ConsumesEntry
+}
+
+// mavenConsumers returns the repos that consume a Maven module of model
+func mavenConsumers(wsPath string, ws *workspace.Workspace, model string) []modelConsumer {
+	names, _ := expandRepoNames(ws, nil)
+	var out []modelConsumer
+	for _, name := range names {
+		for _, c := range consumedModels(wsPath, ws, name) {
+			if c.Model == model && gradle.IsModule(c.Package) {
+				out = append(out, modelConsumer{consumer: name, entry: c})
+			}
+		}
+	}
+	return out
+}
+
+func init() {
+	publishLocalCmd.Flags().StringVar(&publishLocalCodegen, "codegen", "java", "Smithy codegen to publish (java = java-client-codegen)")
+	publishLocalCmd.Flags().BoolVar(&publishLocalRevert, "revert", false, "Put consumers back on the published version and clean mavenLocal")
+	rootCmd.AddCommand(publishLocalCmd)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var pushRepos []string
+
+var pushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Push current branches across repos and print PR links",
+	Long: `Pushes the current branch of each repo that has unpushed commits, setting the
+upstream on first push, then prints the GitHub compare/PR-creation URL for each.
+
+Repos sitting on their default branch are skipped unless named with --repos.
+
+Examples:
+  spark-cli push
+  spark-cli push --repos AppModel,AppAPI`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, pushRepos)
+		if err != nil {
+			return err
+		}
+		explicit := len(pushRepos) > 0
+
+		type prLink struct{ repo, url string }
+		var links []prLink
+		var pushed, failed int
+
+		for _, name := range names {
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+			branch := git.GetCurrentBranch(repoDir)
+			base := getTargetBranch(ws, &repo, repoDir)
+
+			if branch == "HEAD" || branch == "unknown" {
+				ui.Skip.Printf("%-25s detached HEAD\n", name)
+				continue
+			}
+			if branch == base && !explicit {
+				continue
+			}
+
+			upstream := git.Upstream(repoDir)
+			if upstream != "" {
+				ahead, _ := git.AheadBehind(repoDir, branch, upstream)
+				if ahead == 0 {
+					continue
+				}
+			}
+
+			if err := git.Push(repoDir, "origin", branch, upstream == ""); err != nil {
+				ui.Fail.Printf("%-25s %v\n", name, err)
+				failed++
+				continue
+			}
+			pushed++
+			if upstream == "" {
+				ui.OK.Printf("%-25s %s (upstream set to origin/%s)\n", name, branch, branch)
+			} else {
+				ui.OK.Printf("%-25s %s\n", name, branch)
+			}
+
+			if branch != base {
+				if u := pullRequestURL(repoDir, repo, base, branch); u != "" {
+					links = append(links, prLink{name, u})
+				}
+			}
+		}
+
+		if pushed == 0 && failed == 0 {
+			fmt.Println("Nothing to push")
+			return nil
+		}
+
+		if len(links) > 0 {
+			fmt.Println("\nOpen pull requests:")
+			for _, l := range links {
+				fmt.Printf("  %-25s %s\n", l.repo, l.url)
+			}
+		}
+
+		if failed > 0 {
+			return errs.New(errs.KindCommandFailed, "%d repo(s) failed to push", failed)
+		}
+		return nil
+	},
+}
+
+// pullRequestURL returns the page for opening a PR from branch: against the fork's
+// upstream for a fork, otherwise within origin
+func pullRequestURL(repoDir string, repo workspace.RepoDef, base, branch string) string {
+	origin := git.RemoteURL(repoDir, "origin")
+	if repo.Upstream == "" {
+		return compareURL(origin, base, branch)
+	}
+	owner, _, _ := strings.Cut(git.GitHubSlug(origin), "/")
+	if owner == "" {
+		return ""
+	}
+	return compareURL(repo.Upstream, base, owner+":"+branch)
+}
+
+// compareURL returns the GitHub compare page that offers to open a PR for branch into base
+func compareURL(remoteURL, base, branch string) string {
+	slug := git.GitHubSlug(remoteURL)
+	if slug == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://github.com/%s/compare/%s...%s?expand=1", slug, base, branch)
+}
+
+func init() {
+	pushCmd.Flags().StringSliceVar(&pushRepos, "repos", nil, "Only push these repos (default: all repos with unpushed commits)")
+	rootCmd.AddCommand(pushCmd)
+}
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/semver"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseBump     string
+	releasePublish  bool
+	releaseWorkflow string
+	releaseDryRun   bool
+	releaseYes      bool
+)
+
+var releaseCmd = &cobra.Command{
+	Use:   "release <repo>",
+	Short: "Cut a release: bump version, tag, push, and create a GitHub release",
+	Long: `Cuts a release of a workspace repo from its default branch:
+
+  1. picks the next version — --bump major|minor|patch, or inferred from
+     conventional commits since the last v* tag (feat → minor, "!" or
+     BREAKING CHANGE → major, anything else → patch)
+  2. bumps package.json (via npm version) and commits "chore(release): vX.Y.Z"
+  3. tags vX.Y.Z and pushes the branch and tag
+  4. creates a GitHub release with generated notes
+  5. with --publish, triggers the publish workflow on the new tag
+
+The repo must be clean, on its default branch, and up to date with origin, and a
+GitHub token is needed before anything is tagged. If the release fails after the
+tag is pushed, run the command again: a tag at HEAD without a GitHub release is
+finished instead of reported as nothing to release.
+
+Examples:
+  spark-cli release AppModel
+  spark-cli release AppModel --bump minor --publish
+  spark-cli release AppModel --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		p, err := planRelease(wsPath, ws, args[0], releaseBump)
+		if err != nil {
+			return err
+		}
+		if p.commits == 0 {
+			return resumeRelease(p, releasePublish, releaseWorkflow)
+		}
+		fmt.Printf("%s: %s → %s (%s, %s)\n", p.name, p.current.Tag(), p.next.Tag(), p.kind, p.how)
+		if releaseDryRun {
+			return nil
+		}
+		target, err := checkReleaseTarget(p.repoDir, releasePublish)
+		if err != nil {
+			return err
+		}
+		if ok, err := confirmRelease(releaseYes, fmt.Sprintf("Release %s %s?", p.name, p.next.Tag())); !ok {
+			return err
+		}
+		return cutRelease(p, target, releasePublish, releaseWorkflow)
+	},
+}
+
+// releasePlan is a release about to be cut: where the repo stands and the version it gets
+type releasePlan struct {
+	name, repoDir, branch string
+	lastTag               string
+	commits               int // since lastTag; 0 means there is nothing to release
+	current, next         semver.Version
+	kind, how             string // the bump and how it was chosen
+}
+
+// planRelease checks that a repo can be released — clean, on its default branch, up to
+// date with origin — and picks the next version: bump, or inferred from the commits
+func planRelease(wsPath string, ws *workspace.Workspace, name, bump string) (*releasePlan, error) {
+	repo, ok := ws.Repos[name]
+	if !ok {
+		return nil, errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
+	}
+	repoDir := filepath.Join(wsPath, repo.Path)
+
+	// --- Preconditions ---
+	if git.IsDirty(repoDir) {
+		return nil, errs.New(errs.KindRepoDirty, "%s has uncommitted changes — commit or stash before releasing", name)
+	}
+	branch := git.GetCurrentBranch(repoDir)
+	base := getTargetBranch(ws, &repo, repoDir)
+	if branch != base {
+		return nil, fmt.Errorf("%s is on %s — releases are cut from %s", name, branch, base)
+	}
+	if err := git.FetchQuiet(repoDir, "origin"); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", name, err)
+	}
+	if _, behind := git.AheadBehind(repoDir, branch, "origin/"+base); behind > 0 {
+		return nil, fmt.Errorf("%s is %d commit(s) behind origin/%s — run 'spark-cli workspace sync %s' first", name, behind, base, name)
+	}
+
+	// --- Pick the version ---
+	p := &releasePlan{name: name, repoDir: repoDir, branch: branch, lastTag: git.LatestTag(repoDir, "v*")}
+	current, err := currentVersion(repoDir, p.lastTag)
+	if err != nil {
+		return nil, err
+	}
+	p.current = current
+	revRange := "HEAD"
+	if p.lastTag != "" {
+		revRange = p.lastTag + "..HEAD"
+	}
+	msgs, err := git.CommitMessages(repoDir, revRange)
+	if err != nil {
+		return nil, err
+	}
+	p.commits = len(msgs)
+	if p.commits == 0 {
+		return p, nil
+	}
+
+	p.kind, p.how = bump, "--bump"
+	if p.kind == "" {
+		p.kind = semver.Infer(msgs)
+		p.how = fmt.Sprintf("inferred from %d commit(s)", len(msgs))
+	}
+	if p.next, err = current.Bump(p.kind); err != nil {
+		return nil, errs.Wrap(errs.KindUsage, err)
+	}
+	return p, nil
+}
+
+// releaseTarget is the GitHub repo ("owner/name") a release is created in and the token
+// to create it with; slug is "" when origin isn't on GitHub
+type releaseTarget struct {
+	slug, token string
+}
+
+// checkReleaseTarget resolves where the GitHub release goes before anything is tagged, so
+// a missing token can't leave a pushed tag without its release
+func checkReleaseTarget(repoDir string, publish bool) (releaseTarget, error) {
+	slug := git.GitHubSlug(git.RemoteURL(repoDir, "origin"))
+	if slug == "" {
+		if publish {
+			return releaseTarget{}, fmt.Errorf("origin is not a GitHub remote — can't trigger the publish workflow")
+		}
+		return releaseTarget{}, nil
+	}
+	token := resolveGitHubToken()
+	if token == "" {
+		return releaseTarget{}, errs.New(errs.KindAuthExpired, "no GitHub token — set GITHUB_TOKEN or run 'gh auth login'")
+	}
+	return releaseTarget{slug: slug, token: token}, nil
+}
+
+// confirmRelease asks before releasing unless yes; without a terminal it requires yes
+func confirmRelease(yes bool, question string) (bool, error) {
+	if yes {
+		return true, nil
+	}
+	if prompt.NonInteractive {
+		return false, errs.New(errs.KindUsage, "pass --yes to release non-interactively")
+	}
+	if !prompt.Confirm(question, false) {
+		fmt.Println("Aborted")
+		return false, nil
+	}
+	return true, nil
+}
+
+// cutRelease bumps package.json, tags the release, and hands it to publishRelease
+func cutRelease(p *releasePlan, target releaseTarget, publish bool, workflow string) error {
+	repoDir, tag := p.repoDir, p.next.Tag()
+
+	if fileExistsCheck(filepath.Join(repoDir, "package.json")) {
+		command := fmt.Sprintf("npm version %s --no-git-tag-version", p.next)
+		if err := runSyncCmd(repoDir, command, nil, nil); err != nil {
+			return fmt.Errorf("%s failed: %w", command, err)
+		}
+		if err := git.AddAll(repoDir); err != nil {
+			return err
+		}
+		if err := git.Commit(repoDir, "chore(release): "+tag); err != nil {
+			return fmt.Errorf("failed to commit version bump: %w", err)
+		}
+		ui.OK.Printf("Bumped package.json to %s\n", p.next)
+	}
+	if err := git.CreateTag(repoDir, tag, tag); err != nil {
+		return fmt.Errorf("failed to tag %s: %w", tag, err)
+	}
+	return publishRelease(repoDir, p.branch, tag, target, publish, workflow)
+}
+
+// publishRelease pushes branch and tag, creates the GitHub release unless tag already has
+// one, and with publish triggers workflow on the tag. Each step can be re-run, so a release
+// that failed part-way is finished by running it again.
+func publishRelease(repoDir, branch, tag string, target releaseTarget, publish bool, workflow string) error {
+	if err := git.Push(repoDir, "origin", branch, false); err != nil {
+		return fmt.Errorf("failed to push %s: %w", branch, err)
+	}
+	if err := git.Push(repoDir, "origin", tag, false); err != nil {
+		return fmt.Errorf("failed to push %s: %w", tag, err)
+	}
+	ui.OK.Printf("Tagged and pushed %s\n", tag)
+
+	if target.slug == "" {
+		ui.Skip.Println("origin is not a GitHub remote — skipping GitHub release")
+		return nil
+	}
+	rel, err := github.ReleaseByTag(target.token, target.slug, tag)
+	if err != nil {
+		return errs.New(errs.KindCommandFailed, "tag %s pushed, but looking up its GitHub release failed: %w", tag, err)
+	}
+	if rel != nil {
+		ui.Skip.Printf("Release already exists: %s\n", rel.HTMLURL)
+	} else {
+		if rel, err = github.CreateRelease(target.token, target.slug, tag); err != nil {
+			return errs.New(errs.KindCommandFailed, "tag %s pushed, but creating the GitHub release failed: %w", tag, err)
+		}
+		ui.OK.Printf("Release created: %s\n", rel.HTMLURL)
+	}
+
+	if publish {
+		if err := github.DispatchWorkflow(target.token, target.slug, workflow, tag); err != nil {
+			return errs.New(errs.KindCommandFailed, "failed to trigger %s: %w", workflow, err)
+		}
+		ui.OK.Printf("Triggered %s on %s\n", workflow, tag)
+	}
+	return nil
+}
+
+// resumeRelease finishes a release with nothing new to cut: when the last tag is at HEAD
+// but has no GitHub release (creating it failed after the push), it creates it now
+func resumeRelease(p *releasePlan, publish bool, workflow string) error {
+	nothing := fmt.Sprintf("Nothing to release — no commits since %s", p.lastTag)
+	head, err := git.HeadSHA(p.repoDir)
+	if err != nil || p.lastTag == "" || git.TagCommit(p.repoDir, p.lastTag) != head {
+		fmt.Println(nothing)
+		return nil
+	}
+	target, err := checkReleaseTarget(p.repoDir, false)
+	if err != nil {
+		return err
+	}
+	if target.slug == "" {
+		fmt.Println(nothing)
+		return nil
+	}
+	rel, err := github.ReleaseByTag(target.token, target.slug, p.lastTag)
+	if err != nil {
+		return errs.New(errs.KindCommandFailed, "failed to look up the GitHub release for %s: %w", p.lastTag, err)
+	}
+	if rel != nil {
+		fmt.Println(nothing)
+		return nil
+	}
+
+	fmt.Printf("%s: %s is tagged but has no GitHub release\n", p.name, p.lastTag)
+	if releaseDryRun {
+		return nil
+	}
+	if ok, err := confirmRelease(releaseYes, fmt.Sprintf("Finish releasing %s %s?", p.name, p.lastTag)); !ok {
+		return err
+	}
+	return publishRelease(p.repoDir, p.branch, p.lastTag, target, publish, workflow)
+}
+
+// currentVersion is the last release tag's version, falling back to package.json's
+// version and then 0.0.0 for repos that have never been released
+func currentVersion(repoDir, lastTag string) (semver.Version, error) {
+	if lastTag != "" {
+		return semver.Parse(lastTag)
+	}
+	data, err := os.ReadFile(filepath.Join(repoDir, "package.json"))
+	if err == nil {
+		var pkg struct {
+			Version string `json:"version"`
+		}
+		if json.Unmarshal(data, &pkg) == nil && pkg.Version != "" {
+			return semver.Parse(pkg.Version)
+		}
+	}
+	return semver.Version{}, nil
+}
+
+func init() {
+	releaseCmd.Flags().StringVar(&releaseBump, "bump", "", "Version bump: major, minor, or patch (default: inferred from commits)")
+	releaseCmd.Flags().BoolVar(&releasePublish, "publish", false, "Trigger the publish workflow on the new tag")
+	releaseCmd.Flags().StringVar(&releaseWorkflow, "workflow", "publish.yml", "Workflow file dispatched by --publish")
+	releaseCmd.Flags().BoolVar(&releaseDryRun, "dry-run", false, "Show the next version without changing anything")
+	releaseCmd.Flags().BoolVarP(&releaseYes, "yes", "y", false, "Don't ask for confirmation")
+	rootCmd.AddCommand(releaseCmd)
+}
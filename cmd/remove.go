@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -34,7 +35,7 @@ Example:
 
 		repo, ok := ws.Repos[name]
 		if !ok {
-			return fmt.Errorf("repo '%s' not found in workspace", name)
+			return errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
 		}
 
 		repoDir := filepath.Join(wsPath, repo.Path)
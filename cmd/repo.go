@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellquote"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var repoShowJSON bool
+
+var repoCmd = &cobra.Command{
+	Use:   "repo",
+	Short: "Inspect or edit a repo's entry in workspace.json (show | path | set)",
+}
+
+var repoShowCmd = &cobra.Command{
+	Use:   "show [repo]",
+	Short: "Show a repo's manifest entry and what spark-cli resolves from it",
+	Long: `Shows a repo's entry in workspace.json (default: the repo you're in) along with
+the values spark-cli derives from it: whether it's cloned, the checked-out and
+target branches, the build and test commands that will run, and the repos it
+depends on and that depend on it. --json prints the raw manifest entry.
+
+Examples:
+  spark-cli repo show AppAPI
+  spark-cli repo show --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		} else if name, _ = detectCurrentRepo(wsPath, ws); name == "" {
+			return errs.New(errs.KindUsage, "not inside a workspace repo — name the repo to show")
+		}
+		repo, ok := ws.Repos[name]
+		if !ok {
+			return errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
+		}
+
+		if repoShowJSON {
+			out, err := json.MarshalIndent(repo, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
+		}
+
+		repoDir := filepath.Join(wsPath, repo.Path)
+		cloned := git.IsRepo(repoDir)
+		row := func(label, value string) {
+			fmt.Printf("  %-16s %s\n", label+":", value)
+		}
+
+		fmt.Println(name)
+		row("Remote", orDefault(repo.Remote, "(none)"))
+		if repo.Upstream != "" {
+			row("Upstream", repo.Upstream+" (fork: synced from here, pushed to Remote)")
+		}
+		if cloned {
+			row("Path", repo.Path)
+		} else {
+			row("Path", repo.Path+" (not cloned)")
+		}
+		if cloned {
+			row("Branch", fmt.Sprintf("%s (target: %s)", git.GetCurrentBranch(repoDir), getTargetBranch(ws, &repo, repoDir)))
+		}
+		switch {
+		case repo.DefaultBranch != "":
+			row("Default branch", repo.DefaultBranch)
+		case ws.DefaultBranch != "":
+			row("Default branch", ws.DefaultBranch+" (workspace default)")
+		default:
+			row("Default branch", "(detected from origin)")
+		}
+		row("Build", repoCommandDesc(repoDir, repo.BuildCommand, "build", repo.ArgsFor("build", nil)))
+		row("Test", repoCommandDesc(repoDir, repo.TestCommand, "test", repo.ArgsFor("test", nil)))
+
+		declared := make(map[string]bool)
+		for _, dep := range repo.Dependencies {
+			declared[dep] = true
+		}
+		var deps []string
+		for _, dep := range workspace.Dependencies(wsPath, ws, name) {
+			if !declared[dep] {
+				dep += " (spk.config.json)"
+			}
+			deps = append(deps, dep)
+		}
+		row("Dependencies", orDefault(strings.Join(deps, ", "), "(none)"))
+		var dependents []string
+		for _, d := range workspace.Dependents(wsPath, ws, []string{name}) {
+			if d != name {
+				dependents = append(dependents, d)
+			}
+		}
+		row("Dependents", orDefault(strings.Join(dependents, ", "), "(none)"))
+		if len(repo.Tags) > 0 {
+			row("Tags", strings.Join(repo.Tags, ", "))
+		}
+		if repo.ModelFor != "" {
+			row("Model for", repo.ModelFor)
+		}
+		scripts := make([]string, 0, len(repo.ScriptArgs))
+		for script := range repo.ScriptArgs {
+			scripts = append(scripts, script)
+		}
+		sort.Strings(scripts)
+		for _, script := range scripts {
+			row("Args ("+script+")", strings.Join(repo.ScriptArgs[script], " "))
+		}
+		return nil
+	},
+}
+
+// repoCommandDesc describes the command a build or test runs: the manifest's, or the
+// project's script
+func repoCommandDesc(repoDir, configured, script string, args []string) string {
+	if configured != "" {
+		if len(args) > 0 {
+			configured += " " + shellquote.Join(args)
+		}
+		return configured + " (workspace.json)"
+	}
+	if command := buildCommand(repoDir, detectProjectType(repoDir), script, args); command != "" {
+		return command
+	}
+	return "(unknown project type)"
+}
+
+var repoPathCmd = &cobra.Command{
+	Use:   "path [repo]",
+	Short: "Print a repo's directory (or the workspace root)",
+	Long: `Prints the absolute directory of a repo, or of the workspace with no repo. The
+'spark-cli cd' shell function ('spark-cli setup shell') is built on it.
+
+Examples:
+  spark-cli repo path AppAPI
+  cd "$(spark-cli repo path AppAPI)"`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeRepoNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			fmt.Println(wsPath)
+			return nil
+		}
+		repo, ok := ws.Repos[args[0]]
+		if !ok {
+			return errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", args[0])
+		}
+		fmt.Println(filepath.Join(wsPath, repo.Path))
+		return nil
+	},
+}
+
+// completeRepoNames completes the first argument with the workspace's repo names
+func completeRepoNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	_, ws, err := loadWorkspace()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, _ := expandRepoNames(ws, nil)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+var repoSetCmd = &cobra.Command{
+	Use:   "set <repo> <field> <value>",
+	Short: "Set a field of a repo's workspace.json entry",
+	Long: `Sets one field of a repo's entry in workspace.json (remote, path,
+build_command, test_command, default_branch, dependencies, tags, ...) and checks
+the manifest before saving: dependencies must name workspace repos and can't
+form a cycle, and no two repos can share a path. Lists are comma-separated; an
+empty value removes the field. Nested settings such as gradle or docker are
+edited with 'spark-cli config edit'.
+
+Tags group repos for any --repos flag: --repos @api selects every repo tagged api.
+
+Examples:
+  spark-cli repo set AppAPI build_command "npm run build:fast"
+  spark-cli repo set AppAPI dependencies AppModel,BusinessModel
+  spark-cli repo set AppAPI tags api,backend
+  spark-cli repo set Web default_branch ""`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, key, value := args[0], args[1], args[2]
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		repo, ok := ws.Repos[name]
+		if !ok {
+			return errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
+		}
+		if err := workspace.SetField(&repo, key, value); err != nil {
+			return err
+		}
+		ws.Repos[name] = repo
+		if err := saveValidatedWorkspace(wsPath, ws); err != nil {
+			return err
+		}
+		if value == "" {
+			ui.OK.Printf("%s: removed %s\n", name, key)
+		} else {
+			ui.OK.Printf("%s: %s = %s\n", name, key, value)
+		}
+		return nil
+	},
+}
+
+func init() {
+	repoShowCmd.Flags().BoolVar(&repoShowJSON, "json", false, "Print the workspace.json entry as JSON")
+	repoCmd.AddCommand(repoShowCmd, repoPathCmd, repoSetCmd)
+	rootCmd.AddCommand(repoCmd)
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/progress"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reposArchived   bool
+	reposVisibility string
+)
+
+var reposCmd = &cobra.Command{
+	Use:   "repos",
+	Short: "Discover repositories in the GitHub org (search | -h)",
+	Long: `Lists repositories in the GitHub org (Spark-Rewards unless overridden by
+default_github_org in ~/.spk/config.json) using the resolved GitHub token.
+
+Archived repos are hidden unless --archived is passed.
+
+Examples:
+  spark-cli repos
+  spark-cli repos search api
+  spark-cli repos search model --visibility private
+  spark-cli use --browse api        # pick from the list and clone`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReposSearch("")
+	},
+}
+
+var reposSearchCmd = &cobra.Command{
+	Use:   "search <term>",
+	Short: "Search org repositories by name or description",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReposSearch(args[0])
+	},
+}
+
+func runReposSearch(term string) error {
+	repos, err := findOrgRepos(term)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		fmt.Println("No matching repositories")
+		return nil
+	}
+	printOrgRepos(repos)
+	fmt.Printf(binname.Rewrite("\n%d repositories — clone with: spark-cli use <name>  (or spark-cli use --browse %s)\n"), len(repos), term)
+	return nil
+}
+
+// findOrgRepos lists org repos matching term, honoring the --archived/--visibility filters
+func findOrgRepos(term string) ([]github.Repo, error) {
+	token := resolveGitHubToken()
+	if token == "" {
+		return nil, fmt.Errorf("no GitHub token — set GITHUB_TOKEN or run 'gh auth login'")
+	}
+	org := githubOrg()
+	all, err := github.ListOrgRepos(token, org)
+	if err != nil {
+		return nil, err
+	}
+	return filterOrgRepos(all, term, reposArchived, reposVisibility), nil
+}
+
+// filterOrgRepos keeps repos whose name or description contains term (case-insensitive)
+func filterOrgRepos(repos []github.Repo, term string, includeArchived bool, visibility string) []github.Repo {
+	term = strings.ToLower(term)
+	var out []github.Repo
+	for _, r := range repos {
+		if r.Archived && !includeArchived {
+			continue
+		}
+		if visibility != "" && !strings.EqualFold(repoVisibility(r), visibility) {
+			continue
+		}
+		if term != "" && !strings.Contains(strings.ToLower(r.Name), term) && !strings.Contains(strings.ToLower(r.Description), term) {
+			continue
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func repoVisibility(r github.Repo) string {
+	if r.Visibility != "" {
+		return r.Visibility
+	}
+	if r.Private {
+		return "private"
+	}
+	return "public"
+}
+
+func printOrgRepos(repos []github.Repo) {
+	fmt.Printf("%-30s %-10s %s\n", "REPO", "VISIBILITY", "DESCRIPTION")
+	fmt.Printf("%-30s %-10s %s\n", "----", "----------", "-----------")
+	for _, r := range repos {
+		vis := repoVisibility(r)
+		if r.Archived {
+			vis += "*"
+		}
+		fmt.Printf("%-30s %-10s %s\n", r.Name, vis, r.Description)
+	}
+}
+
+// browseAndClone lets the user pick org repos matching term and clones them into the workspace
+func browseAndClone(wsPath, term string) error {
+	repos, err := findOrgRepos(term)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return fmt.Errorf("no repositories match %q", term)
+	}
+
+	labels := make([]string, len(repos))
+	for i, r := range repos {
+		labels[i] = fmt.Sprintf("%-30s %s", r.Name, r.Description)
+	}
+	picked, err := prompt.Pick(fmt.Sprintf("Repositories in %s:", githubOrg()), labels)
+	if err != nil {
+		return err
+	}
+	if len(picked) == 0 {
+		fmt.Println("Nothing selected")
+		return nil
+	}
+
+	org := githubOrg()
+	for n, i := range picked {
+		fmt.Print(progress.Step(n+1, len(picked)))
+		if _, err := cloneRepo(wsPath, org+"/"+repos[i].Name); err != nil {
+			ui.Fail.Printf("%s: %v\n", repos[i].Name, err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{reposCmd, reposSearchCmd, useCmd} {
+		c.Flags().BoolVar(&reposArchived, "archived", false, "Include archived repositories")
+		c.Flags().StringVar(&reposVisibility, "visibility", "", "Only show repos with this visibility (public, private, internal)")
+	}
+	reposCmd.AddCommand(reposSearchCmd)
+	rootCmd.AddCommand(reposCmd)
+}
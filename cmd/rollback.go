@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rollbackRepos  []string
+	rollbackDryRun bool
+	rollbackNoPush bool
+	rollbackDraft  bool
+	rollbackYes    bool
+)
+
+// ticketInRe finds a ticket key inside a branch name such as feat/SPK-123-add-tiers
+var ticketInRe = regexp.MustCompile(`[A-Z][A-Z0-9]*-\d+`)
+
+// rollbackPlan is the commits to revert in one repo
+type rollbackPlan struct {
+	repo    string
+	repoDir string
+	base    string
+	commits []git.MatchedCommit
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <branch|ticket>",
+	Short: "Revert a merged cross-repo change set, consumers before models",
+	Long: `Finds the commits a change set landed on each repo's default branch and opens
+revert PRs for them, ordered so consumers are reverted before the models they
+depend on (the reverse of the build order).
+
+The change set is a branch name (e.g. from 'spark-cli work start') or a ticket.
+Matching follows the default branch's first parents, so a merged PR is one merge
+or squash commit whose message names the branch or ticket — run with --dry-run
+to check what will be reverted. An earlier rollback of the same change set is
+left alone, along with the commits it already reverted.
+
+In each affected repo a revert/<change set> branch is created from
+origin/<default branch>, the commits are reverted newest first, the branch is
+pushed, and a PR is opened; the PRs link each other and list the merge order.
+Your checked-out branch is restored afterwards. With --no-push the revert
+branches stay local and checked out for review.
+
+Examples:
+  spark-cli rollback feat/SPK-42-add-loyalty-tiers --dry-run
+  spark-cli rollback SPK-42
+  spark-cli rollback SPK-42 --repos AppAPI,AppModel --no-push`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changeSet := args[0]
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, rollbackRepos)
+		if err != nil {
+			return err
+		}
+		order, err := workspace.BuildOrder(wsPath, ws, names)
+		if err != nil {
+			return err
+		}
+		order = onlyRepos(order, names)
+
+		patterns := rollbackPatterns(changeSet)
+		var plans []rollbackPlan
+		for i := len(order) - 1; i >= 0; i-- {
+			name := order[i]
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+			base := getTargetBranch(ws, &repo, repoDir)
+			if err := git.FetchQuiet(repoDir, "origin"); err != nil {
+				ui.Warn.Printf("%-25s fetch failed — searching origin/%s as of the last fetch\n", name, base)
+			}
+			commits, err := git.FirstParentCommitsMatching(repoDir, "origin/"+base, patterns)
+			if err != nil {
+				ui.Warn.Printf("%-25s %v\n", name, err)
+				continue
+			}
+			if commits = withoutReverts(commits); len(commits) > 0 {
+				plans = append(plans, rollbackPlan{repo: name, repoDir: repoDir, base: base, commits: commits})
+			}
+		}
+		if len(plans) == 0 {
+			fmt.Printf("No commits for %s found on any default branch\n", changeSet)
+			return nil
+		}
+
+		fmt.Printf("Rollback of %s (in this order):\n", changeSet)
+		for i, p := range plans {
+			fmt.Printf("  %d. %s (origin/%s)\n", i+1, p.repo, p.base)
+			for _, c := range p.commits {
+				fmt.Printf("       %s %s\n", git.ShortRef(c.SHA), c.Subject)
+			}
+		}
+		if rollbackDryRun {
+			return nil
+		}
+		if !rollbackYes {
+			if prompt.NonInteractive {
+				return errs.New(errs.KindUsage, "pass --yes to roll back non-interactively")
+			}
+			if !prompt.Confirm(fmt.Sprintf("Revert in %d repo(s)?", len(plans)), false) {
+				fmt.Println("Aborted")
+				return nil
+			}
+		}
+		fmt.Println()
+
+		revertBranch := "revert/" + changeSet
+		token := ""
+		if !rollbackNoPush {
+			token = resolveGitHubToken()
+		}
+		type openedPR struct {
+			repo, slug string
+			number     int
+			url        string
+		}
+		var opened []openedPR
+		var failed int
+
+		for _, p := range plans {
+			if git.IsDirty(p.repoDir) {
+				ui.Fail.Printf(binname.Rewrite("%-25s uncommitted changes — commit or 'spark-cli stash push' first\n"), p.repo)
+				failed++
+				continue
+			}
+			if git.BranchExists(p.repoDir, revertBranch) {
+				ui.Fail.Printf("%-25s %s already exists\n", p.repo, revertBranch)
+				failed++
+				continue
+			}
+			original := git.GetCurrentBranch(p.repoDir)
+			if err := revertChangeSet(p, revertBranch); err != nil {
+				ui.Fail.Printf("%-25s %v\n", p.repo, err)
+				git.RestoreBranchQuiet(p.repoDir, original)
+				git.DeleteBranch(p.repoDir, revertBranch)
+				failed++
+				continue
+			}
+			if rollbackNoPush {
+				ui.OK.Printf("%-25s reverted %d commit(s) on %s\n", p.repo, len(p.commits), revertBranch)
+				continue
+			}
+
+			err := git.Push(p.repoDir, "origin", revertBranch, true)
+			git.RestoreBranchQuiet(p.repoDir, original)
+			if err != nil {
+				ui.Fail.Printf("%-25s push failed: %v\n", p.repo, err)
+				failed++
+				continue
+			}
+			slug := git.GitHubSlug(git.RemoteURL(p.repoDir, "origin"))
+			if slug == "" || token == "" {
+				ui.OK.Printf("%-25s pushed %s (open the PR manually — no GitHub remote or token)\n", p.repo, revertBranch)
+				continue
+			}
+			pr, err := github.CreatePullRequest(token, slug, github.NewPullRequest{
+				Title: "Revert " + changeSet,
+				Head:  revertBranch,
+				Base:  p.base,
+				Body:  rollbackPRBody(changeSet, plans, nil),
+				Draft: rollbackDraft,
+			})
+			if err != nil {
+				ui.Fail.Printf("%-25s pushed, but opening the PR failed: %v\n", p.repo, err)
+				failed++
+				continue
+			}
+			ui.OK.Printf("%-25s %s\n", p.repo, pr.HTMLURL)
+			opened = append(opened, openedPR{p.repo, slug, pr.Number, pr.HTMLURL})
+		}
+
+		if len(opened) > 1 {
+			links := make(map[string]string)
+			for _, o := range opened {
+				links[o.repo] = o.url
+			}
+			for _, o := range opened {
+				if err := github.UpdatePullRequestBody(token, o.slug, o.number, rollbackPRBody(changeSet, plans, links)); err != nil {
+					fmt.Printf("  %s: couldn't link sibling PRs: %v\n", o.repo, err)
+				}
+			}
+		}
+
+		if failed > 0 {
+			return errs.New(errs.KindCommandFailed, "%d repo(s) failed to roll back", failed)
+		}
+		return nil
+	},
+}
+
+// rollbackPatterns matches commit messages naming the branch, or the ticket in it
+func rollbackPatterns(changeSet string) []string {
+	var patterns []string
+	if !ticketRe.MatchString(changeSet) {
+		// feat/SPK-4 shouldn't match feat/SPK-42-x
+		patterns = append(patterns, regexp.QuoteMeta(changeSet)+"([^A-Za-z0-9._/-]|$)")
+	}
+	if ticket := ticketInRe.FindString(strings.ToUpper(changeSet)); ticket != "" {
+		// SPK-42 shouldn't match SPK-421
+		patterns = append(patterns, regexp.QuoteMeta(ticket)+"([^0-9]|$)")
+	}
+	return patterns
+}
+
+// withoutReverts drops an earlier rollback of the change set from its matched commits:
+// the reverts (a "Revert \"...\"" subject, or reverting a matched commit) and the
+// commits they already reverted
+func withoutReverts(commits []git.MatchedCommit) []git.MatchedCommit {
+	drop := make(map[string]bool)
+	for _, c := range commits {
+		if strings.HasPrefix(c.Subject, `Revert "`) {
+			drop[c.SHA] = true
+		}
+		for _, sha := range c.Reverts {
+			for _, m := range commits {
+				if strings.HasPrefix(m.SHA, sha) {
+					drop[c.SHA], drop[m.SHA] = true, true
+				}
+			}
+		}
+	}
+	var kept []git.MatchedCommit
+	for _, c := range commits {
+		if !drop[c.SHA] {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// revertChangeSet creates branch from origin/<base> and reverts the plan's commits on it,
+// newest first
+func revertChangeSet(p rollbackPlan, branch string) error {
+	if err := git.CreateBranchFrom(p.repoDir, branch, "origin/"+p.base); err != nil {
+		return err
+	}
+	for _, c := range p.commits {
+		if err := git.Revert(p.repoDir, c.SHA, c.Merge); err != nil {
+			git.RevertAbort(p.repoDir)
+			return fmt.Errorf("%w — revert it by hand", err)
+		}
+	}
+	return nil
+}
+
+// rollbackPRBody lists the repos in merge order, with their PR links once known
+func rollbackPRBody(changeSet string, plans []rollbackPlan, links map[string]string) string {
+	body := fmt.Sprintf("Reverts %s.\n\nMerge in this order (consumers before models):\n", changeSet)
+	for i, p := range plans {
+		body += fmt.Sprintf("%d. %s", i+1, p.repo)
+		if u := links[p.repo]; u != "" {
+			body += " — " + u
+		}
+		body += "\n"
+	}
+	return body
+}
+
+func init() {
+	rollbackCmd.Flags().StringSliceVar(&rollbackRepos, "repos", nil, "Only roll back these repos")
+	rollbackCmd.Flags().BoolVar(&rollbackDryRun, "dry-run", false, "List the commits that would be reverted")
+	rollbackCmd.Flags().BoolVar(&rollbackNoPush, "no-push", false, "Leave the revert branches local and checked out")
+	rollbackCmd.Flags().BoolVar(&rollbackDraft, "draft", false, "Open the PRs as drafts")
+	rollbackCmd.Flags().BoolVarP(&rollbackYes, "yes", "y", false, "Don't ask for confirmation")
+	rootCmd.AddCommand(rollbackCmd)
+}
@@ -1,9 +1,28 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/notify"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/progress"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/retry"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
@@ -13,25 +32,297 @@ var (
 	Date    = "unknown"
 )
 
+var (
+	rootTimeout     time.Duration
+	rootErrorFormat string
+	rootNoLogin     bool
+	rootWorkspace   string
+	rootNoInteract  bool
+	rootNoColor     bool
+	rootTrace       bool
+	rootVerbose     int
+)
+
 var rootCmd = &cobra.Command{
 	Use:     "spark-cli",
 	Short:   "spark-cli — multi-repo workspace CLI",
 	Version: Version,
 	Long: `spark-cli manages multi-repo workspaces with shared environment and smart builds.
+It's also installed as spk; help and hints use whichever name you run.
+
+Exit codes:
+  1 error, 2 usage, 3 workspace-not-found, 4 repo-not-found, 5 repo-dirty,
+  6 auth-expired, 7 build-failed, 8 command-failed, 124 timeout, 130 interrupted
 `,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return applyRootFlags()
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		nudgeOutdated(cmd)
+	},
+}
+
+// applyRootFlags applies the global flags. Commands that parse their own flags (cdk)
+// call it again once they've taken the global ones out of their args.
+func applyRootFlags() error {
+	if rootErrorFormat != "text" && rootErrorFormat != "json" {
+		return errs.New(errs.KindUsage, "invalid --error-format %q — use text or json", rootErrorFormat)
+	}
+	applyTimeout(rootTimeout)
+	applyRetryPolicy()
+	aws.DisableLogin(rootNoLogin || os.Getenv("SPK_NO_LOGIN") != "")
+	workspace.SetOverride(rootWorkspace)
+	if rootNoInteract {
+		prompt.NonInteractive = true
+	}
+	if rootNoColor {
+		ui.DisableColor()
+	}
+	if !traceEnabled && (rootTrace || rootVerbose >= 3 || os.Getenv("SPK_TRACE") != "") {
+		traceEnabled = true
+		enableTrace()
+	}
+	return nil
+}
+
+// takeRootFlag sets the global flag at args[i], if it is one, for a command with flag
+// parsing disabled. It returns how many args the flag took (0 if it isn't global).
+func takeRootFlag(args []string, i int) (int, error) {
+	arg := args[i]
+	fs := rootCmd.PersistentFlags()
+	name, val, hasVal := "", "", false
+	switch {
+	case strings.HasPrefix(arg, "--"):
+		name, val, hasVal = strings.Cut(arg[2:], "=")
+	case len(arg) == 2 && arg[0] == '-':
+		if short := fs.ShorthandLookup(arg[1:]); short != nil {
+			name = short.Name
+		}
+	case strings.HasPrefix(arg, "-v") && strings.Trim(arg[1:], "v") == "":
+		// -vvv
+		rootVerbose += len(arg) - 1
+		return 1, nil
+	}
+	f := fs.Lookup(name)
+	if f == nil {
+		return 0, nil
+	}
+	n := 1
+	switch {
+	case hasVal:
+	case f.NoOptDefVal != "":
+		val = f.NoOptDefVal
+	case i+1 < len(args):
+		val = args[i+1]
+		n = 2
+	default:
+		return 0, errs.New(errs.KindUsage, "flag needs an argument: %s", arg)
+	}
+	if err := f.Value.Set(val); err != nil {
+		return 0, errs.New(errs.KindUsage, "invalid argument %q for %s: %v", val, arg, err)
+	}
+	return n, nil
+}
+
+// traceLogName is the trace log inside a .spk/logs directory
+const traceLogName = "trace.log"
+
+// enableTrace starts logging external commands to the workspace's trace log, or the
+// global one outside a workspace
+func enableTrace() {
+	var dir string
+	if wsPath, err := workspace.Find(); err == nil {
+		dir = workspace.SparkDir(wsPath)
+	} else if dir, err = config.GlobalDir(); err != nil {
+		return
+	}
+	path := filepath.Join(dir, "logs", traceLogName)
+	if err := proc.EnableTrace(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: can't write trace log: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Tracing commands to %s\n", path)
+}
+
+// traceEnabled is set once the trace log is open, so applying the flags again doesn't
+// reopen it
+var traceEnabled bool
+
+// cancelTimeout releases the --timeout context once the command finishes
+var cancelTimeout context.CancelFunc = func() {}
+
+// applyTimeout bounds every shell-out made through internal/proc by d (0 = no limit)
+func applyTimeout(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(proc.Context(), d)
+	proc.SetContext(ctx)
+	cancelTimeout = cancel
+}
+
+// applyRetryPolicy configures network retries from ~/.spk/config.json
+func applyRetryPolicy() {
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return
+	}
+	retry.Configure(cfg.RetryMaxAttempts, time.Duration(cfg.RetryBackoffMs)*time.Millisecond)
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	proc.SetContext(ctx)
+
+	applyBinName(rootCmd)
+	usageArgs(rootCmd)
+	args, err := upgradeLegacyArgs(expandAlias(os.Args[1:]))
+	rootCmd.SetArgs(args)
+
+	jsonErrors := wantsJSONErrors(os.Args[1:])
+	if jsonErrors {
+		rootCmd.SilenceErrors = true
+		rootCmd.SilenceUsage = true
+	}
+
+	start := time.Now()
+	var ran *cobra.Command
+	if err == nil {
+		ran, err = rootCmd.ExecuteContextC(ctx)
 	}
+	timedOut, interrupted := proc.TimedOut(), proc.Interrupted()
+	if !interrupted {
+		notifyDone(ran, time.Since(start), err)
+	}
+	cancelTimeout()
+	stop()
+	proc.CloseTrace()
+
+	switch {
+	case timedOut:
+		err = errs.New(errs.KindTimeout, "timed out after %s", rootTimeout)
+	case interrupted:
+		err = errs.New(errs.KindInterrupted, "interrupted")
+	case err == nil:
+		return
+	}
+
+	if jsonErrors {
+		out, _ := json.Marshal(map[string]interface{}{
+			"error":     binname.Rewrite(err.Error()),
+			"kind":      errs.KindOf(err),
+			"exit_code": errs.ExitCode(err),
+		})
+		fmt.Fprintln(os.Stderr, string(out))
+	} else {
+		fmt.Fprintln(os.Stderr, binname.Rewrite(err.Error()))
+	}
+	os.Exit(errs.ExitCode(err))
+}
+
+// applyBinName makes the help of cmd and its subcommands name the binary the way it was
+// invoked — spk or spark-cli — as do errors cobra prints itself
+func applyBinName(cmd *cobra.Command) {
+	if binname.Name() == binname.Canonical {
+		return
+	}
+	if !cmd.HasParent() {
+		cmd.Use = binname.Name()
+		cmd.SetErr(rewriteWriter{os.Stderr})
+		cmd.SetVersionTemplate(binname.Rewrite(cmd.VersionTemplate()))
+	}
+	cmd.Short = binname.Rewrite(cmd.Short)
+	cmd.Long = binname.Rewrite(cmd.Long)
+	cmd.Example = binname.Rewrite(cmd.Example)
+	for _, sub := range cmd.Commands() {
+		applyBinName(sub)
+	}
+}
+
+// usageArgs tags the errors from cmd's and its subcommands' Args validators (ExactArgs,
+// NoArgs, ...) as usage errors, as SetFlagErrorFunc does for flag errors
+func usageArgs(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			return errs.Wrap(errs.KindUsage, validate(cmd, args))
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		usageArgs(sub)
+	}
+}
+
+// rewriteWriter names the binary the way it was invoked in what's written through it
+type rewriteWriter struct{ w io.Writer }
+
+func (r rewriteWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, binname.Rewrite(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// notifyCommands are the long-running commands that announce when they finish
+var notifyCommands = map[string]bool{"build": true, "test": true, "sync": true, "run": true, "ci-run": true}
+
+// defaultNotifyAfter is how long a command must run before it notifies, unless configured
+const defaultNotifyAfter = 30 * time.Second
+
+// notifyDone rings the bell and/or shows a desktop notification with the outcome of a
+// long build, test, or sync, as configured by "notify" in ~/.spk/config.json
+func notifyDone(cmd *cobra.Command, elapsed time.Duration, err error) {
+	if cmd == nil || !notifyCommands[cmd.Name()] {
+		return
+	}
+	cfg, cfgErr := config.LoadGlobal()
+	if cfgErr != nil || cfg.Notify == "" {
+		return
+	}
+	threshold := defaultNotifyAfter
+	if cfg.NotifyAfterSeconds > 0 {
+		threshold = time.Duration(cfg.NotifyAfterSeconds) * time.Second
+	}
+	if elapsed < threshold {
+		return
+	}
+	msg := fmt.Sprintf("✓ %s finished in %s", cmd.CommandPath(), progress.Elapsed(elapsed))
+	if err != nil {
+		msg = fmt.Sprintf("✗ %s failed after %s: %v", cmd.CommandPath(), progress.Elapsed(elapsed), err)
+	}
+	notify.Send(cfg.Notify, "spark-cli", msg)
+}
+
+// wantsJSONErrors scans raw args for --error-format json, so errors raised before flag
+// parsing completes (unknown flags, bad args) are reported in the requested format too
+func wantsJSONErrors(args []string) bool {
+	for i, a := range args {
+		if a == "--" {
+			break
+		}
+		if a == "--error-format=json" || (a == "--error-format" && i+1 < len(args) && args[i+1] == "json") {
+			return true
+		}
+	}
+	return false
 }
 
 func init() {
 	rootCmd.SetVersionTemplate(fmt.Sprintf("spark-cli %s (%s %s)\n", Version, Commit, Date))
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
+	rootCmd.PersistentFlags().StringVar(&rootErrorFormat, "error-format", "text", "How to print failures: text, or json ({\"error\", \"kind\", \"exit_code\"} on stderr)")
+	rootCmd.PersistentFlags().DurationVar(&rootTimeout, "timeout", 0, "Abort after this long, killing any running child processes (e.g. 10m)")
+	rootCmd.PersistentFlags().StringVarP(&rootWorkspace, "workspace", "w", "", "Workspace to use (path or registered name; default: the one you're in, or $SPK_WORKSPACE)")
+	rootCmd.PersistentFlags().BoolVar(&rootNoInteract, "no-interactive", false, "Never prompt (e.g. the repo list build, test, and use offer); fail where a choice is needed")
+	rootCmd.PersistentFlags().BoolVar(&rootNoColor, "no-color", false, "Don't color output (same as setting NO_COLOR)")
+	rootCmd.PersistentFlags().BoolVar(&rootTrace, "trace", false, "Log every external command (git, npm, aws, gh) with its directory, duration, and exit code to .spk/logs/trace.log (or SPK_TRACE=1)")
+	rootCmd.PersistentFlags().CountVarP(&rootVerbose, "verbose", "v", "Verbosity; -vvv is the same as --trace")
+	rootCmd.PersistentFlags().BoolVar(&rootNoLogin, "no-login", false, "Fail instead of starting an AWS SSO login when the session has expired (or SPK_NO_LOGIN=1)")
+
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return errs.Wrap(errs.KindUsage, err)
+	})
+
 	// No "help" subcommand — use -h/--help only
 	rootCmd.SetHelpCommand(&cobra.Command{Hidden: true})
 }
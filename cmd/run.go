@@ -1,18 +1,35 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/gradle"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/metrics"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/retry"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellquote"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
+var (
+	runProfile bool
+	// frozenInstall is --frozen; the workspace's frozen_lockfile setting also applies (see installOptions)
+	frozenInstall bool
+)
+
 type projectType int
 
 const (
@@ -44,13 +61,42 @@ Workspace env includes:
   - workspace.json env overrides
   - GITHUB_TOKEN (auto-resolved from gh auth if not set)
 
+A repo can limit what it sees with names or patterns in workspace.json, so
+e.g. payment keys never reach the mobile build (the denylist wins). The lists
+apply to its env file too ('spark-cli env link'):
+
+  "env_allowlist": ["APP_*", "AWS_REGION", "GITHUB_TOKEN"],
+  "env_denylist":  ["STRIPE_*"]
+
+Gradle builds (including a React Native app's android/) also get each variable
+as a project property (ORG_GRADLE_PROJECT_<KEY>). A repo's "gradle" block in
+workspace.json narrows that and fills local.properties:
+
+  "gradle": {
+    "properties":       { "releaseStoreFile": "ANDROID_KEYSTORE", "releaseStorePassword": "ANDROID_KEYSTORE_PASSWORD" },
+    "local_properties": { "sdk.dir": "ANDROID_HOME" }
+  }
+
+Values starting with ./ or ~/ are made absolute (relative to the workspace root
+or home), so keystores can live in the workspace.
+
+Default args per script go in the repo's "script_args" and are passed ahead of
+any you give (a build_command or test_command gets its script's args appended):
+
+  "script_args": { "test": ["--runInBand"] }
+
 Examples:
-  spark-cli run              # list available scripts for current repo
+  spark-cli run              # list available scripts (Gradle: discovered tasks) for current repo
   spark-cli run build        # npm run build / ./gradlew build
   spark-cli run test         # npm test / ./gradlew test
-  spark-cli run -- ls -la    # run arbitrary command with workspace env`,
-	Args:                  cobra.ArbitraryArgs,
-	DisableFlagParsing:    false,
+  spark-cli run -- ls -la    # run arbitrary command with workspace env
+  spark-cli run build --profile  # print where the time went (install vs compile)
+  spark-cli run build --frozen   # npm ci / pnpm --frozen-lockfile; fail on a stale lockfile
+
+Set "frozen_lockfile": true in workspace.json to make --frozen the default.`,
+	Args:               cobra.ArbitraryArgs,
+	DisableFlagParsing: false,
+	ValidArgsFunction:  completeRunScripts,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		wsPath, err := workspace.Find()
 		if err != nil {
@@ -62,6 +108,11 @@ Examples:
 			return err
 		}
 
+		if runProfile {
+			metrics.EnableProfile()
+			defer metrics.PrintProfile(os.Stdout)
+		}
+
 		// Build workspace env
 		wsEnv := buildWorkspaceEnv(wsPath, ws)
 
@@ -70,11 +121,11 @@ Examples:
 			repoName, repoDir := detectCurrentRepo(wsPath, ws)
 			if repoName != "" {
 				projType := detectProjectType(repoDir)
-				showAvailableScripts(repoDir, projType, repoName)
+				showAvailableScripts(wsPath, repoDir, projType, repoName)
 			} else {
 				fmt.Println("Run any command with workspace env:")
-				fmt.Println("  spark-cli run -- <command>")
-				fmt.Println("  spark-cli run <script>  (inside a repo)")
+				fmt.Println(binname.Rewrite("  spark-cli run -- <command>"))
+				fmt.Println(binname.Rewrite("  spark-cli run <script>  (inside a repo)"))
 			}
 			return nil
 		}
@@ -114,58 +165,177 @@ func buildWorkspaceEnv(wsPath string, ws *workspace.Workspace) map[string]string
 func runRepoScript(wsPath string, ws *workspace.Workspace, repoName, script string, extraArgs []string, wsEnv map[string]string) error {
 	repo, ok := ws.Repos[repoName]
 	if !ok {
-		return fmt.Errorf("repo '%s' not found in workspace", repoName)
+		return errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", repoName)
 	}
 
 	repoDir := filepath.Join(wsPath, repo.Path)
 	if _, err := os.Stat(repoDir); os.IsNotExist(err) {
 		return fmt.Errorf("repo directory %s does not exist", repoDir)
 	}
+	if script == "start" {
+		problems, err := validateRepoEnv(wsPath, ws, repoName, wsEnv)
+		if err != nil {
+			return err
+		}
+		if len(problems) > 0 {
+			ui.Fail.Printf("%s is missing required env:\n", repoName)
+			printEnvProblems(problems)
+			return errs.New(errs.KindCommandFailed, "%s: required env not set (see 'spark-cli env validate')", repoName)
+		}
+	}
+	wsEnv = repo.ScopeEnv(wsEnv)
 
 	projType := detectProjectType(repoDir)
 
 	// Auto-install node_modules if missing for Node projects
 	if projType == projectTypeNode {
-		if err := ensureNodeModules(repoDir, wsEnv); err != nil {
+		if err := ensureNodeModules(wsPath, repoName, repoDir, wsEnv); err != nil {
 			return err
 		}
 	}
 
-	command := buildCommand(repoDir, projType, script, extraArgs)
+	wsEnv, err := gradleEnv(wsPath, repo, repoDir, wsEnv)
+	if err != nil {
+		return err
+	}
+
+	command := buildCommand(repoDir, projType, script, repo.ArgsFor(script, extraArgs))
 	if command == "" {
-		showAvailableScripts(repoDir, projType, repoName)
+		showAvailableScripts(wsPath, repoDir, projType, repoName)
 		return fmt.Errorf("script '%s' not available in %s", script, repoName)
 	}
 
 	fmt.Printf("=== %s: %s ===\n", repoName, command)
-	return runShellCmdWithEnv(repoDir, command, wsEnv)
+	phase := script
+	if script == "build" {
+		phase = metrics.PhaseCompile
+	}
+	stop := metrics.Span(repoName, phase)
+	err = metrics.Time(wsPath, repoName, script, func() error {
+		return runShellCmdWithEnv(repoDir, command, nil, wsEnv)
+	})
+	stop()
+	if err != nil {
+		kind := errs.KindCommandFailed
+		if script == "build" {
+			kind = errs.KindBuildFailed
+		}
+		return errs.New(kind, "%s: %s failed: %w", repoName, command, err)
+	}
+	return nil
 }
 
 func runRawCommand(wsPath string, args []string, wsEnv map[string]string) error {
 	command := strings.Join(args, " ")
 	fmt.Printf("=== run: %s ===\n", command)
-	return runShellCmdWithEnv(wsPath, command, wsEnv)
+	return errs.Wrap(errs.KindCommandFailed, runShellCmdWithEnv(wsPath, command, nil, wsEnv))
 }
 
-func ensureNodeModules(repoDir string, wsEnv map[string]string) error {
-	nodeModules := filepath.Join(repoDir, "node_modules")
-	needsInstall := false
+func ensureNodeModules(wsPath, repoName, repoDir string, wsEnv map[string]string) error {
+	reason := npmInstallNeeded(wsPath, repoName, repoDir)
+	if reason == "" {
+		return nil
+	}
+	opts := installOptions(wsPath)
+	command, err := npm.InstallCommand(repoDir, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s — running %s...\n", reason, installLabel(command))
+	stop := metrics.Span(repoName, metrics.PhaseInstall)
+	var out bytes.Buffer
+	install := func() error {
+		return metrics.Time(wsPath, repoName, "install", func() error {
+			return runInstall(command, opts.Frozen, func(c string) error {
+				out.Reset() // keep only the last attempt's output for diagnosis
+				return stopInstallRetries(runShellCmdTee(repoDir, c, nil, wsEnv, &out), &out)
+			})
+		})
+	}
+	err = install()
+	if err != nil && npm.AuthFailure(out.String()) && recoverNpmAuth(repoDir, wsEnv) {
+		err = install()
+	}
+	stop()
+	if err != nil {
+		explainInstallFailure(os.Stderr, repoDir, out.String(), false)
+		return fmt.Errorf("%s failed: %w", command, err)
+	}
+	recordLockfile(wsPath, repoName, repoDir)
+	fmt.Println()
+	return nil
+}
 
-	if _, err := os.Stat(nodeModules); os.IsNotExist(err) {
-		fmt.Printf("node_modules missing — running npm install...\n")
-		needsInstall = true
-	} else if _, err := os.Stat(filepath.Join(nodeModules, ".package-lock.json")); os.IsNotExist(err) {
-		fmt.Printf("node_modules incomplete — running npm install...\n")
-		needsInstall = true
+// runInstall runs an install command with retries. Unless installs are frozen, a failed
+// `npm ci` (usually a lockfile out of sync with package.json) falls back to `npm install`.
+func runInstall(command string, frozen bool, run func(command string) error) error {
+	err := retry.Do(func() error {
+		return run(command)
+	})
+	if err != nil && command == "npm ci" && !frozen && !proc.Interrupted() {
+		return retry.Do(func() error {
+			return run("npm install")
+		})
+	}
+	return err
+}
+
+// npmInstallNeeded decides whether a Node repo needs installing by comparing its
+// lockfile against the hash recorded in .spk/state at the last install. It returns why
+// an install is needed, or "" if node_modules is up to date.
+func npmInstallNeeded(wsPath, repoName, repoDir string) string {
+	if _, err := os.Stat(filepath.Join(repoDir, "node_modules")); os.IsNotExist(err) {
+		return "node_modules missing"
+	}
+	if !npm.Installed(repoDir) {
+		return "node_modules incomplete"
 	}
 
-	if needsInstall {
-		if err := runShellCmdWithEnv(repoDir, "npm install", wsEnv); err != nil {
-			return fmt.Errorf("npm install failed: %w", err)
+	hash := npm.LockfileHash(repoDir)
+	if hash == "" {
+		return ""
+	}
+	st, err := state.Load(wsPath)
+	if err != nil {
+		return ""
+	}
+	recorded, ok := st.Lockfiles[repoName]
+	if !ok {
+		// First time we've seen this repo installed — trust it and start tracking
+		recordLockfile(wsPath, repoName, repoDir)
+		return ""
+	}
+	if recorded != hash {
+		_, lockfile := npm.PackageManager(repoDir)
+		return lockfile + " changed"
+	}
+	return ""
+}
+
+// installOptions resolves how dependencies are installed from --frozen and the workspace's
+// frozen_lockfile / shared_store settings
+func installOptions(wsPath string) npm.InstallOptions {
+	opts := npm.InstallOptions{Frozen: frozenInstall}
+	if ws, err := workspace.Load(wsPath); err == nil {
+		opts.Frozen = opts.Frozen || ws.FrozenLockfile
+		if ws.SharedStore {
+			opts.StoreDir = sharedStoreDir(wsPath)
 		}
-		fmt.Println()
 	}
-	return nil
+	return opts
+}
+
+// recordLockfile stores the repo's current lockfile hash after a successful install
+func recordLockfile(wsPath, repoName, repoDir string) {
+	hash := npm.LockfileHash(repoDir)
+	if hash == "" {
+		return
+	}
+	state.Update(wsPath, func(s *state.State) error {
+		s.SetLockfile(repoName, hash)
+		return nil
+	})
 }
 
 func detectCurrentRepo(wsPath string, ws *workspace.Workspace) (string, string) {
@@ -234,14 +404,14 @@ func buildNpmCommand(repoDir, script string, extraArgs []string) string {
 	}
 	cmd := fmt.Sprintf("npm run %s", script)
 	if len(extraArgs) > 0 {
-		cmd += " -- " + strings.Join(extraArgs, " ")
+		cmd += " -- " + shellquote.Join(extraArgs)
 	}
 	return cmd
 }
 
 func buildGradleCommand(script string, extraArgs []string) string {
 	allTasks := append([]string{script}, extraArgs...)
-	return "./gradlew " + strings.Join(allTasks, " ")
+	return "./gradlew " + shellquote.Join(allTasks)
 }
 
 func buildGoCommand(script string, extraArgs []string) string {
@@ -249,18 +419,18 @@ func buildGoCommand(script string, extraArgs []string) string {
 	case "build":
 		args := "./..."
 		if len(extraArgs) > 0 {
-			args = strings.Join(extraArgs, " ")
+			args = shellquote.Join(extraArgs)
 		}
 		return "go build " + args
 	case "test":
 		args := "./..."
 		if len(extraArgs) > 0 {
-			args = strings.Join(extraArgs, " ")
+			args = shellquote.Join(extraArgs)
 		}
 		return "go test " + args
 	case "run":
 		if len(extraArgs) > 0 {
-			return "go run " + strings.Join(extraArgs, " ")
+			return "go run " + shellquote.Join(extraArgs)
 		}
 		return "go run ."
 	case "fmt":
@@ -274,7 +444,7 @@ func buildGoCommand(script string, extraArgs []string) string {
 
 func buildMakeCommand(script string, extraArgs []string) string {
 	allTargets := append([]string{script}, extraArgs...)
-	return "make " + strings.Join(allTargets, " ")
+	return "make " + shellquote.Join(allTargets)
 }
 
 func getNpmScripts(repoDir string) map[string]string {
@@ -292,7 +462,7 @@ func getNpmScripts(repoDir string) map[string]string {
 	return pkg.Scripts
 }
 
-func showAvailableScripts(repoDir string, projType projectType, repoName string) {
+func showAvailableScripts(wsPath, repoDir string, projType projectType, repoName string) {
 	fmt.Printf("\nAvailable scripts in %s:\n", repoName)
 	switch projType {
 	case projectTypeNode:
@@ -306,41 +476,138 @@ func showAvailableScripts(repoDir string, projType projectType, repoName string)
 			}
 			sort.Strings(names)
 			for _, name := range names {
-				fmt.Printf("  spark-cli run %s\n", name)
+				fmt.Printf(binname.Rewrite("  spark-cli run %s\n"), name)
 			}
 		}
 	case projectTypeGradle:
-		fmt.Println("  spark-cli run build")
-		fmt.Println("  spark-cli run test")
-		fmt.Println("  spark-cli run clean build")
+		cachePath := gradleTaskCachePath(wsPath, repoName)
+		if gradle.CachedTasks(cachePath) == nil {
+			fmt.Println("  (running ./gradlew tasks — cached until the build scripts change)")
+		}
+		tasks, err := gradle.Tasks(repoDir, cachePath)
+		if err != nil || len(tasks) == 0 {
+			if err != nil {
+				fmt.Printf("  %s %v\n", ui.Warn, err)
+			}
+			fmt.Println(binname.Rewrite("  spark-cli run build"))
+			fmt.Println(binname.Rewrite("  spark-cli run test"))
+			fmt.Println(binname.Rewrite("  spark-cli run clean build"))
+			break
+		}
+		group := ""
+		for _, t := range tasks {
+			if t.Group != group {
+				group = t.Group
+				fmt.Printf("  %s:\n", group)
+			}
+			if t.Description != "" {
+				fmt.Printf(binname.Rewrite("    spark-cli run %-30s %s\n"), t.Name, t.Description)
+			} else {
+				fmt.Printf(binname.Rewrite("    spark-cli run %s\n"), t.Name)
+			}
+		}
 	case projectTypeGo:
-		fmt.Println("  spark-cli run build")
-		fmt.Println("  spark-cli run test")
-		fmt.Println("  spark-cli run fmt")
-		fmt.Println("  spark-cli run vet")
+		fmt.Println(binname.Rewrite("  spark-cli run build"))
+		fmt.Println(binname.Rewrite("  spark-cli run test"))
+		fmt.Println(binname.Rewrite("  spark-cli run fmt"))
+		fmt.Println(binname.Rewrite("  spark-cli run vet"))
 	case projectTypeMake:
-		fmt.Println("  spark-cli run <target>")
+		fmt.Println(binname.Rewrite("  spark-cli run <target>"))
 	default:
 		fmt.Println("  (no recognized project type)")
 	}
 	fmt.Println()
 }
 
+// gradleTaskCachePath is where a repo's discovered Gradle tasks are cached
+func gradleTaskCachePath(wsPath, repoName string) string {
+	return filepath.Join(workspace.SparkDir(wsPath), "cache", "gradle-tasks", repoName+".json")
+}
+
+// completeRunScripts completes 'spark-cli run' with the current repo's scripts. Gradle
+// tasks come from the cache 'spark-cli run' fills, since running Gradle is too slow here.
+func completeRunScripts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	wsPath, err := workspace.Find()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	ws, err := workspace.Load(wsPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	repoName, repoDir := detectCurrentRepo(wsPath, ws)
+	if repoName == "" {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	var names []string
+	switch detectProjectType(repoDir) {
+	case projectTypeNode:
+		for name := range getNpmScripts(repoDir) {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	case projectTypeGradle:
+		for _, t := range gradle.CachedTasks(gradleTaskCachePath(wsPath, repoName)) {
+			names = append(names, t.Name+"\t"+t.Description)
+		}
+		if names == nil {
+			names = []string{"build", "test", "clean"}
+		}
+	case projectTypeGo:
+		names = []string{"build", "test", "run", "fmt", "vet"}
+	default:
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
 func fileExistsCheck(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
 
-func runShellCmdWithEnv(dir, command string, wsEnv map[string]string) error {
+// shellCommand returns the shell invocation for command: a login shell locally so nvm/asdf
+// PATH setup applies, a plain shell in CI where the environment is explicit
+func shellCommand(command string) (string, []string) {
 	shell := os.Getenv("SHELL")
+	if ciMode {
+		if shell == "" {
+			shell = "/bin/bash"
+		}
+		return shell, []string{"-c", command}
+	}
 	if shell == "" {
 		shell = "/bin/zsh"
 	}
+	return shell, []string{"-l", "-c", command}
+}
+
+// runShellCmdWithEnv runs command in dir with wsEnv on top of the process environment,
+// limited to the keys repo allows (env_allowlist, env_denylist). repo is nil outside a
+// repo, and when wsEnv was scoped before spark-cli added variables of its own (Gradle
+// properties, docker build args) that the lists would strip.
+func runShellCmdWithEnv(dir, command string, repo *workspace.RepoDef, wsEnv map[string]string) error {
+	return runShellCmdTee(dir, command, repo, wsEnv, nil)
+}
 
-	cmd := exec.Command(shell, "-l", "-c", command)
+// runShellCmdTee is runShellCmdWithEnv that also copies the command's output to tee
+func runShellCmdTee(dir, command string, repo *workspace.RepoDef, wsEnv map[string]string, tee io.Writer) error {
+	if repo != nil {
+		wsEnv = repo.ScopeEnv(wsEnv)
+	}
+	shell, args := shellCommand(command)
+	cmd := proc.Foreground(shell, args...)
 	cmd.Dir = dir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	if tee != nil {
+		cmd.Stdout = io.MultiWriter(os.Stdout, tee)
+		cmd.Stderr = io.MultiWriter(os.Stderr, tee)
+	}
 	cmd.Stdin = os.Stdin
 
 	if len(wsEnv) > 0 {
@@ -365,7 +632,7 @@ func runShellCmdWithEnv(dir, command string, wsEnv map[string]string) error {
 
 // ensureGitHubToken auto-resolves GITHUB_TOKEN from gh auth if not already set
 func ensureGitHubToken(wsEnv map[string]string) map[string]string {
-	if os.Getenv("GITHUB_TOKEN") != "" {
+	if ciMode || os.Getenv("GITHUB_TOKEN") != "" {
 		return wsEnv
 	}
 	if wsEnv != nil {
@@ -374,7 +641,7 @@ func ensureGitHubToken(wsEnv map[string]string) map[string]string {
 		}
 	}
 
-	out, err := exec.Command("gh", "auth", "token").Output()
+	out, err := proc.Command("gh", "auth", "token").Output()
 	if err != nil {
 		return wsEnv
 	}
@@ -392,6 +659,24 @@ func ensureGitHubToken(wsEnv map[string]string) map[string]string {
 	return wsEnv
 }
 
+// resolveGitHubToken returns a GitHub token from the environment, the workspace env
+// (when inside a workspace), or gh auth — in that order. Returns "" if none is found.
+func resolveGitHubToken() string {
+	if t := os.Getenv("GITHUB_TOKEN"); t != "" {
+		return t
+	}
+	if wsPath, err := workspace.Find(); err == nil {
+		if ws, err := workspace.Load(wsPath); err == nil {
+			if t := buildSyncEnv(wsPath, ws)["GITHUB_TOKEN"]; t != "" {
+				return t
+			}
+		}
+	}
+	return ensureGitHubTokenSync(nil)["GITHUB_TOKEN"]
+}
+
 func init() {
+	runCmd.Flags().BoolVar(&runProfile, "profile", false, "Print a breakdown of time spent installing and running the script")
+	runCmd.Flags().BoolVar(&frozenInstall, "frozen", false, "Install with npm ci / pnpm --frozen-lockfile and fail instead of updating the lockfile")
 	rootCmd.AddCommand(runCmd)
 }
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scanYes   bool
+	scanDepth int
+)
+
+// scanSkipDirs are never descended into when looking for repos
+var scanSkipDirs = map[string]bool{
+	"node_modules": true,
+	"build":        true,
+	"dist":         true,
+	"vendor":       true,
+}
+
+// scannedRepo is an unregistered checkout found in the workspace
+type scannedRepo struct {
+	name   string
+	dir    string
+	remote string
+	build  string
+	test   string
+}
+
+var workspaceScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Find git repos in the workspace that aren't registered and add them",
+	Long: `Walks the workspace directory for git checkouts missing from workspace.json
+and offers to register them, reading the remote from the checkout and the
+build/test commands from the project type (npm scripts, Gradle, Go, Make).
+
+Registered repos whose directory is gone are reported too.
+
+Examples:
+  spark-cli workspace scan
+  spark-cli workspace scan --yes          # register everything found
+  spark-cli workspace scan --depth 3      # look deeper (e.g. <org>/<domain>/<repo>)`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+
+		known := make(map[string]string)
+		for name, repo := range ws.Repos {
+			known[filepath.Join(wsPath, repo.Path)] = name
+		}
+		var missing []string
+		for dir, name := range known {
+			if _, err := os.Stat(dir); os.IsNotExist(err) {
+				missing = append(missing, name)
+			}
+		}
+		sort.Strings(missing)
+		for _, name := range missing {
+			ui.Warn.Printf(binname.Rewrite("%-25s registered but %s is missing — re-clone with 'spark-cli use %s' or drop it with 'spark-cli remove %s'\n"),
+				name, ws.Repos[name].Path, orDefault(ws.Repos[name].Remote, name), name)
+		}
+
+		found, err := scanForRepos(wsPath, known, scanDepth)
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			ui.OK.Println("workspace.json matches the repos on disk")
+			return nil
+		}
+
+		options := make([]string, len(found))
+		preselected := make([]bool, len(found))
+		for i, r := range found {
+			rel, _ := filepath.Rel(wsPath, r.dir)
+			options[i] = fmt.Sprintf("%-25s %-25s %s", r.name, rel, orDefault(r.remote, "(no remote)"))
+			preselected[i] = true
+		}
+
+		picked := make([]int, len(found))
+		for i := range found {
+			picked[i] = i
+		}
+		if !scanYes {
+			if prompt.NonInteractive {
+				return errs.New(errs.KindUsage, "found %d unregistered repo(s) — pass --yes to register them non-interactively", len(found))
+			}
+			picked, err = prompt.MultiSelect(fmt.Sprintf("Found %d unregistered repo(s):", len(found)), options, preselected)
+			if err != nil {
+				return errs.Wrap(errs.KindUsage, err)
+			}
+		}
+
+		ws, err = workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		if ws.Repos == nil {
+			ws.Repos = make(map[string]workspace.RepoDef)
+		}
+		added := 0
+		for _, i := range picked {
+			r := found[i]
+			if _, exists := ws.Repos[r.name]; exists {
+				ui.Skip.Printf(binname.Rewrite("%-25s a repo with this name is already registered — adopt it with 'spark-cli adopt %s --name <other>'\n"), r.name, r.dir)
+				continue
+			}
+			rel, _ := filepath.Rel(wsPath, r.dir)
+			ws.Repos[r.name] = workspace.RepoDef{
+				Remote:       r.remote,
+				Path:         rel,
+				BuildCommand: r.build,
+				TestCommand:  r.test,
+			}
+			ui.OK.Printf("%-25s registered (build: %s, test: %s)\n", r.name, orDefault(r.build, "-"), orDefault(r.test, "-"))
+			added++
+		}
+		if added == 0 {
+			return nil
+		}
+		if err := workspace.Save(wsPath, ws); err != nil {
+			return err
+		}
+		if err := workspace.GenerateVSCodeWorkspace(wsPath); err != nil {
+			fmt.Printf("Warning: failed to update VS Code workspace: %v\n", err)
+		}
+		return nil
+	},
+}
+
+// scanForRepos walks wsPath up to depth levels for git checkouts not in known, without
+// descending into repos, hidden directories, or dependency/build output
+func scanForRepos(wsPath string, known map[string]string, depth int) ([]scannedRepo, error) {
+	var found []scannedRepo
+	err := filepath.WalkDir(wsPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == wsPath {
+			return nil
+		}
+		rel, _ := filepath.Rel(wsPath, path)
+		if strings.HasPrefix(d.Name(), ".") || scanSkipDirs[d.Name()] || strings.Count(rel, string(filepath.Separator)) >= depth {
+			return filepath.SkipDir
+		}
+		if _, ok := known[path]; ok {
+			return filepath.SkipDir
+		}
+		if !fileExistsCheck(filepath.Join(path, ".git")) {
+			return nil
+		}
+
+		r := scannedRepo{name: d.Name(), dir: path, remote: git.RemoteURL(path, "origin")}
+		if r.remote != "" {
+			r.name = strings.TrimSuffix(git.RepoNameFromRemote(r.remote), ".git")
+		}
+		projType := detectProjectType(path)
+		r.build = buildCommand(path, projType, "build", nil)
+		r.test = buildCommand(path, projType, "test", nil)
+		found = append(found, r)
+		return filepath.SkipDir
+	})
+	sort.Slice(found, func(i, j int) bool { return found[i].dir < found[j].dir })
+	return found, err
+}
+
+func init() {
+	workspaceScanCmd.Flags().BoolVarP(&scanYes, "yes", "y", false, "Register every repo found without asking")
+	workspaceScanCmd.Flags().IntVar(&scanDepth, "depth", 2, "How many directory levels below the workspace to search")
+	workspaceCmd.AddCommand(workspaceScanCmd)
+}
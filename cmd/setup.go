@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellquote"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellrc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setupShellName   string
+	setupShellRemove bool
+	setupShellPrint  bool
+)
+
+var setupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Set up spark-cli on this machine (shell)",
+}
+
+var setupShellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Add completion, the 'spark-cli cd' function, and PATH to your shell's startup file",
+	Long: `Adds a block to your shell's startup file that:
+
+  - loads tab completion for commands, flags, and repo names
+  - defines 'spark-cli cd [repo]', which cds into a repo (or the workspace root)
+  - puts the spark-cli binary's directory on PATH, when it isn't already
+
+The block sits between two marker comments and is rewritten in place on every
+run, so it's safe to run again after upgrading or moving the binary. --remove
+takes it out; --print shows it without writing.
+
+When spk is installed next to spark-cli, both names get completion and cd.
+
+The shell is $SHELL's unless --shell. Files written:
+  bash  ~/.bashrc
+  zsh   ~/.zshrc ($ZDOTDIR/.zshrc)
+  fish  ~/.config/fish/conf.d/spark-cli.fish
+
+Open a new terminal (or source the file) afterwards.
+
+Examples:
+  spark-cli setup shell
+  spark-cli setup shell --shell zsh --print
+  spark-cli setup shell --remove`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setupShell(setupShellName, setupShellRemove, setupShellPrint)
+	},
+}
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish | install>",
+	Short: "Print the shell completion script, or install it (install)",
+	Long: `Prints the completion script for a shell. 'spark-cli completion install' (the
+same as 'spark-cli setup shell') loads it from your shell's startup file, so
+this is only needed to wire completion up by hand.
+
+Examples:
+  spark-cli completion install
+  spark-cli completion zsh > "${fpath[1]}/_spark-cli"
+  source <(spark-cli completion bash)`,
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: shellrc.Shells,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case shellrc.Bash:
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case shellrc.Zsh:
+			return rootCmd.GenZshCompletion(os.Stdout)
+		default:
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		}
+	},
+}
+
+var completionInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Load completion from your shell's startup file (same as 'setup shell')",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setupShell(setupShellName, false, false)
+	},
+}
+
+// cdCmd only runs when the shell function isn't installed: a process can't change its
+// parent shell's directory
+var cdCmd = &cobra.Command{
+	Use:               "cd [repo]",
+	Short:             "cd into a repo (needs 'spark-cli setup shell')",
+	Hidden:            true,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeRepoNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return errs.New(errs.KindUsage, "'spark-cli cd' is a shell function — run 'spark-cli setup shell' and open a new terminal (or cd \"$(spark-cli repo path %s)\")", strings.Join(args, " "))
+	},
+}
+
+// setupShell writes (or removes, or prints) the managed block in shell's startup file
+func setupShell(shell string, remove, print bool) error {
+	if shell == "" {
+		if shell = shellrc.Detect(); shell == "" {
+			return errs.New(errs.KindUsage, "can't tell your shell from $SHELL (%s) — pass --shell %s", orDefault(os.Getenv("SHELL"), "unset"), strings.Join(shellrc.Shells, "|"))
+		}
+	}
+	rc, err := shellrc.RCFile(shell)
+	if err != nil {
+		return errs.New(errs.KindUsage, "%v", err)
+	}
+
+	if remove {
+		removed, err := shellrc.Remove(rc)
+		if err != nil {
+			return err
+		}
+		if removed {
+			ui.OK.Printf("Removed the spark-cli block from %s\n", rc)
+		} else {
+			fmt.Printf("No spark-cli block in %s\n", rc)
+		}
+		return nil
+	}
+
+	binDir, onPath := binaryDir()
+	body := shellBlock(shell, binDir, onPath)
+	if print {
+		fmt.Printf("%s\n%s\n%s\n", shellrc.Begin, body, shellrc.End)
+		return nil
+	}
+	changed, err := shellrc.Apply(rc, body)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		ui.OK.Printf("%s is already set up\n", rc)
+		return nil
+	}
+	ui.OK.Printf(binname.Rewrite("Updated %s: completion, 'spark-cli cd'"), rc)
+	if !onPath {
+		fmt.Printf(", PATH += %s", binDir)
+	}
+	fmt.Println()
+	fmt.Printf("Open a new terminal, or run: source %s\n", rc)
+	return nil
+}
+
+// binaryDir returns the directory of the running binary and whether PATH already has it
+func binaryDir() (string, bool) {
+	self, err := os.Executable()
+	if err != nil {
+		return "", true
+	}
+	if resolved, err := filepath.EvalSymlinks(self); err == nil {
+		self = resolved
+	}
+	dir := filepath.Dir(self)
+	onPath := slices.ContainsFunc(filepath.SplitList(os.Getenv("PATH")), func(p string) bool {
+		if resolved, err := filepath.EvalSymlinks(p); err == nil {
+			p = resolved
+		}
+		return p == dir
+	})
+	return dir, onPath
+}
+
+// shellBlock returns the startup-file lines for shell; binDir is added to PATH unless
+// onPath. The completion and cd function are set up for each name the binary is
+// installed under in binDir.
+func shellBlock(shell, binDir string, onPath bool) string {
+	var b strings.Builder
+	b.WriteString("# Managed by 'spark-cli setup shell' — rerun it instead of editing\n")
+	if shell == shellrc.Fish {
+		if !onPath {
+			fmt.Fprintf(&b, "fish_add_path %s\n", shellquote.Quote(binDir))
+		}
+		for _, name := range installedNames(binDir) {
+			fmt.Fprintf(&b, `command %[1]s completion fish | source
+function %[1]s --wraps %[1]s
+    if test "$argv[1]" = cd
+        set -l dir (command %[1]s repo path $argv[2..-1]); and cd $dir
+    else
+        command %[1]s $argv
+    end
+end
+`, name)
+		}
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+
+	if !onPath {
+		fmt.Fprintf(&b, "export PATH=%s:\"$PATH\"\n", shellquote.Quote(binDir))
+	}
+	if shell == shellrc.Zsh {
+		b.WriteString("(( $+functions[compdef] )) || { autoload -Uz compinit && compinit; }\n")
+	}
+	for _, name := range installedNames(binDir) {
+		fmt.Fprintf(&b, `source <(command %[1]s completion %[2]s)
+%[1]s() {
+    if [ "$1" = cd ]; then
+        shift
+        local dir
+        dir="$(command %[1]s repo path "$@")" && cd "$dir"
+    else
+        command %[1]s "$@"
+    fi
+}
+`, name, shell)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// installedNames returns the names the binary is installed under in binDir — spark-cli,
+// spk, or both — falling back to spark-cli
+func installedNames(binDir string) []string {
+	var names []string
+	for _, name := range []string{binname.Canonical, binname.Alias} {
+		if _, err := os.Stat(filepath.Join(binDir, name)); err == nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		names = []string{binname.Canonical}
+	}
+	return names
+}
+
+func init() {
+	setupShellCmd.Flags().StringVar(&setupShellName, "shell", "", "Shell to set up: bash, zsh, or fish (default: from $SHELL)")
+	setupShellCmd.Flags().BoolVar(&setupShellRemove, "remove", false, "Remove the spark-cli block instead")
+	setupShellCmd.Flags().BoolVar(&setupShellPrint, "print", false, "Print the block instead of writing it")
+	completionInstallCmd.Flags().StringVar(&setupShellName, "shell", "", "Shell to set up: bash, zsh, or fish (default: from $SHELL)")
+	setupCmd.AddCommand(setupShellCmd)
+	completionCmd.AddCommand(completionInstallCmd)
+	rootCmd.AddCommand(setupCmd, completionCmd, cdCmd)
+}
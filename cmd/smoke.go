@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/smoke"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	smokeEnv     string
+	smokeRepos   []string
+	smokeTimeout time.Duration
+	smokeJSON    bool
+)
+
+// smokeResult is the outcome of one check
+type smokeResult struct {
+	Repo     string `json:"repo"`
+	Check    string `json:"check"`
+	Target   string `json:"target"`
+	OK       bool   `json:"ok"`
+	Detail   string `json:"detail"`
+	Duration string `json:"duration"`
+}
+
+var smokeCmd = &cobra.Command{
+	Use:   "smoke",
+	Short: "Check that workspace services are alive",
+	Long: `Runs the smoke checks declared per repo in workspace.json and prints a
+pass/fail table — a quick "is everything alive" after a deploy or 'spark-cli dev'.
+
+  "repos": {
+    "AppAPI": {
+      "smoke": [
+        { "name": "health", "http": "${APP_API_URL}/health" },
+        { "name": "schema", "graphql": "${APP_API_URL}/graphql" }
+      ]
+    },
+    "AppCLI": {
+      "smoke": [{ "command": "./bin/cli --version", "contains": "cli" }]
+    }
+  }
+
+Each check sets one of "http" (must answer 2xx, or "status"), "graphql" (must
+answer an introspection query), or "command" (must exit 0 in the repo).
+"contains" additionally requires a string in the body or output.
+
+URLs and commands expand ${VAR} from the workspace env and ${ENV} to the
+target environment. --env checks another environment (its variables are
+fetched from SSM, or LocalStack for a local env) without switching to it.
+
+Examples:
+  spark-cli smoke
+  spark-cli smoke --env prod
+  spark-cli smoke --repos AppAPI --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+
+		names, err := smokeRepoNames(ws, smokeRepos)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			fmt.Println(binname.Rewrite("No smoke checks defined — add a \"smoke\" list to a repo in workspace.json (see 'spark-cli smoke --help')"))
+			return nil
+		}
+
+		env := activeEnv(ws, "")
+		vars := buildWorkspaceEnv(wsPath, ws)
+		if smokeEnv != "" && smokeEnv != env {
+			if _, ok := ws.Environments[smokeEnv]; !ok && ws.LocalEndpoint(smokeEnv) == "" {
+				ui.Warn.Printf("Environment %q is not declared in workspace.json — using default profile/region\n", smokeEnv)
+			}
+			fetched, err := fetchEnvVars(ws, smokeEnv, false)
+			if err != nil {
+				return err
+			}
+			for k, v := range fetched {
+				vars[k] = v
+			}
+			env = smokeEnv
+		}
+		vars["ENV"] = env
+
+		if !smokeJSON {
+			fmt.Printf("Smoke checks against %s\n\n", env)
+		}
+
+		var (
+			results []smokeResult
+			mu      sync.Mutex
+			wg      sync.WaitGroup
+		)
+		for _, name := range names {
+			repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+			for i, check := range ws.Repos[name].Smoke {
+				wg.Add(1)
+				go func(name string, i int, check workspace.SmokeCheck) {
+					defer wg.Done()
+					r := runSmokeCheck(name, i, check, repoDir, vars)
+					mu.Lock()
+					results = append(results, r)
+					mu.Unlock()
+				}(name, i, check)
+			}
+		}
+		wg.Wait()
+
+		sort.SliceStable(results, func(i, j int) bool {
+			if results[i].Repo != results[j].Repo {
+				return results[i].Repo < results[j].Repo
+			}
+			return results[i].Check < results[j].Check
+		})
+
+		var failed int
+		for _, r := range results {
+			if !r.OK {
+				failed++
+			}
+		}
+
+		if smokeJSON {
+			out, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+		} else {
+			printSmokeTable(results)
+		}
+
+		if failed > 0 {
+			return errs.New(errs.KindCommandFailed, "%d of %d smoke check(s) failed", failed, len(results))
+		}
+		if !smokeJSON {
+			fmt.Println()
+			ui.OK.Printf("All %d check(s) passed\n", len(results))
+		}
+		return nil
+	},
+}
+
+// smokeRepoNames returns the repos (optionally limited to only) that declare smoke checks
+func smokeRepoNames(ws *workspace.Workspace, only []string) ([]string, error) {
+	var names []string
+	if len(only) > 0 {
+		for _, name := range only {
+			repo, ok := ws.Repos[name]
+			if !ok {
+				return nil, errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
+			}
+			if len(repo.Smoke) == 0 {
+				ui.Skip.Printf("%s has no smoke checks\n", name)
+				continue
+			}
+			names = append(names, name)
+		}
+		return names, nil
+	}
+	for name, repo := range ws.Repos {
+		if len(repo.Smoke) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func runSmokeCheck(repo string, i int, check workspace.SmokeCheck, repoDir string, vars map[string]string) smokeResult {
+	expand := func(s string) string {
+		return os.Expand(s, func(k string) string {
+			if v, ok := vars[k]; ok {
+				return v
+			}
+			return os.Getenv(k)
+		})
+	}
+
+	r := smokeResult{Repo: repo, Check: check.Name}
+	ctx, cancel := context.WithTimeout(proc.Context(), smokeTimeout)
+	defer cancel()
+	start := time.Now()
+
+	var err error
+	switch {
+	case check.HTTP != "":
+		r.Target = expand(check.HTTP)
+		r.Check = orDefault(r.Check, "http")
+		r.Detail, err = smoke.HTTP(ctx, r.Target, check.Status, check.Contains)
+	case check.GraphQL != "":
+		r.Target = expand(check.GraphQL)
+		r.Check = orDefault(r.Check, "graphql")
+		r.Detail, err = smoke.GraphQL(ctx, r.Target, check.Contains)
+	case check.Command != "":
+		r.Target = expand(check.Command)
+		r.Check = orDefault(r.Check, "command")
+		r.Detail, err = runSmokeCommand(ctx, repoDir, r.Target, check.Contains, vars)
+	default:
+		r.Check = orDefault(r.Check, fmt.Sprintf("#%d", i+1))
+		err = fmt.Errorf("check needs one of http, graphql, or command")
+	}
+	r.Duration = time.Since(start).Round(time.Millisecond).String()
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", smokeTimeout)
+		}
+		r.Detail = err.Error()
+		return r
+	}
+	r.OK = true
+	return r
+}
+
+// runSmokeCommand runs command in repoDir and returns the first line of its output
+func runSmokeCommand(ctx context.Context, repoDir, command, want string, vars map[string]string) (string, error) {
+	shell, args := shellCommand(command)
+	cmd := proc.CommandContext(ctx, shell, args...)
+	cmd.Dir = repoDir
+	cmd.Env = os.Environ()
+	for k, v := range vars {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	first, _, _ := strings.Cut(output, "\n")
+	if err != nil {
+		if first != "" {
+			return "", fmt.Errorf("%v: %s", err, first)
+		}
+		return "", err
+	}
+	if want != "" && !strings.Contains(output, want) {
+		return "", fmt.Errorf("output doesn't contain %q", want)
+	}
+	return first, nil
+}
+
+func printSmokeTable(results []smokeResult) {
+	fmt.Printf("  %-20s %-12s %-8s %s\n", "REPO", "CHECK", "TIME", "RESULT")
+	for _, r := range results {
+		icon := ui.OK
+		if !r.OK {
+			icon = ui.Fail
+		}
+		detail := r.Detail
+		if len(detail) > 80 {
+			detail = detail[:77] + "..."
+		}
+		fmt.Printf("%s %-20s %-12s %-8s %s\n", icon, r.Repo, r.Check, r.Duration, detail)
+		if !r.OK && r.Target != "" {
+			fmt.Printf("  %-20s %-12s %-8s %s\n", "", "", "", r.Target)
+		}
+	}
+}
+
+func init() {
+	smokeCmd.Flags().StringVar(&smokeEnv, "env", "", "Environment to check (default: the workspace's active env)")
+	smokeCmd.Flags().StringSliceVar(&smokeRepos, "repos", nil, "Only check these repos")
+	smokeCmd.Flags().DurationVar(&smokeTimeout, "timeout", 10*time.Second, "Timeout per check")
+	smokeCmd.Flags().BoolVar(&smokeJSON, "json", false, "Print results as JSON")
+	rootCmd.AddCommand(smokeCmd)
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	stashMessage   string
+	stashRepos     []string
+	stashUntracked bool
+)
+
+var stashCmd = &cobra.Command{
+	Use:   "stash",
+	Short: "Stash/unstash changes across repos as one set (push | pop | list | drop)",
+	Long: `Parks a cross-repo WIP: stashes every dirty repo at once and remembers which
+repos took part, so the whole set can be restored later with one command.
+
+Stash sets are tracked in .spk/state.json; each repo's stash is tagged
+"spark-cli stash <id>" so it can be found even if you stash more on top.
+
+Examples:
+  spark-cli stash push -m "auth refactor"
+  spark-cli stash push -m "wip" --repos AppAPI,AppModel -u
+  spark-cli stash list
+  spark-cli stash pop          # most recent set
+  spark-cli stash pop 2
+  spark-cli stash drop 2`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStashList()
+	},
+}
+
+var stashPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Stash changes in all dirty repos as a named set",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		names, err := selectRepos(wsPath, ws, stashRepos)
+		if err != nil {
+			return err
+		}
+
+		st, err := state.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		set := state.StashSet{
+			ID:        nextStashID(st),
+			Message:   stashMessage,
+			CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		}
+		marker := stashMarker(set.ID)
+
+		for _, name := range names {
+			repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+			if !git.IsDirty(repoDir) {
+				continue
+			}
+			if err := git.StashPushMessage(repoDir, marker+" "+set.Message, stashUntracked); err != nil {
+				ui.Fail.Printf("%-25s %v\n", name, err)
+				continue
+			}
+			ui.OK.Printf("%-25s stashed\n", name)
+			set.Repos = append(set.Repos, name)
+		}
+
+		if len(set.Repos) == 0 {
+			fmt.Println("No dirty repos — nothing to stash")
+			return nil
+		}
+
+		st.Stashes = append(st.Stashes, set)
+		if err := state.Save(wsPath, st); err != nil {
+			return err
+		}
+		fmt.Printf(binname.Rewrite("\nStash set %s saved (%d repos) — restore with: spark-cli stash pop %s\n"), set.ID, len(set.Repos), set.ID)
+		return nil
+	},
+}
+
+var stashPopCmd = &cobra.Command{
+	Use:   "pop [id]",
+	Short: "Restore a stash set (default: most recent)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStashApply(args, true)
+	},
+}
+
+var stashDropCmd = &cobra.Command{
+	Use:   "drop [id]",
+	Short: "Discard a stash set without applying it",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStashApply(args, false)
+	},
+}
+
+var stashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stash sets",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStashList()
+	},
+}
+
+// loadWorkspace finds and loads the current workspace
+func loadWorkspace() (string, *workspace.Workspace, error) {
+	wsPath, err := workspace.Find()
+	if err != nil {
+		return "", nil, err
+	}
+	ws, err := workspace.Load(wsPath)
+	if err != nil {
+		return "", nil, err
+	}
+	return wsPath, ws, nil
+}
+
+// stashMarker tags a set's git stashes; the trailing ':' keeps set 1 from matching set 10
+func stashMarker(id string) string {
+	return "spark-cli stash " + id + ":"
+}
+
+func nextStashID(st *state.State) string {
+	max := 0
+	for _, s := range st.Stashes {
+		if n, err := strconv.Atoi(s.ID); err == nil && n > max {
+			max = n
+		}
+	}
+	return strconv.Itoa(max + 1)
+}
+
+func runStashList() error {
+	wsPath, _, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+	st, err := state.Load(wsPath)
+	if err != nil {
+		return err
+	}
+	if len(st.Stashes) == 0 {
+		fmt.Println("No stash sets")
+		return nil
+	}
+	fmt.Printf("%-4s %-20s %-30s %s\n", "ID", "CREATED", "MESSAGE", "REPOS")
+	for _, s := range st.Stashes {
+		fmt.Printf("%-4s %-20s %-30s %v\n", s.ID, s.CreatedAt, orDefault(s.Message, "-"), s.Repos)
+	}
+	return nil
+}
+
+// runStashApply pops (apply=true) or drops each repo's stash in a set. Repos that fail
+// stay in the set so the command can be retried after resolving conflicts.
+func runStashApply(args []string, apply bool) error {
+	wsPath, ws, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+	id := ""
+	if len(args) == 1 {
+		id = args[0]
+	}
+
+	return state.Update(wsPath, func(st *state.State) error {
+		set, idx := st.FindStash(id)
+		if set == nil {
+			if id == "" {
+				return fmt.Errorf("no stash sets")
+			}
+			return fmt.Errorf("stash set %s not found — run 'spark-cli stash list'", id)
+		}
+
+		marker := stashMarker(set.ID)
+		var remaining []string
+		for _, name := range set.Repos {
+			repo, ok := ws.Repos[name]
+			if !ok {
+				ui.Skip.Printf("%-25s no longer in workspace\n", name)
+				continue
+			}
+			repoDir := filepath.Join(wsPath, repo.Path)
+			ref := git.FindStashRef(repoDir, marker)
+			if ref == "" {
+				ui.Skip.Printf("%-25s stash not found (already applied?)\n", name)
+				continue
+			}
+			verb := "restored"
+			if apply {
+				err = git.StashPopRef(repoDir, ref)
+			} else {
+				verb = "dropped"
+				err = git.StashDropRef(repoDir, ref)
+			}
+			if err != nil {
+				ui.Fail.Printf("%-25s %v\n", name, err)
+				remaining = append(remaining, name)
+				continue
+			}
+			ui.OK.Printf("%-25s %s\n", name, verb)
+		}
+
+		if len(remaining) > 0 {
+			set.Repos = remaining
+			fmt.Printf("\n%d repo(s) still stashed in set %s — resolve and re-run\n", len(remaining), set.ID)
+			return nil
+		}
+		st.Stashes = append(st.Stashes[:idx], st.Stashes[idx+1:]...)
+		return nil
+	})
+}
+
+func init() {
+	stashPushCmd.Flags().StringVarP(&stashMessage, "message", "m", "", "Description for the stash set")
+	stashPushCmd.Flags().StringSliceVar(&stashRepos, "repos", nil, "Only stash these repos (default: all dirty repos)")
+	stashPushCmd.Flags().BoolVarP(&stashUntracked, "include-untracked", "u", false, "Also stash untracked files")
+
+	stashCmd.AddCommand(stashPushCmd, stashPopCmd, stashDropCmd, stashListCmd)
+	rootCmd.AddCommand(stashCmd)
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/metrics"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// regressionThreshold flags a repo/op whose recent runs are this much slower than before
+const regressionThreshold = 1.2
+
+var (
+	statsOp     string
+	statsRepo   string
+	statsWindow int
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show build/test/install/sync timing history (slowest repos, regressions)",
+	Long: `Shows timings recorded locally in .spk/metrics — nothing is sent anywhere.
+
+Every build, test, install, and sync spark-cli runs is timed per repo. stats
+lists each repo/operation sorted by average duration and compares the most
+recent runs against the ones before them, flagging regressions (⚠) where the
+recent average is 20%+ slower.
+
+Examples:
+  spark-cli stats
+  spark-cli stats --op build
+  spark-cli stats --repo AppAPI --window 10`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, _, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		entries, err := metrics.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		if statsWindow < 1 {
+			statsWindow = 1
+		}
+
+		rows := summarizeMetrics(entries, statsOp, statsRepo, statsWindow)
+		if len(rows) == 0 {
+			fmt.Println("No timings recorded yet — run a build, test, or sync first")
+			return nil
+		}
+
+		fmt.Printf("%-25s %-10s %5s %9s %9s %9s  %s\n", "REPO", "OP", "RUNS", "AVG", "LAST", "RECENT", "TREND")
+		var regressions []string
+		for _, r := range rows {
+			trend := "-"
+			if r.prior > 0 {
+				change := float64(r.recent-r.prior) / float64(r.prior) * 100
+				trend = fmt.Sprintf("%+.0f%%", change)
+				if float64(r.recent) > float64(r.prior)*regressionThreshold {
+					trend += " " + ui.Warn.String()
+					regressions = append(regressions, fmt.Sprintf("%s %s", r.repo, r.op))
+				}
+			}
+			fmt.Printf("%-25s %-10s %5d %9s %9s %9s  %s\n",
+				r.repo, r.op, r.runs, formatDuration(r.avg), formatDuration(r.last), formatDuration(r.recent), trend)
+		}
+
+		if len(regressions) > 0 {
+			fmt.Println()
+			ui.Warn.Printf("Slower than before: %s\n", strings.Join(regressions, ", "))
+		}
+		return nil
+	},
+}
+
+// statsRow aggregates timings for one repo/op pair
+type statsRow struct {
+	repo   string
+	op     string
+	runs   int
+	avg    time.Duration
+	last   time.Duration
+	recent time.Duration // average of the last window runs
+	prior  time.Duration // average of the window before that; 0 if not enough history
+}
+
+// summarizeMetrics groups successful entries by repo/op, slowest average first
+func summarizeMetrics(entries []metrics.Entry, op, repo string, window int) []statsRow {
+	groups := make(map[string][]metrics.Entry)
+	var keys []string
+	for _, e := range entries {
+		if !e.OK || (op != "" && e.Op != op) || (repo != "" && e.Repo != repo) {
+			continue
+		}
+		key := e.Repo + "\x00" + e.Op
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], e)
+	}
+
+	rows := make([]statsRow, 0, len(keys))
+	for _, key := range keys {
+		runs := groups[key]
+		n := len(runs)
+		row := statsRow{
+			repo: runs[0].Repo,
+			op:   runs[0].Op,
+			runs: n,
+			avg:  averageDuration(runs),
+			last: runs[n-1].Duration(),
+		}
+		recentStart := max(n-window, 0)
+		row.recent = averageDuration(runs[recentStart:])
+		if recentStart > 0 {
+			row.prior = averageDuration(runs[max(recentStart-window, 0):recentStart])
+		}
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].avg != rows[j].avg {
+			return rows[i].avg > rows[j].avg
+		}
+		return rows[i].repo < rows[j].repo
+	})
+	return rows
+}
+
+func averageDuration(entries []metrics.Entry) time.Duration {
+	if len(entries) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, e := range entries {
+		total += e.Duration()
+	}
+	return total / time.Duration(len(entries))
+}
+
+// formatDuration rounds to a readable precision (e.g. 850ms, 12.3s, 4m5s)
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsOp, "op", "", "Only show one operation (build, test, install, sync, ...)")
+	statsCmd.Flags().StringVar(&statsRepo, "repo", "", "Only show one repo")
+	statsCmd.Flags().IntVar(&statsWindow, "window", 5, "Number of recent runs compared against the runs before them")
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellquote"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// storeDirName is the shared pnpm store inside .spk
+const storeDirName = "store"
+
+var storeCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Manage the shared dependency store (enable | disable | prune)",
+	Long: `Opt-in mode that installs every Node repo with pnpm against one
+content-addressable store in .spk/store. Packages are stored once and hard-linked
+into each repo's node_modules, so near-identical repos stop costing a full copy
+each and reinstalls mostly skip the network.
+
+Repos keep their package-lock.json: spark-cli imports it into a throwaway pnpm
+lockfile for each install, so the exact locked versions are used and nothing in
+the repo changes. Repos that already use pnpm just point at the shared store.
+
+Requires pnpm (npm install -g pnpm). Takes effect on the next install; run
+'spark-cli workspace sync -i' or delete node_modules to reinstall now.
+
+Examples:
+  spark-cli store            # show status and store size
+  spark-cli store enable
+  spark-cli store prune      # drop packages no repo references anymore
+  spark-cli store disable`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		dir := sharedStoreDir(wsPath)
+		if ws.SharedStore {
+			fmt.Println("Shared store: enabled")
+		} else {
+			fmt.Println(binname.Rewrite("Shared store: disabled — enable with 'spark-cli store enable'"))
+		}
+		fmt.Printf("Path:         %s\n", dir)
+		if _, err := os.Stat(dir); err == nil {
+			fmt.Printf("Size:         %s\n", formatBytes(dirSize(dir)))
+		}
+		return nil
+	},
+}
+
+var storeEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Install Node repos through the shared pnpm store",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := npm.CheckPNPM(); err != nil {
+			return err
+		}
+		return setSharedStore(true)
+	},
+}
+
+var storeDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Go back to per-repo npm installs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setSharedStore(false)
+	},
+}
+
+var storePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove packages from the shared store that no repo references",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, _, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		if err := npm.CheckPNPM(); err != nil {
+			return err
+		}
+		dir := sharedStoreDir(wsPath)
+		before := dirSize(dir)
+		if err := runShellCmdWithEnv(wsPath, "pnpm store prune --store-dir "+shellquote.Quote(dir), nil, nil); err != nil {
+			return fmt.Errorf("pnpm store prune failed: %w", err)
+		}
+		ui.OK.Printf("Store pruned: %s → %s\n", formatBytes(before), formatBytes(dirSize(dir)))
+		return nil
+	},
+}
+
+func setSharedStore(enabled bool) error {
+	wsPath, ws, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+	ws.SharedStore = enabled
+	if err := workspace.Save(wsPath, ws); err != nil {
+		return err
+	}
+	if enabled {
+		ui.OK.Printf("Shared store enabled at %s\n", sharedStoreDir(wsPath))
+		fmt.Println("  Existing node_modules are reused until the next install")
+	} else {
+		ui.OK.Println("Shared store disabled — repos install with npm again")
+	}
+	return nil
+}
+
+// sharedStoreDir returns .spk/store for a workspace
+func sharedStoreDir(wsPath string) string {
+	return filepath.Join(workspace.SparkDir(wsPath), storeDirName)
+}
+
+// dirSize sums the sizes of regular files under dir
+func dirSize(dir string) int64 {
+	var total int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+// formatBytes renders a byte count as e.g. 512 B, 3.4 MB, 12.0 GB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	storeCmd.AddCommand(storeEnableCmd)
+	storeCmd.AddCommand(storeDisableCmd)
+	storeCmd.AddCommand(storePruneCmd)
+	rootCmd.AddCommand(storeCmd)
+}
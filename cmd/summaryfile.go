@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// --summary-file, shared by the multi-repo commands (build, test, sync)
+var summaryFile string
+
+func addSummaryFileFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&summaryFile, "summary-file", "", "Write a JSON summary of the run (per-repo status, durations, errors) to this file, for CI")
+}
+
+// runReport collects a multi-repo command's per-repo results for --summary-file. Repos
+// use ci-run's shape, so one parser reads both.
+type runReport struct {
+	command string
+	start   time.Time
+	repos   []ciResult
+}
+
+func newRunReport(command string) *runReport {
+	return &runReport{command: command, start: time.Now()}
+}
+
+// add records a repo's result: passed, or failed when err is set. message says what
+// else happened (e.g. restored from the build cache).
+func (r *runReport) add(repo string, d time.Duration, message string, err error) {
+	res := ciResult{Repo: repo, Status: "passed", DurationMs: d.Milliseconds(), Message: message}
+	if err != nil {
+		res.Status = "failed"
+		res.Error = binname.Rewrite(err.Error())
+	}
+	r.repos = append(r.repos, res)
+}
+
+// skip records a repo that wasn't run, and why
+func (r *runReport) skip(repo, why string) {
+	r.repos = append(r.repos, ciResult{Repo: repo, Status: "skipped", Message: why})
+}
+
+// finish writes the report to --summary-file, if one was given, and returns the
+// command's error. A report that can't be written fails a run that otherwise passed.
+func (r *runReport) finish(runErr error) error {
+	if summaryFile == "" {
+		return runErr
+	}
+
+	status, kindErr := "passed", runErr
+	switch {
+	case proc.TimedOut():
+		status, kindErr = "failed", errs.New(errs.KindTimeout, "timed out")
+	case proc.Interrupted():
+		status, kindErr = "interrupted", errs.New(errs.KindInterrupted, "interrupted")
+	case runErr != nil:
+		status = "failed"
+	}
+	report := struct {
+		Command    string     `json:"command"`
+		Status     string     `json:"status"` // passed, failed, interrupted
+		ExitCode   int        `json:"exit_code"`
+		Error      string     `json:"error,omitempty"`
+		StartedAt  time.Time  `json:"started_at"`
+		DurationMs int64      `json:"duration_ms"`
+		Repos      []ciResult `json:"repos"`
+	}{
+		Command:    r.command,
+		Status:     status,
+		StartedAt:  r.start.UTC().Truncate(time.Second),
+		DurationMs: time.Since(r.start).Milliseconds(),
+		Repos:      r.repos,
+	}
+	if kindErr != nil {
+		report.ExitCode = errs.ExitCode(kindErr)
+		report.Error = binname.Rewrite(kindErr.Error())
+	}
+	if report.Repos == nil {
+		report.Repos = []ciResult{}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(summaryFile), 0755); err == nil {
+			err = os.WriteFile(summaryFile, append(data, '\n'), 0644)
+		}
+	}
+	switch {
+	case err == nil:
+		return runErr
+	case runErr != nil:
+		ui.Warn.Printf("failed to write %s: %v\n", summaryFile, err)
+		return runErr
+	}
+	return fmt.Errorf("failed to write %s: %w", summaryFile, err)
+}
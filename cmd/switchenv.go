@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var switchEnvCmd = &cobra.Command{
+	Use:   "switch-env <env>",
+	Short: "Switch the workspace's active environment (profile + .env) in one step",
+	Long: `Switches the workspace to another environment: resolves its AWS profile (from
+"environments" in workspace.json, if defined), fetches its SSM parameters, rewrites
+the workspace .env, and records it as the active env. The workspace's default
+"aws_profile" is left alone; the profile is looked up per environment each time.
+
+Nothing is saved unless the fetch succeeds, and the .env and workspace.json are
+updated together, so a failed switch leaves the workspace on its previous
+environment.
+
+Examples:
+  spark-cli switch-env prod
+  spark-cli switch-env beta`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env := args[0]
+
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		prevEnv := ws.ActiveEnv()
+		prevProfile, _ := ws.ResolveEnv(prevEnv)
+		profile, _ := ws.ResolveEnv(env)
+
+		before, err := workspace.ReadGlobalEnv(wsPath)
+		if err != nil {
+			return err
+		}
+
+		envVars, err := fetchEnvVars(ws, env, true)
+		if err != nil {
+			return fmt.Errorf("switch to %s aborted: %w", env, err)
+		}
+
+		// Record the env first; if the .env can't be written, both go back
+		prevEnvPath := ws.SSMEnvPath
+		ws.SSMEnvPath = env
+		if err := workspace.Save(wsPath, ws); err != nil {
+			return fmt.Errorf("switch to %s aborted: failed to save workspace: %w", env, err)
+		}
+		envPath := workspace.GlobalEnvPath(wsPath)
+		backup, readErr := os.ReadFile(envPath)
+		if err := writeWorkspaceEnv(wsPath, ws, env, envVars); err != nil {
+			if readErr == nil {
+				os.WriteFile(envPath, backup, 0644)
+			} else if os.IsNotExist(readErr) {
+				os.Remove(envPath)
+			}
+			ws.SSMEnvPath = prevEnvPath
+			if saveErr := workspace.Save(wsPath, ws); saveErr != nil {
+				return fmt.Errorf("switch to %s failed (%v) and workspace.json couldn't be restored: %w", env, err, saveErr)
+			}
+			return fmt.Errorf("switch to %s aborted: %w", env, err)
+		}
+
+		fmt.Println()
+		fmt.Printf("Environment: %s → %s\n", prevEnv, env)
+		if prevProfile != profile {
+			fmt.Printf("AWS profile: %s → %s\n", orDefault(prevProfile, "(not set)"), orDefault(profile, "(not set)"))
+		}
+		printEnvChanges(before, envVars)
+		return nil
+	},
+}
+
+// printEnvChanges lists keys added or changed between two env maps (values are not printed)
+func printEnvChanges(before, after map[string]string) {
+	var added, changed []string
+	unchanged := 0
+	for k, v := range after {
+		old, ok := before[k]
+		switch {
+		case !ok:
+			added = append(added, k)
+		case old != v:
+			changed = append(changed, k)
+		default:
+			unchanged++
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(changed)
+
+	if len(added) == 0 && len(changed) == 0 {
+		fmt.Println("No env keys changed")
+		return
+	}
+	fmt.Printf("Env keys: %d added, %d changed, %d unchanged\n", len(added), len(changed), unchanged)
+	for _, k := range added {
+		fmt.Printf("  + %s\n", k)
+	}
+	for _, k := range changed {
+		fmt.Printf("  ~ %s\n", k)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(switchEnvCmd)
+}
@@ -1,18 +1,31 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/metrics"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/progress"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/retry"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/settings"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -23,19 +36,65 @@ var (
 	syncEnv      string
 	syncInstall  bool
 	syncUpdate   bool
+	syncProfile  bool
+
+	syncRebuildLinked bool
 )
 
 var syncCmd = &cobra.Command{
 	Use:   "sync [repo-name]",
 	Short: "Sync repos (git fetch+rebase); use --env to refresh workspace .env",
-	Long: `Syncs workspace repos with parallel fetches and rebases all local branches.
+	Long: `Syncs workspace repos with parallel fetches and rebases all local branches,
+then summarizes what changed — new commits per repo, repos skipped and why, .env
+keys that changed, installs — and suggests next steps, such as rebuilding repos
+whose dependencies just changed.
+
+When a model (e.g. AppModel) picks up new commits, repos that link its local
+build in node_modules are using a stale build. Sync warns about them; with
+--rebuild-linked it rebuilds the model and relinks those repos.
+
+Sync also regenerates <name>.code-workspace: a folder per repo plus VS Code
+tasks ("spark-cli: build AppAPI", "spark-cli: test all", "spark-cli: sync",
+"spark-cli: dev Web", ...) for the command palette, with TypeScript and Go
+problem matchers where they apply. Your own settings and tasks are kept.
+
+Every repo is synced even when one fails; the summary lists what failed and the
+exit code is non-zero if a repo, install, .env refresh, or rebuild failed.
+--fail-fast stops at the first failure and leaves the remaining repos as they are.
+--summary-file writes each repo's status, duration, and error as JSON, for CI
+(see 'spark-cli build --help').
+
+Once a day sync also asks GitHub whether repos were renamed or moved to another
+org, and updates their remotes in workspace.json. Git follows GitHub's redirect
+until the old name is reused, so clones keep working meanwhile; --fix-remotes
+checks now and also points each moved clone's origin at the new name.
+
+A fork ("upstream" set on the repo, e.g. by 'spark-cli use --fork') is fetched
+from and rebased onto its upstream repo; pushes still go to origin, the fork.
+
+--onto rebases a repo onto another branch, such as a release branch, instead of
+its default branch — on this and every later sync, until you pass its default
+branch again. Without a branch it lists the remote's branches to pick from.
+Only the checked-out branch and branches tracking the --onto branch are rebased;
+the default branch and other work keep their base.
 
   spark-cli workspace sync                # sync all repos (parallel)
-  spark-cli workspace sync --install      # sync + npm install where package-lock changed
+  spark-cli workspace sync --install      # sync + npm ci where package-lock changed since last install
+  spark-cli workspace sync -i --frozen    # never rewrite lockfiles; fail if one is out of date
   spark-cli workspace sync --env beta     # sync and refresh .env from beta
-  spark-cli workspace sync BusinessAPI    # sync one repo`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
+  spark-cli workspace sync BusinessAPI    # sync one repo
+  spark-cli workspace sync AppAPI --onto release/2.3  # sync onto a release branch from now on
+  spark-cli workspace sync AppAPI --onto  # pick the branch from origin's
+  spark-cli workspace sync --profile      # break down time per repo (git, install, link)
+  spark-cli workspace sync --fix-remotes  # follow repos renamed on GitHub, clones included
+  spark-cli workspace sync --rebuild-linked  # rebuild updated models that repos link locally
+  spark-cli workspace sync --fail-fast    # stop at the first repo that fails
+  spark-cli workspace sync --summary-file sync-summary.json  # per-repo results as JSON, for CI`,
+	Args: syncOntoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		report := newRunReport("sync")
+		defer func() { err = report.finish(err) }()
+
 		wsPath, err := workspace.Find()
 		if err != nil {
 			return err
@@ -46,26 +105,62 @@ var syncCmd = &cobra.Command{
 			return err
 		}
 
+		if syncProfile {
+			metrics.EnableProfile()
+			defer metrics.PrintProfile(os.Stdout)
+		}
+
+		if syncUpdate && installOptions(wsPath).Frozen {
+			return errs.New(errs.KindUsage, "--update rewrites lockfiles and can't be combined with frozen installs")
+		}
+		if args, err = applySyncOnto(cmd, wsPath, ws, args); err != nil {
+			return err
+		}
+
+		summary := &syncSummary{}
+		defer summary.report(report)
+		if len(args) == 1 {
+			summary.moved = checkMovedRemotes(wsPath, ws, args)
+		} else {
+			summary.moved = checkMovedRemotes(wsPath, ws, sortedKeys(ws.Repos))
+		}
 		if len(args) == 1 {
-			if err := syncRepo(wsPath, ws, args[0]); err != nil {
+			if err := syncRepo(wsPath, ws, args[0], summary); err != nil {
 				return err
 			}
 		} else {
-			if err := syncAllRepos(wsPath, ws); err != nil {
+			if err := syncAllRepos(wsPath, ws, summary); err != nil {
 				return err
 			}
 		}
 
-		if syncEnv != "" {
+		if syncEnv != "" && !proc.Interrupted() {
+			before, _ := workspace.ReadGlobalEnv(wsPath)
 			if err := refreshEnvQuiet(wsPath, ws); err != nil {
 				fmt.Printf("Warning: failed to refresh .env: %v\n", err)
+				summary.envError = err
 			} else {
 				fmt.Println("Refreshed workspace environment")
+				after, _ := workspace.ReadGlobalEnv(wsPath)
+				summary.envRefreshed = syncEnvName(ws)
+				summary.envChanged = changedEnvKeys(before, after)
+			}
+		}
+
+		if names, err := selectRepos(wsPath, ws, args); err == nil {
+			refreshHooks(wsPath, ws, names)
+		}
+
+		if !proc.Interrupted() {
+			summary.staleLinks = findStaleLinks(wsPath, ws, summary.results)
+			if syncRebuildLinked && len(summary.staleLinks) > 0 {
+				summary.rebuilt, summary.rebuildFailed = rebuildLinkedModels(wsPath, ws, summary.staleLinks)
 			}
 		}
 
 		workspace.GenerateVSCodeWorkspace(wsPath)
-		return nil
+		printSyncSummary(wsPath, ws, summary)
+		return summary.err()
 	},
 }
 
@@ -75,11 +170,15 @@ type repoSyncResult struct {
 	branch          string
 	status          string // "synced", "skipped", "failed"
 	message         string
+	upstream        string
 	ahead           int
 	behind          int
+	pulled          int // upstream commits the branch picked up
 	dirty           bool
 	dirtyStatus     string
 	lockfileChanged bool
+	onto            bool // upstream is the branch remembered by --onto
+	duration        time.Duration
 }
 
 // SSM parameter suffixes to fetch
@@ -115,42 +214,39 @@ var ssmToEnvKey = map[string]string{
 	"stripePublicKey":        "STRIPE_PUBLIC_KEY",
 }
 
-func refreshEnv(wsPath string, ws *workspace.Workspace) error {
-	if err := aws.CheckCLI(); err != nil {
-		return err
-	}
-
-	profile := ws.AWSProfile
-	region := ws.AWSRegion
-	if region == "" {
-		region = "us-east-1"
+// workspaceSSMParams returns the SSM parameters the workspace env is read from: the
+// built-in ones, then the workspace's "ssm_params"
+func workspaceSSMParams(ws *workspace.Workspace) []string {
+	params := slices.Clone(ssmParamSuffixes)
+	extra := make([]string, 0, len(ws.SSMParams))
+	for name := range ws.SSMParams {
+		if !slices.Contains(params, name) {
+			extra = append(extra, name)
+		}
 	}
+	sort.Strings(extra)
+	return append(params, extra...)
+}
 
-	env := syncEnv
-	if env == "" && ws.SSMEnvPath != "" {
-		env = ws.SSMEnvPath
-	}
-	if env == "" {
-		env = "beta"
+// ssmEnvKey returns the workspace env key the SSM parameter name is written as
+func ssmEnvKey(ws *workspace.Workspace, name string) string {
+	if key := ws.SSMParams[name]; key != "" {
+		return key
 	}
-
-	fmt.Printf("Checking AWS credentials (profile: %s)...\n", orDefault(profile, "default"))
-	if err := aws.GetCallerIdentity(profile); err != nil {
-		fmt.Println("AWS session expired, logging in...")
-		if err := aws.SSOLogin(profile); err != nil {
-			return fmt.Errorf("AWS login failed: %w", err)
-		}
+	if key, ok := ssmToEnvKey[name]; ok {
+		return key
 	}
+	return name
+}
 
-	fmt.Printf("Fetching environment from /app/%s/... (%d parameters)\n", env, len(ssmParamSuffixes))
-	ssmVars, err := github.FetchMultipleFromSSM(profile, env, region, ssmParamSuffixes)
+func refreshEnv(wsPath string, ws *workspace.Workspace) error {
+	env := syncEnvName(ws)
+	envVars, err := fetchEnvVars(ws, env, true)
 	if err != nil {
-		return fmt.Errorf("failed to fetch parameters: %w", err)
+		return err
 	}
 
-	envVars := mapSSMToEnv(ssmVars, region, env, ws)
-
-	if err := workspace.WriteGlobalEnv(wsPath, envVars); err != nil {
+	if err := writeWorkspaceEnv(wsPath, ws, env, envVars); err != nil {
 		return err
 	}
 
@@ -159,94 +255,86 @@ func refreshEnv(wsPath string, ws *workspace.Workspace) error {
 }
 
 func refreshEnvQuiet(wsPath string, ws *workspace.Workspace) error {
-	if err := aws.CheckCLI(); err != nil {
+	env := syncEnvName(ws)
+	envVars, err := fetchEnvVars(ws, env, false)
+	if err != nil {
 		return err
 	}
+	return writeWorkspaceEnv(wsPath, ws, env, envVars)
+}
 
-	profile := ws.AWSProfile
-	region := ws.AWSRegion
-	if region == "" {
-		region = "us-east-1"
-	}
+// syncEnvName returns the env selected by --env, falling back to the env setting
+func syncEnvName(ws *workspace.Workspace) string {
+	return activeEnv(ws, syncEnv)
+}
 
-	env := syncEnv
-	if env == "" && ws.SSMEnvPath != "" {
-		env = ws.SSMEnvPath
+// fetchEnvVars logs in if needed and returns the workspace env vars for env from SSM
+func fetchEnvVars(ws *workspace.Workspace, env string, verbose bool) (map[string]string, error) {
+	if err := aws.CheckCLI(); err != nil {
+		return nil, err
 	}
-	if env == "" {
-		env = "beta"
+
+	profile, region := settings.Load(ws).AWS(env)
+
+	if endpoint := ws.LocalEndpoint(env); endpoint != "" {
+		if verbose {
+			fmt.Printf("Fetching environment from LocalStack at %s (/app/%s/...)\n", endpoint, env)
+		}
+		ssmVars, err := github.FetchMultipleFromSSMAt(endpoint, "", env, region, workspaceSSMParams(ws))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch parameters from LocalStack (is it running? 'spark-cli local up'): %w", err)
+		}
+		envVars := mapSSMToEnv(ssmVars, region, env, ws)
+		for k, v := range localEndpointEnv(endpoint) {
+			if _, pinned := ws.Env[k]; !pinned {
+				envVars[k] = v
+			}
+		}
+		return envVars, nil
 	}
 
-	if err := aws.GetCallerIdentityQuiet(profile); err != nil {
+	if verbose {
+		fmt.Printf("Checking AWS credentials (profile: %s)...\n", orDefault(profile, "default"))
+		if err := aws.GetCallerIdentity(profile); err != nil {
+			fmt.Println("AWS session expired, logging in...")
+			if err := aws.SSOLogin(profile); err != nil {
+				return nil, errs.New(errs.KindAuthExpired, "AWS login failed: %w", err)
+			}
+		}
+	} else if err := aws.GetCallerIdentityQuiet(profile); err != nil {
 		if err := aws.SSOLogin(profile); err != nil {
-			return fmt.Errorf("AWS login failed: %w", err)
+			return nil, errs.New(errs.KindAuthExpired, "AWS login failed: %w", err)
 		}
 	}
 
-	ssmVars, err := github.FetchMultipleFromSSM(profile, env, region, ssmParamSuffixes)
+	var spin *progress.Spinner
+	if verbose {
+		spin = progress.Start(fmt.Sprintf("Fetching environment from /app/%s/... (%d parameters)", env, len(workspaceSSMParams(ws))))
+	}
+	ssmVars, err := github.FetchMultipleFromSSM(profile, env, region, workspaceSSMParams(ws))
+	if spin != nil {
+		spin.Stop(err)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to fetch parameters: %w", err)
+		return nil, fmt.Errorf("failed to fetch parameters: %w", err)
 	}
 
-	envVars := mapSSMToEnv(ssmVars, region, env, ws)
-	return workspace.WriteGlobalEnv(wsPath, envVars)
+	return mapSSMToEnv(ssmVars, region, env, ws), nil
 }
 
 func mapSSMToEnv(ssmVars map[string]string, region, env string, ws *workspace.Workspace) map[string]string {
 	envVars := make(map[string]string)
 	for ssmKey, value := range ssmVars {
-		if envKey, ok := ssmToEnvKey[ssmKey]; ok {
-			envVars[envKey] = value
-		} else {
-			envVars[ssmKey] = value
-		}
-	}
-
-	// Business Website NEXT_PUBLIC_* mappings
-	if v, ok := envVars["BUSINESS_USERPOOL_ID"]; ok && v != "" {
-		envVars["NEXT_PUBLIC_USERPOOL_ID"] = v
-	}
-	if v, ok := envVars["BUSINESS_WEB_CLIENT_ID"]; ok && v != "" {
-		envVars["NEXT_PUBLIC_WEB_CLIENT_ID"] = v
-	}
-	if v, ok := envVars["BUSINESS_IDENTITY_POOL_ID"]; ok && v != "" {
-		envVars["NEXT_PUBLIC_IDENTITY_POOL_ID"] = v
-	}
-	if envVars["NEXT_PUBLIC_USERPOOL_ID"] == "" {
-		if v, ok := envVars["USERPOOL_ID"]; ok && v != "" {
-			envVars["NEXT_PUBLIC_USERPOOL_ID"] = v
-		}
-	}
-	if envVars["NEXT_PUBLIC_WEB_CLIENT_ID"] == "" {
-		if v, ok := envVars["WEB_CLIENT_ID"]; ok && v != "" {
-			envVars["NEXT_PUBLIC_WEB_CLIENT_ID"] = v
-		}
-	}
-	if envVars["NEXT_PUBLIC_IDENTITY_POOL_ID"] == "" {
-		if v, ok := envVars["IDENTITY_POOL_ID"]; ok && v != "" {
-			envVars["NEXT_PUBLIC_IDENTITY_POOL_ID"] = v
-		}
-	}
-	if v, ok := envVars["SQUARE_CLIENT_ID"]; ok && v != "" {
-		envVars["NEXT_PUBLIC_SQUARE_CLIENT"] = v
-	}
-	if v, ok := envVars["CLOVER_APP_ID"]; ok && v != "" {
-		envVars["NEXT_PUBLIC_CLOVER_APP_ID"] = v
-	}
-	if v, ok := envVars["GOOGLE_MAPS_KEY"]; ok && v != "" {
-		envVars["NEXT_PUBLIC_GOOGLE_MAPS_API_KEY"] = v
-	}
-	if v, ok := envVars["STRIPE_PUBLIC_KEY"]; ok && v != "" {
-		envVars["NEXT_PUBLIC_STRIPE_KEY"] = v
+		envVars[ssmEnvKey(ws, ssmKey)] = value
 	}
 
+	// Browser-facing NEXT_PUBLIC_* copies are derived per repo by the env generator
 	envVars["AWS_REGION"] = region
-	envVars["NEXT_PUBLIC_AWS_REGION"] = region
 	envVars["APP_ENV"] = env
-	if env != "" {
-		envVars["NEXT_PUBLIC_APP_ENV"] = env
-	}
 
+	for k, v := range ws.ServiceEnv(env) {
+		envVars[k] = v
+	}
 	for k, v := range ws.Env {
 		envVars[k] = v
 	}
@@ -263,7 +351,46 @@ func getTargetBranch(ws *workspace.Workspace, repo *workspace.RepoDef, repoDir s
 	if ws.DefaultBranch != "" {
 		return ws.DefaultBranch
 	}
-	return git.GetDefaultBranch(repoDir)
+	return defaultBranch(repoDir)
+}
+
+// defaultBranches memoizes this run's default branch lookups by repo directory
+var (
+	defaultBranchMu sync.Mutex
+	defaultBranches = make(map[string]state.DefaultBranch)
+)
+
+// defaultBranch returns repoDir's default branch as detected by git.GetDefaultBranch,
+// cached in .spk/state.json until the repo is next fetched. Set default_branch on the
+// repo ('spark-cli repo set <repo> default_branch <branch>') to skip detection.
+func defaultBranch(repoDir string) string {
+	defaultBranchMu.Lock()
+	defer defaultBranchMu.Unlock()
+	key := git.FetchKey(repoDir)
+	if c, ok := defaultBranches[repoDir]; ok && c.Key == key {
+		return c.Branch
+	}
+
+	wsPath, inWorkspace := workspace.FindFrom(repoDir)
+	rel, _ := filepath.Rel(wsPath, repoDir)
+	if inWorkspace {
+		if st, err := state.Load(wsPath); err == nil {
+			if c, ok := st.DefaultBranches[rel]; ok && c.Key == key {
+				defaultBranches[repoDir] = c
+				return c.Branch
+			}
+		}
+	}
+
+	branch := git.GetDefaultBranch(repoDir)
+	defaultBranches[repoDir] = state.DefaultBranch{Branch: branch, Key: key}
+	if inWorkspace {
+		state.Update(wsPath, func(s *state.State) error {
+			s.SetDefaultBranch(rel, branch, key)
+			return nil
+		})
+	}
+	return branch
 }
 
 // cdkLambdaMappings defines which Lambda repo each CDK repo needs symlinked inside it.
@@ -279,6 +406,7 @@ var cdkLambdaMappings = []struct {
 // linkCDKDependencies creates symlinks from each CDK repo to its sibling Lambda repo.
 // Uses relative symlinks so they work on any machine.
 func linkCDKDependencies(wsPath string) {
+	defer metrics.Span("(workspace)", metrics.PhaseLink)()
 	fmt.Println("\nLinking CDK dependencies...")
 	anyLinked := false
 	for _, m := range cdkLambdaMappings {
@@ -314,7 +442,7 @@ func linkCDKDependencies(wsPath string) {
 		// Create relative symlink: ../Lambda from inside CDK dir
 		target := filepath.Join("..", m.Lambda)
 		if err := os.Symlink(target, symlinkPath); err != nil {
-			fmt.Printf("  ✗ %s → %s: %v\n", m.CDK, m.Lambda, err)
+			fmt.Printf("  %s %s → %s: %v\n", ui.Fail, m.CDK, m.Lambda, err)
 		} else {
 			fmt.Printf("  🔗 %s → %s\n", m.CDK, m.Lambda)
 			anyLinked = true
@@ -325,10 +453,10 @@ func linkCDKDependencies(wsPath string) {
 	}
 }
 
-func syncRepo(wsPath string, ws *workspace.Workspace, name string) error {
+func syncRepo(wsPath string, ws *workspace.Workspace, name string, summary *syncSummary) error {
 	repo, ok := ws.Repos[name]
 	if !ok {
-		return fmt.Errorf("repo '%s' not found — run 'spark-cli list' to see repos", name)
+		return errs.New(errs.KindRepoNotFound, "repo '%s' not found — run 'spark-cli list' to see repos", name)
 	}
 
 	repoDir := filepath.Join(wsPath, repo.Path)
@@ -336,11 +464,12 @@ func syncRepo(wsPath string, ws *workspace.Workspace, name string) error {
 		return fmt.Errorf("repo directory missing — run 'spark-cli use %s'", name)
 	}
 
-	result := syncRepoFull(wsPath, ws, name, repo, repoDir)
+	result := timedSyncRepo(wsPath, ws, name, repo, repoDir)
 	printResult(result)
+	summary.results = append(summary.results, result)
 
-	if syncInstall && result.lockfileChanged {
-		installRepo(wsPath, ws, name, repoDir)
+	if syncInstall {
+		installRepo(wsPath, ws, name, repoDir, summary)
 	}
 
 	// If we just synced a CDK repo, ensure its Lambda symlink is in place
@@ -354,9 +483,9 @@ func syncRepo(wsPath string, ws *workspace.Workspace, name string) error {
 	return nil
 }
 
-func syncAllRepos(wsPath string, ws *workspace.Workspace) error {
+func syncAllRepos(wsPath string, ws *workspace.Workspace, summary *syncSummary) error {
 	if len(ws.Repos) == 0 {
-		fmt.Println("No repos in workspace — run 'spark-cli use <repo>' to add one")
+		fmt.Println(binname.Rewrite("No repos in workspace — run 'spark-cli use <repo>' to add one"))
 		return nil
 	}
 
@@ -366,29 +495,46 @@ func syncAllRepos(wsPath string, ws *workspace.Workspace) error {
 	}
 	sort.Strings(allNames)
 
-	// Phase 1: parallel fetch all repos
-	fmt.Println("Fetching all repos...")
-	var wg sync.WaitGroup
-	for _, name := range allNames {
-		repo := ws.Repos[name]
-		repoDir := filepath.Join(wsPath, repo.Path)
+	// Phase 1: parallel fetch all repos, at most `concurrency` at a time
+	spin := progress.Start(fmt.Sprintf("Fetching %d repos", len(allNames)))
+	forEachRepo(ws, allNames, func(_ int, name string) {
+		repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
 		if _, err := os.Stat(repoDir); os.IsNotExist(err) {
-			continue
+			return
 		}
-		wg.Add(1)
-		go func(dir string) {
-			defer wg.Done()
-			git.FetchQuiet(dir, "origin")
-		}(repoDir)
-	}
-	wg.Wait()
+		defer metrics.Span(name, metrics.PhaseGit)()
+		git.FetchQuiet(repoDir, "origin")
+		if repo := ws.Repos[name]; repo.Upstream != "" {
+			fetchUpstream(repoDir, repo)
+		}
+	})
+	spin.Stop(nil)
 
 	// Phase 2: rebase all branches sequentially (safe, needs working tree)
 	results := make([]repoSyncResult, 0, len(allNames))
-	for _, name := range allNames {
+	stop := stopOnFailure(false)
+	stopped := false
+	for i, name := range allNames {
 		repo := ws.Repos[name]
 		repoDir := filepath.Join(wsPath, repo.Path)
 
+		if proc.Interrupted() {
+			results = append(results, repoSyncResult{
+				name:    name,
+				status:  "skipped",
+				message: "interrupted",
+			})
+			continue
+		}
+		if stopped {
+			results = append(results, repoSyncResult{
+				name:    name,
+				status:  "skipped",
+				message: "not run (--fail-fast)",
+			})
+			continue
+		}
+
 		if _, err := os.Stat(repoDir); os.IsNotExist(err) {
 			results = append(results, repoSyncResult{
 				name:    name,
@@ -398,34 +544,48 @@ func syncAllRepos(wsPath string, ws *workspace.Workspace) error {
 			continue
 		}
 
-		result := syncRepoFull(wsPath, ws, name, repo, repoDir)
+		spin := progress.Start(progress.Step(i+1, len(allNames)) + "Rebasing " + name)
+		result := timedSyncRepo(wsPath, ws, name, repo, repoDir)
+		spin.Clear()
 		results = append(results, result)
+		stopped = stop && result.status == "failed"
 	}
 
 	// Phase 3: print status table
 	fmt.Println()
 	printStatusTable(results)
+	summary.results = results
+
+	if proc.Interrupted() {
+		return nil
+	}
 
-	// Phase 4: npm install where package-lock changed
+	// Phase 4: install where package-lock.json differs from the last install
 	if syncInstall {
-		fmt.Println("\nInstalling dependencies where package-lock.json changed...")
+		fmt.Println("\nInstalling dependencies where the lockfile changed...")
 		wsEnv := buildSyncEnv(wsPath, ws)
-		var installed int
+		var pending []string
 		for _, r := range results {
-			if !r.lockfileChanged {
-				continue
-			}
-			repo := ws.Repos[r.name]
-			repoDir := filepath.Join(wsPath, repo.Path)
+			repoDir := filepath.Join(wsPath, ws.Repos[r.name].Path)
 			if _, err := os.Stat(filepath.Join(repoDir, "package.json")); os.IsNotExist(err) {
 				continue
 			}
-			fmt.Printf("  npm install %s...", r.name)
-			if err := runSyncCmd(repoDir, "npm install", wsEnv); err != nil {
-				fmt.Printf(" ✗ %v\n", err)
-			} else {
-				fmt.Printf(" ✓\n")
+			if npmInstallNeeded(wsPath, r.name, repoDir) != "" {
+				pending = append(pending, r.name)
+			}
+		}
+		var installed int
+		for i, name := range pending {
+			if proc.Interrupted() || stopped {
+				break
+			}
+			repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+			if syncInstallRepo(wsPath, name, repoDir, ws.Repos[name].ScopeEnv(wsEnv), progress.Step(i+1, len(pending))) == nil {
 				installed++
+				summary.installed = append(summary.installed, name)
+			} else {
+				summary.installFailed = append(summary.installFailed, name)
+				stopped = stop
 			}
 		}
 		if installed > 0 {
@@ -458,11 +618,13 @@ func syncAllRepos(wsPath string, ws *workspace.Workspace) error {
 			for _, pkg := range pkgs {
 				fmt.Printf("  %s: %s@latest...", name, pkg)
 				cmd := fmt.Sprintf("npm install %s@latest --save", pkg)
-				if err := runSyncCmd(repoDir, cmd, wsEnv); err != nil {
-					fmt.Printf(" ✗\n")
+				if err := runSyncInstall(repoDir, cmd, &repo, wsEnv); err != nil {
+					fmt.Printf(" %s\n", ui.Fail)
 				} else {
-					fmt.Printf(" ✓\n")
+					fmt.Printf(" %s\n", ui.OK)
+					recordLockfile(wsPath, name, repoDir)
 					updated++
+					summary.updated = append(summary.updated, name+": "+pkg)
 				}
 			}
 		}
@@ -479,15 +641,89 @@ func syncAllRepos(wsPath string, ws *workspace.Workspace) error {
 	return nil
 }
 
+// timedSyncRepo runs syncRepoFull and records its duration in the workspace metrics
+func timedSyncRepo(wsPath string, ws *workspace.Workspace, name string, repo workspace.RepoDef, repoDir string) repoSyncResult {
+	start := time.Now()
+	stop := metrics.Span(name, metrics.PhaseGit)
+	result := syncRepoFull(wsPath, ws, name, repo, repoDir)
+	stop()
+	result.duration = time.Since(start)
+	metrics.Record(wsPath, metrics.Entry{
+		Repo:       name,
+		Op:         "sync",
+		DurationMs: result.duration.Milliseconds(),
+		OK:         result.status != "failed",
+	})
+	return result
+}
+
+// syncInstallRepo runs the install behind a spinner labelled "<step><install command> <repo>",
+// printing the ✓/✗ outcome and recording its duration and the lockfile it installed from
+func syncInstallRepo(wsPath, name, repoDir string, wsEnv map[string]string, step string) error {
+	opts := installOptions(wsPath)
+	command, err := npm.InstallCommand(repoDir, opts)
+	if err != nil {
+		ui.Fail.Printf("%s%s: %v\n", step, name, err)
+		return err
+	}
+
+	defer metrics.Span(name, metrics.PhaseInstall)()
+	var out bytes.Buffer
+	install := func() error {
+		spin := progress.Start(fmt.Sprintf("%s%s %s", step, installLabel(command), name))
+		err := metrics.Time(wsPath, name, "install", func() error {
+			return runInstall(command, opts.Frozen, func(c string) error {
+				out.Reset() // keep only the last attempt's output for diagnosis
+				return stopInstallRetries(runSyncCmdOutput(repoDir, c, nil, wsEnv, &out), &out)
+			})
+		})
+		spin.Stop(err)
+		return err
+	}
+	err = install()
+	if err != nil && npm.AuthFailure(out.String()) && recoverNpmAuth(repoDir, wsEnv) {
+		err = install()
+	}
+	if err != nil {
+		explainInstallFailure(os.Stdout, repoDir, out.String(), true)
+		return err
+	}
+	recordLockfile(wsPath, name, repoDir)
+	return nil
+}
+
+// installLabel shortens an install command to its leading "npm ci" / "pnpm install"
+func installLabel(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) < 2 {
+		return command
+	}
+	if fields[1] == "import" {
+		return "pnpm install"
+	}
+	return fields[0] + " " + fields[1]
+}
+
+// fetchUpstream fetches a fork's upstream, adding or updating the git remote to match
+// workspace.json first
+func fetchUpstream(repoDir string, repo workspace.RepoDef) error {
+	if err := git.SetRemote(repoDir, workspace.UpstreamRemote, repo.Upstream); err != nil {
+		return fmt.Errorf("failed to set the %s remote: %w", workspace.UpstreamRemote, err)
+	}
+	return git.FetchQuiet(repoDir, workspace.UpstreamRemote)
+}
+
 // syncRepoFull fetches, rebases all local branches onto main, and returns status
 func syncRepoFull(wsPath string, ws *workspace.Workspace, name string, repo workspace.RepoDef, repoDir string) repoSyncResult {
 	currentBranch := git.GetCurrentBranch(repoDir)
-	targetBranch := getTargetBranch(ws, &repo, repoDir)
-	upstream := fmt.Sprintf("origin/%s", targetBranch)
+	targetBranch, onto := syncTargetBranch(wsPath, ws, name, &repo, repoDir)
+	upstream := fmt.Sprintf("%s/%s", repo.SyncRemote(), targetBranch)
 
 	result := repoSyncResult{
-		name:   name,
-		branch: currentBranch,
+		name:     name,
+		branch:   currentBranch,
+		upstream: upstream,
+		onto:     onto,
 	}
 
 	// Get ahead/behind for current branch vs origin/main
@@ -496,7 +732,11 @@ func syncRepoFull(wsPath string, ws *workspace.Workspace, name string, repo work
 	// Check dirty
 	if git.IsDirty(repoDir) {
 		result.dirty = true
-		status, err := git.StatusShortColor(repoDir)
+		var status string
+		var err error
+		if ui.ColorEnabled() {
+			status, err = git.StatusShortColor(repoDir)
+		}
 		if err != nil || status == "" {
 			status, _ = git.Status(repoDir)
 		}
@@ -507,35 +747,49 @@ func syncRepoFull(wsPath string, ws *workspace.Workspace, name string, repo work
 	}
 
 	if syncNoRebase {
-		if err := git.Pull(repoDir); err != nil {
+		pull := func() error { return git.Pull(repoDir) }
+		if repo.Upstream != "" {
+			pull = func() error { return git.PullFrom(repoDir, workspace.UpstreamRemote, targetBranch) }
+		}
+		if err := pull(); err != nil {
 			result.status = "failed"
 			result.message = err.Error()
 			return result
 		}
 		result.status = "synced"
+		if currentBranch == targetBranch {
+			result.pulled = result.behind
+		}
 		return result
 	}
 
 	// Record package-lock hash before rebase
 	lockBefore := fileHash(filepath.Join(repoDir, "package-lock.json"))
 
-	// Get all local branches
-	branches := git.ListLocalBranches(repoDir)
+	// With --onto the default branch keeps its own history: it's never rebased onto
+	// the --onto branch, even when checked out
+	def := ""
+	if onto {
+		def = getTargetBranch(ws, &repo, repoDir)
+	}
+	keepCurrent := onto && currentBranch == def
 
 	// Rebase current branch first
-	if err := git.RebaseQuiet(repoDir, upstream); err != nil {
-		git.RebaseAbortQuiet(repoDir)
-		result.status = "failed"
-		result.message = fmt.Sprintf("rebase %s onto %s failed", currentBranch, upstream)
-		return result
+	if !keepCurrent {
+		if err := git.RebaseQuiet(repoDir, upstream); err != nil {
+			git.RebaseAbortQuiet(repoDir)
+			result.status = "failed"
+			result.message = fmt.Sprintf("rebase %s onto %s failed", currentBranch, upstream)
+			return result
+		}
 	}
 
 	// Rebase other local branches onto main
 	var rebasedOthers []string
 	var failedOthers []string
-	for _, branch := range branches {
-		if branch == currentBranch || branch == targetBranch {
-			continue
+	for _, branch := range syncOtherBranches(repoDir, currentBranch, targetBranch, def, onto) {
+		if proc.Interrupted() {
+			break
 		}
 		// Checkout, rebase, come back
 		if err := git.CheckoutQuiet(repoDir, branch); err != nil {
@@ -549,19 +803,41 @@ func syncRepoFull(wsPath string, ws *workspace.Workspace, name string, repo work
 		}
 	}
 
-	// Return to original branch
-	git.CheckoutQuiet(repoDir, currentBranch)
+	// Return to original branch (even if interrupted mid-loop)
+	git.RestoreBranchQuiet(repoDir, currentBranch)
+
+	// Keep submodules and LFS objects in step with the rebased checkout
+	var extrasFailed []string
+	if git.HasSubmodules(repoDir) {
+		if err := git.SubmoduleUpdateQuiet(repoDir); err != nil {
+			extrasFailed = append(extrasFailed, "submodule update")
+		}
+	}
+	if git.UsesLFS(repoDir) && git.LFSInstalled() {
+		if err := git.LFSPullQuiet(repoDir); err != nil {
+			extrasFailed = append(extrasFailed, "lfs pull")
+		}
+	}
 
 	// Check if package-lock changed
 	lockAfter := fileHash(filepath.Join(repoDir, "package-lock.json"))
 	result.lockfileChanged = lockBefore != lockAfter
 
 	// Recompute ahead/behind after rebase
+	if !keepCurrent {
+		result.pulled = result.behind
+	}
 	result.ahead, result.behind = git.AheadBehind(repoDir, currentBranch, upstream)
 
 	result.status = "synced"
+	if keepCurrent {
+		result.message = fmt.Sprintf("%s is the default branch — not rebased onto %s", currentBranch, upstream)
+	}
 	if len(rebasedOthers) > 0 {
-		result.message = fmt.Sprintf("+%d branches rebased", len(rebasedOthers))
+		if result.message != "" {
+			result.message += ", "
+		}
+		result.message += fmt.Sprintf("+%d branches rebased", len(rebasedOthers))
 	}
 	if len(failedOthers) > 0 {
 		if result.message != "" {
@@ -569,16 +845,22 @@ func syncRepoFull(wsPath string, ws *workspace.Workspace, name string, repo work
 		}
 		result.message += fmt.Sprintf("%d branch rebase(s) failed: %s", len(failedOthers), strings.Join(failedOthers, ", "))
 	}
+	if len(extrasFailed) > 0 {
+		if result.message != "" {
+			result.message += ", "
+		}
+		result.message += strings.Join(extrasFailed, " and ") + " failed"
+	}
 
 	return result
 }
 
 func printResult(r repoSyncResult) {
-	icon := "✓"
+	icon := ui.OK
 	if r.status == "skipped" {
-		icon = "⏭"
+		icon = ui.Skip
 	} else if r.status == "failed" {
-		icon = "✗"
+		icon = ui.Fail
 	}
 	line := fmt.Sprintf("%s %-25s %-20s", icon, r.name, r.branch)
 	if r.ahead > 0 || r.behind > 0 {
@@ -590,6 +872,9 @@ func printResult(r repoSyncResult) {
 	if r.lockfileChanged {
 		line += " [lock changed]"
 	}
+	if r.onto {
+		line += " [onto " + r.upstream + "]"
+	}
 	if r.message != "" {
 		line += " — " + r.message
 	}
@@ -621,16 +906,18 @@ func fileHash(path string) string {
 	return fmt.Sprintf("%d-%d", info.Size(), info.ModTime().UnixNano())
 }
 
-func installRepo(wsPath string, ws *workspace.Workspace, name, repoDir string) {
+func installRepo(wsPath string, ws *workspace.Workspace, name, repoDir string, summary *syncSummary) {
 	if _, err := os.Stat(filepath.Join(repoDir, "package.json")); os.IsNotExist(err) {
 		return
 	}
-	wsEnv := buildSyncEnv(wsPath, ws)
-	fmt.Printf("  npm install %s...", name)
-	if err := runSyncCmd(repoDir, "npm install", wsEnv); err != nil {
-		fmt.Printf(" ✗ %v\n", err)
+	if npmInstallNeeded(wsPath, name, repoDir) == "" {
+		return
+	}
+	wsEnv := ws.Repos[name].ScopeEnv(buildSyncEnv(wsPath, ws))
+	if syncInstallRepo(wsPath, name, repoDir, wsEnv, "") == nil {
+		summary.installed = append(summary.installed, name)
 	} else {
-		fmt.Printf(" ✓\n")
+		summary.installFailed = append(summary.installFailed, name)
 	}
 }
 
@@ -646,15 +933,23 @@ func buildSyncEnv(wsPath string, ws *workspace.Workspace) map[string]string {
 	return ensureGitHubTokenSync(wsEnv)
 }
 
-func runSyncCmd(dir, command string, wsEnv map[string]string) error {
-	shell := os.Getenv("SHELL")
-	if shell == "" {
-		shell = "/bin/zsh"
+// runSyncCmd runs command quietly in dir, with wsEnv scoped to repo as runShellCmdWithEnv
+// does (repo is nil outside a repo)
+func runSyncCmd(dir, command string, repo *workspace.RepoDef, wsEnv map[string]string) error {
+	return runSyncCmdOutput(dir, command, repo, wsEnv, nil)
+}
+
+// runSyncCmdOutput is runSyncCmd that writes the command's output to out instead of
+// discarding it
+func runSyncCmdOutput(dir, command string, repo *workspace.RepoDef, wsEnv map[string]string, out io.Writer) error {
+	if repo != nil {
+		wsEnv = repo.ScopeEnv(wsEnv)
 	}
-	cmd := exec.Command(shell, "-l", "-c", command)
+	shell, args := shellCommand(command)
+	cmd := proc.Command(shell, args...)
 	cmd.Dir = dir
-	cmd.Stdout = nil
-	cmd.Stderr = nil
+	cmd.Stdout = out
+	cmd.Stderr = out
 
 	if len(wsEnv) > 0 {
 		envMap := make(map[string]string)
@@ -675,6 +970,15 @@ func runSyncCmd(dir, command string, wsEnv map[string]string) error {
 	return cmd.Run()
 }
 
+// runSyncInstall runs a package install command, retrying transient registry/network failures
+func runSyncInstall(dir, command string, repo *workspace.RepoDef, wsEnv map[string]string) error {
+	var out bytes.Buffer
+	return retry.Do(func() error {
+		out.Reset()
+		return stopInstallRetries(runSyncCmdOutput(dir, command, repo, wsEnv, &out), &out)
+	})
+}
+
 // findSparkPackages reads package.json and returns all @spark-rewards/* dependency names
 func findSparkPackages(repoDir string) []string {
 	pkgPath := filepath.Join(repoDir, "package.json")
@@ -710,7 +1014,7 @@ func findSparkPackages(repoDir string) []string {
 }
 
 func ensureGitHubTokenSync(wsEnv map[string]string) map[string]string {
-	if os.Getenv("GITHUB_TOKEN") != "" {
+	if ciMode || os.Getenv("GITHUB_TOKEN") != "" {
 		return wsEnv
 	}
 	if wsEnv != nil {
@@ -718,7 +1022,7 @@ func ensureGitHubTokenSync(wsEnv map[string]string) map[string]string {
 			return wsEnv
 		}
 	}
-	out, err := exec.Command("gh", "auth", "token").Output()
+	out, err := proc.Command("gh", "auth", "token").Output()
 	if err != nil {
 		return wsEnv
 	}
@@ -734,9 +1038,18 @@ func ensureGitHubTokenSync(wsEnv map[string]string) map[string]string {
 
 func init() {
 	syncCmd.Flags().StringVar(&syncBranch, "branch", "", "Target branch (default: main)")
+	syncCmd.Flags().StringVar(&syncOnto, "onto", "", "Rebase the repo onto this `branch` from now on; without one, pick from a list")
+	syncCmd.Flags().Lookup("onto").NoOptDefVal = ontoPick
+	syncCmd.MarkFlagsMutuallyExclusive("branch", "onto")
+	syncCmd.Flags().BoolVar(&syncFixRemotes, "fix-remotes", false, "Check GitHub for renamed or moved repos now, and point their clones' origin at the new name")
 	syncCmd.Flags().BoolVar(&syncNoRebase, "no-rebase", false, "Use git pull instead of rebase")
 	syncCmd.Flags().StringVar(&syncEnv, "env", "", "Refresh .env from this SSM environment (e.g. beta, prod)")
-	syncCmd.Flags().BoolVarP(&syncInstall, "install", "i", false, "Run npm install on repos where package-lock.json changed")
+	syncCmd.Flags().BoolVarP(&syncInstall, "install", "i", false, "Install dependencies in repos whose lockfile changed since their last install")
 	syncCmd.Flags().BoolVarP(&syncUpdate, "update", "u", false, "Update @spark-rewards/* packages to latest in all repos")
+	syncCmd.Flags().BoolVar(&frozenInstall, "frozen", false, "With --install, use npm ci / pnpm --frozen-lockfile and fail instead of updating lockfiles")
+	syncCmd.Flags().BoolVar(&syncRebuildLinked, "rebuild-linked", false, "Rebuild models that picked up new commits and relink the repos that link them locally")
+	syncCmd.Flags().BoolVar(&syncProfile, "profile", false, "Print a per-repo breakdown of time spent in git, install, and linking")
+	addFailurePolicyFlags(syncCmd, false)
+	addSummaryFileFlag(syncCmd)
 	workspaceCmd.AddCommand(syncCmd)
 }
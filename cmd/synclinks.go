@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/progress"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/spkconfig"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+)
+
+// staleLink is a consumer whose node_modules symlink points at a model that just
+// picked up new commits, so the build it links to no longer matches the source
+type staleLink struct {
+	consumer string
+	entry    spkconfig.ConsumesEntry
+}
+
+// findStaleLinks returns the local model links whose model was updated by this sync
+func findStaleLinks(wsPath string, ws *workspace.Workspace, results []repoSyncResult) []staleLink {
+	changed := make(map[string]bool)
+	for _, r := range results {
+		if r.status == "synced" && r.pulled > 0 {
+			changed[r.name] = true
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(ws.Repos))
+	for name := range ws.Repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var stale []staleLink
+	for _, name := range names {
+		repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+		cfg, err := spkconfig.Load(repoDir)
+		if err != nil || cfg == nil {
+			continue
+		}
+		for _, c := range cfg.Consumes {
+			if changed[c.Model] && consumerLinked(wsPath, ws, name, c) {
+				stale = append(stale, staleLink{consumer: name, entry: c})
+			}
+		}
+	}
+	return stale
+}
+
+// rebuildLinkedModels rebuilds each model with stale links and points its consumers'
+// symlinks at the fresh build. It returns the models rebuilt and those whose build failed.
+func rebuildLinkedModels(wsPath string, ws *workspace.Workspace, stale []staleLink) (rebuilt, failed []string) {
+	var models []string
+	byModel := make(map[string][]staleLink)
+	for _, l := range stale {
+		if _, seen := byModel[l.entry.Model]; !seen {
+			models = append(models, l.entry.Model)
+		}
+		byModel[l.entry.Model] = append(byModel[l.entry.Model], l)
+	}
+
+	fmt.Println("\nRebuilding models with local links...")
+	wsEnv := buildSyncEnv(wsPath, ws)
+	st, err := state.Load(wsPath)
+	if err != nil {
+		st = &state.State{}
+	}
+	for i, model := range models {
+		if proc.Interrupted() {
+			break
+		}
+		step := progress.Step(i+1, len(models))
+		start := time.Now()
+		if err := buildRepo(wsPath, ws, model, wsEnv); err != nil {
+			ui.Fail.Printf("%s%s: %v\n", step, model, err)
+			failed = append(failed, model)
+			continue
+		}
+		ui.OK.Printf("%s%-25s built (%s)\n", step, model, progress.Elapsed(time.Since(start)))
+		rebuilt = append(rebuilt, model)
+
+		for _, l := range byModel[model] {
+			if enabled, why := linkEnabled(ws, st, l.consumer); !enabled {
+				ui.Skip.Printf("  %s → %s: not relinked (%s)\n", l.consumer, l.entry.Package, why)
+				continue
+			}
+			if _, err := linkModel(wsPath, ws, l.consumer, l.entry); err != nil {
+				ui.Fail.Printf("  %s → %s: %v\n", l.consumer, l.entry.Package, err)
+				continue
+			}
+			fmt.Printf("  🔗 %s → %s\n", l.consumer, l.entry.Package)
+		}
+	}
+	return rebuilt, failed
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var syncOnto string
+
+// ontoPick is --onto given without a branch: pick one from the remote's branches
+const ontoPick = "?"
+
+// syncOntoArgs validates sync's args: one repo at most, plus the branch when --onto is
+// followed by a space rather than "=" (cobra reads "--onto release/2.3" as a bare --onto)
+func syncOntoArgs(cmd *cobra.Command, args []string) error {
+	if syncOnto == ontoPick && len(args) == 2 {
+		return nil
+	}
+	return cobra.MaximumNArgs(1)(cmd, args)
+}
+
+// applySyncOnto handles --onto: it resolves the branch (picking one if none was given),
+// remembers it as the repo's sync target, and returns the args without the branch
+func applySyncOnto(cmd *cobra.Command, wsPath string, ws *workspace.Workspace, args []string) ([]string, error) {
+	if !cmd.Flags().Changed("onto") {
+		return args, nil
+	}
+	if syncOnto == ontoPick && len(args) == 2 {
+		syncOnto, args = args[1], args[:1]
+	}
+	if len(args) == 0 {
+		return nil, errs.New(errs.KindUsage, "--onto needs a repo — 'spark-cli workspace sync <repo> --onto <branch>'")
+	}
+	name := args[0]
+	repo, ok := ws.Repos[name]
+	if !ok {
+		return nil, errs.New(errs.KindRepoNotFound, "repo '%s' not found — run 'spark-cli list' to see repos", name)
+	}
+	repoDir := filepath.Join(wsPath, repo.Path)
+	if _, err := os.Stat(repoDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("repo directory missing — run 'spark-cli use %s'", name)
+	}
+
+	def := getTargetBranch(ws, &repo, repoDir)
+	remote := repo.SyncRemote()
+	branch := syncOnto
+	if branch == ontoPick || !git.RemoteBranchExists(repoDir, remote, branch) {
+		if repo.Upstream != "" {
+			fetchUpstream(repoDir, repo)
+		} else {
+			git.FetchQuiet(repoDir, remote)
+		}
+	}
+	if branch == ontoPick {
+		current, _ := syncTargetBranch(wsPath, ws, name, &repo, repoDir)
+		picked, err := pickOntoBranch(name, repoDir, remote, def, current)
+		if err != nil {
+			return nil, err
+		}
+		branch = picked
+	} else if !git.RemoteBranchExists(repoDir, remote, branch) {
+		return nil, errs.New(errs.KindUsage, "%s has no branch '%s' — 'spark-cli workspace sync %s --onto' lists them", remote, branch, name)
+	}
+
+	remembered := branch
+	if branch == def {
+		remembered = ""
+	}
+	if err := state.Update(wsPath, func(s *state.State) error {
+		s.SetSyncOnto(name, remembered)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if remembered == "" {
+		ui.OK.Printf("%s syncs onto its default branch, %s, again\n", name, def)
+	} else {
+		ui.OK.Printf("%s now syncs onto %s — %s\n", name, branch,
+			binname.Rewrite(fmt.Sprintf("'spark-cli workspace sync %s --onto %s' goes back", name, def)))
+	}
+	return args, nil
+}
+
+// pickOntoBranch lists remote's branches, the default first, and returns the one picked
+func pickOntoBranch(name, repoDir, remote, def, current string) (string, error) {
+	branches := git.RemoteBranches(repoDir, remote)
+	slices.Sort(branches)
+	if i := slices.Index(branches, def); i > 0 {
+		branches = append([]string{def}, slices.Delete(branches, i, i+1)...)
+	}
+	labels := make([]string, len(branches))
+	for i, b := range branches {
+		labels[i] = b
+		switch b {
+		case current:
+			labels[i] += " (current)"
+		case def:
+			labels[i] += " (default)"
+		}
+	}
+	picked, err := prompt.Pick(fmt.Sprintf("Sync %s onto which branch of %s?", name, remote), labels)
+	if err != nil {
+		return "", errs.Wrap(errs.KindUsage, err)
+	}
+	if len(picked) != 1 {
+		return "", errs.New(errs.KindUsage, "pick one branch")
+	}
+	return branches[picked[0]], nil
+}
+
+// syncOtherBranches returns the local branches besides current that sync rebases onto
+// target: all of them normally, but with an --onto override only those tracking target
+// (on any remote), and never the repo's default branch def
+func syncOtherBranches(repoDir, current, target, def string, onto bool) []string {
+	var tracking map[string]string
+	if onto {
+		tracking = git.BranchUpstreams(repoDir)
+	}
+	var branches []string
+	for _, branch := range git.ListLocalBranches(repoDir) {
+		if branch == current || branch == target {
+			continue
+		}
+		if onto {
+			_, tracked, _ := strings.Cut(tracking[branch], "/")
+			if branch == def || tracked != target {
+				continue
+			}
+		}
+		branches = append(branches, branch)
+	}
+	return branches
+}
+
+// syncTargetBranch returns the branch sync rebases a repo onto: --branch, then the one
+// remembered by --onto, then its default. onto reports whether --onto's applied.
+func syncTargetBranch(wsPath string, ws *workspace.Workspace, name string, repo *workspace.RepoDef, repoDir string) (branch string, onto bool) {
+	if syncBranch == "" {
+		if st, err := state.Load(wsPath); err == nil && st.SyncOnto[name] != "" {
+			return st.SyncOnto[name], true
+		}
+	}
+	return getTargetBranch(ws, repo, repoDir), false
+}
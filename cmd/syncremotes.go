@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/progress"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+)
+
+// remotesCheckEvery is how often sync asks GitHub whether repos were renamed or moved
+const remotesCheckEvery = 24 * time.Hour
+
+var syncFixRemotes bool
+
+// movedRemote is a repo GitHub now knows by another name
+type movedRemote struct {
+	repo     string
+	from, to string // "owner/name" before and now
+	manifest bool   // workspace.json's remote was updated
+	clone    string // the clone's origin: "fixed", "stale" (still the old name), or "" (not moved)
+}
+
+// remoteCheck is what GitHub said about one repo's remotes; "" where nothing moved
+type remoteCheck struct {
+	manifest, manifestTo string // workspace.json's remote
+	origin, originTo     string // the clone's origin
+	originURL            string
+}
+
+// checkMovedRemotes asks GitHub for the current name of each repo — at most once a day,
+// or every time with --fix-remotes — and updates workspace.json's remotes for the ones
+// that were renamed or moved. With --fix-remotes it also points the clones' origin at
+// the new name. Repos it can't look up (no token, offline, not on GitHub) are left alone.
+func checkMovedRemotes(wsPath string, ws *workspace.Workspace, names []string) []movedRemote {
+	if !syncFixRemotes && !remotesCheckDue(wsPath) {
+		return nil
+	}
+	state.Update(wsPath, func(s *state.State) error {
+		s.RemotesCheckedAt = time.Now().UTC().Format(time.RFC3339)
+		return nil
+	})
+	token := resolveGitHubToken()
+	if token == "" {
+		if syncFixRemotes {
+			ui.Warn.Println("Can't check GitHub for renamed repos without a token — set GITHUB_TOKEN or run 'gh auth login'")
+		}
+		return nil
+	}
+
+	renamed := func(slug string) string {
+		if slug == "" {
+			return ""
+		}
+		r, err := github.GetRepo(token, slug)
+		if err != nil || r.FullName == "" || strings.EqualFold(r.FullName, slug) {
+			return ""
+		}
+		return r.FullName
+	}
+	checks := make([]remoteCheck, len(names))
+	spin := progress.Start("Checking GitHub for renamed repos")
+	forEachRepo(ws, names, func(i int, name string) {
+		c := remoteCheck{manifest: git.GitHubSlug(ws.Repos[name].Remote)}
+		c.manifestTo = renamed(c.manifest)
+		repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+		if _, err := os.Stat(repoDir); err == nil && git.IsRepo(repoDir) {
+			c.originURL = git.RemoteURL(repoDir, "origin")
+			c.origin = git.GitHubSlug(c.originURL)
+			if strings.EqualFold(c.origin, c.manifest) {
+				c.originTo = c.manifestTo
+			} else {
+				c.originTo = renamed(c.origin)
+			}
+		}
+		checks[i] = c
+	})
+	spin.Stop(nil)
+
+	var moved []movedRemote
+	manifestChanged := false
+	for i, name := range names {
+		c := checks[i]
+		if c.manifestTo == "" && c.originTo == "" {
+			continue
+		}
+		m := movedRemote{repo: name, from: c.manifest, to: c.manifestTo}
+		if c.manifestTo != "" {
+			repo := ws.Repos[name]
+			repo.Remote = renameRemoteURL(repo.Remote, c.manifest, c.manifestTo)
+			ws.Repos[name] = repo
+			m.manifest = true
+			manifestChanged = true
+		}
+		if c.originTo != "" {
+			if m.to == "" {
+				m.from, m.to = c.origin, c.originTo
+			}
+			m.clone = "stale"
+			if syncFixRemotes {
+				repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+				if err := git.SetRemoteURL(repoDir, "origin", renameRemoteURL(c.originURL, c.origin, c.originTo)); err != nil {
+					ui.Warn.Printf("%s: failed to update origin: %v\n", name, err)
+				} else {
+					m.clone = "fixed"
+				}
+			}
+		}
+		moved = append(moved, m)
+	}
+	if manifestChanged {
+		if err := workspace.Save(wsPath, ws); err != nil {
+			ui.Warn.Printf("failed to save the new remotes to workspace.json: %v\n", err)
+		}
+	}
+	return moved
+}
+
+// remotesCheckDue reports whether a day has passed since sync last checked for renamed repos
+func remotesCheckDue(wsPath string) bool {
+	st, err := state.Load(wsPath)
+	if err != nil {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, st.RemotesCheckedAt)
+	return err != nil || time.Since(t) >= remotesCheckEvery
+}
+
+// renameRemoteURL replaces the "owner/name" from in a remote URL with to, keeping its
+// protocol
+func renameRemoteURL(remote, from, to string) string {
+	i := strings.Index(strings.ToLower(remote), strings.ToLower(from))
+	if i < 0 {
+		return remote
+	}
+	return remote[:i] + to + remote[i+len(from):]
+}
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+)
+
+// syncSummary collects what a sync did, for the report printed when it finishes
+type syncSummary struct {
+	results       []repoSyncResult
+	installed     []string
+	installFailed []string
+	updated       []string // "<repo>: <package>" upgraded by --update
+	envRefreshed  string   // env the .env was refreshed from, if it was
+	envChanged    []string // .env keys added, changed, or removed
+	envError      error
+	staleLinks    []staleLink // local model links whose model changed
+	rebuilt       []string    // models rebuilt by --rebuild-linked
+	rebuildFailed []string
+	moved         []movedRemote // repos renamed or moved on GitHub
+}
+
+// printSyncSummary prints what sync changed and what to do next
+func printSyncSummary(wsPath string, ws *workspace.Workspace, s *syncSummary) {
+	var rebased, current, skipped, failed []repoSyncResult
+	for _, r := range s.results {
+		switch {
+		case r.status == "failed":
+			failed = append(failed, r)
+		case r.status == "skipped":
+			skipped = append(skipped, r)
+		case r.pulled > 0:
+			rebased = append(rebased, r)
+		default:
+			current = append(current, r)
+		}
+	}
+
+	fmt.Println("\nSummary:")
+	if len(rebased) > 0 {
+		parts := make([]string, len(rebased))
+		for i, r := range rebased {
+			parts[i] = fmt.Sprintf("%s (%d new commit(s))", r.name, r.pulled)
+		}
+		fmt.Printf("  %s Updated     %s\n", ui.OK, strings.Join(parts, ", "))
+	}
+	if len(current) > 0 {
+		names := make([]string, len(current))
+		for i, r := range current {
+			names[i] = r.name
+		}
+		fmt.Printf("  %s Up to date  %s\n", ui.OK, strings.Join(names, ", "))
+	}
+	for _, r := range skipped {
+		fmt.Printf("  %s Skipped     %s — %s\n", ui.Skip, r.name, r.message)
+		if r.dirtyStatus != "" {
+			for _, line := range strings.Split(r.dirtyStatus, "\n") {
+				fmt.Printf("                  %s\n", line)
+			}
+		}
+	}
+	for _, r := range failed {
+		fmt.Printf("  %s Failed      %s — %s\n", ui.Fail, r.name, r.message)
+	}
+	for _, m := range s.moved {
+		var updated []string
+		if m.manifest {
+			updated = append(updated, "workspace.json")
+		}
+		if m.clone == "fixed" {
+			updated = append(updated, "origin")
+		}
+		icon := ui.OK
+		if m.clone == "stale" {
+			icon = ui.Warn
+		}
+		fmt.Printf("  %s Moved       %s: %s → %s", icon, m.repo, m.from, m.to)
+		if len(updated) > 0 {
+			fmt.Printf(" (updated %s)", strings.Join(updated, " and "))
+		}
+		fmt.Println()
+	}
+
+	switch {
+	case s.envError != nil:
+		fmt.Printf("  %s Env         .env not refreshed: %v\n", ui.Fail, s.envError)
+	case s.envRefreshed != "" && len(s.envChanged) == 0:
+		fmt.Printf("  %s Env         .env unchanged (%s)\n", ui.OK, s.envRefreshed)
+	case s.envRefreshed != "":
+		fmt.Printf("  %s Env         %d key(s) changed from %s: %s\n", ui.OK, len(s.envChanged),
+			s.envRefreshed, strings.Join(s.envChanged, ", "))
+	}
+
+	if len(s.installed) > 0 {
+		fmt.Printf("  %s Installed   %s\n", ui.OK, strings.Join(s.installed, ", "))
+	}
+	if len(s.installFailed) > 0 {
+		fmt.Printf("  %s Install     failed in %s\n", ui.Fail, strings.Join(s.installFailed, ", "))
+	}
+	if len(s.updated) > 0 {
+		fmt.Printf("  %s Upgraded    %s\n", ui.OK, strings.Join(s.updated, ", "))
+	}
+
+	relinked := make(map[string][]string)
+	for _, l := range s.staleLinks {
+		relinked[l.entry.Model] = append(relinked[l.entry.Model], l.consumer)
+	}
+	for _, model := range s.rebuilt {
+		fmt.Printf("  %s Rebuilt     %s, relinked %s\n", ui.OK, model, strings.Join(relinked[model], ", "))
+	}
+	for _, model := range s.rebuildFailed {
+		fmt.Printf("  %s Rebuild     %s failed\n", ui.Fail, model)
+	}
+	for _, l := range s.staleLinks {
+		if !slices.Contains(s.rebuilt, l.entry.Model) {
+			fmt.Printf("  %s Stale link  %s → %s (%s changed since it was built)\n", ui.Warn, l.consumer, l.entry.Package, l.entry.Model)
+		}
+	}
+
+	steps := syncFollowUps(wsPath, ws, s, rebased, skipped, failed)
+	if len(steps) == 0 {
+		return
+	}
+	fmt.Println("\nNext steps:")
+	for _, step := range steps {
+		fmt.Printf("  • %s\n", step)
+	}
+}
+
+// err returns sync's error when a repo, install, .env refresh, or rebuild failed,
+// naming what did; printSyncSummary has already listed the details
+func (s *syncSummary) err() error {
+	var failed []string
+	for _, r := range s.results {
+		if r.status == "failed" {
+			failed = append(failed, r.name)
+		}
+	}
+	for _, name := range s.installFailed {
+		failed = append(failed, name+" (install)")
+	}
+	for _, model := range s.rebuildFailed {
+		failed = append(failed, model+" (rebuild)")
+	}
+	if s.envError != nil {
+		failed = append(failed, ".env refresh")
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return errs.New(errs.KindCommandFailed, "sync failed in %s", strings.Join(failed, ", "))
+}
+
+// report adds each repo's sync to r: its rebase, and its install or model rebuild
+// if it failed
+func (s *syncSummary) report(r *runReport) {
+	for _, res := range s.results {
+		var err error
+		switch {
+		case res.status == "skipped":
+			r.skip(res.name, res.message)
+			continue
+		case res.status == "failed":
+			err = errors.New(res.message)
+		case slices.Contains(s.installFailed, res.name):
+			err = errors.New("install failed")
+		case slices.Contains(s.rebuildFailed, res.name):
+			err = errors.New("rebuild failed")
+		}
+		message := ""
+		if err == nil && res.pulled > 0 {
+			message = fmt.Sprintf("%d new commit(s)", res.pulled)
+		}
+		r.add(res.name, res.duration, message, err)
+	}
+}
+
+// syncFollowUps suggests what to run after a sync: rebuilds of repos whose
+// dependencies changed, installs for changed lockfiles, and fixes for skipped or
+// failed repos
+func syncFollowUps(wsPath string, ws *workspace.Workspace, s *syncSummary, rebased, skipped, failed []repoSyncResult) []string {
+	var steps []string
+
+	// Repos that depend on something that just changed need a rebuild
+	changedDeps := make(map[string][]string)
+	for _, r := range rebased {
+		for _, dependent := range workspace.Dependents(wsPath, ws, []string{r.name}) {
+			if dependent == r.name {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(wsPath, ws.Repos[dependent].Path)); err != nil {
+				continue
+			}
+			changedDeps[dependent] = append(changedDeps[dependent], packageLabel(wsPath, ws, r.name))
+		}
+	}
+	dependents := make([]string, 0, len(changedDeps))
+	for name := range changedDeps {
+		dependents = append(dependents, name)
+	}
+	sort.Strings(dependents)
+	for _, name := range dependents {
+		steps = append(steps, fmt.Sprintf(binname.Rewrite("%s needs rebuild because %s changed — 'spark-cli build %s --deps'"),
+			name, strings.Join(changedDeps[name], " and "), name))
+	}
+
+	var staleModels []string
+	for _, l := range s.staleLinks {
+		if !slices.Contains(s.rebuilt, l.entry.Model) && !slices.Contains(staleModels, l.entry.Model) {
+			staleModels = append(staleModels, l.entry.Model)
+		}
+	}
+	for _, model := range staleModels {
+		steps = append(steps, fmt.Sprintf(binname.Rewrite("Rebuild %s so the repos linking it pick up its changes — 'spark-cli build %s' or 'spark-cli workspace sync --rebuild-linked'"), model, model))
+	}
+
+	installed := make(map[string]bool)
+	for _, name := range s.installed {
+		installed[name] = true
+	}
+	for _, name := range s.installFailed {
+		installed[name] = true
+	}
+	var needInstall []string
+	for _, r := range s.results {
+		if r.lockfileChanged && !installed[r.name] {
+			needInstall = append(needInstall, r.name)
+		}
+	}
+	if len(needInstall) > 0 {
+		steps = append(steps, fmt.Sprintf(binname.Rewrite("Lockfile changed in %s — 'spark-cli workspace sync --install'"), strings.Join(needInstall, ", ")))
+	}
+	for _, name := range s.installFailed {
+		steps = append(steps, fmt.Sprintf("Install failed in %s — see the cause and fix above, or run it by hand: cd %s && npm install", name, ws.Repos[name].Path))
+	}
+
+	for _, r := range skipped {
+		switch {
+		case r.dirty:
+			steps = append(steps, fmt.Sprintf(binname.Rewrite("Commit or stash %s's changes, then 'spark-cli workspace sync %s'"), r.name, r.name))
+		case r.message == "not cloned":
+			steps = append(steps, fmt.Sprintf(binname.Rewrite("%s isn't cloned — 'spark-cli use %s'"), r.name, r.name))
+		}
+	}
+	for _, m := range s.moved {
+		if m.clone == "stale" {
+			steps = append(steps, fmt.Sprintf(binname.Rewrite("%s's origin still points at %s — 'spark-cli workspace sync --fix-remotes' moves it to %s"), m.repo, m.from, m.to))
+		}
+	}
+	for _, r := range failed {
+		repoDir := filepath.Join(wsPath, ws.Repos[r.name].Path)
+		if strings.HasPrefix(r.message, "rebase ") && git.IsRepo(repoDir) {
+			steps = append(steps, fmt.Sprintf("Rebase %s by hand to resolve conflicts: cd %s && git rebase %s",
+				r.name, ws.Repos[r.name].Path, r.upstream))
+		}
+	}
+	if proc.Interrupted() {
+		steps = append(steps, "Sync was interrupted — run 'spark-cli workspace sync' again to finish")
+	}
+	return steps
+}
+
+// packageLabel names a repo by its npm package when it has one, e.g.
+// "AppModel (@spark-rewards/sra-sdk)"
+func packageLabel(wsPath string, ws *workspace.Workspace, name string) string {
+	repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+	for _, dir := range []string{npm.BuildOutputDir(repoDir), repoDir} {
+		if pkg, err := npm.GetPackageName(dir); err == nil && pkg != "" {
+			return fmt.Sprintf("%s (%s)", name, pkg)
+		}
+	}
+	return name
+}
+
+// changedEnvKeys returns the keys added, changed, or removed between two .env files
+func changedEnvKeys(before, after map[string]string) []string {
+	var keys []string
+	for k, v := range after {
+		if old, ok := before[k]; !ok || old != v {
+			keys = append(keys, k)
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
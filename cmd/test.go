@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/metrics"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellquote"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testAll     bool
+	testProfile bool
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test [repo...]",
+	Short: "Run repos' tests (test_command or the test script)",
+	Long: `Runs the tests of the given repos (default: the repo you're in, otherwise
+pick from a list), using the repo's test_command from workspace.json or the
+project's test script (npm test, ./gradlew test, go test ./...). Every repo runs
+and failures are listed at the end; the exit code is non-zero if any failed.
+--fail-fast stops at the first repo whose tests fail. --summary-file writes each
+repo's status, duration, and error as JSON, for CI (see 'spark-cli build --help').
+
+Examples:
+  spark-cli test                   # current repo
+  spark-cli test AppAPI AppModel
+  spark-cli test --all
+  spark-cli test --all --fail-fast
+  spark-cli test --all --profile   # print where the time went (install vs tests)
+  spark-cli test --all --summary-file test-summary.json`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		report := newRunReport("test")
+		defer func() { err = report.finish(err) }()
+
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+
+		if testProfile {
+			metrics.EnableProfile()
+			defer metrics.PrintProfile(os.Stdout)
+		}
+
+		names := args
+		switch {
+		case testAll:
+			names, err = selectRepos(wsPath, ws, nil)
+			if err != nil {
+				return err
+			}
+		case len(names) == 0:
+			current, _ := detectCurrentRepo(wsPath, ws)
+			if current != "" {
+				names = []string{current}
+				break
+			}
+			names, err = pickRepos(ws, "Test which repos?", "not inside a workspace repo — name repos to test or pass --all")
+			if err != nil {
+				return err
+			}
+		}
+
+		wsEnv := buildWorkspaceEnv(wsPath, ws)
+		stop := stopOnFailure(false)
+		failures := &repoFailures{what: "tests", kind: errs.KindCommandFailed}
+		for _, name := range names {
+			if proc.Interrupted() {
+				return proc.Context().Err()
+			}
+			if _, ok := ws.Repos[name]; !ok {
+				return errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
+			}
+			if stop && len(failures.failed) > 0 {
+				failures.skip(name, "stopped at the first failure (--fail-fast)")
+				report.skip(name, "stopped at the first failure")
+				continue
+			}
+			start := time.Now()
+			err := testRepo(wsPath, ws, name, wsEnv)
+			if err != nil {
+				ui.Fail.Printf("%s: %v\n\n", name, err)
+				failures.add(name, err)
+			}
+			report.add(name, time.Since(start), "", err)
+		}
+		failures.summarize()
+		return failures.err()
+	},
+}
+
+// testRepo runs a repo's test_command if it has one, otherwise its test script
+func testRepo(wsPath string, ws *workspace.Workspace, name string, wsEnv map[string]string) error {
+	repo := ws.Repos[name]
+	if repo.TestCommand == "" {
+		return runRepoScript(wsPath, ws, name, "test", nil, wsEnv)
+	}
+
+	repoDir := filepath.Join(wsPath, repo.Path)
+	if _, err := os.Stat(repoDir); os.IsNotExist(err) {
+		return fmt.Errorf("repo directory %s does not exist", repoDir)
+	}
+	wsEnv = repo.ScopeEnv(wsEnv)
+	if detectProjectType(repoDir) == projectTypeNode {
+		if err := ensureNodeModules(wsPath, name, repoDir, wsEnv); err != nil {
+			return err
+		}
+	}
+	wsEnv, err := gradleEnv(wsPath, repo, repoDir, wsEnv)
+	if err != nil {
+		return err
+	}
+
+	command := repo.TestCommand
+	if args := repo.ArgsFor("test", nil); len(args) > 0 {
+		command += " " + shellquote.Join(args)
+	}
+	fmt.Printf("=== %s: %s ===\n", name, command)
+	defer metrics.Span(name, "test")()
+	return metrics.Time(wsPath, name, "test", func() error {
+		return runShellCmdWithEnv(repoDir, command, nil, wsEnv)
+	})
+}
+
+func init() {
+	testCmd.Flags().BoolVar(&testAll, "all", false, "Test every cloned repo")
+	testCmd.Flags().BoolVar(&testProfile, "profile", false, "Print a per-repo breakdown of time spent installing and testing")
+	addFailurePolicyFlags(testCmd, false)
+	addSummaryFileFlag(testCmd)
+	rootCmd.AddCommand(testCmd)
+}
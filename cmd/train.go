@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/gradle"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trainBump           string
+	trainWorkflow       string
+	trainPublishTimeout time.Duration
+	trainDraft          bool
+	trainYes            bool
+	trainRestart        bool
+	trainStatus         bool
+)
+
+// Train stages, in order. A train resumes from the stage it stopped at.
+const (
+	trainStageBuild   = "build"
+	trainStageRelease = "release"
+	trainStagePublish = "publish"
+	trainStageBump    = "bump"
+	trainStageDone    = "done"
+)
+
+// Where each consumer's bump stands
+const (
+	trainStatusPending = "pending"
+	trainStatusOpened  = "opened"
+	trainStatusFailed  = "failed"
+)
+
+// trainPollInterval is how often the publish stage asks the registry for the new version
+const trainPollInterval = 20 * time.Second
+
+var trainCmd = &cobra.Command{
+	Use:   "train <model>",
+	Short: "Release a model and open version-bump PRs in its consumers",
+	Long: `Runs a release train for a model repo, one stage after another:
+
+  1. build    builds the model (and its codegen)
+  2. release  cuts a release like 'spark-cli release --publish': bumps the
+              version, tags, pushes, creates the GitHub release, and triggers
+              the publish workflow
+  3. publish  waits until the registry has the new SDK version of every npm
+              package the consumers use (--publish-timeout)
+  4. bump     opens a PR in each consumer installing the new version, like
+              'spark-cli bump --no-test
+
+Consumers are the repos whose spk.config.json "consumes" the model with an npm
+package. Progress is kept in .spk/state.json: if a stage fails or the publish
+is slow, fix what's wrong and run the same command to resume where it stopped.
+A release that was tagged but not finished resumes by pushing the tag, creating
+the GitHub release, and triggering the workflow. Without a terminal, pass --yes.
+--status shows the trains in progress; --restart starts a model's over.
+
+Examples:
+  spark-cli train AppModel
+  spark-cli train AppModel --bump minor --draft
+  spark-cli train AppModel               # resume after a failure
+  spark-cli train --status`,
+	Args: cobra.RangeArgs(0, 1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		if trainStatus {
+			return printTrains(wsPath, args)
+		}
+		if len(args) == 0 {
+			return errs.New(errs.KindUsage, "name the model to release, e.g. 'spark-cli train AppModel' (or --status)")
+		}
+		model := args[0]
+		if _, ok := ws.Repos[model]; !ok {
+			return errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", model)
+		}
+		return runTrain(wsPath, ws, model)
+	},
+}
+
+// runTrain starts model's train, or resumes it from the stage it stopped at
+func runTrain(wsPath string, ws *workspace.Workspace, model string) error {
+	st, err := state.Load(wsPath)
+	if err != nil {
+		return err
+	}
+	t := st.Trains[model]
+	if t == nil || trainRestart || t.Stage == trainStageDone {
+		t = &state.Train{Model: model, Stage: trainStageBuild, StartedAt: time.Now().UTC().Format(time.RFC3339)}
+		for _, c := range npmConsumers(wsPath, ws, model) {
+			t.Consumers = append(t.Consumers, state.TrainConsumer{Repo: c.consumer, Package: c.entry.Package, Status: trainStatusPending})
+		}
+		if len(t.Consumers) == 0 {
+			return errs.New(errs.KindUsage, "no repo consumes an npm package of %s — nothing to bump", model)
+		}
+	} else {
+		fmt.Printf("Resuming %s train at %s\n", model, t.Stage)
+	}
+
+	for t.Stage != trainStageDone {
+		if proc.Interrupted() {
+			return errs.New(errs.KindInterrupted, "train interrupted at %s — run 'spark-cli train %s' to resume", t.Stage, model)
+		}
+		fmt.Printf("\n── %s: %s ──\n", model, t.Stage)
+		next, err := runTrainStage(wsPath, ws, t)
+		if err != nil {
+			if saveErr := saveTrain(wsPath, t); saveErr != nil {
+				return saveErr
+			}
+			return err
+		}
+		if next == "" {
+			// Aborted at a prompt: nothing was changed, so there is nothing to resume
+			return nil
+		}
+		t.Stage = next
+		if err := saveTrain(wsPath, t); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	printTrain(t)
+	return nil
+}
+
+// runTrainStage runs t's current stage and returns the stage to go on to, or "" if the
+// user declined to release
+func runTrainStage(wsPath string, ws *workspace.Workspace, t *state.Train) (string, error) {
+	switch t.Stage {
+	case trainStageBuild:
+		if err := buildRepo(wsPath, ws, t.Model, buildSyncEnv(wsPath, ws)); err != nil {
+			return "", err
+		}
+		return trainStageRelease, nil
+
+	case trainStageRelease:
+		if t.Tag != "" {
+			repo := ws.Repos[t.Model]
+			repoDir := filepath.Join(wsPath, repo.Path)
+			if git.TagCommit(repoDir, t.Tag) != "" {
+				// Tagged on an earlier run: push it and finish the release
+				fmt.Printf("%s was tagged — finishing the release\n", t.Tag)
+				target, err := checkReleaseTarget(repoDir, true)
+				if err != nil {
+					return "", err
+				}
+				branch := getTargetBranch(ws, &repo, repoDir)
+				if err := publishRelease(repoDir, branch, t.Tag, target, true, trainWorkflow); err != nil {
+					return "", err
+				}
+				return trainStagePublish, nil
+			}
+			t.Version, t.Tag = "", ""
+		}
+
+		p, err := planRelease(wsPath, ws, t.Model, trainBump)
+		if err != nil {
+			return "", err
+		}
+		if p.commits == 0 {
+			return "", errs.New(errs.KindUsage, "nothing to release — no commits on %s since %s", t.Model, p.lastTag)
+		}
+		fmt.Printf("%s: %s → %s (%s, %s)\n", t.Model, p.current.Tag(), p.next.Tag(), p.kind, p.how)
+		fmt.Printf("Then bumps: %s\n", trainConsumerList(t))
+		target, err := checkReleaseTarget(p.repoDir, true)
+		if err != nil {
+			return "", err
+		}
+		if ok, err := confirmRelease(trainYes, fmt.Sprintf("Release %s %s and bump its consumers?", t.Model, p.next.Tag())); !ok {
+			return "", err
+		}
+		t.Version, t.Tag = p.next.String(), p.next.Tag()
+		if err := saveTrain(wsPath, t); err != nil {
+			return "", err
+		}
+		if err := cutRelease(p, target, true, trainWorkflow); err != nil {
+			return "", err
+		}
+		return trainStagePublish, nil
+
+	case trainStagePublish:
+		return trainStageBump, waitForPublish(wsPath, ws, t)
+
+	case trainStageBump:
+		token := resolveGitHubToken()
+		var failed int
+		for i := range t.Consumers {
+			c := &t.Consumers[i]
+			if c.Status == trainStatusOpened {
+				continue
+			}
+			url, err := openBumpPR(wsPath, ws, c.Repo, c.Package, t.Version, bumpOptions{token: token, draft: trainDraft, via: "spark-cli train"})
+			if err != nil {
+				c.Status, c.Error = trainStatusFailed, err.Error()
+				ui.Fail.Printf("%-25s %v\n", c.Repo, err)
+				failed++
+				continue
+			}
+			c.Status, c.PR, c.Error = trainStatusOpened, url, ""
+			ui.OK.Printf("%-25s %s\n", c.Repo, orDefault(url, "pushed"))
+		}
+		if failed > 0 {
+			return "", errs.New(errs.KindCommandFailed, "%d consumer bump(s) failed — fix them and run 'spark-cli train %s' to retry", failed, t.Model)
+		}
+		return trainStageDone, nil
+	}
+	return "", fmt.Errorf("unknown train stage %q", t.Stage)
+}
+
+// waitForPublish polls the registry until every package the train bumps has t.Version
+func waitForPublish(wsPath string, ws *workspace.Workspace, t *state.Train) error {
+	env := []string{"GITHUB_TOKEN=" + resolveGitHubToken()}
+	pending := make(map[string]string) // package → a consumer dir whose .npmrc routes it
+	for _, c := range t.Consumers {
+		pending[c.Package] = filepath.Join(wsPath, ws.Repos[c.Repo].Path)
+	}
+
+	deadline := time.Now().Add(trainPublishTimeout)
+	for {
+		for pkg, dir := range pending {
+			if npm.HasVersion(dir, pkg, t.Version, env) {
+				ui.OK.Printf("%s@%s published\n", pkg, t.Version)
+				delete(pending, pkg)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) || proc.Interrupted() {
+			return errs.New(errs.KindTimeout, "%s not published at %s yet — check the publish workflow, then run 'spark-cli train %s' to resume",
+				strings.Join(sortedKeys(pending), ", "), t.Version, t.Model)
+		}
+		fmt.Printf("  waiting for %s@%s...\n", strings.Join(sortedKeys(pending), ", "), t.Version)
+		select {
+		case <-proc.Context().Done():
+		case <-time.After(trainPollInterval):
+		}
+	}
+}
+
+// npmConsumers returns the repos that consume an npm package of model
+func npmConsumers(wsPath string, ws *workspace.Workspace, model string) []modelConsumer {
+	names, _ := expandRepoNames(ws, nil)
+	var out []modelConsumer
+	for _, name := range names {
+		for _, c := range consumedModels(wsPath, ws, name) {
+			if c.Model == model && !gradle.IsModule(c.Package) {
+				out = append(out, modelConsumer{consumer: name, entry: c})
+			}
+		}
+	}
+	return out
+}
+
+// saveTrain records t in the workspace state
+func saveTrain(wsPath string, t *state.Train) error {
+	return state.Update(wsPath, func(s *state.State) error {
+		if s.Trains == nil {
+			s.Trains = make(map[string]*state.Train)
+		}
+		s.Trains[t.Model] = t
+		return nil
+	})
+}
+
+// printTrains prints the recorded trains, or only those for models
+func printTrains(wsPath string, models []string) error {
+	st, err := state.Load(wsPath)
+	if err != nil {
+		return err
+	}
+	if len(models) == 0 {
+		for model := range st.Trains {
+			models = append(models, model)
+		}
+		sort.Strings(models)
+	}
+	found := false
+	for _, model := range models {
+		if t := st.Trains[model]; t != nil {
+			if found {
+				fmt.Println()
+			}
+			printTrain(t)
+			found = true
+		}
+	}
+	if !found {
+		fmt.Println(binname.Rewrite("No release trains — start one with 'spark-cli train <model>'"))
+	}
+	return nil
+}
+
+// printTrain prints a train's stage and each consumer's bump
+func printTrain(t *state.Train) {
+	started := ""
+	if at, err := time.Parse(time.RFC3339, t.StartedAt); err == nil {
+		started = ", started " + formatAge(time.Since(at))
+	}
+	fmt.Printf("%s %s  (%s%s)\n", t.Model, orDefault(t.Version, "(not released yet)"), t.Stage, started)
+	for _, c := range t.Consumers {
+		mark := ui.Skip
+		switch c.Status {
+		case trainStatusOpened:
+			mark = ui.OK
+		case trainStatusFailed:
+			mark = ui.Fail
+		}
+		detail := c.Status
+		switch {
+		case c.PR != "":
+			detail = c.PR
+		case c.Error != "":
+			detail = c.Error
+		}
+		fmt.Printf("  %s %-25s %-35s %s\n", mark, c.Repo, c.Package, detail)
+	}
+}
+
+// trainConsumerList names the consumers a train bumps, e.g. "AppAPI (@spark-rewards/sra-sdk)"
+func trainConsumerList(t *state.Train) string {
+	parts := make([]string, len(t.Consumers))
+	for i, c := range t.Consumers {
+		parts[i] = fmt.Sprintf("%s (%s)", c.Repo, c.Package)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	trainCmd.Flags().StringVar(&trainBump, "bump", "", "Version bump: major, minor, or patch (default: inferred from commits)")
+	trainCmd.Flags().StringVar(&trainWorkflow, "workflow", "publish.yml", "Workflow file that publishes the SDK")
+	trainCmd.Flags().DurationVar(&trainPublishTimeout, "publish-timeout", 15*time.Minute, "How long to wait for the new version to reach the registry")
+	trainCmd.Flags().BoolVar(&trainDraft, "draft", false, "Open the bump PRs as drafts")
+	trainCmd.Flags().BoolVarP(&trainYes, "yes", "y", false, "Don't ask for confirmation")
+	trainCmd.Flags().BoolVar(&trainRestart, "restart", false, "Start the model's train over instead of resuming it")
+	trainCmd.Flags().BoolVar(&trainStatus, "status", false, "Show the trains in progress")
+	rootCmd.AddCommand(trainCmd)
+}
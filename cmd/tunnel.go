@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/tunnel"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// tunnelStartTimeout bounds how long we wait for the tool to print its public URL
+const tunnelStartTimeout = 30 * time.Second
+
+var (
+	tunnelProvider string
+	tunnelVar      string
+)
+
+var tunnelCmd = &cobra.Command{
+	Use:   "tunnel <port|service>",
+	Short: "Expose a local service publicly and point the workspace env at it",
+	Long: `Starts a cloudflared (or ngrok) tunnel to a local port and writes the public
+URL into the workspace .env — regenerating the repos' env files — so consumers
+such as the mobile app on a real device hit your local backend. Ctrl-C closes
+the tunnel and restores the previous value.
+
+The argument is a port, or a service from the "services" registry whose
+"local" URL gives the port (its "env" variable is updated):
+
+  "services": {
+    "AppAPI": { "env": "APP_API_URL", "urls": { "local": "http://localhost:4000", ... } }
+  }
+
+With a bare port, --var names the variable to update (default APP_API_URL).
+Restart Metro / the dev server after the tunnel is up to pick up the new URL.
+
+Examples:
+  spark-cli tunnel AppAPI
+  spark-cli tunnel 4000 --var APP_API_URL
+  spark-cli tunnel 4000 --provider ngrok`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		provider, err := tunnel.Detect(tunnelProvider)
+		if err != nil {
+			return errs.New(errs.KindUsage, "%w", err)
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		port, key, err := tunnelTarget(ws, args[0])
+		if err != nil {
+			return err
+		}
+
+		logPath, out, err := startTunnel(wsPath, provider, port)
+		if err != nil {
+			return err
+		}
+
+		var public string
+		select {
+		case public = <-out.url:
+		case err := <-out.done:
+			return errs.New(errs.KindCommandFailed, "%s exited before the tunnel was up (%v) — see %s", provider, err, logPath)
+		case <-time.After(tunnelStartTimeout):
+			return errs.New(errs.KindTimeout, "%s didn't report a public URL within %s — see %s", provider, tunnelStartTimeout, logPath)
+		case <-proc.Context().Done():
+			return nil
+		}
+
+		before, _ := workspace.ReadGlobalEnv(wsPath)
+		previous, hadPrevious := before[key]
+		if err := workspace.WriteGlobalEnv(wsPath, map[string]string{key: public}); err != nil {
+			return err
+		}
+		if err := regenerateRepoEnvFiles(wsPath, ws); err != nil {
+			return err
+		}
+		ui.OK.Printf("localhost:%d is public at %s (%s)\n", port, public, provider)
+		ui.OK.Printf("%s=%s written to the workspace env\n", key, public)
+		fmt.Println("  Restart Metro / dev servers to pick it up. Ctrl-C to close the tunnel.")
+
+		<-out.done
+		stopped := proc.Interrupted()
+
+		// Restoring regenerates env files, which runs git — not under the cancelled context
+		proc.SetContext(context.Background())
+		if hadPrevious {
+			err = workspace.WriteGlobalEnv(wsPath, map[string]string{key: previous})
+		} else {
+			err = workspace.UnsetGlobalEnv(wsPath, map[string]string{key: public})
+		}
+		if err == nil {
+			err = regenerateRepoEnvFiles(wsPath, ws)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore %s: %w", key, err)
+		}
+		fmt.Println()
+		ui.OK.Printf("Tunnel closed, %s restored\n", key)
+
+		if !stopped {
+			return errs.New(errs.KindCommandFailed, "%s exited unexpectedly — see %s", provider, logPath)
+		}
+		return nil
+	},
+}
+
+// tunnelOutput reports the tunnel's public URL and when the tool exits
+type tunnelOutput struct {
+	url  chan string
+	done chan error
+}
+
+// tunnelTarget resolves the argument to a local port and the env var to update
+func tunnelTarget(ws *workspace.Workspace, arg string) (int, string, error) {
+	if port, err := strconv.Atoi(arg); err == nil {
+		return port, orDefault(tunnelVar, "APP_API_URL"), nil
+	}
+	name, ok := findService(ws, arg)
+	if !ok {
+		return 0, "", errs.New(errs.KindUsage, "%q is neither a port nor a registered service (see 'spark-cli url')", arg)
+	}
+	local, ok := ws.Services[name].URLs["local"]
+	if !ok {
+		return 0, "", errs.New(errs.KindUsage, "service %s has no \"local\" URL — pass the port instead", name)
+	}
+	u, err := url.Parse(local)
+	if err != nil || u.Port() == "" {
+		return 0, "", errs.New(errs.KindUsage, "can't find a port in %s's local URL %q — pass the port instead", name, local)
+	}
+	port, _ := strconv.Atoi(u.Port())
+	return port, orDefault(tunnelVar, ws.ServiceVar(name)), nil
+}
+
+// startTunnel runs the provider in the background, logging to .spk/logs, and watches its
+// output for the public URL
+func startTunnel(wsPath string, provider tunnel.Provider, port int) (string, *tunnelOutput, error) {
+	logDir := filepath.Join(workspace.SparkDir(wsPath), "logs")
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return "", nil, err
+	}
+	logPath := filepath.Join(logDir, "tunnel.log")
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	pr, pw := io.Pipe()
+	name, args := tunnel.Args(provider, port)
+	cmd := proc.Command(name, args...)
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return "", nil, fmt.Errorf("failed to start %s: %w", provider, err)
+	}
+	fmt.Printf("Starting %s tunnel to localhost:%d (logs: %s)...\n", provider, port, logPath)
+
+	out := &tunnelOutput{url: make(chan string, 1), done: make(chan error, 1)}
+	go func() {
+		found := false
+		scanner := bufio.NewScanner(pr)
+		for scanner.Scan() {
+			line := scanner.Text()
+			fmt.Fprintln(logFile, line)
+			if u := tunnel.PublicURL(provider, line); u != "" && !found {
+				found = true
+				out.url <- u
+			}
+		}
+		io.Copy(logFile, pr)
+		logFile.Close()
+	}()
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		out.done <- err
+	}()
+	return logPath, out, nil
+}
+
+func init() {
+	tunnelCmd.Flags().StringVar(&tunnelProvider, "provider", "", "Tunnel tool: cloudflared or ngrok (default: whichever is installed)")
+	tunnelCmd.Flags().StringVar(&tunnelVar, "var", "", "Env var to point at the tunnel (default: the service's variable, or APP_API_URL)")
+	rootCmd.AddCommand(tunnelCmd)
+}
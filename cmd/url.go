@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	urlEnv  string
+	urlOpen bool
+)
+
+var urlCmd = &cobra.Command{
+	Use:   "url [service]",
+	Short: "Print or open a service URL for an environment",
+	Long: `Looks up a service in the workspace's endpoint registry. With no service,
+lists every service and its URL for the environment.
+
+  "services": {
+    "AppAPI":          { "env": "APP_API_URL", "urls": { "beta": "https://api.beta.example.com", "prod": "https://api.example.com" } },
+    "BusinessWebsite": { "urls": { "beta": "https://business.beta.example.com" } }
+  }
+
+When the workspace env is written (sync, switch-env), each service's URL for
+the active environment is exported as its "env" variable (default:
+<NAME>_URL, e.g. BUSINESSWEBSITE_URL), so generated .env files pick it up.
+
+Examples:
+  spark-cli url
+  spark-cli url AppAPI
+  spark-cli url AppAPI --env prod --open`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		env := activeEnv(ws, urlEnv)
+
+		if len(args) == 0 {
+			if len(ws.Services) == 0 {
+				fmt.Println(binname.Rewrite("No services registered — add a \"services\" map to workspace.json (see 'spark-cli url --help')"))
+				return nil
+			}
+			fmt.Printf("Services (%s):\n", env)
+			for _, name := range sortedServiceNames(ws) {
+				u, ok := ws.Services[name].URLs[env]
+				if !ok {
+					u = "(none)"
+				}
+				fmt.Printf("  %-25s %-20s %s\n", name, ws.ServiceVar(name), u)
+			}
+			return nil
+		}
+
+		name, ok := findService(ws, args[0])
+		if !ok {
+			return errs.New(errs.KindUsage, "unknown service %q — registered: %s", args[0], strings.Join(sortedServiceNames(ws), ", "))
+		}
+		u, ok := ws.Services[name].URLs[env]
+		if !ok {
+			return errs.New(errs.KindUsage, "%s has no URL for %s — defined for: %s", name, env, strings.Join(sortedKeys(ws.Services[name].URLs), ", "))
+		}
+
+		fmt.Println(u)
+		if urlOpen {
+			return openURL(u)
+		}
+		return nil
+	},
+}
+
+// findService matches a service name case-insensitively
+func findService(ws *workspace.Workspace, name string) (string, bool) {
+	if _, ok := ws.Services[name]; ok {
+		return name, true
+	}
+	for s := range ws.Services {
+		if strings.EqualFold(s, name) {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+func sortedServiceNames(ws *workspace.Workspace) []string {
+	var names []string
+	for name := range ws.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// openURL opens u in the default browser
+func openURL(u string) error {
+	opener := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		opener = "open"
+	}
+	if err := exec.Command(opener, u).Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", u, err)
+	}
+	return nil
+}
+
+func init() {
+	urlCmd.Flags().StringVar(&urlEnv, "env", "", "Environment (default: the workspace's active env)")
+	urlCmd.Flags().BoolVar(&urlOpen, "open", false, "Open the URL in the browser")
+	rootCmd.AddCommand(urlCmd)
+}
@@ -4,9 +4,20 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/progress"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/retry"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/settings"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -14,61 +25,266 @@ import (
 var (
 	useBuildCmd string
 	useDeps     []string
+	useBrowse   bool
+	useProtocol string
+	usePath     string
+	useFork     bool
 )
 
-const defaultGitHubOrg = "Spark-Rewards"
-
 var useCmd = &cobra.Command{
-	Use:   "use <repo>",
-	Short: "Clone a repo into workspace (--build, --deps | -h)",
+	Use:   "use [repo]",
+	Short: "Clone a repo into workspace (--build, --deps, --browse | -h)",
 	Long: `Clones a GitHub repository into the current workspace and registers it
 in the workspace manifest.
 
 If only a repo name is provided, it defaults to the Spark-Rewards org.
 
+Repos are cloned to <workspace>/<repo> unless workspace.json sets a "layout":
+"org" for <org>/<repo>, or a template such as "services/{repo}" or
+"{org}/{repo}". --path puts a single repo anywhere inside the workspace.
+
+Repos are cloned over ssh when a key for github.com is set up, otherwise over https
+using your GitHub token. The choice is saved as clone_protocol in ~/.spk/config.json;
+override with --protocol ssh|https.
+
+--fork is for repos you can't push to: it forks the repo into your GitHub account
+(reusing your fork if you have one), clones the fork as origin, and records the
+original as the repo's "upstream". Sync then rebases onto the upstream's branches
+while 'spark-cli push' pushes to your fork and links PRs against the upstream.
+Passing --fork for a repo that's already cloned switches its origin to the fork.
+
 Examples:
   spark-cli use BusinessAPI                              # clones Spark-Rewards/BusinessAPI
   spark-cli use other-org/SomeRepo                       # clones other-org/SomeRepo
-  spark-cli use git@github.com:other-org/Repo.git        # full URL`,
-	Args: cobra.ExactArgs(1),
+  spark-cli use git@github.com:other-org/Repo.git        # full URL
+  spark-cli use PaymentsAPI --path backend/PaymentsAPI   # custom location
+  spark-cli use other-org/SomeRepo --fork                # clone your fork, sync from other-org
+  spark-cli use                                          # pick from the org's repos
+  spark-cli use --browse                                 # same
+  spark-cli use --browse api                             # ...matching "api"`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		repoArg := args[0]
-
 		// Find workspace
 		wsPath, err := workspace.Find()
 		if err != nil {
-			return fmt.Errorf("you must be inside a spark-cli workspace — run 'spark-cli create workspace <path>' first")
+			return errs.New(errs.KindWorkspaceNotFound, "you must be inside a spark-cli workspace — run 'spark-cli workspace create <path>' first")
 		}
 
-		// Resolve the remote URL
-		remote := resolveRemote(repoArg)
-		repoName := git.RepoNameFromRemote(repoArg)
-		targetDir := filepath.Join(wsPath, repoName)
+		if useProtocol != "" && useProtocol != git.ProtocolSSH && useProtocol != git.ProtocolHTTPS {
+			return fmt.Errorf("invalid --protocol %q — use ssh or https", useProtocol)
+		}
 
-		// Check if already cloned
-		if _, err := os.Stat(targetDir); err == nil {
-			if git.IsRepo(targetDir) {
-				fmt.Printf("Repository '%s' already exists at %s\n", repoName, targetDir)
-				// Still register it in manifest if not present
-				return registerRepo(wsPath, repoName, remote, targetDir)
+		if len(args) == 0 && !useBrowse {
+			if prompt.NonInteractive {
+				return errs.New(errs.KindUsage, "name a repo to clone (or pass --browse)")
+			}
+			useBrowse = true
+		}
+		if useBrowse {
+			if usePath != "" {
+				return errs.New(errs.KindUsage, "--path applies to a single repo, not --browse")
 			}
-			return fmt.Errorf("directory %s exists but is not a git repository", targetDir)
+			term := ""
+			if len(args) == 1 {
+				term = args[0]
+			}
+			return browseAndClone(wsPath, term)
 		}
+		repoArg := args[0]
+
+		_, err = cloneRepo(wsPath, repoArg)
+		return err
+	},
+}
 
-		// Clone
-		fmt.Printf("Cloning %s into %s...\n", remote, targetDir)
-		if err := git.Clone(remote, targetDir); err != nil {
-			return fmt.Errorf("git clone failed: %w", err)
+// cloneRepo clones repoArg (name, org/repo, or URL) into the workspace and registers it.
+// Returns the repo name. Already-cloned repos are registered without re-cloning.
+func cloneRepo(wsPath, repoArg string) (string, error) {
+	remote := resolveRemote(repoArg)
+	repoName := git.RepoNameFromRemote(repoArg)
+	upstream := ""
+	if useFork {
+		fork, err := forkRemote(remote)
+		if err != nil {
+			return "", err
 		}
+		remote, upstream = fork, remote
+	}
+	targetDir, err := cloneTarget(wsPath, orDefault(upstream, remote), repoName, usePath)
+	if err != nil {
+		return "", err
+	}
 
-		// Register in workspace manifest
-		if err := registerRepo(wsPath, repoName, remote, targetDir); err != nil {
-			return err
+	// Check if already cloned
+	if _, err := os.Stat(targetDir); err == nil {
+		if git.IsRepo(targetDir) {
+			fmt.Printf("Repository '%s' already exists at %s\n", repoName, targetDir)
+			if upstream != "" && git.RemoteURL(targetDir, "origin") != remote {
+				if err := git.SetRemote(targetDir, "origin", remote); err != nil {
+					return "", fmt.Errorf("failed to point origin at the fork: %w", err)
+				}
+				ui.OK.Printf("origin is now your fork, %s\n", remote)
+			}
+			// Still register it in manifest if not present
+			if err := registerRepo(wsPath, repoName, remote, upstream, targetDir); err != nil {
+				return "", err
+			}
+			if len(useDeps) == 0 {
+				offerDetectedDeps(wsPath, repoName)
+			}
+			return repoName, nil
 		}
+		return "", fmt.Errorf("directory %s exists but is not a git repository", targetDir)
+	}
 
-		fmt.Printf("Repository '%s' added to workspace\n", repoName)
-		return nil
-	},
+	// Clone
+	if err := os.MkdirAll(filepath.Dir(targetDir), 0755); err != nil {
+		return "", err
+	}
+	fmt.Printf("Cloning %s into %s...\n", remote, targetDir)
+	start := time.Now()
+	clone := func() error {
+		if strings.HasPrefix(remote, "https://") {
+			return git.CloneWithToken(remote, targetDir, resolveGitHubToken())
+		}
+		return git.Clone(remote, targetDir)
+	}
+	if upstream != "" {
+		// A new fork can take a few seconds before it clones
+		err = retry.Do(clone)
+	} else {
+		err = clone()
+	}
+	if err != nil {
+		return "", fmt.Errorf("git clone failed: %w", err)
+	}
+	if strings.HasPrefix(remote, "https://") {
+		if err := git.UseGHCredentialHelper(targetDir); err != nil {
+			fmt.Println("Note: install gh and run 'gh auth login' so later fetches can authenticate over https")
+		}
+	}
+
+	initRepoExtras(targetDir)
+
+	// Register in workspace manifest
+	if err := registerRepo(wsPath, repoName, remote, upstream, targetDir); err != nil {
+		return "", err
+	}
+
+	fmt.Printf("Repository '%s' added to workspace (%s)\n", repoName, progress.Elapsed(time.Since(start)))
+	if len(useDeps) == 0 {
+		offerDetectedDeps(wsPath, repoName)
+	}
+	return repoName, nil
+}
+
+// detectedEdge is a dependency found between two workspace repos
+type detectedEdge struct {
+	consumer string
+	dep      workspace.DetectedDependency
+}
+
+// offerDetectedDeps finds the workspace packages a new repo uses (and the repos that use
+// its packages) and offers to record them as dependencies
+func offerDetectedDeps(wsPath, name string) {
+	ws, err := workspace.Load(wsPath)
+	if err != nil {
+		return
+	}
+	var edges []detectedEdge
+	for _, d := range workspace.DetectDependencies(wsPath, ws, name) {
+		edges = append(edges, detectedEdge{name, d})
+	}
+	others := make([]string, 0, len(ws.Repos))
+	for other := range ws.Repos {
+		if other != name {
+			others = append(others, other)
+		}
+	}
+	sort.Strings(others)
+	for _, other := range others {
+		for _, d := range workspace.DetectDependencies(wsPath, ws, other) {
+			if d.Repo == name {
+				edges = append(edges, detectedEdge{other, d})
+			}
+		}
+	}
+	if len(edges) == 0 {
+		return
+	}
+
+	fmt.Println("\nDetected dependencies between workspace repos:")
+	for _, e := range edges {
+		fmt.Printf("  %s → %s (%s in %s)\n", e.consumer, e.dep.Repo, e.dep.Package, e.dep.File)
+	}
+	if prompt.NonInteractive {
+		fmt.Println(binname.Rewrite("Record them with 'spark-cli deps add <repo> <dependency>'"))
+		return
+	}
+	if !prompt.Confirm("Record them in workspace.json?", true) {
+		return
+	}
+
+	recorded := 0
+	for _, e := range edges {
+		repo := ws.Repos[e.consumer]
+		before := repo.Dependencies
+		repo.Dependencies = append(append([]string(nil), before...), e.dep.Repo)
+		ws.Repos[e.consumer] = repo
+		if err := workspace.Validate(wsPath, ws); err != nil {
+			repo.Dependencies = before
+			ws.Repos[e.consumer] = repo
+			ui.Warn.Printf("Skipped %s → %s: %v\n", e.consumer, e.dep.Repo, err)
+			continue
+		}
+		recorded++
+	}
+	if recorded == 0 {
+		return
+	}
+	if err := workspace.Save(wsPath, ws); err != nil {
+		fmt.Printf("Warning: failed to save dependencies: %v\n", err)
+		return
+	}
+	ui.OK.Printf("Recorded %d dependency link(s) in workspace.json\n", recorded)
+}
+
+// cloneTarget returns where a repo goes: rel (relative to the workspace) if set, else
+// the workspace layout
+func cloneTarget(wsPath, remote, repoName, rel string) (string, error) {
+	if rel == "" {
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return "", err
+		}
+		org, _, _ := strings.Cut(git.GitHubSlug(remote), "/")
+		rel = ws.RepoPath(org, repoName)
+	}
+	targetDir := filepath.Join(wsPath, rel)
+	if !isSubdir(wsPath, targetDir) {
+		return "", errs.New(errs.KindUsage, "repo path %q must be inside the workspace", rel)
+	}
+	return targetDir, nil
+}
+
+// initRepoExtras finishes a fresh clone: submodules and Git LFS objects
+func initRepoExtras(repoDir string) {
+	if git.HasSubmodules(repoDir) {
+		fmt.Println("Initializing submodules...")
+		if err := git.SubmoduleUpdate(repoDir); err != nil {
+			fmt.Printf("Warning: submodule update failed: %v\n", err)
+		}
+	}
+	if git.UsesLFS(repoDir) {
+		if !git.LFSInstalled() {
+			fmt.Println("Warning: repo uses Git LFS but git-lfs is not installed — brew install git-lfs && git lfs install")
+			return
+		}
+		fmt.Println("Pulling Git LFS objects...")
+		if err := git.LFSPull(repoDir); err != nil {
+			fmt.Printf("Warning: git lfs pull failed: %v\n", err)
+		}
+	}
 }
 
 func resolveRemote(arg string) string {
@@ -77,17 +293,73 @@ func resolveRemote(arg string) string {
 		return arg
 	}
 
+	protocol := cloneProtocol()
+
 	// If no slash, prepend Spark-Rewards org (or config override)
 	if !containsSlash(arg) {
-		org := defaultGitHubOrg
-		cfg, err := config.LoadGlobal()
-		if err == nil && cfg.DefaultGithubOrg != "" {
-			org = cfg.DefaultGithubOrg
+		return git.BuildRemoteURLWithProtocol(githubOrg()+"/"+arg, protocol)
+	}
+
+	return git.BuildRemoteURLWithProtocol(arg, protocol)
+}
+
+// cloneProtocol returns ssh or https: --protocol wins, then clone_protocol from the global
+// config; otherwise ssh access to github.com is probed once and the result is saved.
+func cloneProtocol() string {
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		cfg = &config.GlobalConfig{}
+	}
+
+	protocol := useProtocol
+	if protocol == "" {
+		protocol = cfg.CloneProtocol
+	}
+	if protocol == "" {
+		fmt.Println("Checking ssh access to github.com...")
+		protocol = git.ProtocolSSH
+		if !git.CanSSHToGitHub() {
+			protocol = git.ProtocolHTTPS
+			fmt.Println("No ssh key for github.com — cloning over https with your GitHub token")
 		}
-		return git.BuildRemoteURL(org + "/" + arg)
 	}
 
-	return git.BuildRemoteURL(arg)
+	if protocol != cfg.CloneProtocol {
+		cfg.CloneProtocol = protocol
+		if err := config.SaveGlobal(cfg); err != nil {
+			fmt.Printf("Warning: failed to save clone protocol: %v\n", err)
+		}
+	}
+	return protocol
+}
+
+// forkRemote forks the GitHub repo at remote into your account and returns the fork's
+// remote URL, over the same protocol
+func forkRemote(remote string) (string, error) {
+	slug := git.GitHubSlug(remote)
+	if slug == "" {
+		return "", errs.New(errs.KindUsage, "--fork needs a GitHub repo, not %s", remote)
+	}
+	token := resolveGitHubToken()
+	if token == "" {
+		return "", fmt.Errorf("no GitHub token to fork with — set GITHUB_TOKEN or run 'gh auth login'")
+	}
+	spin := progress.Start("Forking " + slug)
+	fork, err := github.CreateFork(token, slug)
+	spin.Stop(err)
+	if err != nil {
+		return "", fmt.Errorf("failed to fork %s: %w", slug, err)
+	}
+	protocol := git.ProtocolSSH
+	if strings.HasPrefix(remote, "https://") {
+		protocol = git.ProtocolHTTPS
+	}
+	return git.BuildRemoteURLWithProtocol(fork.FullName, protocol), nil
+}
+
+// githubOrg returns the resolved github_org setting (Spark-Rewards unless overridden)
+func githubOrg() string {
+	return currentSettings().String(settings.GitHubOrg)
 }
 
 func containsSlash(s string) bool {
@@ -99,17 +371,35 @@ func containsSlash(s string) bool {
 	return false
 }
 
-func registerRepo(wsPath, name, remote, targetDir string) error {
-	relPath, _ := filepath.Rel(wsPath, targetDir)
-	repo := workspace.RepoDef{
-		Remote:       remote,
-		Path:         relPath,
-		BuildCommand: useBuildCmd,
-		Dependencies: useDeps,
+func registerRepo(wsPath, name, remote, upstream, targetDir string) error {
+	ws, err := workspace.Load(wsPath)
+	if err != nil {
+		return err
+	}
+	// An existing entry keeps its settings (tags, script_args, env lists, ...); only
+	// the fork's remotes and the flags given change
+	repo, exists := ws.Repos[name]
+	if !exists {
+		relPath, _ := filepath.Rel(wsPath, targetDir)
+		repo = workspace.RepoDef{Remote: remote, Path: relPath}
+	}
+	if upstream != "" {
+		repo.Remote, repo.Upstream = remote, upstream
+	}
+	if useBuildCmd != "" {
+		repo.BuildCommand = useBuildCmd
+	}
+	if len(useDeps) > 0 {
+		repo.Dependencies = useDeps
 	}
 	if err := workspace.AddRepo(wsPath, name, repo); err != nil {
 		return err
 	}
+	if upstream != "" {
+		if err := fetchUpstream(targetDir, repo); err != nil {
+			fmt.Printf("Warning: failed to fetch upstream: %v\n", err)
+		}
+	}
 
 	if err := workspace.GenerateVSCodeWorkspace(wsPath); err != nil {
 		fmt.Printf("Warning: failed to update VS Code workspace: %v\n", err)
@@ -120,5 +410,9 @@ func registerRepo(wsPath, name, remote, targetDir string) error {
 func init() {
 	useCmd.Flags().StringVar(&useBuildCmd, "build", "", "Build command for this repo (e.g., 'npm run build')")
 	useCmd.Flags().StringSliceVar(&useDeps, "deps", nil, "Dependencies (other repo names that must build first)")
+	useCmd.Flags().StringVar(&useProtocol, "protocol", "", "Clone over ssh or https (saved as the default in ~/.spk/config.json)")
+	useCmd.Flags().StringVar(&usePath, "path", "", "Clone into this directory (relative to the workspace) instead of the layout's")
+	useCmd.Flags().BoolVar(&useBrowse, "browse", false, "List org repositories and pick which to clone (optional search term)")
+	useCmd.Flags().BoolVar(&useFork, "fork", false, "Clone your fork of the repo (forking it on GitHub if needed) and sync from the original")
 	rootCmd.AddCommand(useCmd)
 }
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/gradle"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/progress"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyRepos     []string
+	verifyKeepLinks bool
+	verifyTypecheck bool
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [model...]",
+	Short: "Check that local model changes don't break their consumers",
+	Long: `Builds the models in the workspace, links each into every repo that consumes
+it, runs each consumer's tests against the local build, and reports which
+consumers break — before the model's PR merges.
+
+With no models, every model some repo consumes is verified. --repos limits the
+consumers checked. Consumers that weren't linked before are put back on their
+installed (published) packages afterwards, unless --keep-links; ones already
+linked stay linked, to the fresh build.
+
+--typecheck only type-checks each consumer instead of running its tests: its
+"typecheck" script when package.json has one, else 'tsc --noEmit'. Consumers
+with neither are skipped.
+
+Examples:
+  spark-cli verify
+  spark-cli verify AppModel
+  spark-cli verify --typecheck
+  spark-cli verify AppModel --repos AppAPI,Web`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		consumers, err := expandRepoNames(ws, verifyRepos)
+		if err != nil {
+			return err
+		}
+		for _, model := range args {
+			if _, ok := ws.Repos[model]; !ok {
+				return errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", model)
+			}
+		}
+		return runVerify(wsPath, ws, args, consumers)
+	},
+}
+
+// verifyResult is how one consumer fared against the local models
+type verifyResult struct {
+	consumer string
+	err      error
+	skipped  string // why the consumer had nothing to check, if it didn't
+	elapsed  time.Duration
+}
+
+// runVerify builds models, then links them into each of consumers that consumes one and
+// runs its checks. An empty models means every consumed model.
+func runVerify(wsPath string, ws *workspace.Workspace, models, consumers []string) error {
+	plan := make(map[string][]modelConsumer) // consumer → its entries for the verified models
+	var consumed []string
+	for _, name := range consumers {
+		for _, c := range consumedModels(wsPath, ws, name) {
+			if len(models) > 0 && !slices.Contains(models, c.Model) {
+				continue
+			}
+			plan[name] = append(plan[name], modelConsumer{consumer: name, entry: c})
+			if !slices.Contains(consumed, c.Model) {
+				consumed = append(consumed, c.Model)
+			}
+		}
+	}
+	if len(plan) == 0 {
+		fmt.Println("No consumers to verify — no repo's spk.config.json consumes these models")
+		return nil
+	}
+	order, err := workspace.BuildOrder(wsPath, ws, consumed)
+	if err != nil {
+		return errs.New(errs.KindUsage, "%v", err)
+	}
+	models = slices.DeleteFunc(order, func(n string) bool { return !slices.Contains(consumed, n) })
+
+	names := make([]string, 0, len(plan))
+	for name := range plan {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("Verifying %s against %d consumer(s)\n\n", joinNames(models), len(names))
+
+	wsEnv := buildSyncEnv(wsPath, ws)
+	for _, model := range models {
+		if err := buildRepo(wsPath, ws, model, wsEnv); err != nil {
+			return errs.New(errs.KindBuildFailed, "%s doesn't build, so nothing was verified: %w", model, err)
+		}
+	}
+
+	var results []verifyResult
+	for i, name := range names {
+		if proc.Interrupted() {
+			break
+		}
+		fmt.Printf("\n%s%s\n", progress.Step(i+1, len(names)), name)
+		start := time.Now()
+		skipped, err := verifyConsumer(wsPath, ws, name, plan[name], wsEnv)
+		results = append(results, verifyResult{consumer: name, err: err, skipped: skipped, elapsed: time.Since(start)})
+	}
+
+	fmt.Println("\nSummary:")
+	var broken int
+	for _, r := range results {
+		if r.err != nil {
+			ui.Fail.Printf("%-25s breaks: %v\n", r.consumer, r.err)
+			broken++
+			continue
+		}
+		if r.skipped != "" {
+			ui.Skip.Printf("%-25s not checked: %s\n", r.consumer, r.skipped)
+			continue
+		}
+		ui.OK.Printf("%-25s passes (%s)\n", r.consumer, progress.Elapsed(r.elapsed))
+	}
+	if broken > 0 {
+		return errs.New(errs.KindCommandFailed, "%d consumer(s) break against the local %s", broken, joinNames(models))
+	}
+	return nil
+}
+
+// verifyConsumer links the consumer to the local models, runs its tests (or type-check),
+// and undoes the links it made unless --keep-links. It returns why the consumer was
+// skipped when there was nothing to check.
+func verifyConsumer(wsPath string, ws *workspace.Workspace, name string, entries []modelConsumer, wsEnv map[string]string) (skipped string, err error) {
+	consumerDir := filepath.Join(wsPath, ws.Repos[name].Path)
+	// Install first: an install after linking would replace the links
+	if detectProjectType(consumerDir) == projectTypeNode {
+		if err := ensureNodeModules(wsPath, name, consumerDir, ws.Repos[name].ScopeEnv(wsEnv)); err != nil {
+			return "", err
+		}
+	}
+	for _, l := range entries {
+		c := l.entry
+		if !consumerLinked(wsPath, ws, name, c) && !verifyKeepLinks {
+			// Gradle substitutions leave the published version in place; npm links replace it
+			restore := func() error { return nil }
+			if !gradle.IsModule(c.Package) {
+				if restore, err = npm.SetAside(consumerDir, c.Package); err != nil {
+					return "", fmt.Errorf("couldn't set aside the installed %s: %w", c.Package, err)
+				}
+			}
+			defer func() {
+				undo := unlinkModel(wsPath, ws, name, c)
+				if undo == nil {
+					undo = restore()
+				}
+				if undo != nil {
+					fmt.Printf("  %s couldn't restore %s: %v\n", ui.Warn, c.Package, undo)
+				}
+			}()
+		}
+		if _, err := linkModel(wsPath, ws, name, c); err != nil {
+			return "", fmt.Errorf("couldn't link %s: %w", c.Package, err)
+		}
+		fmt.Printf("  🔗 %s → local %s\n", c.Package, c.Model)
+	}
+	if verifyTypecheck {
+		return typecheckRepo(ws, name, consumerDir, wsEnv)
+	}
+	return "", testRepo(wsPath, ws, name, wsEnv)
+}
+
+// typecheckRepo type-checks the repo in repoDir without emitting anything: its
+// "typecheck" script, else tsc --noEmit. It returns why it was skipped when the repo has
+// neither.
+func typecheckRepo(ws *workspace.Workspace, name, repoDir string, wsEnv map[string]string) (string, error) {
+	var command string
+	switch {
+	case workspace.HasNpmScript(repoDir, "typecheck"):
+		manager, _ := npm.PackageManager(repoDir)
+		command = manager + " run typecheck"
+	case fileExistsCheck(filepath.Join(repoDir, "tsconfig.json")):
+		command = "npx tsc --noEmit"
+	default:
+		return "no typecheck script or tsconfig.json", nil
+	}
+	fmt.Printf("=== %s: %s ===\n", name, command)
+	repo := ws.Repos[name]
+	if err := runShellCmdWithEnv(repoDir, command, &repo, wsEnv); err != nil {
+		return "", errs.New(errs.KindBuildFailed, "%s: type-check failed: %w", name, err)
+	}
+	return "", nil
+}
+
+// joinNames lists names for a sentence, e.g. "AppModel and PaymentsModel"
+func joinNames(names []string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	}
+	return fmt.Sprintf("%s and %s", strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
+
+func init() {
+	verifyCmd.Flags().StringSliceVar(&verifyRepos, "repos", nil, "Only verify these consumers")
+	verifyCmd.Flags().BoolVar(&verifyKeepLinks, "keep-links", false, "Leave consumers linked to the local models afterwards")
+	verifyCmd.Flags().BoolVar(&verifyTypecheck, "typecheck", false, "Only type-check consumers instead of running their tests")
+	rootCmd.AddCommand(verifyCmd)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/settings"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show who you are across AWS, GitHub, npm, and git",
+	Long: `Shows the identities spark-cli will act as — useful before deploying.
+
+  AWS     caller identity for the workspace profile (account mapped to beta/prod/central)
+  GitHub  user that owns the resolved GITHUB_TOKEN
+  npm     auth status for the GitHub Packages registry
+  git     user.name / user.email
+
+Example:
+  spark-cli whoami`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile := currentSettings().String(settings.AWSProfile)
+		dir, _ := os.Getwd()
+
+		// AWS
+		fmt.Printf("%-8s ", "AWS")
+		if err := aws.CheckCLI(); err != nil {
+			ui.Fail.Println("aws CLI not installed")
+		} else if id, err := aws.CallerIdentity(profile); err != nil {
+			ui.Fail.Printf("not logged in (profile: %s) — run 'aws sso login --profile %s'\n", orDefault(profile, "default"), orDefault(profile, "default"))
+		} else {
+			account := id.Account
+			if name := aws.AccountName(id.Account); name != "" {
+				account = fmt.Sprintf("%s (%s)", id.Account, name)
+			}
+			ui.OK.Printf("%s\n", id.Arn)
+			fmt.Printf("%-8s   account %s, profile %s\n", "", account, orDefault(profile, "default"))
+		}
+
+		// GitHub
+		fmt.Printf("%-8s ", "GitHub")
+		token := resolveGitHubToken()
+		if token == "" {
+			ui.Fail.Println("no token — set GITHUB_TOKEN or run 'gh auth login'")
+		} else if u, err := github.GetAuthenticatedUser(token); err != nil {
+			ui.Fail.Printf("%v\n", err)
+		} else if u.Name != "" {
+			ui.OK.Printf("%s (%s)\n", u.Login, u.Name)
+		} else {
+			ui.OK.Printf("%s\n", u.Login)
+		}
+
+		// npm
+		fmt.Printf("%-8s ", "npm")
+		if err := npm.CheckNPM(); err != nil {
+			ui.Fail.Println("npm not installed")
+		} else if user, err := npm.Whoami(dir, npm.GitHubPackagesRegistry); err != nil {
+			ui.Fail.Printf(binname.Rewrite("%v — 'spark-cli npm auth' sets it up\n"), err)
+		} else {
+			ui.OK.Printf("%s @ %s\n", user, npm.GitHubPackagesRegistry)
+		}
+
+		// git
+		fmt.Printf("%-8s ", "git")
+		name := git.ConfigValue(dir, "user.name")
+		email := git.ConfigValue(dir, "user.email")
+		if name == "" && email == "" {
+			ui.Fail.Println("user.name / user.email not set")
+		} else {
+			ui.OK.Printf("%s <%s>\n", orDefault(name, "(no name)"), orDefault(email, "no email"))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whoamiCmd)
+}
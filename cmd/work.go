@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	workType  string
+	workRepos []string
+	workDraft bool
+)
+
+// ticketRe matches issue keys like SPK-123
+var ticketRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*-\d+$`)
+
+var workCmd = &cobra.Command{
+	Use:   "work",
+	Short: "Ticket-based branches and PRs across repos (start | finish)",
+	Long: `Ties a unit of work to a ticket across repos.
+
+'work start' creates the same branch (<type>/<TICKET>-<description>) from the
+default branch in each selected repo and remembers the ticket in .spk/state.json.
+While a ticket is active, 'spark-cli commit' prefixes messages with it.
+
+'work finish' pushes those branches and opens a PR per repo titled with the
+ticket; each PR description links the ticket and the sibling PRs.
+
+Examples:
+  spark-cli work start SPK-123 add loyalty tiers
+  spark-cli work start SPK-123 add loyalty tiers --type fix --repos AppModel,AppAPI
+  spark-cli work                 # show the active ticket
+  spark-cli work finish --draft`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, _, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		st, err := state.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		if st.Work == nil {
+			fmt.Println(binname.Rewrite("No active ticket — start one with 'spark-cli work start <ticket> <description>'"))
+			return nil
+		}
+		w := st.Work
+		fmt.Printf("Ticket: %s", w.Ticket)
+		if w.Title != "" {
+			fmt.Printf(" — %s", w.Title)
+		}
+		fmt.Printf("\nBranch: %s\nRepos:  %s\n", w.Branch, strings.Join(w.Repos, ", "))
+		return nil
+	},
+}
+
+var workStartCmd = &cobra.Command{
+	Use:   "start <ticket> [description...]",
+	Short: "Create a ticket branch across selected repos",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ticket := strings.ToUpper(args[0])
+		if !ticketRe.MatchString(ticket) {
+			return errs.New(errs.KindUsage, "invalid ticket %q — expected something like SPK-123", args[0])
+		}
+		title := strings.Join(args[1:], " ")
+		branch := workBranchName(workType, ticket, title)
+
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		st, err := state.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		if st.Work != nil && st.Work.Ticket != ticket {
+			if !prompt.Confirm(fmt.Sprintf("%s is still active — replace it with %s?", st.Work.Ticket, ticket), false) {
+				return nil
+			}
+		}
+
+		names, err := selectRepos(wsPath, ws, workRepos)
+		if err != nil {
+			return err
+		}
+		if len(workRepos) == 0 {
+			current, _ := detectCurrentRepo(wsPath, ws)
+			pre := make([]bool, len(names))
+			for i, n := range names {
+				pre[i] = n == current
+			}
+			picked, err := prompt.MultiSelect(fmt.Sprintf("Repos for %s:", ticket), names, pre)
+			if err != nil {
+				return errs.Wrap(errs.KindUsage, err)
+			}
+			var chosen []string
+			for _, i := range picked {
+				chosen = append(chosen, names[i])
+			}
+			names = chosen
+		}
+		if len(names) == 0 {
+			fmt.Println("No repos selected")
+			return nil
+		}
+
+		var started []string
+		for _, name := range names {
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+
+			if git.BranchExists(repoDir, branch) {
+				if err := git.CheckoutQuiet(repoDir, branch); err != nil {
+					ui.Fail.Printf("%-25s couldn't switch to existing %s\n", name, branch)
+					continue
+				}
+				ui.OK.Printf("%-25s switched to existing %s\n", name, branch)
+				started = append(started, name)
+				continue
+			}
+
+			base := getTargetBranch(ws, &repo, repoDir)
+			if err := git.FetchQuiet(repoDir, "origin"); err != nil {
+				fmt.Printf("  %s: fetch failed — branching from the last fetched origin/%s\n", name, base)
+			}
+			if err := git.CreateBranchFrom(repoDir, branch, "origin/"+base); err != nil {
+				ui.Fail.Printf("%-25s %v\n", name, err)
+				continue
+			}
+			ui.OK.Printf("%-25s %s (from origin/%s)\n", name, branch, base)
+			started = append(started, name)
+		}
+		if len(started) == 0 {
+			return errs.New(errs.KindCommandFailed, "no branches created")
+		}
+
+		return state.Update(wsPath, func(s *state.State) error {
+			s.Work = &state.WorkItem{
+				Ticket:    ticket,
+				Title:     title,
+				Branch:    branch,
+				Repos:     started,
+				StartedAt: time.Now().UTC().Format(time.RFC3339),
+			}
+			return nil
+		})
+	},
+}
+
+var workFinishCmd = &cobra.Command{
+	Use:   "finish",
+	Short: "Push the ticket branches and open linked PRs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		st, err := state.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		w := st.Work
+		if w == nil {
+			return errs.New(errs.KindUsage, "no active ticket — start one with 'spark-cli work start'")
+		}
+		token := resolveGitHubToken()
+
+		type openedPR struct {
+			repo, slug string
+			number     int
+			url        string
+		}
+		var opened []openedPR
+		var failed int
+
+		for _, name := range w.Repos {
+			repo, ok := ws.Repos[name]
+			if !ok {
+				continue
+			}
+			repoDir := filepath.Join(wsPath, repo.Path)
+			base := getTargetBranch(ws, &repo, repoDir)
+
+			if ahead, _ := git.AheadBehind(repoDir, w.Branch, "origin/"+base); ahead == 0 {
+				ui.Skip.Printf("%-25s no commits on %s\n", name, w.Branch)
+				continue
+			}
+			if err := git.Push(repoDir, "origin", w.Branch, true); err != nil {
+				ui.Fail.Printf("%-25s push failed: %v\n", name, err)
+				failed++
+				continue
+			}
+
+			slug := git.GitHubSlug(git.RemoteURL(repoDir, "origin"))
+			if slug == "" || token == "" {
+				ui.OK.Printf("%-25s pushed (open the PR manually — no GitHub remote or token)\n", name)
+				continue
+			}
+			// A re-run after a partial failure picks up the PRs it already opened
+			pr, err := github.OpenPullRequest(token, slug, w.Branch)
+			if err != nil {
+				ui.Fail.Printf("%-25s pushed, but looking up its PR failed: %v\n", name, err)
+				failed++
+				continue
+			}
+			if pr != nil {
+				ui.OK.Printf("%-25s %s (already open)\n", name, pr.HTMLURL)
+				opened = append(opened, openedPR{name, slug, pr.Number, pr.HTMLURL})
+				continue
+			}
+			pr, err = github.CreatePullRequest(token, slug, github.NewPullRequest{
+				Title: workPRTitle(w, repoDir),
+				Head:  w.Branch,
+				Base:  base,
+				Body:  workPRBody(w, nil),
+				Draft: workDraft,
+			})
+			if err != nil {
+				ui.Fail.Printf("%-25s pushed, but opening the PR failed: %v\n", name, err)
+				failed++
+				continue
+			}
+			ui.OK.Printf("%-25s %s\n", name, pr.HTMLURL)
+			opened = append(opened, openedPR{name, slug, pr.Number, pr.HTMLURL})
+		}
+
+		// Cross-link sibling PRs once they all exist
+		if len(opened) > 1 {
+			var links []string
+			for _, o := range opened {
+				links = append(links, fmt.Sprintf("%s: %s", o.repo, o.url))
+			}
+			for _, o := range opened {
+				if err := github.UpdatePullRequestBody(token, o.slug, o.number, workPRBody(w, links)); err != nil {
+					fmt.Printf("  %s: couldn't link sibling PRs: %v\n", o.repo, err)
+				}
+			}
+		}
+
+		if failed > 0 {
+			return errs.New(errs.KindCommandFailed, "%d repo(s) failed — fix and re-run 'spark-cli work finish'", failed)
+		}
+		return state.Update(wsPath, func(s *state.State) error {
+			s.Work = nil
+			return nil
+		})
+	},
+}
+
+// workBranchName builds <type>/<TICKET>-<slugified title>
+func workBranchName(kind, ticket, title string) string {
+	slug := strings.Trim(regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > 40 {
+		slug = strings.TrimRight(slug[:40], "-")
+	}
+	name := ticket
+	if slug != "" {
+		name += "-" + slug
+	}
+	return kind + "/" + name
+}
+
+// workPRTitle is "<TICKET>: <title>", using the subject of the work branch's tip when
+// the ticket was started without a description
+func workPRTitle(w *state.WorkItem, repoDir string) string {
+	title := w.Title
+	if title == "" {
+		// <branch>^! is just the tip, whatever is checked out
+		if msgs, err := git.CommitMessages(repoDir, w.Branch+"^!"); err == nil && len(msgs) > 0 {
+			title = strings.SplitN(msgs[0], "\n", 2)[0]
+			title = strings.TrimPrefix(title, w.Ticket+": ")
+		}
+	}
+	return fmt.Sprintf("%s: %s", w.Ticket, title)
+}
+
+// workPRBody references the ticket and, once known, the sibling PRs
+func workPRBody(w *state.WorkItem, siblings []string) string {
+	body := fmt.Sprintf("Ticket: %s\n", w.Ticket)
+	if len(siblings) > 0 {
+		body += "\nRelated PRs:\n"
+		for _, s := range siblings {
+			body += "- " + s + "\n"
+		}
+	}
+	return body
+}
+
+func init() {
+	workStartCmd.Flags().StringVar(&workType, "type", "feat", "Branch prefix (feat, fix, chore, ...)")
+	workStartCmd.Flags().StringSliceVar(&workRepos, "repos", nil, "Repos to branch (default: choose interactively)")
+	workFinishCmd.Flags().BoolVar(&workDraft, "draft", false, "Open the PRs as drafts")
+	workCmd.AddCommand(workStartCmd)
+	workCmd.AddCommand(workFinishCmd)
+	rootCmd.AddCommand(workCmd)
+}
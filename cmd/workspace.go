@@ -4,32 +4,51 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/devcontainer"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/docker"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/settings"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/spkconfig"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/state"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
 var (
-	workspaceCreateProfile string
-	workspaceCreateRegion  string
+	workspaceCreateProfile    string
+	workspaceCreateRegion     string
 	workspaceConfigureProfile string
 	workspaceConfigureList    bool
 )
 
 var workspaceCmd = &cobra.Command{
 	Use:     "workspace",
-	Short:   "Manage workspace (ws, info | create | configure --profile, --list | -h)",
+	Short:   "Manage workspace (ws, info | create | switch | rename | set | backup | restore | configure --profile, --list | -h)",
 	Aliases: []string{"ws", "info"},
 	Long: `Show workspace info or run a workspace subcommand.
 Use 'workspace' or 'ws' (same command).
 
-With no subcommand, lists the workspace name, repos, and AWS profile.
+With no subcommand, shows an overview of the workspace: its AWS profile and
+environment, when .env was last refreshed and from which env, whether the SSO
+session is still valid, each repo's branch, status, and last successful sync
+and build, and which consumers use a locally built model.
 
 Examples:
   spark-cli workspace                    # or: spark-cli ws
   spark-cli ws create [path]             # create a new workspace
+  spark-cli ws switch <name>             # default workspace outside any workspace dir
   spark-cli workspace configure --profile dev   # set default AWS profile`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		wsPath, err := workspace.Find()
@@ -50,7 +69,7 @@ Examples:
 		// List configured AWS profiles; mark the one selected for this workspace
 		profiles := aws.GetSSOProfiles()
 		if len(profiles) > 0 {
-			fmt.Println("AWS profiles (swap with: spark-cli workspace configure --profile <name>):")
+			fmt.Println(binname.Rewrite("AWS profiles (swap with: spark-cli workspace configure --profile <name>):"))
 			for _, p := range profiles {
 				mark := ""
 				if p == ws.AWSProfile {
@@ -61,39 +80,147 @@ Examples:
 			fmt.Println()
 		}
 
+		printWorkspaceEnvStatus(wsPath, ws)
+
 		if len(ws.Repos) > 0 {
-			fmt.Printf("%-20s %-15s %-10s %s\n", "REPO", "BRANCH", "STATUS", "PATH")
-			fmt.Printf("%-20s %-15s %-10s %s\n", "----", "------", "------", "----")
-
-			for name, repo := range ws.Repos {
-				repoDir := filepath.Join(wsPath, repo.Path)
-				branch := "-"
-				status := "missing"
-
-				if _, err := os.Stat(repoDir); err == nil {
-					if git.IsRepo(repoDir) {
-						b, _ := git.CurrentBranch(repoDir)
-						if b != "" {
-							branch = b
-						}
-						if git.IsDirty(repoDir) {
-							status = "unstaged-changes"
-						} else {
-							status = "up-to-date"
-						}
-					}
-				}
+			fmt.Printf("%-20s %-15s %-18s %-10s %-10s %s\n", "REPO", "BRANCH", "STATUS", "LAST SYNC", "LAST BUILD", "PATH")
+			fmt.Printf("%-20s %-15s %-18s %-10s %-10s %s\n", "----", "------", "------", "---------", "----------", "----")
 
-				fmt.Printf("%-20s %-15s %-10s %s\n", name, branch, status, repo.Path)
+			names, _ := expandRepoNames(ws, nil)
+			branches := make([]string, len(names))
+			statuses := make([]string, len(names))
+			forEachRepo(ws, names, func(i int, name string) {
+				repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+				branches[i], statuses[i] = "-", "missing"
+				if _, err := os.Stat(repoDir); err != nil || !git.IsRepo(repoDir) {
+					return
+				}
+				snap, err := git.TakeSnapshot(repoDir)
+				if err != nil {
+					return
+				}
+				branches[i] = orDefault(snap.Branch, "(detached)")
+				statuses[i] = "up-to-date"
+				if snap.Dirty {
+					statuses[i] = "unstaged-changes"
+				}
+			})
+			lastSync := lastSuccessByRepo(wsPath, "sync")
+			lastBuild := lastSuccessByRepo(wsPath, "build")
+			for i, name := range names {
+				fmt.Printf("%-20s %-15s %-18s %-10s %-10s %s\n", name, branches[i], statuses[i],
+					sinceOrNever(lastSync[name]), sinceOrNever(lastBuild[name]), ws.Repos[name].Path)
 			}
+			printWorkspaceLinks(wsPath, ws, names)
 		} else {
-			fmt.Println("No repos — run 'spark-cli use <repo>' to add one")
+			fmt.Println(binname.Rewrite("No repos — run 'spark-cli use <repo>' to add one"))
 		}
 
 		return nil
 	},
 }
 
+// printWorkspaceEnvStatus shows where the workspace .env came from and whether the AWS
+// credentials it's fetched with are still good
+func printWorkspaceEnvStatus(wsPath string, ws *workspace.Workspace) {
+	fmt.Printf("%-13s %s\n", ".env", describeEnvRefresh(wsPath, activeEnv(ws, "")))
+	profile := settings.Load(ws).String(settings.AWSProfile)
+	fmt.Printf("%-13s %s: %s\n", "Credentials", orDefault(profile, "default"), describeCredentials(profile))
+	fmt.Println()
+}
+
+// describeEnvRefresh says when the workspace .env was last written and from which env,
+// flagging one fetched for an env other than the active one
+func describeEnvRefresh(wsPath, env string) string {
+	info, err := os.Stat(workspace.GlobalEnvPath(wsPath))
+	if err != nil {
+		return "missing — run 'spark-cli workspace sync' to fetch it"
+	}
+	st, _ := state.Load(wsPath)
+	if st != nil && st.EnvRefresh != nil {
+		if t, err := time.Parse(time.RFC3339, st.EnvRefresh.WrittenAt); err == nil {
+			desc := fmt.Sprintf("%s, refreshed %s", st.EnvRefresh.Env, formatAge(time.Since(t)))
+			if st.EnvRefresh.Env != env {
+				desc += fmt.Sprintf(binname.Rewrite(" %s active env is %s — run 'spark-cli workspace sync --env %[2]s'"), ui.Warn, env)
+			}
+			return desc
+		}
+	}
+	return fmt.Sprintf("modified %s (source env unknown)", formatAge(time.Since(info.ModTime())))
+}
+
+// describeCredentials reports how long the profile's SSO session has left. Profiles
+// without SSO aren't checked, since that would mean a call to AWS.
+func describeCredentials(profile string) string {
+	if !aws.IsSSOConfigured(profile) {
+		return "not an SSO profile (not checked)"
+	}
+	loginHint := "aws sso login"
+	if profile != "" {
+		loginHint += " --profile " + profile
+	}
+	expires, ok := aws.SSOExpiry(profile)
+	if !ok {
+		return fmt.Sprintf("%s not logged in — run '%s'", ui.Fail, loginHint)
+	}
+	left := time.Until(expires)
+	switch {
+	case left <= 0:
+		return fmt.Sprintf("%s expired %s — run '%s'", ui.Fail, formatAge(-left), loginHint)
+	case left < time.Hour:
+		return fmt.Sprintf("%s expiring in %dm", ui.Warn, int(left.Minutes()))
+	default:
+		return fmt.Sprintf("%s valid for %dh%02dm", ui.OK, int(left.Hours()), int(left.Minutes())%60)
+	}
+}
+
+// printWorkspaceLinks shows which consumers use a model built in the workspace (a
+// symlink in node_modules) and which use the published package
+func printWorkspaceLinks(wsPath string, ws *workspace.Workspace, names []string) {
+	var lines []string
+	st, err := state.Load(wsPath)
+	if err != nil {
+		st = &state.State{}
+	}
+	for _, name := range names {
+		repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+		cfg, err := spkconfig.Load(repoDir)
+		if err != nil || cfg == nil {
+			continue
+		}
+		for _, c := range cfg.Consumes {
+			how := "published"
+			if consumerLinked(wsPath, ws, name, c) {
+				how = "🔗 local " + c.Model
+				if pin, ok := st.LinkPins[name][c.Package]; ok {
+					if head, moved := linkPinMoved(wsPath, ws, pin); moved {
+						how += fmt.Sprintf(" %s built from %s, now at %s", ui.Warn, shortCommit(pin.Commit), shortCommit(head))
+					}
+				}
+			} else if enabled, why := linkEnabled(ws, st, name); !enabled {
+				how += " (" + why + ")"
+			}
+			lines = append(lines, fmt.Sprintf("  %-25s %-40s %s", name, c.Package, how))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println("Model links:")
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+}
+
+// sinceOrNever formats how long ago t was, or "never" for the zero time
+func sinceOrNever(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return formatAge(time.Since(t))
+}
+
 var workspaceCreateCmd = &cobra.Command{
 	Use:   "create [path]",
 	Short: "Create a new spark-cli workspace",
@@ -135,11 +262,167 @@ Examples:
 		}
 		fmt.Println("\nNext steps:")
 		fmt.Printf("  cd %s\n", absPath)
-		fmt.Println("  spark-cli use <org/repo>")
+		fmt.Println(binname.Rewrite("  spark-cli use <org/repo>"))
 		return nil
 	},
 }
 
+var workspaceSwitchCmd = &cobra.Command{
+	Use:   "switch <name|path>",
+	Short: "Set the workspace commands use outside any workspace directory",
+	Long: `Makes a registered workspace the default for commands run from outside a
+workspace directory (inside one, that workspace still wins). The name is the
+workspace's name or directory name; see ~/.spk/config.json for registrations.
+
+--workspace <name|path> or SPK_WORKSPACE selects one for a single command.
+
+Examples:
+  spark-cli workspace switch spark
+  spark-cli workspace switch ~/code/other-workspace
+  spark-cli -w spark sync`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Resolve(args[0])
+		if err != nil {
+			return err
+		}
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		if err := config.RegisterWorkspace(wsPath); err != nil {
+			return err
+		}
+		cfg, err := config.LoadGlobal()
+		if err != nil {
+			return err
+		}
+		cfg.CurrentWorkspace = wsPath
+		if err := config.SaveGlobal(cfg); err != nil {
+			return err
+		}
+		ui.OK.Printf("Switched to workspace '%s' (%s)\n", ws.Name, wsPath)
+		return nil
+	},
+}
+
+var workspaceRenameCmd = &cobra.Command{
+	Use:   "rename <new-name>",
+	Short: "Rename the workspace and the files named after it",
+	Long: `Sets the workspace name in workspace.json and brings along everything named
+after it: the <name>.code-workspace file, a generated devcontainer.json, and
+the LocalStack container.
+
+Example:
+  spark-cli workspace rename rewards`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		newName := strings.TrimSpace(args[0])
+		if newName == "" || strings.ContainsAny(newName, `/\`) {
+			return errs.New(errs.KindUsage, "invalid workspace name %q", args[0])
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		oldName := ws.Name
+		if oldName == newName {
+			fmt.Printf("Workspace is already named '%s'\n", newName)
+			return nil
+		}
+		oldCodeWorkspace := workspace.VSCodeWorkspacePath(wsPath)
+		oldContainer := localStackContainer(ws)
+
+		ws.Name = newName
+		if err := workspace.Save(wsPath, ws); err != nil {
+			return err
+		}
+		ui.OK.Printf("Renamed workspace '%s' → '%s'\n", oldName, newName)
+
+		newCodeWorkspace := workspace.VSCodeWorkspacePath(wsPath)
+		if fileExistsCheck(oldCodeWorkspace) {
+			if err := os.Rename(oldCodeWorkspace, newCodeWorkspace); err != nil {
+				return fmt.Errorf("failed to rename %s: %w", filepath.Base(oldCodeWorkspace), err)
+			}
+		} else if err := workspace.GenerateVSCodeWorkspace(wsPath); err != nil {
+			return err
+		}
+		ui.OK.Printf("VS Code workspace: %s\n", filepath.Base(newCodeWorkspace))
+
+		devcontainerPath := filepath.Join(wsPath, devcontainer.Dir, "devcontainer.json")
+		if devcontainer.IsGenerated(devcontainerPath) {
+			c, err := devcontainer.Load(devcontainerPath)
+			if err == nil {
+				c.Name = newName
+				err = c.Write(devcontainerPath)
+			}
+			if err != nil {
+				ui.Warn.Printf("devcontainer.json not updated: %v\n", err)
+			} else {
+				ui.OK.Println("devcontainer.json name updated")
+			}
+		}
+
+		if newContainer := localStackContainer(ws); docker.CheckCLI() == nil && newContainer != oldContainer {
+			if proc.Command("docker", "rename", oldContainer, newContainer).Run() == nil {
+				ui.OK.Printf("LocalStack container renamed to %s\n", newContainer)
+			}
+		}
+		if entries, _ := os.ReadDir(filepath.Join(workspace.SparkDir(wsPath), "compose")); len(entries) > 0 {
+			ui.Warn.Println("Compose stacks started before the rename keep the old project name — bring them down first, then up again")
+		}
+		return nil
+	},
+}
+
+var workspaceSetCmd = &cobra.Command{
+	Use:   "set <field> <value>",
+	Short: "Set a workspace.json field (default_branch, layout, ...)",
+	Long: `Sets a top-level field of workspace.json and checks the manifest before saving.
+Lists are comma-separated; an empty value removes the field. Maps such as
+environments or services are edited with 'spark-cli config edit'.
+
+Examples:
+  spark-cli workspace set default_branch develop
+  spark-cli workspace set layout org
+  spark-cli workspace set frozen_lockfile true
+  spark-cli workspace set default_branch ""`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+		if key == "name" {
+			return errs.New(errs.KindUsage, "use 'spark-cli workspace rename' to change the name")
+		}
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		if err := workspace.SetField(ws, key, value); err != nil {
+			return err
+		}
+		if err := saveValidatedWorkspace(wsPath, ws); err != nil {
+			return err
+		}
+		if value == "" {
+			ui.OK.Printf("Removed %s\n", key)
+		} else {
+			ui.OK.Printf("%s = %s\n", key, value)
+		}
+		return nil
+	},
+}
+
+// saveValidatedWorkspace saves ws if it passes the checks 'config edit' applies
+func saveValidatedWorkspace(wsPath string, ws *workspace.Workspace) error {
+	if err := workspace.Validate(wsPath, ws); err != nil {
+		return errs.New(errs.KindUsage, "not saved: %v", err)
+	}
+	if err := settings.Validate(ws, nil); err != nil {
+		return errs.New(errs.KindUsage, "not saved: %v", err)
+	}
+	return workspace.Save(wsPath, ws)
+}
+
 var workspaceConfigureCmd = &cobra.Command{
 	Use:   "configure",
 	Short: "Set or list default AWS profile for this workspace",
@@ -216,6 +499,11 @@ func runWorkspaceConfigureProfile(profileName string) error {
 	if err != nil {
 		return err
 	}
+	return runWorkspaceConfigureProfileAt(wsPath, profileName)
+}
+
+// runWorkspaceConfigureProfileAt sets the AWS profile of the workspace at wsPath
+func runWorkspaceConfigureProfileAt(wsPath, profileName string) error {
 	if err := aws.CheckCLI(); err != nil {
 		return err
 	}
@@ -250,13 +538,123 @@ func runWorkspaceConfigureProfile(profileName string) error {
 			if err := aws.GetCallerIdentity(profileName); err != nil {
 				return fmt.Errorf("verification failed after login: %w", err)
 			}
-			fmt.Println("✓ Login successful")
+			ui.OK.Println("Login successful")
 		}
 	}
-	fmt.Println("Use 'spark-cli workspace sync' with this profile.")
+	fmt.Println(binname.Rewrite("Use 'spark-cli workspace sync' with this profile."))
 	return nil
 }
 
+// selectRepos returns the sorted names of cloned workspace repos, limited to only when
+// non-empty; "@tag" in only stands for every repo with that tag. Unknown names are an
+// error; repos that aren't cloned are skipped.
+func selectRepos(wsPath string, ws *workspace.Workspace, only []string) ([]string, error) {
+	names, err := expandRepoNames(ws, only)
+	if err != nil {
+		return nil, err
+	}
+	var cloned []string
+	for _, name := range names {
+		if git.IsRepo(filepath.Join(wsPath, ws.Repos[name].Path)) {
+			cloned = append(cloned, name)
+		}
+	}
+	return cloned, nil
+}
+
+// expandRepoNames returns the sorted workspace repos named in only (all of them when
+// empty), expanding "@tag" to the repos with that tag
+func expandRepoNames(ws *workspace.Workspace, only []string) ([]string, error) {
+	var names []string
+	if len(only) == 0 {
+		for name := range ws.Repos {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, name := range only {
+		if tag, ok := strings.CutPrefix(name, "@"); ok {
+			tagged := reposTagged(ws, tag)
+			if len(tagged) == 0 {
+				return nil, errs.New(errs.KindRepoNotFound, "no repos tagged '%s'", tag)
+			}
+			for _, t := range tagged {
+				add(t)
+			}
+			continue
+		}
+		if _, ok := ws.Repos[name]; !ok {
+			return nil, errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
+		}
+		add(name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// forEachRepo calls fn for each of names in parallel, at most `concurrency` at a time.
+// fn must not print or touch shared state without its own locking.
+func forEachRepo(ws *workspace.Workspace, names []string, fn func(i int, name string)) {
+	sem := make(chan struct{}, max(1, settings.Load(ws).Int(settings.Concurrency)))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i, name)
+		}()
+	}
+	wg.Wait()
+}
+
+// reposTagged returns the names of the repos tagged tag
+func reposTagged(ws *workspace.Workspace, tag string) []string {
+	var names []string
+	for name, repo := range ws.Repos {
+		if repo.HasTag(tag) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pickRepos lets the user choose workspace repos when none were named; usage is the error
+// returned when prompting isn't allowed
+func pickRepos(ws *workspace.Workspace, label, usage string) ([]string, error) {
+	if prompt.NonInteractive {
+		return nil, errs.New(errs.KindUsage, "%s", usage)
+	}
+	names := make([]string, 0, len(ws.Repos))
+	for name := range ws.Repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	picked, err := prompt.Pick(label, names)
+	if err != nil {
+		return nil, errs.Wrap(errs.KindUsage, err)
+	}
+	if len(picked) == 0 {
+		return nil, errs.New(errs.KindUsage, "nothing selected")
+	}
+	chosen := make([]string, len(picked))
+	for i, p := range picked {
+		chosen[i] = names[p]
+	}
+	return chosen, nil
+}
+
 func orDefault(val, def string) string {
 	if val == "" {
 		return def
@@ -268,6 +666,9 @@ func init() {
 	rootCmd.AddCommand(workspaceCmd)
 	workspaceCmd.AddCommand(workspaceCreateCmd)
 	workspaceCmd.AddCommand(workspaceConfigureCmd)
+	workspaceCmd.AddCommand(workspaceSwitchCmd)
+	workspaceCmd.AddCommand(workspaceRenameCmd)
+	workspaceCmd.AddCommand(workspaceSetCmd)
 	workspaceConfigureCmd.AddCommand(workspaceConfigureSSOCmd)
 
 	workspaceCreateCmd.Flags().StringVar(&workspaceCreateProfile, "aws-profile", "", "AWS SSO profile name")
@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/backup"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/settings"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// backupPassphraseEnv supplies the .env passphrase without a prompt (scripts, CI)
+const backupPassphraseEnv = "SPK_BACKUP_PASSPHRASE"
+
+var (
+	backupNoEnv    bool
+	backupProfile  string
+	backupRegion   string
+	restoreNoClone bool
+	restoreForce   bool
+)
+
+var workspaceBackupCmd = &cobra.Command{
+	Use:   "backup [file | s3://bucket/key]",
+	Short: "Archive the workspace's .spk, manifest, and encrypted .env",
+	Long: `Writes a .tar.gz of the workspace's metadata — .spk (the manifest, state,
+metrics, and settings) and the .code-workspace file — plus the workspace .env,
+encrypted with a passphrase (AES-256-GCM). 'spark-cli workspace restore' rebuilds
+the workspace from it on another machine.
+
+Repos aren't included: restore clones them again. Left out of .spk are the
+shared store, logs, caches, and compose state, which are rebuilt on demand.
+
+The passphrase is asked for, or read from $SPK_BACKUP_PASSPHRASE. --no-env
+leaves the .env out instead. The archive goes to the file given (default
+<workspace>-<date>.spk-backup.tar.gz here), or to S3 with an s3:// URL (a URL
+ending in / gets the default file name), using the workspace's AWS profile
+unless --profile.
+
+Examples:
+  spark-cli workspace backup
+  spark-cli workspace backup ~/Dropbox/spark.spk-backup.tar.gz
+  spark-cli workspace backup s3://my-bucket/backups/ --profile dev`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, ws, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("%s-%s.spk-backup.tar.gz", ws.Name, time.Now().Format("20060102-150405"))
+		dest := name
+		if len(args) == 1 {
+			dest = args[0]
+			if strings.HasSuffix(dest, "/") {
+				dest += name
+			}
+		}
+
+		passphrase := ""
+		if _, err := os.Stat(workspace.GlobalEnvPath(wsPath)); err == nil && !backupNoEnv {
+			if passphrase, err = backupPassphrase(true); err != nil {
+				return err
+			}
+		}
+
+		toS3 := strings.HasPrefix(dest, "s3://")
+		path := dest
+		if toS3 {
+			tmp, err := os.CreateTemp("", "spk-backup-*.tar.gz")
+			if err != nil {
+				return err
+			}
+			tmp.Close()
+			defer os.Remove(tmp.Name())
+			path = tmp.Name()
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		info, err := backup.Create(f, wsPath, ws.Name, passphrase)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+			return fmt.Errorf("failed to write backup: %w", err)
+		}
+		if toS3 {
+			profile, region := settings.Load(ws).AWS(activeEnv(ws, ""))
+			if err := aws.S3Copy(orDefault(backupProfile, profile), orDefault(backupRegion, region), path, dest); err != nil {
+				return errs.New(errs.KindCommandFailed, "upload failed: %w", err)
+			}
+		}
+
+		env := "not included"
+		if info.Env {
+			env = "encrypted"
+		}
+		ui.OK.Printf("Backed up '%s' to %s\n", ws.Name, dest)
+		fmt.Printf("  %d file(s), .env %s\n", len(info.Files), env)
+		fmt.Printf(binname.Rewrite("Restore with 'spark-cli workspace restore %s <path>'\n"), dest)
+		return nil
+	},
+}
+
+var workspaceRestoreCmd = &cobra.Command{
+	Use:   "restore <file | s3://bucket/key> [path]",
+	Short: "Rebuild a workspace from a backup, cloning its repos",
+	Long: `Restores a 'spark-cli workspace backup' into path (default: the current
+directory): the .spk metadata, the .code-workspace file, and the .env, decrypted
+with the backup's passphrase (asked for, or $SPK_BACKUP_PASSPHRASE). The
+workspace is registered, then every repo in the manifest that isn't there yet is
+cloned from its remote, unless --no-clone.
+
+It won't overwrite an existing workspace unless --force.
+
+Examples:
+  spark-cli workspace restore spark-20260101-120000.spk-backup.tar.gz ~/code/spark
+  spark-cli workspace restore s3://my-bucket/backups/spark.spk-backup.tar.gz --profile dev
+  spark-cli workspace restore backup.tar.gz --no-clone`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src := args[0]
+		wsPath := "."
+		if len(args) == 2 {
+			wsPath = args[1]
+		}
+		wsPath, err := filepath.Abs(wsPath)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(workspace.ManifestPath(wsPath)); err == nil && !restoreForce {
+			return errs.New(errs.KindUsage, "%s is already a workspace — restore somewhere else, or --force to overwrite its metadata", wsPath)
+		}
+
+		path := src
+		if strings.HasPrefix(src, "s3://") {
+			tmp, err := os.CreateTemp("", "spk-backup-*.tar.gz")
+			if err != nil {
+				return err
+			}
+			tmp.Close()
+			defer os.Remove(tmp.Name())
+			if err := aws.S3Copy(backupProfile, backupRegion, src, tmp.Name()); err != nil {
+				return errs.New(errs.KindCommandFailed, "download failed: %w", err)
+			}
+			path = tmp.Name()
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		info, err := backup.ReadInfo(f)
+		f.Close()
+		if err != nil {
+			return errs.New(errs.KindUsage, "%s: %v", src, err)
+		}
+		fmt.Printf("Backup of '%s' from %s, %s\n", info.Workspace, orDefault(info.Host, "unknown host"), formatAge(time.Since(info.CreatedAt)))
+
+		passphrase := ""
+		if info.Env {
+			if passphrase, err = backupPassphrase(false); err != nil {
+				return err
+			}
+		}
+		if f, err = os.Open(path); err != nil {
+			return err
+		}
+		err = backup.Restore(f, wsPath, passphrase)
+		f.Close()
+		if errors.Is(err, backup.ErrPassphrase) {
+			return errs.New(errs.KindUsage, "couldn't decrypt the .env: %v — nothing was restored", err)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore: %w", err)
+		}
+		if err := config.RegisterWorkspace(wsPath); err != nil {
+			return err
+		}
+		ui.OK.Printf("Restored '%s' into %s\n", info.Workspace, wsPath)
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		if restoreNoClone {
+			fmt.Println(binname.Rewrite("Clone its repos with 'spark-cli use <repo>'"))
+			return nil
+		}
+		return cloneWorkspaceRepos(wsPath, ws)
+	},
+}
+
+// backupPassphrase reads the .env passphrase from $SPK_BACKUP_PASSPHRASE or asks for it,
+// twice when confirm is set
+func backupPassphrase(confirm bool) (string, error) {
+	if p := os.Getenv(backupPassphraseEnv); p != "" {
+		return p, nil
+	}
+	if prompt.NonInteractive {
+		return "", errs.New(errs.KindUsage, "set %s to encrypt or decrypt the .env non-interactively (or --no-env when backing up)", backupPassphraseEnv)
+	}
+	p := prompt.Secret("Passphrase for the .env")
+	if p == "" {
+		return "", errs.New(errs.KindUsage, "a passphrase is required for the .env (or --no-env when backing up)")
+	}
+	if confirm && prompt.Secret("Repeat passphrase") != p {
+		return "", errs.New(errs.KindUsage, "passphrases don't match")
+	}
+	return p, nil
+}
+
+// cloneWorkspaceRepos clones each repo in the manifest that isn't in the workspace yet
+func cloneWorkspaceRepos(wsPath string, ws *workspace.Workspace) error {
+	names := make([]string, 0, len(ws.Repos))
+	for name := range ws.Repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failed int
+	for _, name := range names {
+		repo := ws.Repos[name]
+		dir := filepath.Join(wsPath, repo.Path)
+		if _, err := os.Stat(dir); err == nil {
+			continue
+		}
+		if repo.Remote == "" {
+			ui.Skip.Printf("%s: no remote in the manifest\n", name)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return err
+		}
+		fmt.Printf("Cloning %s into %s...\n", repo.Remote, repo.Path)
+		var err error
+		if strings.HasPrefix(repo.Remote, "https://") {
+			err = git.CloneWithToken(repo.Remote, dir, resolveGitHubToken())
+		} else {
+			err = git.Clone(repo.Remote, dir)
+		}
+		if err != nil {
+			ui.Fail.Printf("%s: git clone failed: %v\n", name, err)
+			failed++
+			continue
+		}
+		initRepoExtras(dir)
+	}
+	if failed > 0 {
+		return errs.New(errs.KindCommandFailed, "%d repo(s) failed to clone — retry with 'spark-cli use <repo>'", failed)
+	}
+	fmt.Println(binname.Rewrite("Install dependencies with 'spark-cli workspace sync -i'"))
+	return nil
+}
+
+func init() {
+	workspaceBackupCmd.Flags().BoolVar(&backupNoEnv, "no-env", false, "Leave the .env out of the backup")
+	workspaceBackupCmd.Flags().StringVar(&backupProfile, "profile", "", "AWS profile for an s3:// destination (default: the resolved aws_profile)")
+	workspaceBackupCmd.Flags().StringVar(&backupRegion, "region", "", "AWS region for an s3:// destination (default: the resolved aws_region)")
+	workspaceRestoreCmd.Flags().StringVar(&backupProfile, "profile", "", "AWS profile for an s3:// backup")
+	workspaceRestoreCmd.Flags().StringVar(&backupRegion, "region", "", "AWS region for an s3:// backup")
+	workspaceRestoreCmd.Flags().BoolVar(&restoreNoClone, "no-clone", false, "Don't clone the workspace's repos")
+	workspaceRestoreCmd.Flags().BoolVar(&restoreForce, "force", false, "Overwrite the metadata of an existing workspace")
+	workspaceCmd.AddCommand(workspaceBackupCmd, workspaceRestoreCmd)
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/metrics"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var workspacesPrune bool
+
+var workspacesCmd = &cobra.Command{
+	Use:   "workspaces",
+	Short: "List registered workspaces with repo, dirty, env, and sync status",
+	Long: `Lists every workspace registered in ~/.spk/config.json: its path, number of
+repos, how many have uncommitted changes, the active environment, and when it
+was last synced. The workspace commands use from here is marked with *.
+
+Registrations whose directory (or manifest) no longer exists are flagged;
+--prune removes them. A manifest that exists but can't be read is reported and
+kept.
+
+Examples:
+  spark-cli workspaces
+  spark-cli workspaces --prune`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadGlobal()
+		if err != nil {
+			return err
+		}
+		if len(cfg.Workspaces) == 0 {
+			fmt.Println(binname.Rewrite("No workspaces registered — create one with 'spark-cli workspace create <path>'"))
+			return nil
+		}
+		current, _ := workspace.Find()
+
+		fmt.Printf("  %-20s %-6s %-6s %-10s %-12s %s\n", "NAME", "REPOS", "DIRTY", "ENV", "LAST SYNC", "PATH")
+		var kept, gone []string
+		for _, wsPath := range cfg.Workspaces {
+			// Only a manifest that's not there counts as missing — a parse or permission
+			// error (or an unmounted volume) is reported but never pruned
+			if _, err := os.Stat(workspace.ManifestPath(wsPath)); os.IsNotExist(err) {
+				ui.Fail.Printf("%-20s %-6s %-6s %-10s %-12s %s\n", filepath.Base(wsPath), "-", "-", "-", "missing", wsPath)
+				gone = append(gone, wsPath)
+				continue
+			}
+			kept = append(kept, wsPath)
+			ws, err := workspace.Load(wsPath)
+			if err != nil {
+				ui.Warn.Printf("%-20s %-6s %-6s %-10s %-12s %s — %v\n", filepath.Base(wsPath), "-", "-", "-", "unreadable", wsPath, err)
+				continue
+			}
+
+			var dirty atomic.Int32
+			names, _ := expandRepoNames(ws, nil)
+			forEachRepo(ws, names, func(_ int, name string) {
+				repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+				if git.IsRepo(repoDir) && git.IsDirty(repoDir) {
+					dirty.Add(1)
+				}
+			})
+			mark := " "
+			if wsPath == current {
+				mark = "*"
+			}
+			fmt.Printf("%s %-20s %-6d %-6d %-10s %-12s %s\n", mark, ws.Name, len(ws.Repos), dirty.Load(), activeEnv(ws, ""), lastSyncAge(wsPath), wsPath)
+		}
+
+		if len(gone) == 0 {
+			return nil
+		}
+		if !workspacesPrune {
+			fmt.Printf(binname.Rewrite("\n%d registration(s) point at missing workspaces — 'spark-cli workspaces --prune' to remove them\n"), len(gone))
+			return nil
+		}
+		cfg.Workspaces = kept
+		for _, p := range gone {
+			if cfg.CurrentWorkspace == p {
+				cfg.CurrentWorkspace = ""
+			}
+		}
+		if err := config.SaveGlobal(cfg); err != nil {
+			return err
+		}
+		fmt.Println()
+		ui.OK.Printf("Removed %d missing workspace(s) from ~/.spk/config.json\n", len(gone))
+		return nil
+	},
+}
+
+// lastSyncAge returns how long ago the workspace last synced a repo, from its metrics
+func lastSyncAge(wsPath string) string {
+	entries, _ := metrics.Load(wsPath)
+	var last time.Time
+	for _, e := range entries {
+		if e.Op != "sync" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, e.Time); err == nil && t.After(last) {
+			last = t
+		}
+	}
+	if last.IsZero() {
+		return "never"
+	}
+	return formatAge(time.Since(last))
+}
+
+// formatAge renders d as a short "3h ago"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+func init() {
+	workspacesCmd.Flags().BoolVar(&workspacesPrune, "prune", false, "Remove registrations whose workspace no longer exists")
+	rootCmd.AddCommand(workspacesCmd)
+}
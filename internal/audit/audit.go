@@ -0,0 +1,192 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+)
+
+// Severities from least to most severe
+var Severities = []string{"info", "low", "moderate", "high", "critical"}
+
+// SeverityRank orders severities; unknown severities rank lowest (-1)
+func SeverityRank(s string) int {
+	s = strings.ToLower(s)
+	if s == "medium" {
+		s = "moderate"
+	}
+	for i, sev := range Severities {
+		if sev == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// Finding is one vulnerable package, merged across the repos it appears in
+type Finding struct {
+	Package  string   `json:"package"`
+	Severity string   `json:"severity"`
+	Titles   []string `json:"titles,omitempty"`
+	URLs     []string `json:"urls,omitempty"`
+	Repos    []string `json:"repos"`
+	Fixable  bool     `json:"fix_available"`
+}
+
+// NpmAudit runs `npm audit --json` in dir. npm exits non-zero when it finds anything, so
+// only unparseable output is treated as an error.
+func NpmAudit(dir string) ([]Finding, error) {
+	cmd := proc.Command("npm", "audit", "--json")
+	cmd.Dir = dir
+	out, _ := cmd.Output()
+
+	var report struct {
+		Vulnerabilities map[string]struct {
+			Name         string            `json:"name"`
+			Severity     string            `json:"severity"`
+			Via          []json.RawMessage `json:"via"`
+			FixAvailable json.RawMessage   `json:"fixAvailable"`
+		} `json:"vulnerabilities"`
+		Error *struct {
+			Summary string `json:"summary"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(out, &report); err != nil {
+		return nil, fmt.Errorf("npm audit produced no report (is node_modules/package-lock.json present?)")
+	}
+	if report.Error != nil {
+		return nil, fmt.Errorf("npm audit: %s", report.Error.Summary)
+	}
+
+	var findings []Finding
+	for name, v := range report.Vulnerabilities {
+		f := Finding{
+			Package:  name,
+			Severity: v.Severity,
+			Fixable:  string(v.FixAvailable) != "" && string(v.FixAvailable) != "false",
+		}
+		for _, raw := range v.Via {
+			var advisory struct {
+				Title string `json:"title"`
+				URL   string `json:"url"`
+			}
+			// via holds either advisory objects or names of vulnerable dependencies
+			if json.Unmarshal(raw, &advisory) == nil && advisory.Title != "" {
+				f.Titles = append(f.Titles, advisory.Title)
+				f.URLs = append(f.URLs, advisory.URL)
+			}
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+// OSVAvailable reports whether osv-scanner is installed
+func OSVAvailable() bool {
+	_, err := exec.LookPath("osv-scanner")
+	return err == nil
+}
+
+// OSVScan runs osv-scanner over dir (covers Gradle/Maven and other lockfiles npm audit can't)
+func OSVScan(dir string) ([]Finding, error) {
+	cmd := proc.Command("osv-scanner", "--format", "json", "-r", dir)
+	out, _ := cmd.Output()
+
+	var report struct {
+		Results []struct {
+			Packages []struct {
+				Package struct {
+					Name string `json:"name"`
+				} `json:"package"`
+				Vulnerabilities []struct {
+					ID       string `json:"id"`
+					Summary  string `json:"summary"`
+					Database struct {
+						Severity string `json:"severity"`
+					} `json:"database_specific"`
+				} `json:"vulnerabilities"`
+			} `json:"packages"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(out, &report); err != nil {
+		return nil, fmt.Errorf("osv-scanner produced no report")
+	}
+
+	var findings []Finding
+	for _, r := range report.Results {
+		for _, p := range r.Packages {
+			f := Finding{Package: p.Package.Name, Severity: "unknown"}
+			for _, v := range p.Vulnerabilities {
+				if SeverityRank(v.Database.Severity) > SeverityRank(f.Severity) {
+					f.Severity = strings.ToLower(v.Database.Severity)
+				}
+				f.Titles = append(f.Titles, v.Summary)
+				f.URLs = append(f.URLs, "https://osv.dev/"+v.ID)
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+// Merge dedupes per-repo findings by package, keeping the highest severity and every
+// repo and advisory it appears in. Results are sorted most severe first.
+func Merge(perRepo map[string][]Finding) []Finding {
+	byPkg := make(map[string]*Finding)
+	for repo, findings := range perRepo {
+		for _, f := range findings {
+			m, ok := byPkg[f.Package]
+			if !ok {
+				m = &Finding{Package: f.Package, Severity: f.Severity}
+				byPkg[f.Package] = m
+			}
+			if SeverityRank(f.Severity) > SeverityRank(m.Severity) {
+				m.Severity = f.Severity
+			}
+			m.Fixable = m.Fixable || f.Fixable
+			m.Repos = appendUnique(m.Repos, repo)
+			for i, t := range f.Titles {
+				if !contains(m.Titles, t) {
+					m.Titles = append(m.Titles, t)
+					if i < len(f.URLs) {
+						m.URLs = append(m.URLs, f.URLs[i])
+					}
+				}
+			}
+		}
+	}
+
+	merged := make([]Finding, 0, len(byPkg))
+	for _, f := range byPkg {
+		sort.Strings(f.Repos)
+		merged = append(merged, *f)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		ri, rj := SeverityRank(merged[i].Severity), SeverityRank(merged[j].Severity)
+		if ri != rj {
+			return ri > rj
+		}
+		return merged[i].Package < merged[j].Package
+	})
+	return merged
+}
+
+func appendUnique(list []string, s string) []string {
+	if contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
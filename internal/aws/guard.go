@@ -0,0 +1,56 @@
+package aws
+
+import "fmt"
+
+// envAccountAliases maps environment names that don't appear in KnownSSOAccounts to the
+// account they live in (the pipeline stacks are deployed from the central account)
+var envAccountAliases = map[string]string{
+	"pipeline": "central",
+}
+
+// ExpectedAccount returns the account ID an environment must run against
+func ExpectedAccount(env string) (string, bool) {
+	if alias, ok := envAccountAliases[env]; ok {
+		env = alias
+	}
+	for _, a := range KnownSSOAccounts {
+		if a.Name == env {
+			return a.Account, true
+		}
+	}
+	return "", false
+}
+
+// VerifyAccount refuses to continue unless the profile's STS account matches the account
+// expected for env. Unknown environments are not checked.
+func VerifyAccount(profile, env string) error {
+	expected, ok := ExpectedAccount(env)
+	if !ok {
+		return nil
+	}
+	return VerifyAccountID(profile, env, expected)
+}
+
+// VerifyAccountID is VerifyAccount with an explicit expected account ID
+func VerifyAccountID(profile, env, expected string) error {
+	id, err := CallerIdentity(profile)
+	if err != nil {
+		return fmt.Errorf("could not verify AWS account for %s (profile: %s): %w", env, orDefaultProfile(profile), err)
+	}
+	if id.Account != expected {
+		actual := id.Account
+		if name := AccountName(id.Account); name != "" {
+			actual = fmt.Sprintf("%s (%s)", id.Account, name)
+		}
+		return fmt.Errorf("account mismatch: env %q expects account %s but profile %s is %s — refusing to run",
+			env, expected, orDefaultProfile(profile), actual)
+	}
+	return nil
+}
+
+func orDefaultProfile(profile string) string {
+	if profile == "" {
+		return "default"
+	}
+	return profile
+}
@@ -1,14 +1,18 @@
 package aws
 
 import (
-	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
-	"strconv"
+	"runtime"
 	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/prompt"
 )
 
 // SSOAccount holds a known AWS account for SSO setup reference
@@ -24,14 +28,40 @@ var KnownSSOAccounts = []SSOAccount{
 	{Name: "central", Account: "417975668372"},
 }
 
-// SSOLogin runs `aws sso login` with the given profile
+// loginDisabled makes SSOLogin fail instead of starting a login; set by --no-login
+var loginDisabled bool
+
+// DisableLogin makes SSOLogin fail fast with instructions instead of opening a browser
+func DisableLogin(disabled bool) {
+	loginDisabled = disabled
+}
+
+// Headless reports whether there's no local browser to open (an SSH session, or Linux
+// without a display)
+func Headless() bool {
+	if os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != "" {
+		return true
+	}
+	return runtime.GOOS == "linux" && os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == ""
+}
+
+// SSOLogin runs `aws sso login` with the given profile. On a headless machine it uses the
+// device-code flow and prints the URL and code to enter on any other device.
 func SSOLogin(profile string) error {
 	args := []string{"sso", "login"}
 	if profile != "" {
 		args = append(args, "--profile", profile)
 	}
+	if loginDisabled {
+		return fmt.Errorf("AWS session for profile %s has expired and --no-login is set — run 'aws %s' (add --use-device-code on a headless machine)",
+			orDefaultProfile(profile), strings.Join(args, " "))
+	}
+	if Headless() {
+		fmt.Println("No browser available — open the URL below on any device and enter the code")
+		args = append(args, "--use-device-code", "--no-browser")
+	}
 
-	cmd := exec.Command("aws", args...)
+	cmd := proc.Foreground("aws", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
@@ -54,7 +84,7 @@ func GetCallerIdentity(profile string) error {
 		args = append(args, "--profile", profile)
 	}
 
-	cmd := exec.Command("aws", args...)
+	cmd := proc.Command("aws", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
@@ -67,10 +97,49 @@ func GetCallerIdentityQuiet(profile string) error {
 		args = append(args, "--profile", profile)
 	}
 
-	cmd := exec.Command("aws", args...)
+	cmd := proc.Command("aws", args...)
 	return cmd.Run()
 }
 
+// Identity is the result of `aws sts get-caller-identity`
+type Identity struct {
+	Account string `json:"Account"`
+	Arn     string `json:"Arn"`
+	UserID  string `json:"UserId"`
+}
+
+// CallerIdentity returns the STS caller identity for a profile
+func CallerIdentity(profile string) (*Identity, error) {
+	args := []string{"sts", "get-caller-identity", "--output", "json"}
+	if profile != "" {
+		args = append(args, "--profile", profile)
+	}
+
+	out, err := proc.Command("aws", args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+
+	var id Identity
+	if err := json.Unmarshal(out, &id); err != nil {
+		return nil, fmt.Errorf("failed to parse caller identity: %w", err)
+	}
+	return &id, nil
+}
+
+// AccountName maps an account ID to its KnownSSOAccounts name (e.g. "beta"), or "" if unknown
+func AccountName(accountID string) string {
+	for _, a := range KnownSSOAccounts {
+		if a.Account == accountID {
+			return a.Name
+		}
+	}
+	return ""
+}
+
 // GetSSOProfiles returns a list of SSO-configured profiles from ~/.aws/config
 func GetSSOProfiles() []string {
 	configPath := filepath.Join(os.Getenv("HOME"), ".aws", "config")
@@ -143,24 +212,12 @@ func PromptProfileSelection() (string, error) {
 		return "", fmt.Errorf("no SSO profiles found")
 	}
 
-	reader := bufio.NewReader(os.Stdin)
-
-	fmt.Println("\nAvailable SSO profiles:")
-	for i, p := range profiles {
-		fmt.Printf("  %d. %s\n", i+1, p)
-	}
 	fmt.Println()
-
-	fmt.Print("Enter the number of the profile to use: ")
-	input, _ := reader.ReadString('\n')
-	input = strings.TrimSpace(input)
-
-	idx, err := strconv.Atoi(input)
-	if err != nil || idx < 1 || idx > len(profiles) {
-		return "", fmt.Errorf("invalid selection — enter a number between 1 and %d", len(profiles))
+	idx, err := prompt.Select("Available SSO profiles:", profiles)
+	if err != nil {
+		return "", err
 	}
-
-	return profiles[idx-1], nil
+	return profiles[idx], nil
 }
 
 // PrintSSOAccountReference prints the known AWS account IDs (for identifying accounts in the wizard list)
@@ -175,7 +232,7 @@ func PrintSSOAccountReference() {
 
 // RunConfigureSSO runs `aws configure sso` interactively (wrapper for first-time or new profile setup)
 func RunConfigureSSO() error {
-	cmd := exec.Command("aws", "configure", "sso")
+	cmd := proc.Foreground("aws", "configure", "sso")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
@@ -213,16 +270,16 @@ func ShowSSOSetupInstructionsNoRun() {
 	fmt.Println("  No SSO profiles found in ~/.aws/config")
 	fmt.Println()
 	PrintSSOAccountReference()
-	fmt.Println("  Run 'spark-cli workspace configure sso' to set up a profile (runs aws configure sso),")
-	fmt.Println("  or run 'aws configure sso' yourself. Then: spark-cli workspace configure --profile <name>")
+	fmt.Println(binname.Rewrite("  Run 'spark-cli workspace configure sso' to set up a profile (runs aws configure sso),"))
+	fmt.Println(binname.Rewrite("  or run 'aws configure sso' yourself. Then: spark-cli workspace configure --profile <name>"))
 	fmt.Println()
 }
 
 // ShowSSOSetupInstructionsShort prints account reference + short blurb for adding a profile
 func ShowSSOSetupInstructionsShort() {
 	fmt.Println()
-	fmt.Println("  To add another profile: spark-cli workspace configure sso")
+	fmt.Println(binname.Rewrite("  To add another profile: spark-cli workspace configure sso"))
 	PrintSSOAccountReference()
-	fmt.Println("  Then: spark-cli workspace configure --profile <profile-name>")
+	fmt.Println(binname.Rewrite("  Then: spark-cli workspace configure --profile <profile-name>"))
 	fmt.Println()
 }
@@ -0,0 +1,42 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+)
+
+// S3Copy runs `aws s3 cp src dst`; either side may be a local path or an s3:// URL
+func S3Copy(profile, region, src, dst string) error {
+	args := []string{"s3", "cp", src, dst, "--only-show-errors"}
+	if profile != "" {
+		args = append(args, "--profile", profile)
+	}
+	if region != "" {
+		args = append(args, "--region", region)
+	}
+
+	cmd := proc.Command("aws", args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("aws s3 cp %s: %s", src, msg)
+	}
+	return nil
+}
+
+// S3Exists reports whether an object exists at an s3:// URL
+func S3Exists(profile, region, url string) bool {
+	args := []string{"s3", "ls", url}
+	if profile != "" {
+		args = append(args, "--profile", profile)
+	}
+	if region != "" {
+		args = append(args, "--region", region)
+	}
+	return proc.Command("aws", args...).Run() == nil
+}
@@ -0,0 +1,79 @@
+package aws
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SSOExpiry returns when the cached SSO token for profile expires, read from the AWS
+// CLI's ~/.aws/sso/cache. ok is false when the profile isn't SSO or has never logged in.
+func SSOExpiry(profile string) (expires time.Time, ok bool) {
+	profile = orDefaultProfile(profile)
+	header := "profile " + profile
+	if profile == "default" {
+		header = "default"
+	}
+	section := configSection(header)
+	startURL := section["sso_start_url"]
+	if session := section["sso_session"]; session != "" {
+		startURL = configSection("sso-session " + session)["sso_start_url"]
+	}
+	if startURL == "" {
+		return time.Time{}, false
+	}
+
+	files, _ := filepath.Glob(filepath.Join(os.Getenv("HOME"), ".aws", "sso", "cache", "*.json"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var token struct {
+			StartURL    string `json:"startUrl"`
+			ExpiresAt   string `json:"expiresAt"`
+			AccessToken string `json:"accessToken"`
+		}
+		if json.Unmarshal(data, &token) != nil || token.StartURL != startURL || token.AccessToken == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, token.ExpiresAt)
+		if err != nil {
+			// Older CLIs wrote "2024-01-02T03:04:05UTC"
+			if t, err = time.Parse("2006-01-02T15:04:05UTC", token.ExpiresAt); err != nil {
+				continue
+			}
+		}
+		if t.After(expires) {
+			expires, ok = t, true
+		}
+	}
+	return expires, ok
+}
+
+// configSection returns the key/value pairs of a ~/.aws/config section, e.g.
+// "profile dev" or "sso-session spark"
+func configSection(name string) map[string]string {
+	values := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(os.Getenv("HOME"), ".aws", "config"))
+	if err != nil {
+		return values
+	}
+	inSection := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inSection = strings.TrimSpace(line[1:len(line)-1]) == name
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if key, value, found := strings.Cut(line, "="); found {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,304 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+)
+
+// InfoFile describes the backup; it is the first entry in the archive
+const InfoFile = "spk-backup.json"
+
+// encryptedEnv is the archive entry holding the workspace .env, encrypted
+const encryptedEnv = ".env.enc"
+
+// skipDirs are the .spk dirs left out of a backup: they're large or rebuilt on demand
+var skipDirs = []string{"store", "logs", "cache", "compose"}
+
+// Encrypted .env layout: magic, salt, nonce, then AES-256-GCM ciphertext
+const (
+	magic      = "SPKENC1\n"
+	saltSize   = 16
+	iterations = 600000
+)
+
+// ErrPassphrase means the .env couldn't be decrypted with the passphrase given
+var ErrPassphrase = errors.New("wrong passphrase, or the backup is corrupt")
+
+// Info is what a backup holds
+type Info struct {
+	Workspace string    `json:"workspace"`
+	CreatedAt time.Time `json:"created_at"`
+	Host      string    `json:"host,omitempty"`
+	// Env reports whether the workspace .env is in the backup (encrypted)
+	Env   bool     `json:"env"`
+	Files []string `json:"files"`
+}
+
+// Create writes a gzipped tar of wsPath's .spk dir, .code-workspace files, and, when
+// passphrase isn't empty, its .env encrypted with passphrase
+func Create(w io.Writer, wsPath, name, passphrase string) (*Info, error) {
+	info := &Info{Workspace: name, CreatedAt: time.Now().UTC()}
+	info.Host, _ = os.Hostname()
+
+	files, err := collect(wsPath)
+	if err != nil {
+		return nil, err
+	}
+	var env []byte
+	if passphrase != "" {
+		plain, err := os.ReadFile(filepath.Join(wsPath, ".env"))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err == nil {
+			if env, err = encrypt(plain, passphrase); err != nil {
+				return nil, err
+			}
+			info.Env = true
+		}
+	}
+	info.Files = files
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeEntry(tw, InfoFile, data, 0644); err != nil {
+		return nil, err
+	}
+	for _, rel := range files {
+		path := filepath.Join(wsPath, rel)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		st, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeEntry(tw, filepath.ToSlash(rel), data, st.Mode().Perm()); err != nil {
+			return nil, err
+		}
+	}
+	if env != nil {
+		if err := writeEntry(tw, encryptedEnv, env, 0600); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return info, gz.Close()
+}
+
+// ReadInfo returns the description of the backup in r
+func ReadInfo(r io.Reader) (*Info, error) {
+	var info *Info
+	err := walk(r, func(name string, _ fs.FileMode, data []byte) error {
+		if name == InfoFile {
+			info = &Info{}
+			if err := json.Unmarshal(data, info); err != nil {
+				return fmt.Errorf("unreadable %s: %w", InfoFile, err)
+			}
+			return io.EOF
+		}
+		return nil
+	})
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("not a spark-cli backup (no %s)", InfoFile)
+	}
+	return info, nil
+}
+
+// Restore unpacks the backup in r into wsPath. The .env is decrypted with passphrase;
+// with an empty passphrase it is left out. Nothing is written if decryption fails.
+func Restore(r io.Reader, wsPath, passphrase string) error {
+	type entry struct {
+		path string
+		mode fs.FileMode
+		data []byte
+	}
+	var entries []entry
+	var env []byte
+	err := walk(r, func(name string, mode fs.FileMode, data []byte) error {
+		switch name {
+		case InfoFile:
+			return nil
+		case encryptedEnv:
+			if passphrase == "" {
+				return nil
+			}
+			plain, err := decrypt(data, passphrase)
+			if err != nil {
+				return err
+			}
+			env = plain
+			return nil
+		}
+		rel := filepath.FromSlash(name)
+		if !filepath.IsLocal(rel) {
+			return fmt.Errorf("backup entry %q is outside the workspace", name)
+		}
+		entries = append(entries, entry{filepath.Join(wsPath, rel), mode, data})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(wsPath, 0755); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := os.MkdirAll(filepath.Dir(e.path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(e.path, e.data, e.mode); err != nil {
+			return err
+		}
+	}
+	if env != nil {
+		return os.WriteFile(filepath.Join(wsPath, ".env"), env, 0600)
+	}
+	return nil
+}
+
+// collect returns the workspace-relative files a backup holds, .spk first
+func collect(wsPath string) ([]string, error) {
+	var files []string
+	sparkDir := filepath.Join(wsPath, config.SparkDir)
+	err := filepath.WalkDir(sparkDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(wsPath, path)
+		if d.IsDir() {
+			if filepath.Dir(path) == sparkDir && slices.Contains(skipDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	extras, _ := filepath.Glob(filepath.Join(wsPath, "*.code-workspace"))
+	for _, path := range extras {
+		files = append(files, filepath.Base(path))
+	}
+	return files, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, mode fs.FileMode) error {
+	hdr := &tar.Header{Name: name, Mode: int64(mode), Size: int64(len(data)), ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// walk calls fn with each regular file in the gzipped tar in r, stopping at fn's first error
+func walk(r io.Reader, fn func(name string, mode fs.FileMode, data []byte) error) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("not a spark-cli backup: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if err := fn(strings.TrimPrefix(hdr.Name, "./"), fs.FileMode(hdr.Mode).Perm(), data); err != nil {
+			return err
+		}
+	}
+}
+
+func encrypt(plain []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte(magic), salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plain, []byte(magic)), nil
+}
+
+func decrypt(data []byte, passphrase string) ([]byte, error) {
+	if !strings.HasPrefix(string(data), magic) || len(data) < len(magic)+saltSize {
+		return nil, ErrPassphrase
+	}
+	data = data[len(magic):]
+	gcm, err := newGCM(passphrase, data[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	data = data[saltSize:]
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrPassphrase
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(magic))
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+	return plain, nil
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
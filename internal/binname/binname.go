@@ -0,0 +1,81 @@
+package binname
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Canonical is the name the binary is built and documented as; Alias is the short name
+// installed next to it. Both run the same command tree.
+const (
+	Canonical = "spark-cli"
+	Alias     = "spk"
+)
+
+var (
+	once sync.Once
+	name string
+)
+
+// Name returns the name spark-cli was invoked as (from os.Args[0]): Alias or Canonical.
+// Any other name, like a renamed or test binary, counts as Canonical.
+func Name() string {
+	once.Do(func() {
+		name = Canonical
+		if len(os.Args) > 0 && strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe") == Alias {
+			name = Alias
+		}
+	})
+	return name
+}
+
+// Rewrite replaces the command name in s — "spark-cli" as a word of its own, as in
+// "run 'spark-cli use <repo>'" — with the name spark-cli was invoked as. Paths and file
+// names that contain it, like spark-cli.fish or homebrew-spark-cli, are left alone.
+func Rewrite(s string) string {
+	if Name() == Canonical || !strings.Contains(s, Canonical) {
+		return s
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, Canonical)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		end := i + len(Canonical)
+		b.WriteString(s[:i])
+		if standalone(s, i, end) {
+			b.WriteString(Name())
+		} else {
+			b.WriteString(Canonical)
+		}
+		s = s[end:]
+	}
+}
+
+// standalone reports whether s[i:end] is a word of its own rather than part of a longer
+// word, path, or file name. An underscore before it is allowed (zsh completion files are
+// named _<command>), as is a period ending a sentence after it.
+func standalone(s string, i, end int) bool {
+	if i > 0 {
+		if c := s[i-1]; isWordByte(c) || c == '-' || c == '.' || c == '/' {
+			return false
+		}
+	}
+	if end < len(s) {
+		switch c := s[end]; {
+		case isWordByte(c) || c == '-' || c == '_':
+			return false
+		case c == '.':
+			return end+1 == len(s) || !isWordByte(s[end+1])
+		}
+	}
+	return true
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,405 @@
+package buildcache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+)
+
+// DefaultOutputs are the build output dirs cached when a repo doesn't list cache_outputs
+var DefaultOutputs = []string{"smithy/build", "dist"}
+
+// ErrDirty means the repo has uncommitted changes, so its sources can't be keyed
+var ErrDirty = errors.New("uncommitted changes")
+
+// Remote is a resolved remote cache location
+type Remote struct {
+	Bucket  string
+	Prefix  string
+	Profile string
+	Region  string
+}
+
+// URL returns the s3:// URL of a repo's artifact for key
+func (r Remote) URL(repo, key string) string {
+	prefix := strings.Trim(r.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return fmt.Sprintf("s3://%s/%s%s/%s.tar.gz", r.Bucket, prefix, repo, key)
+}
+
+// Key hashes a repo's committed sources (HEAD tree) together with the keys of the repos it
+// depends on, so a model change invalidates everything generated from it
+func Key(repoDir string, depKeys []string) (string, error) {
+	if git.IsDirty(repoDir) {
+		return "", ErrDirty
+	}
+	tree, err := git.TreeHash(repoDir)
+	if err != nil {
+		return "", err
+	}
+	deps := append([]string(nil), depKeys...)
+	sort.Strings(deps)
+
+	h := sha256.New()
+	io.WriteString(h, tree)
+	for _, d := range deps {
+		io.WriteString(h, "\n"+d)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Outputs returns the output dirs that exist in repoDir out of the configured ones
+func Outputs(repoDir string, configured []string) []string {
+	var present []string
+	for _, dir := range outputDirs(configured) {
+		if info, err := os.Stat(filepath.Join(repoDir, dir)); err == nil && info.IsDir() {
+			present = append(present, dir)
+		}
+	}
+	return present
+}
+
+// Stale reports whether a tracked source file was modified after the newest file in the
+// repo's build outputs. ok is false when there are no outputs to compare against.
+func Stale(repoDir string, configured []string) (stale, ok bool) {
+	present := Outputs(repoDir, configured)
+	if len(present) == 0 {
+		return false, false
+	}
+	var built time.Time
+	for _, dir := range present {
+		if t := NewestModTime(filepath.Join(repoDir, dir)); t.After(built) {
+			built = t
+		}
+	}
+	if built.IsZero() {
+		return false, false
+	}
+	_, changed, ok := NewestSource(repoDir, configured)
+	if !ok {
+		return false, false
+	}
+	return changed.After(built), true
+}
+
+// NewestModTime returns the latest modification time of a file under dir, or the zero
+// time if it has none
+func NewestModTime(dir string) time.Time {
+	var newest time.Time
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		return nil
+	})
+	return newest
+}
+
+// NewestSource returns the most recently modified tracked file in repoDir outside its
+// build outputs, and when it changed. ok is false when the tracked files can't be listed.
+func NewestSource(repoDir string, configured []string) (path string, changed time.Time, ok bool) {
+	files, err := git.TrackedFiles(repoDir)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	outputs := outputDirs(configured)
+	for _, f := range files {
+		if underAny(f, outputs) {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(repoDir, f)); err == nil && info.ModTime().After(changed) {
+			path, changed = f, info.ModTime()
+		}
+	}
+	return path, changed, true
+}
+
+func underAny(path string, dirs []string) bool {
+	path = filepath.ToSlash(path)
+	for _, dir := range dirs {
+		dir = strings.TrimSuffix(filepath.ToSlash(dir), "/")
+		if path == dir || strings.HasPrefix(path, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func outputDirs(configured []string) []string {
+	if len(configured) == 0 {
+		return DefaultOutputs
+	}
+	return configured
+}
+
+// CheckOutputs rejects configured output dirs that aren't strictly inside the repo: the
+// repo root itself (".") or anything reaching outside it would be wiped on a cache hit
+func CheckOutputs(configured []string) error {
+	for _, dir := range configured {
+		clean := filepath.Clean(filepath.FromSlash(dir))
+		if !filepath.IsLocal(clean) || clean == "." {
+			return fmt.Errorf("cache_outputs entry %q must be a directory inside the repo", dir)
+		}
+	}
+	return nil
+}
+
+// Fetch downloads a repo's artifact and unpacks it into repoDir, replacing the configured
+// output dirs. The artifact is unpacked beside them first, so the current outputs are
+// only replaced once it has unpacked cleanly. It returns false on a cache miss.
+func Fetch(r Remote, repo, key, repoDir string, configured []string) (bool, error) {
+	if err := CheckOutputs(configured); err != nil {
+		return false, err
+	}
+	url := r.URL(repo, key)
+	if !aws.S3Exists(r.Profile, r.Region, url) {
+		return false, nil
+	}
+
+	tmp, err := os.CreateTemp("", "spk-cache-*.tar.gz")
+	if err != nil {
+		return false, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := aws.S3Copy(r.Profile, r.Region, url, tmp.Name()); err != nil {
+		return false, err
+	}
+
+	staging, err := os.MkdirTemp(repoDir, ".spk-cache-")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(staging)
+
+	dirs := outputDirs(configured)
+	if err := extract(tmp.Name(), staging, dirs); err != nil {
+		return false, fmt.Errorf("failed to unpack cached artifact: %w", err)
+	}
+	if err := swapIn(staging, repoDir, dirs); err != nil {
+		return false, fmt.Errorf("failed to replace build outputs: %w", err)
+	}
+	return true, nil
+}
+
+// swapIn replaces each output dir in repoDir with its unpacked copy in staging (or
+// removes it when the artifact has none). Dirs nested in another output dir move with it.
+func swapIn(staging, repoDir string, dirs []string) error {
+	for _, dir := range dirs {
+		dir = filepath.Clean(filepath.FromSlash(dir))
+		if nestedOutput(dir, dirs) {
+			continue
+		}
+		if link := symlinkOnPath(repoDir, filepath.Dir(dir)); link != "" {
+			return fmt.Errorf("refusing to replace %s through the symlink %s", filepath.ToSlash(dir), link)
+		}
+		dest := filepath.Join(repoDir, dir)
+		if err := os.RemoveAll(dest); err != nil {
+			return err
+		}
+		src := filepath.Join(staging, dir)
+		if _, err := os.Lstat(src); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := os.Rename(src, dest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nestedOutput reports whether dir lies inside another of the output dirs
+func nestedOutput(dir string, dirs []string) bool {
+	for _, other := range dirs {
+		other = filepath.Clean(filepath.FromSlash(other))
+		if other != dir && underAny(dir, []string{other}) {
+			return true
+		}
+	}
+	return false
+}
+
+// Push archives outputs (relative to repoDir) and uploads them under key
+func Push(r Remote, repo, key, repoDir string, outputs []string) error {
+	if len(outputs) == 0 {
+		return fmt.Errorf("no build outputs to cache")
+	}
+	if err := CheckOutputs(outputs); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "spk-cache-*.tar.gz")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := archive(tmp, repoDir, outputs); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to archive build outputs: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return aws.S3Copy(r.Profile, r.Region, tmp.Name(), r.URL(repo, key))
+}
+
+func archive(w io.Writer, repoDir string, outputs []string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, out := range outputs {
+		root := filepath.Join(repoDir, out)
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(repoDir, path)
+			if err != nil {
+				return err
+			}
+
+			link := ""
+			if info.Mode()&os.ModeSymlink != 0 {
+				if link, err = os.Readlink(path); err != nil {
+					return err
+				}
+			}
+			hdr, err := tar.FileInfoHeader(info, link)
+			if err != nil {
+				return err
+			}
+			hdr.Name = filepath.ToSlash(rel)
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(tw, f)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// extract unpacks an archive into dir. Every entry has to fall under one of the
+// output dirs, and none may be written through a symlink — whether the archive made it
+// or it was already there — so an artifact can't reach outside them.
+func extract(path, dir string, dirs []string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("refusing to unpack %q outside the repo", hdr.Name)
+		}
+		if !underAny(name, dirs) {
+			return fmt.Errorf("refusing to unpack %q outside the cache outputs (%s)", hdr.Name, strings.Join(dirs, ", "))
+		}
+		// A symlink entry may replace nothing, so only its parents are checked
+		check := name
+		if hdr.Typeflag == tar.TypeSymlink {
+			check = filepath.Dir(name)
+		}
+		if link := symlinkOnPath(dir, check); link != "" {
+			return fmt.Errorf("refusing to unpack %q through the symlink %s", hdr.Name, link)
+		}
+		dest := filepath.Join(dir, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dest, os.FileMode(hdr.Mode)|0700); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			os.MkdirAll(filepath.Dir(dest), 0755)
+			if err := os.Symlink(hdr.Linkname, dest); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			os.MkdirAll(filepath.Dir(dest), 0755)
+			out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			out.Close()
+		}
+	}
+}
+
+// symlinkOnPath returns the first component of name (relative to repoDir), itself
+// included, that is a symlink, or ""
+func symlinkOnPath(repoDir, name string) string {
+	cur := repoDir
+	for _, part := range strings.Split(name, string(filepath.Separator)) {
+		if part == "." || part == "" {
+			continue
+		}
+		cur = filepath.Join(cur, part)
+		info, err := os.Lstat(cur)
+		if err != nil {
+			return ""
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			rel, _ := filepath.Rel(repoDir, cur)
+			return filepath.ToSlash(rel)
+		}
+	}
+	return ""
+}
@@ -13,10 +13,36 @@ const (
 )
 
 type GlobalConfig struct {
-	DefaultGithubOrg string   `json:"default_github_org"`
-	DefaultAWSProfile string  `json:"default_aws_profile"`
-	DefaultAWSRegion  string  `json:"default_aws_region"`
-	Workspaces       []string `json:"workspaces"`
+	DefaultGithubOrg  string   `json:"default_github_org"`
+	DefaultAWSProfile string   `json:"default_aws_profile"`
+	DefaultAWSRegion  string   `json:"default_aws_region"`
+	Workspaces        []string `json:"workspaces"`
+
+	// Defaults for workspaces that don't set their own (see `spark-cli config`)
+	DefaultEnv         string `json:"default_env,omitempty"`
+	DefaultLinkMode    string `json:"default_link_mode,omitempty"`
+	DefaultConcurrency int    `json:"default_concurrency,omitempty"`
+	DefaultNudgeBehind string `json:"default_nudge_behind,omitempty"`
+
+	// CurrentWorkspace is used when the current directory isn't inside a workspace
+	// (set by `spark-cli workspace switch`)
+	CurrentWorkspace string `json:"current_workspace,omitempty"`
+
+	// CloneProtocol is "ssh" or "https"; detected on first clone when empty
+	CloneProtocol string `json:"clone_protocol,omitempty"`
+
+	// Retry policy for network-bound operations (git fetch, npm install, SSM)
+	RetryMaxAttempts int `json:"retry_max_attempts,omitempty"`
+	RetryBackoffMs   int `json:"retry_backoff_ms,omitempty"`
+
+	// Notify announces builds, tests, and syncs that ran longer than NotifyAfterSeconds
+	// (default 30): "bell", "desktop", "both", or "" for off
+	Notify             string `json:"notify,omitempty"`
+	NotifyAfterSeconds int    `json:"notify_after_seconds,omitempty"`
+
+	// Aliases maps a short command name to the arguments it expands to, e.g.
+	// "b": "run build" (set with `spark-cli alias`)
+	Aliases map[string]string `json:"aliases,omitempty"`
 }
 
 // GlobalDir returns ~/.spk
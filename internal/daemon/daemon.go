@@ -0,0 +1,93 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+)
+
+// FileName is the running daemon's record inside .spk
+const FileName = "daemon.json"
+
+// Status is what a running daemon records about itself
+type Status struct {
+	PID       int    `json:"pid"`
+	StartedAt string `json:"started_at"`
+	Interval  string `json:"interval"`
+	// Socket is the JSON-RPC socket editor integrations connect to
+	Socket  string `json:"socket,omitempty"`
+	LastRun string `json:"last_run,omitempty"`
+	// LastError is the first thing that went wrong in the last run, if anything did
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Path returns .spk/daemon.json for a workspace
+func Path(wsPath string) string {
+	return filepath.Join(wsPath, config.SparkDir, FileName)
+}
+
+// LogPath returns .spk/logs/daemon.log for a workspace
+func LogPath(wsPath string) string {
+	return filepath.Join(wsPath, config.SparkDir, "logs", "daemon.log")
+}
+
+// Load reads the daemon record; it returns nil when there is none
+func Load(wsPath string) (*Status, error) {
+	data, err := os.ReadFile(Path(wsPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Status
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+	return &s, nil
+}
+
+// Save writes the daemon record atomically
+func Save(wsPath string, s *Status) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := Path(wsPath)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Remove deletes the daemon record if it belongs to pid
+func Remove(wsPath string, pid int) {
+	if s, err := Load(wsPath); err == nil && s != nil && s.PID == pid {
+		os.Remove(Path(wsPath))
+	}
+}
+
+// Running returns the record of the workspace's daemon if its process is alive
+func Running(wsPath string) *Status {
+	s, err := Load(wsPath)
+	if err != nil || s == nil || !Alive(s.PID) {
+		return nil
+	}
+	return s
+}
+
+// Alive reports whether a process with pid exists
+func Alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
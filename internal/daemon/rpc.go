@@ -0,0 +1,179 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+)
+
+// SocketName is the daemon's JSON-RPC socket inside .spk
+const SocketName = "daemon.sock"
+
+// SocketPath returns .spk/daemon.sock for a workspace
+func SocketPath(wsPath string) string {
+	return filepath.Join(wsPath, config.SparkDir, SocketName)
+}
+
+// JSON-RPC 2.0 error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeServerError    = -32000
+)
+
+// Request is a JSON-RPC 2.0 request; requests without an id are notifications and get
+// no response
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC 2.0 response
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// InvalidParams returns the error a handler reports for params it can't use
+func InvalidParams(format string, a ...any) *Error {
+	return &Error{Code: CodeInvalidParams, Message: fmt.Sprintf(format, a...)}
+}
+
+// Handler answers one method; params is the raw "params" value (nil if absent)
+type Handler func(params json.RawMessage) (any, error)
+
+// Serve answers newline-delimited JSON-RPC requests on the unix socket at path until
+// ctx is done. Each connection is served on its own goroutine, one request at a time.
+func Serve(ctx context.Context, path string, handlers map[string]Handler) error {
+	os.Remove(path) // left behind by a daemon that didn't exit cleanly
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	// Only the workspace's owner may drive its builds
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+		os.Remove(path)
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go serveConn(conn, handlers)
+	}
+}
+
+func serveConn(conn net.Conn, handlers map[string]Handler) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		resp, ok := handle(line, handlers)
+		if !ok {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handle runs one request, reporting false for notifications (no response)
+func handle(line []byte, handlers map[string]Handler) (Response, bool) {
+	resp := Response{JSONRPC: "2.0", ID: json.RawMessage("null")}
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		resp.Error = &Error{Code: CodeParseError, Message: "invalid JSON: " + err.Error()}
+		return resp, true
+	}
+	if len(req.ID) > 0 {
+		resp.ID = req.ID
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &Error{Code: CodeInvalidRequest, Message: `expected {"jsonrpc": "2.0", "method": ...}`}
+		return resp, true
+	}
+
+	h, ok := handlers[req.Method]
+	if !ok {
+		resp.Error = &Error{Code: CodeMethodNotFound, Message: "unknown method " + req.Method}
+		return resp, len(req.ID) > 0
+	}
+	result, err := h(req.Params)
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeServerError, Message: err.Error()}
+		}
+		resp.Error = rpcErr
+	} else {
+		resp.Result = result
+	}
+	return resp, len(req.ID) > 0
+}
+
+// Call sends one request to the daemon listening at path and returns its result
+func Call(path, method string, params any) (json.RawMessage, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	req := map[string]any{"jsonrpc": "2.0", "id": 1, "method": method}
+	if params != nil {
+		req["params"] = params
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *Error          `json:"error"`
+	}
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("bad response from the daemon: %w", err)
+	}
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return resp.Result, nil
+}
@@ -0,0 +1,236 @@
+package devcontainer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Dir is the devcontainer directory at the workspace root
+const Dir = ".devcontainer"
+
+// generatedMarker is stored in the config so regeneration never clobbers a hand-written one
+const generatedMarker = "Generated by spark-cli devcontainer generate"
+
+// Default tool versions when no repo pins one
+const (
+	DefaultNode = "lts"
+	DefaultJava = "17"
+	DefaultGo   = "latest"
+)
+
+// Feature references from the devcontainers/features collection
+const (
+	featureNode   = "ghcr.io/devcontainers/features/node:1"
+	featureJava   = "ghcr.io/devcontainers/features/java:1"
+	featureGo     = "ghcr.io/devcontainers/features/go:1"
+	featureAWS    = "ghcr.io/devcontainers/features/aws-cli:1"
+	featureGitHub = "ghcr.io/devcontainers/features/github-cli:1"
+	featureDocker = "ghcr.io/devcontainers/features/docker-in-docker:2"
+)
+
+// Tools are the toolchains the workspace needs; empty means not needed
+type Tools struct {
+	Node   string
+	Java   string
+	Go     string
+	Docker bool
+}
+
+// Config is the subset of devcontainer.json spark-cli writes
+type Config struct {
+	Comment           string                    `json:"//"`
+	Name              string                    `json:"name"`
+	Image             string                    `json:"image"`
+	Features          map[string]map[string]any `json:"features"`
+	WorkspaceFolder   string                    `json:"workspaceFolder,omitempty"`
+	RunArgs           []string                  `json:"runArgs,omitempty"`
+	RemoteEnv         map[string]string         `json:"remoteEnv,omitempty"`
+	Secrets           map[string]Secret         `json:"secrets,omitempty"`
+	ForwardPorts      []int                     `json:"forwardPorts,omitempty"`
+	PostCreateCommand string                    `json:"postCreateCommand,omitempty"`
+	Customizations    map[string]any            `json:"customizations,omitempty"`
+}
+
+// Secret is a Codespaces secret the workspace env expects
+type Secret struct {
+	Description string `json:"description"`
+}
+
+// NewConfig builds a config for the given tools
+func NewConfig(name, image string, tools Tools) *Config {
+	features := map[string]map[string]any{
+		featureAWS:    {},
+		featureGitHub: {},
+	}
+	if tools.Node != "" {
+		features[featureNode] = map[string]any{"version": tools.Node}
+	}
+	if tools.Java != "" {
+		features[featureJava] = map[string]any{"version": tools.Java, "installGradle": true}
+	}
+	if tools.Go != "" {
+		features[featureGo] = map[string]any{"version": tools.Go}
+	}
+	if tools.Docker {
+		features[featureDocker] = map[string]any{}
+	}
+	return &Config{
+		Comment:  generatedMarker + " — edit workspace.json and regenerate instead",
+		Name:     name,
+		Image:    image,
+		Features: features,
+	}
+}
+
+// IsGenerated reports whether the config at path was written by spark-cli
+func IsGenerated(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), generatedMarker)
+}
+
+// Load reads a devcontainer.json written by spark-cli
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Write saves the config as devcontainer.json
+func (c *Config) Write(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// NodeVersion reads a repo's pinned Node version from .nvmrc, .node-version, or .tool-versions
+func NodeVersion(repoDir string) string {
+	for _, f := range []string{".nvmrc", ".node-version"} {
+		if v := firstLine(filepath.Join(repoDir, f)); v != "" {
+			if strings.HasPrefix(v, "lts") || v == "node" {
+				return DefaultNode // nvm aliases (lts/*, lts/hydrogen, node)
+			}
+			return strings.TrimPrefix(v, "v")
+		}
+	}
+	return toolVersions(repoDir)["nodejs"]
+}
+
+// JavaVersion reads a repo's pinned Java major version from .java-version, .sdkmanrc, or
+// .tool-versions
+func JavaVersion(repoDir string) string {
+	v := firstLine(filepath.Join(repoDir, ".java-version"))
+	if v == "" {
+		if line := grepLine(filepath.Join(repoDir, ".sdkmanrc"), "java="); line != "" {
+			v = strings.TrimPrefix(line, "java=")
+		}
+	}
+	if v == "" {
+		v = toolVersions(repoDir)["java"]
+		v = v[strings.LastIndex(v, "-")+1:] // temurin-17.0.9 → 17.0.9
+	}
+	return majorOf(v)
+}
+
+// GoVersion reads the go directive from go.mod
+func GoVersion(repoDir string) string {
+	if line := grepLine(filepath.Join(repoDir, "go.mod"), "go "); line != "" {
+		return strings.TrimSpace(strings.TrimPrefix(line, "go "))
+	}
+	return ""
+}
+
+// Newer returns whichever version has the higher major (then minor) number; non-numeric
+// versions (lts, latest) lose to pinned ones
+func Newer(a, b string) string {
+	if a == "" {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		if pa[i] != pb[i] {
+			if pa[i] > pb[i] {
+				return a
+			}
+			return b
+		}
+	}
+	if len(pb) > len(pa) {
+		return b
+	}
+	return a
+}
+
+func versionParts(v string) []int {
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+func majorOf(v string) string {
+	if i := strings.IndexByte(v, '.'); i > 0 {
+		return v[:i]
+	}
+	return v
+}
+
+func toolVersions(repoDir string) map[string]string {
+	versions := make(map[string]string)
+	f, err := os.Open(filepath.Join(repoDir, ".tool-versions"))
+	if err != nil {
+		return versions
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) >= 2 && !strings.HasPrefix(fields[0], "#") {
+			versions[fields[0]] = fields[1]
+		}
+	}
+	return versions
+}
+
+func firstLine(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(line)
+}
+
+func grepLine(path, prefix string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); strings.HasPrefix(line, prefix) {
+			return line
+		}
+	}
+	return ""
+}
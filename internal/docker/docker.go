@@ -0,0 +1,81 @@
+package docker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// argRe matches `ARG NAME` and `ARG NAME=default` instructions
+var argRe = regexp.MustCompile(`(?i)^\s*ARG\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// CheckCLI verifies that docker is installed
+func CheckCLI() error {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return fmt.Errorf("docker not found — install Docker Desktop from https://www.docker.com/products/docker-desktop")
+	}
+	return nil
+}
+
+// DeclaredArgs returns the build args a Dockerfile declares with ARG, in order
+func DeclaredArgs(dockerfile string) ([]string, error) {
+	f, err := os.Open(dockerfile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var args []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := argRe.FindStringSubmatch(scanner.Text()); m != nil && !seen[m[1]] {
+			seen[m[1]] = true
+			args = append(args, m[1])
+		}
+	}
+	return args, scanner.Err()
+}
+
+// ImageName lowercases a repo name into a valid image name
+func ImageName(repo string) string {
+	return strings.ToLower(repo)
+}
+
+// Service is one repo's entry in a generated compose file
+type Service struct {
+	Build       ServiceBuild `json:"build"`
+	Image       string       `json:"image"`
+	Ports       []string     `json:"ports,omitempty"`
+	Environment []string     `json:"environment,omitempty"`
+	DependsOn   []string     `json:"depends_on,omitempty"`
+}
+
+// ServiceBuild points compose at a repo's Dockerfile
+type ServiceBuild struct {
+	Context    string `json:"context"`
+	Dockerfile string `json:"dockerfile,omitempty"`
+}
+
+// WriteCompose writes a compose file. Compose reads JSON as YAML, so no YAML encoder is
+// needed. Environment lists only key names — values come from the environment compose
+// runs in, keeping secrets out of the file.
+func WriteCompose(path, project string, services map[string]Service) error {
+	for name, svc := range services {
+		sort.Strings(svc.Environment)
+		services[name] = svc
+	}
+	data, err := json.MarshalIndent(struct {
+		Name     string             `json:"name"`
+		Services map[string]Service `json:"services"`
+	}{project, services}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
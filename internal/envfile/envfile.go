@@ -0,0 +1,85 @@
+package envfile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Framework identifies how a repo expects to find its env file
+type Framework string
+
+const (
+	FrameworkNext        Framework = "next"
+	FrameworkReactNative Framework = "react-native"
+	FrameworkCDK         Framework = "cdk"
+	FrameworkDefault     Framework = "default"
+)
+
+// fileNames maps each framework to the env file(s) it loads, in the repo root.
+// Next.js gives .env.local precedence over a committed .env, so linking .env.local
+// never shadows a repo's own defaults file. React Native (react-native-dotenv /
+// react-native-config) and CDK apps read .env.
+var fileNames = map[Framework][]string{
+	FrameworkNext:        {".env.local"},
+	FrameworkReactNative: {".env"},
+	FrameworkCDK:         {".env"},
+	FrameworkDefault:     {".env"},
+}
+
+// Detect returns the framework a repo uses, based on config files and package.json deps
+func Detect(repoDir string) Framework {
+	for _, f := range []string{"next.config.js", "next.config.mjs", "next.config.ts"} {
+		if exists(filepath.Join(repoDir, f)) {
+			return FrameworkNext
+		}
+	}
+
+	deps := packageDeps(repoDir)
+	if deps["next"] {
+		return FrameworkNext
+	}
+	if deps["react-native"] || deps["expo"] {
+		return FrameworkReactNative
+	}
+	if exists(filepath.Join(repoDir, "cdk.json")) {
+		return FrameworkCDK
+	}
+	return FrameworkDefault
+}
+
+// FileNames returns the env file names a framework reads
+func FileNames(fw Framework) []string {
+	if names, ok := fileNames[fw]; ok {
+		return names
+	}
+	return fileNames[FrameworkDefault]
+}
+
+// packageDeps returns the set of dependency names declared in package.json
+func packageDeps(repoDir string) map[string]bool {
+	data, err := os.ReadFile(filepath.Join(repoDir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+	deps := make(map[string]bool)
+	for name := range pkg.Dependencies {
+		deps[name] = true
+	}
+	for name := range pkg.DevDependencies {
+		deps[name] = true
+	}
+	return deps
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,120 @@
+package envfile
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// generatedHeader marks env files spark-cli writes and may overwrite
+const generatedHeader = "# Generated by spark-cli from the workspace .env — edit \"env_keys\" in workspace.json instead"
+
+// nextPublicPrefix exposes a variable to the browser bundle in Next.js
+const nextPublicPrefix = "NEXT_PUBLIC_"
+
+// DefaultKeys are the key patterns a framework's generated env file gets when the repo
+// doesn't list its own. React Native gets only client-side config: react-native-config
+// compiles every key in .env into the app binary.
+var DefaultKeys = map[Framework][]string{
+	FrameworkNext: {nextPublicPrefix + "*"},
+	FrameworkReactNative: {
+		"APP_ENV", "AWS_REGION", "*USERPOOL_ID", "*WEB_CLIENT_ID", "*IDENTITY_POOL_ID",
+		"*_API_URL", "*_PUBLIC_KEY", "GOOGLE_MAPS_KEY",
+	},
+}
+
+// nextPublicSources lists, for browser keys whose name differs from the workspace key,
+// the workspace keys to take the value from in order of preference. Any other
+// NEXT_PUBLIC_<KEY> falls back to <KEY>.
+var nextPublicSources = map[string][]string{
+	"NEXT_PUBLIC_USERPOOL_ID":         {"BUSINESS_USERPOOL_ID", "USERPOOL_ID"},
+	"NEXT_PUBLIC_WEB_CLIENT_ID":       {"BUSINESS_WEB_CLIENT_ID", "WEB_CLIENT_ID"},
+	"NEXT_PUBLIC_IDENTITY_POOL_ID":    {"BUSINESS_IDENTITY_POOL_ID", "IDENTITY_POOL_ID"},
+	"NEXT_PUBLIC_SQUARE_CLIENT":       {"SQUARE_CLIENT_ID"},
+	"NEXT_PUBLIC_CLOVER_APP_ID":       {"CLOVER_APP_ID"},
+	"NEXT_PUBLIC_GOOGLE_MAPS_API_KEY": {"GOOGLE_MAPS_KEY"},
+	"NEXT_PUBLIC_STRIPE_KEY":          {"STRIPE_PUBLIC_KEY"},
+	"NEXT_PUBLIC_AWS_REGION":          {"AWS_REGION"},
+	"NEXT_PUBLIC_APP_ENV":             {"APP_ENV"},
+}
+
+// Generates reports whether spark-cli writes a filtered env file for the framework
+// rather than linking the whole workspace .env
+func Generates(fw Framework) bool {
+	_, ok := DefaultKeys[fw]
+	return ok
+}
+
+// Generate picks the variables a repo declares from the workspace env. keys are exact
+// names or path.Match patterns (NEXT_PUBLIC_*). For Next.js, browser keys are derived
+// from their server-side counterparts, but only keys already named NEXT_PUBLIC_ or
+// listed exactly are ever exposed — a pattern never publishes an arbitrary secret.
+func Generate(fw Framework, vars map[string]string, keys []string) map[string]string {
+	if len(keys) == 0 {
+		keys = DefaultKeys[fw]
+	}
+
+	candidates := make(map[string]string)
+	for k, v := range vars {
+		candidates[k] = v
+	}
+	if fw == FrameworkNext {
+		for public, sources := range nextPublicSources {
+			if _, ok := candidates[public]; ok {
+				continue
+			}
+			for _, src := range sources {
+				if v := vars[src]; v != "" {
+					candidates[public] = v
+					break
+				}
+			}
+		}
+		for _, k := range keys {
+			name, ok := strings.CutPrefix(k, nextPublicPrefix)
+			if !ok || strings.ContainsAny(k, "*?[") {
+				continue
+			}
+			if _, exists := candidates[k]; !exists && vars[name] != "" {
+				candidates[k] = vars[name]
+			}
+		}
+	}
+
+	out := make(map[string]string)
+	for k, v := range candidates {
+		for _, pattern := range keys {
+			if ok, _ := path.Match(pattern, k); ok {
+				out[k] = v
+				break
+			}
+		}
+	}
+	return out
+}
+
+// IsGenerated reports whether the file at path was written by WriteGenerated
+func IsGenerated(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.HasPrefix(string(data), generatedHeader)
+}
+
+// WriteGenerated writes vars as a sorted env file under the generated header
+func WriteGenerated(path string, vars map[string]string) error {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(generatedHeader + "\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", k, vars[k])
+	}
+	// A stale symlink to the workspace .env would otherwise be written through
+	os.Remove(path)
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
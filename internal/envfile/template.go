@@ -0,0 +1,34 @@
+package envfile
+
+import (
+	"os"
+	"strings"
+)
+
+// TemplateName is the env template written into each repo
+const TemplateName = ".env.example"
+
+// templateHeader marks templates spark-cli writes and keeps up to date
+const templateHeader = "# Generated by spark-cli from \"required_env\" in spk.config.json — edit that instead, then run 'spark-cli env template'"
+
+// Template returns the contents of an env template listing keys, in order, without values
+func Template(keys []string) string {
+	var b strings.Builder
+	b.WriteString(templateHeader + "\n")
+	b.WriteString("# Values come from the workspace .env ('spark-cli workspace sync --env <env>')\n\n")
+	seen := make(map[string]bool)
+	for _, k := range keys {
+		if k = strings.TrimSpace(k); k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		b.WriteString(k + "=\n")
+	}
+	return b.String()
+}
+
+// IsTemplate reports whether the file at path is an env template spark-cli wrote
+func IsTemplate(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.HasPrefix(string(data), templateHeader)
+}
@@ -0,0 +1,88 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind is a stable, machine-readable error category
+type Kind string
+
+const (
+	KindGeneric           Kind = "error"
+	KindUsage             Kind = "usage"
+	KindWorkspaceNotFound Kind = "workspace-not-found"
+	KindRepoNotFound      Kind = "repo-not-found"
+	KindRepoDirty         Kind = "repo-dirty"
+	KindAuthExpired       Kind = "auth-expired"
+	KindBuildFailed       Kind = "build-failed"
+	KindCommandFailed     Kind = "command-failed"
+	KindTimeout           Kind = "timeout"
+	KindInterrupted       Kind = "interrupted"
+)
+
+// exitCodes are part of the CLI contract — scripts depend on them, so never renumber
+var exitCodes = map[Kind]int{
+	KindGeneric:           1,
+	KindUsage:             2,
+	KindWorkspaceNotFound: 3,
+	KindRepoNotFound:      4,
+	KindRepoDirty:         5,
+	KindAuthExpired:       6,
+	KindBuildFailed:       7,
+	KindCommandFailed:     8,
+	KindTimeout:           124,
+	KindInterrupted:       130,
+}
+
+// Error is an error tagged with a Kind
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+// New returns a formatted error of the given kind
+func New(kind Kind, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// Wrap tags err with kind (nil stays nil). An already-tagged error keeps its kind.
+func Wrap(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// KindOf returns the kind of err, or KindGeneric if untagged
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindGeneric
+}
+
+// ExitCode returns the process exit code for err
+func ExitCode(err error) int {
+	if code, ok := exitCodes[KindOf(err)]; ok {
+		return code
+	}
+	return 1
+}
+
+// ExitCodes returns the kind → exit code table (for help output)
+func ExitCodes() map[Kind]int {
+	out := make(map[Kind]int, len(exitCodes))
+	for k, v := range exitCodes {
+		out[k] = v
+	}
+	return out
+}
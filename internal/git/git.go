@@ -1,17 +1,24 @@
 package git
 
 import (
+	"encoding/base64"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/retry"
 )
 
 // Clone clones a repository into the target directory
 func Clone(remote, targetDir string) error {
-	cmd := exec.Command("git", "clone", remote, targetDir)
+	cmd := proc.Foreground("git", "clone", remote, targetDir)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
@@ -19,7 +26,7 @@ func Clone(remote, targetDir string) error {
 
 // Pull runs git pull in the given directory
 func Pull(repoDir string) error {
-	cmd := exec.Command("git", "pull")
+	cmd := proc.Foreground("git", "pull")
 	cmd.Dir = repoDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -28,7 +35,7 @@ func Pull(repoDir string) error {
 
 // Status runs git status in the given directory and returns the output
 func Status(repoDir string) (string, error) {
-	cmd := exec.Command("git", "status", "--short")
+	cmd := proc.Command("git", "status", "--short")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -39,7 +46,7 @@ func Status(repoDir string) (string, error) {
 
 // StatusLong returns full git status output (for showing unstaged changes when unable to rebase)
 func StatusLong(repoDir string) (string, error) {
-	cmd := exec.Command("git", "status")
+	cmd := proc.Command("git", "status")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -50,7 +57,7 @@ func StatusLong(repoDir string) (string, error) {
 
 // StatusShortColor returns git status --short with ANSI colors (staged vs unstaged like git status)
 func StatusShortColor(repoDir string) (string, error) {
-	cmd := exec.Command("git", "status", "--short", "--color=always")
+	cmd := proc.Command("git", "status", "--short", "--color=always")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -61,7 +68,7 @@ func StatusShortColor(repoDir string) (string, error) {
 
 // CurrentBranch returns the current branch name
 func CurrentBranch(repoDir string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd := proc.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -88,6 +95,77 @@ func BuildRemoteURL(orgRepo string) string {
 	return fmt.Sprintf("git@github.com:%s.git", orgRepo)
 }
 
+// Clone protocols for BuildRemoteURLWithProtocol
+const (
+	ProtocolSSH   = "ssh"
+	ProtocolHTTPS = "https"
+)
+
+// BuildRemoteURLWithProtocol constructs a git URL from org/repo using ssh or https
+func BuildRemoteURLWithProtocol(orgRepo, protocol string) string {
+	if protocol == ProtocolHTTPS {
+		if strings.HasPrefix(orgRepo, "git@") || strings.HasPrefix(orgRepo, "https://") {
+			return orgRepo
+		}
+		return fmt.Sprintf("https://github.com/%s.git", orgRepo)
+	}
+	return BuildRemoteURL(orgRepo)
+}
+
+// CanSSHToGitHub reports whether ssh keys are set up for github.com. GitHub exits 1 on
+// `ssh -T` even when authentication succeeds, so the greeting is checked instead.
+func CanSSHToGitHub() bool {
+	cmd := proc.Command("ssh", "-T",
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=5",
+		"-o", "StrictHostKeyChecking=accept-new",
+		"git@github.com")
+	out, _ := cmd.CombinedOutput()
+	return strings.Contains(string(out), "successfully authenticated")
+}
+
+// CloneWithToken clones an https remote, authenticating with a GitHub token for this
+// command only (the token is never written to .git/config). The header is passed in
+// git's GIT_CONFIG_* environment rather than argv, so it doesn't show up in ps.
+func CloneWithToken(remote, targetDir, token string) error {
+	if token == "" || !strings.HasPrefix(remote, "https://") {
+		return Clone(remote, targetDir)
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+	cmd := proc.Foreground("git", "clone", remote, targetDir)
+	cmd.Env = withGitConfig(os.Environ(), "http.https://github.com/.extraheader", "AUTHORIZATION: basic "+auth)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// withGitConfig adds key=value to env as a GIT_CONFIG_KEY_n/GIT_CONFIG_VALUE_n pair,
+// after any pairs env already has
+func withGitConfig(env []string, key, value string) []string {
+	n := 0
+	out := make([]string, 0, len(env)+3)
+	for _, kv := range env {
+		if v, ok := strings.CutPrefix(kv, "GIT_CONFIG_COUNT="); ok {
+			n, _ = strconv.Atoi(v)
+			continue
+		}
+		out = append(out, kv)
+	}
+	return append(out,
+		fmt.Sprintf("GIT_CONFIG_COUNT=%d", n+1),
+		fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", n, key),
+		fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", n, value))
+}
+
+// UseGHCredentialHelper configures repoDir to get https credentials from the gh CLI,
+// so later fetches work without ssh keys. No-op if gh is not installed.
+func UseGHCredentialHelper(repoDir string) error {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return err
+	}
+	return runQuiet(repoDir, "git", "config", "--local", "credential.https://github.com.helper", "!gh auth git-credential")
+}
+
 // RepoNameFromRemote extracts the repo name from a remote URL or org/repo string
 func RepoNameFromRemote(remote string) string {
 	// Handle org/repo format
@@ -106,16 +184,18 @@ func Fetch(repoDir, remote string) error {
 	if remote == "" {
 		remote = "origin"
 	}
-	cmd := exec.Command("git", "fetch", remote)
-	cmd.Dir = repoDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return retry.Do(func() error {
+		cmd := proc.Foreground("git", "fetch", remote)
+		cmd.Dir = repoDir
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	})
 }
 
 // Rebase runs git rebase on the specified upstream branch
 func Rebase(repoDir, upstream string) error {
-	cmd := exec.Command("git", "rebase", upstream)
+	cmd := proc.Foreground("git", "rebase", upstream)
 	cmd.Dir = repoDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -124,7 +204,7 @@ func Rebase(repoDir, upstream string) error {
 
 // RebaseAbort aborts an in-progress rebase
 func RebaseAbort(repoDir string) error {
-	cmd := exec.Command("git", "rebase", "--abort")
+	cmd := proc.Cleanup("git", "rebase", "--abort")
 	cmd.Dir = repoDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -133,7 +213,7 @@ func RebaseAbort(repoDir string) error {
 
 // runQuiet runs a command with stdout/stderr discarded (for sync to avoid flooding output)
 func runQuiet(repoDir string, name string, args ...string) error {
-	cmd := exec.Command(name, args...)
+	cmd := proc.Command(name, args...)
 	cmd.Dir = repoDir
 	cmd.Stdout = io.Discard
 	cmd.Stderr = io.Discard
@@ -145,7 +225,20 @@ func FetchQuiet(repoDir, remote string) error {
 	if remote == "" {
 		remote = "origin"
 	}
-	return runQuiet(repoDir, "git", "fetch", remote)
+	return retry.Do(func() error {
+		return runQuiet(repoDir, "git", "fetch", remote)
+	})
+}
+
+// FetchPruneQuiet fetches remote and drops remote-tracking refs for branches deleted on
+// it, with output suppressed
+func FetchPruneQuiet(repoDir, remote string) error {
+	if remote == "" {
+		remote = "origin"
+	}
+	return retry.Do(func() error {
+		return runQuiet(repoDir, "git", "fetch", "--prune", remote)
+	})
 }
 
 // RebaseQuiet runs git rebase with output suppressed
@@ -153,14 +246,19 @@ func RebaseQuiet(repoDir, upstream string) error {
 	return runQuiet(repoDir, "git", "rebase", upstream)
 }
 
-// RebaseAbortQuiet aborts a rebase with output suppressed
+// RebaseAbortQuiet aborts a rebase with output suppressed. It runs even after an
+// interrupt so a cancelled sync never leaves a repo mid-rebase.
 func RebaseAbortQuiet(repoDir string) error {
-	return runQuiet(repoDir, "git", "rebase", "--abort")
+	cmd := proc.Cleanup("git", "rebase", "--abort")
+	cmd.Dir = repoDir
+	cmd.Stdout = io.Discard
+	cmd.Stderr = io.Discard
+	return cmd.Run()
 }
 
 // Stash stashes uncommitted changes
 func Stash(repoDir string) error {
-	cmd := exec.Command("git", "stash", "push", "-m", "spark-cli-sync-autostash")
+	cmd := proc.Foreground("git", "stash", "push", "-m", "spark-cli-sync-autostash")
 	cmd.Dir = repoDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -169,7 +267,7 @@ func Stash(repoDir string) error {
 
 // StashPop pops the most recent stash
 func StashPop(repoDir string) error {
-	cmd := exec.Command("git", "stash", "pop")
+	cmd := proc.Foreground("git", "stash", "pop")
 	cmd.Dir = repoDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -178,7 +276,7 @@ func StashPop(repoDir string) error {
 
 // HasStash checks if there are any stashed changes
 func HasStash(repoDir string) bool {
-	cmd := exec.Command("git", "stash", "list")
+	cmd := proc.Command("git", "stash", "list")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -198,13 +296,13 @@ func IsDirty(repoDir string) bool {
 
 // IsUpToDate returns true if HEAD equals origin/targetBranch (e.g. after fetch)
 func IsUpToDate(repoDir, targetBranch string) bool {
-	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd := proc.Command("git", "rev-parse", "HEAD")
 	cmd.Dir = repoDir
 	head, err := cmd.Output()
 	if err != nil {
 		return false
 	}
-	cmd = exec.Command("git", "rev-parse", "origin/"+targetBranch)
+	cmd = proc.Command("git", "rev-parse", "origin/"+targetBranch)
 	cmd.Dir = repoDir
 	upstream, err := cmd.Output()
 	if err != nil {
@@ -224,7 +322,7 @@ func GetCurrentBranch(repoDir string) string {
 
 // ListLocalBranches returns all local branch names
 func ListLocalBranches(repoDir string) []string {
-	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/heads/")
+	cmd := proc.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/heads/")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -237,9 +335,131 @@ func ListLocalBranches(repoDir string) []string {
 	return strings.Split(raw, "\n")
 }
 
+// RemoteBranches returns the branches of remote as of the last fetch, without the
+// remote's name and its HEAD
+func RemoteBranches(repoDir, remote string) []string {
+	cmd := proc.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/remotes/"+remote+"/")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var branches []string
+	for _, ref := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		branch, ok := strings.CutPrefix(ref, remote+"/")
+		if ok && branch != "HEAD" {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+// GoneBranches returns local branches whose configured upstream no longer exists (as of
+// the last fetch --prune)
+func GoneBranches(repoDir string) []string {
+	cmd := proc.Command("git", "for-each-ref", "--format=%(refname:short)%00%(upstream:track)", "refs/heads/")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var gone []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		branch, track, _ := strings.Cut(line, "\x00")
+		if track == "[gone]" {
+			gone = append(gone, branch)
+		}
+	}
+	return gone
+}
+
+// BranchUpstreams maps each local branch with an upstream to it ("origin/main")
+func BranchUpstreams(repoDir string) map[string]string {
+	cmd := proc.Command("git", "for-each-ref", "--format=%(refname:short)%00%(upstream:short)", "refs/heads/")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	upstreams := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		branch, upstream, _ := strings.Cut(line, "\x00")
+		if upstream != "" {
+			upstreams[branch] = upstream
+		}
+	}
+	return upstreams
+}
+
+// UpstreamTips returns each local branch's upstream commit as of the last fetch, for the
+// branches whose upstream still exists
+func UpstreamTips(repoDir string) map[string]string {
+	cmd := proc.Command("git", "for-each-ref", "--format=%(refname)%00%(objectname)%00%(upstream)", "refs/heads/", "refs/remotes/")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	shas := make(map[string]string)
+	upstreams := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.Split(line, "\x00")
+		if len(parts) != 3 {
+			continue
+		}
+		shas[parts[0]] = parts[1]
+		if branch, ok := strings.CutPrefix(parts[0], "refs/heads/"); ok && parts[2] != "" {
+			upstreams[branch] = parts[2]
+		}
+	}
+	tips := make(map[string]string)
+	for branch, ref := range upstreams {
+		if sha := shas[ref]; sha != "" {
+			tips[branch] = sha
+		}
+	}
+	return tips
+}
+
+// BranchSHA returns the commit a local branch points at, or ""
+func BranchSHA(repoDir, branch string) string {
+	cmd := proc.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// IsAncestor reports whether commit is rev or an ancestor of it; false if either is unknown
+func IsAncestor(repoDir, commit, rev string) bool {
+	return runQuiet(repoDir, "git", "merge-base", "--is-ancestor", commit, rev) == nil
+}
+
+// OnRemoteBranch reports whether commit is contained in any remote-tracking branch
+func OnRemoteBranch(repoDir, commit string) bool {
+	cmd := proc.Command("git", "branch", "-r", "--contains", commit)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(out)) != ""
+}
+
+// DeleteBranch force-deletes a local branch (squash-merged branches aren't ancestors of
+// the base, so a plain -d would refuse them)
+func DeleteBranch(repoDir, branch string) error {
+	cmd := proc.Command("git", "branch", "-D", branch)
+	cmd.Dir = repoDir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git branch -D %s: %s", branch, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
 // AheadBehind returns how many commits local is ahead/behind upstream
 func AheadBehind(repoDir, local, upstream string) (ahead, behind int) {
-	cmd := exec.Command("git", "rev-list", "--left-right", "--count", fmt.Sprintf("%s...%s", local, upstream))
+	cmd := proc.Command("git", "rev-list", "--left-right", "--count", fmt.Sprintf("%s...%s", local, upstream))
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -258,9 +478,68 @@ func CheckoutQuiet(repoDir, branch string) error {
 	return runQuiet(repoDir, "git", "checkout", branch)
 }
 
+// HooksDir returns the directory git runs hooks from (honoring core.hooksPath)
+func HooksDir(repoDir string) (string, error) {
+	cmd := proc.Command("git", "rev-parse", "--git-path", "hooks")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("not a git repo: %s", repoDir)
+	}
+	dir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoDir, dir)
+	}
+	return dir, nil
+}
+
+// BranchExists reports whether a local branch exists
+func BranchExists(repoDir, branch string) bool {
+	return runQuiet(repoDir, "git", "show-ref", "--verify", "--quiet", "refs/heads/"+branch) == nil
+}
+
+// RemoteBranchExists reports whether remote/branch is known locally (as of the last fetch)
+func RemoteBranchExists(repoDir, remote, branch string) bool {
+	return runQuiet(repoDir, "git", "show-ref", "--verify", "--quiet", "refs/remotes/"+remote+"/"+branch) == nil
+}
+
+// CreateBranchFrom creates branch at start (e.g. origin/main) and switches to it, carrying
+// over any uncommitted changes
+func CreateBranchFrom(repoDir, branch, start string) error {
+	cmd := proc.Command("git", "checkout", "-b", branch, "--no-track", start)
+	cmd.Dir = repoDir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RestoreBranchQuiet switches back to a branch even after an interrupt
+func RestoreBranchQuiet(repoDir, branch string) error {
+	cmd := proc.Cleanup("git", "checkout", branch)
+	cmd.Dir = repoDir
+	cmd.Stdout = io.Discard
+	cmd.Stderr = io.Discard
+	return cmd.Run()
+}
+
+// FetchKey identifies the repo's last fetch and origin/HEAD update by their mtimes, for
+// caching what's derived from remote refs; it changes whenever either does
+func FetchKey(repoDir string) string {
+	key := ""
+	for _, f := range []string{"FETCH_HEAD", "refs/remotes/origin/HEAD"} {
+		if info, err := os.Stat(filepath.Join(repoDir, ".git", f)); err == nil {
+			key += strconv.FormatInt(info.ModTime().UnixNano(), 36)
+		}
+		key += "/"
+	}
+	return key
+}
+
 // GetDefaultBranch attempts to determine the default branch (main or prod)
 func GetDefaultBranch(repoDir string) string {
-	cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
+	cmd := proc.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err == nil {
@@ -272,7 +551,7 @@ func GetDefaultBranch(repoDir string) string {
 	}
 
 	for _, branch := range []string{"main", "prod"} {
-		cmd := exec.Command("git", "rev-parse", "--verify", "origin/"+branch)
+		cmd := proc.Command("git", "rev-parse", "--verify", "origin/"+branch)
 		cmd.Dir = repoDir
 		if err := cmd.Run(); err == nil {
 			return branch
@@ -281,3 +560,592 @@ func GetDefaultBranch(repoDir string) string {
 
 	return "main"
 }
+
+// ConfigValue returns a git config value as seen from repoDir ("" if unset)
+func ConfigValue(repoDir, key string) string {
+	cmd := proc.Command("git", "config", "--get", key)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// ChangedFiles lists files that differ between the merge base of base and HEAD, and HEAD
+func ChangedFiles(repoDir, base string) ([]string, error) {
+	cmd := proc.Command("git", "diff", "--name-only", base+"...HEAD")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff against %s", base)
+	}
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// TreeHash returns the hash of HEAD's tree, which identifies the committed sources
+// independent of commit metadata
+func TreeHash(repoDir string) (string, error) {
+	cmd := proc.Command("git", "rev-parse", "HEAD^{tree}")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD tree in %s", repoDir)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// HeadSHA returns the full HEAD commit
+func HeadSHA(repoDir string) (string, error) {
+	cmd := proc.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD in %s", repoDir)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ShortSHA returns the abbreviated HEAD commit
+func ShortSHA(repoDir string) (string, error) {
+	cmd := proc.Command("git", "rev-parse", "--short", "HEAD")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD in %s", repoDir)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// HasSubmodules reports whether the repo declares submodules in .gitmodules
+func HasSubmodules(repoDir string) bool {
+	_, err := os.Stat(filepath.Join(repoDir, ".gitmodules"))
+	return err == nil
+}
+
+// UsesLFS reports whether the repo tracks files with Git LFS (filter=lfs in .gitattributes)
+func UsesLFS(repoDir string) bool {
+	data, err := os.ReadFile(filepath.Join(repoDir, ".gitattributes"))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), "filter=lfs")
+}
+
+// LFSInstalled reports whether the git-lfs extension is available
+func LFSInstalled() bool {
+	return proc.Command("git", "lfs", "version").Run() == nil
+}
+
+// SubmoduleUpdate initializes and updates all submodules recursively
+func SubmoduleUpdate(repoDir string) error {
+	return retry.Do(func() error {
+		cmd := proc.Foreground("git", "submodule", "update", "--init", "--recursive")
+		cmd.Dir = repoDir
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	})
+}
+
+// SubmoduleUpdateQuiet is SubmoduleUpdate with output suppressed
+func SubmoduleUpdateQuiet(repoDir string) error {
+	return retry.Do(func() error {
+		return runQuiet(repoDir, "git", "submodule", "update", "--init", "--recursive")
+	})
+}
+
+// LFSPull downloads LFS objects for the current checkout
+func LFSPull(repoDir string) error {
+	return retry.Do(func() error {
+		cmd := proc.Foreground("git", "lfs", "pull")
+		cmd.Dir = repoDir
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	})
+}
+
+// LFSPullQuiet is LFSPull with output suppressed
+func LFSPullQuiet(repoDir string) error {
+	return retry.Do(func() error {
+		return runQuiet(repoDir, "git", "lfs", "pull")
+	})
+}
+
+// StashPushMessage stashes uncommitted changes (including untracked files if requested)
+// under the given message
+func StashPushMessage(repoDir, message string, includeUntracked bool) error {
+	args := []string{"stash", "push", "-m", message}
+	if includeUntracked {
+		args = append(args, "--include-untracked")
+	}
+	return runQuiet(repoDir, "git", args...)
+}
+
+// FindStashRef returns the stash ref (e.g. stash@{2}) whose message starts with marker as
+// a whole word, or ""
+func FindStashRef(repoDir, marker string) string {
+	cmd := proc.Command("git", "stash", "list", "--format=%gd%x09%gs")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		ref, subject, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		// subjects read "On <branch>: <message>"; branch names can't contain ':'
+		if _, msg, found := strings.Cut(subject, ": "); found {
+			subject = msg
+		}
+		if subject == marker || strings.HasPrefix(subject, marker+" ") {
+			return ref
+		}
+	}
+	return ""
+}
+
+// StashPopRef applies and drops a specific stash
+func StashPopRef(repoDir, ref string) error {
+	cmd := proc.Command("git", "stash", "pop", ref)
+	cmd.Dir = repoDir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// StashDropRef drops a specific stash without applying it
+func StashDropRef(repoDir, ref string) error {
+	return runQuiet(repoDir, "git", "stash", "drop", ref)
+}
+
+// DiffStat returns `git diff --stat` output for tracked changes against HEAD
+func DiffStat(repoDir string) string {
+	cmd := proc.Command("git", "diff", "HEAD", "--stat")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(out), "\n")
+}
+
+// MergeBase returns the best common ancestor of a and b, or "" if there is none
+func MergeBase(repoDir, a, b string) string {
+	cmd := proc.Command("git", "merge-base", a, b)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// DiffTo writes the diff between rev and the working tree (or its --stat summary) to w.
+// Color follows git's color.ui setting, so it applies when w is a terminal.
+func DiffTo(repoDir, rev string, stat bool, w io.Writer) error {
+	args := []string{"--no-pager", "diff", rev}
+	if stat {
+		args = append(args, "--stat")
+	}
+	cmd := proc.Command("git", args...)
+	cmd.Dir = repoDir
+	cmd.Stdout = w
+	cmd.Stderr = w
+	return cmd.Run()
+}
+
+// HasDiff reports whether the working tree differs from rev
+func HasDiff(repoDir, rev string) bool {
+	return runQuiet(repoDir, "git", "diff", "--quiet", rev) != nil
+}
+
+// UntrackedFiles lists untracked files not covered by .gitignore
+func UntrackedFiles(repoDir string) []string {
+	cmd := proc.Command("git", "ls-files", "--others", "--exclude-standard")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	raw := strings.TrimSpace(string(out))
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, "\n")
+}
+
+// TrackedFiles lists the files git tracks in the repo, relative to repoDir
+func TrackedFiles(repoDir string) ([]string, error) {
+	cmd := proc.Command("git", "ls-files", "-z")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in %s", repoDir)
+	}
+	var files []string
+	for _, f := range strings.Split(string(out), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// LastCommitTime returns when HEAD was committed
+func LastCommitTime(repoDir string) (time.Time, error) {
+	cmd := proc.Command("git", "log", "-1", "--format=%ct", "HEAD")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read HEAD in %s", repoDir)
+	}
+	sec, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected commit time %q in %s", strings.TrimSpace(string(out)), repoDir)
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// Snapshot is a repo's branch and working tree state
+type Snapshot struct {
+	Branch   string // "" when HEAD is detached
+	Head     string // commit SHA, "" before the first commit
+	Upstream string // e.g. origin/main, "" without one
+	Ahead    int    // commits ahead of Upstream
+	Behind   int    // commits behind Upstream
+	Dirty    bool   // uncommitted or untracked changes
+}
+
+// TakeSnapshot reads the branch, upstream distance, and dirtiness with a single
+// `git status --porcelain=v2 --branch`
+func TakeSnapshot(repoDir string) (*Snapshot, error) {
+	cmd := proc.Command("git", "status", "--porcelain=v2", "--branch")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed in %s", repoDir)
+	}
+	s := &Snapshot{}
+	for _, line := range strings.Split(string(out), "\n") {
+		header, ok := strings.CutPrefix(line, "# ")
+		if !ok {
+			if line != "" {
+				s.Dirty = true
+			}
+			continue
+		}
+		key, value, _ := strings.Cut(header, " ")
+		switch key {
+		case "branch.oid":
+			if value != "(initial)" {
+				s.Head = value
+			}
+		case "branch.head":
+			if value != "(detached)" {
+				s.Branch = value
+			}
+		case "branch.upstream":
+			s.Upstream = value
+		case "branch.ab":
+			fmt.Sscanf(value, "+%d -%d", &s.Ahead, &s.Behind)
+		}
+	}
+	return s, nil
+}
+
+// RefTimes returns the commit time of each of refs (full names such as refs/heads/main)
+// that exists, with a single git for-each-ref
+func RefTimes(repoDir string, refs ...string) map[string]time.Time {
+	times := make(map[string]time.Time)
+	cmd := proc.Command("git", append([]string{"for-each-ref", "--format=%(refname) %(committerdate:unix)"}, refs...)...)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return times
+	}
+	wanted := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		wanted[ref] = true
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		ref, sec, ok := strings.Cut(line, " ")
+		if !ok || !wanted[ref] {
+			continue // for-each-ref also matches refs below a pattern
+		}
+		if n, err := strconv.ParseInt(sec, 10, 64); err == nil {
+			times[ref] = time.Unix(n, 0)
+		}
+	}
+	return times
+}
+
+// AddAll stages all changes, including untracked files
+func AddAll(repoDir string) error {
+	return runQuiet(repoDir, "git", "add", "-A")
+}
+
+// Commit creates a commit with the given message from the staged changes
+func Commit(repoDir, message string) error {
+	cmd := proc.Foreground("git", "commit", "-m", message)
+	cmd.Dir = repoDir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Upstream returns the upstream ref of the current branch (e.g. origin/feat/x), or "" if none
+func Upstream(repoDir string) string {
+	cmd := proc.Command("git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Push pushes branch to remote, setting upstream when setUpstream is true
+func Push(repoDir, remote, branch string, setUpstream bool) error {
+	args := []string{"push"}
+	if setUpstream {
+		args = append(args, "-u")
+	}
+	args = append(args, remote, branch)
+	return retry.Do(func() error {
+		cmd := proc.Foreground("git", args...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+		}
+		return nil
+	})
+}
+
+// LatestTag returns the most recent tag reachable from HEAD matching pattern (e.g. "v*"),
+// or "" if there is none
+func LatestTag(repoDir, pattern string) string {
+	cmd := proc.Command("git", "describe", "--tags", "--abbrev=0", "--match", pattern)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// CommitMessages returns the full messages of commits in revRange (e.g. "v1.2.0..HEAD"),
+// newest first
+func CommitMessages(repoDir, revRange string) ([]string, error) {
+	cmd := proc.Command("git", "log", "--format=%B%x00", revRange)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits in %s", revRange)
+	}
+	var msgs []string
+	for _, m := range strings.Split(string(out), "\x00") {
+		if m = strings.TrimSpace(m); m != "" {
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs, nil
+}
+
+// MatchedCommit is a commit found by FirstParentCommitsMatching
+type MatchedCommit struct {
+	SHA     string
+	Subject string
+	Merge   bool
+	// Reverts lists the commits this one reverts ("This reverts commit <sha>.")
+	Reverts []string
+}
+
+var revertsRe = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+
+// FirstParentCommitsMatching returns the first-parent commits on ref whose message
+// matches any of patterns (extended regexps), newest first. Following first parents only
+// yields the merge or squash commit for a merged PR rather than the commits inside it.
+func FirstParentCommitsMatching(repoDir, ref string, patterns []string) ([]MatchedCommit, error) {
+	args := []string{"log", "--first-parent", "--extended-regexp", "--format=%H%x00%P%x00%s%x00%b%x1e"}
+	for _, p := range patterns {
+		args = append(args, "--grep="+p)
+	}
+	args = append(args, ref)
+	cmd := proc.Command("git", args...)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log of %s", ref)
+	}
+	var commits []MatchedCommit
+	for _, record := range strings.Split(string(out), "\x1e") {
+		parts := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		c := MatchedCommit{
+			SHA:     parts[0],
+			Subject: parts[2],
+			Merge:   len(strings.Fields(parts[1])) > 1,
+		}
+		for _, m := range revertsRe.FindAllStringSubmatch(parts[3], -1) {
+			c.Reverts = append(c.Reverts, m[1])
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// Revert creates a commit reverting sha; merge commits are reverted against their first
+// parent
+func Revert(repoDir, sha string, merge bool) error {
+	args := []string{"revert", "--no-edit"}
+	if merge {
+		args = append(args, "-m", "1")
+	}
+	cmd := proc.Command("git", append(args, sha)...)
+	cmd.Dir = repoDir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git revert %s: %s", ShortRef(sha), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RevertAbort abandons an in-progress revert, even after an interrupt
+func RevertAbort(repoDir string) error {
+	cmd := proc.Cleanup("git", "revert", "--abort")
+	cmd.Dir = repoDir
+	cmd.Stdout = io.Discard
+	cmd.Stderr = io.Discard
+	return cmd.Run()
+}
+
+// ShortRef abbreviates a full commit hash for display
+func ShortRef(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// LogSubjects returns "<short-hash> <subject>" for the first-parent commits on ref in the
+// date range (git date syntax, e.g. 2024-05-01; until may be empty)
+func LogSubjects(repoDir, ref, since, until string) ([]string, error) {
+	args := []string{"log", "--first-parent", "--format=%h %s", "--since=" + since}
+	if until != "" {
+		args = append(args, "--until="+until)
+	}
+	args = append(args, ref)
+	cmd := proc.Command("git", args...)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log of %s", ref)
+	}
+	var lines []string
+	for _, l := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines, nil
+}
+
+// TagCommit returns the commit tag points at, or "" if there is no such tag
+func TagCommit(repoDir, tag string) string {
+	cmd := proc.Command("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+tag+"^{commit}")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// CreateTag creates an annotated tag at HEAD
+func CreateTag(repoDir, tag, message string) error {
+	cmd := proc.Command("git", "tag", "-a", tag, "-m", message)
+	cmd.Dir = repoDir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RemoteURL returns the URL of a remote ("" if not configured)
+func RemoteURL(repoDir, remote string) string {
+	cmd := proc.Command("git", "remote", "get-url", remote)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// SetRemoteURL points remote at url
+func SetRemoteURL(repoDir, remote, url string) error {
+	return runQuiet(repoDir, "git", "remote", "set-url", remote, url)
+}
+
+// SetRemote points remote at url, adding the remote if it doesn't exist
+func SetRemote(repoDir, remote, url string) error {
+	switch RemoteURL(repoDir, remote) {
+	case url:
+		return nil
+	case "":
+		return runQuiet(repoDir, "git", "remote", "add", remote, url)
+	}
+	return SetRemoteURL(repoDir, remote, url)
+}
+
+// PullFrom pulls branch from remote into the current branch
+func PullFrom(repoDir, remote, branch string) error {
+	cmd := proc.Foreground("git", "pull", remote, branch)
+	cmd.Dir = repoDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// GitHubSlug extracts "org/repo" from a GitHub ssh or https URL, or "" if it isn't one
+func GitHubSlug(remoteURL string) string {
+	var path string
+	switch {
+	case strings.HasPrefix(remoteURL, "git@github.com:"):
+		path = strings.TrimPrefix(remoteURL, "git@github.com:")
+	case strings.HasPrefix(remoteURL, "https://github.com/"):
+		path = strings.TrimPrefix(remoteURL, "https://github.com/")
+	case strings.HasPrefix(remoteURL, "ssh://git@github.com/"):
+		path = strings.TrimPrefix(remoteURL, "ssh://git@github.com/")
+	default:
+		return ""
+	}
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")
+	if strings.Count(path, "/") != 1 {
+		return ""
+	}
+	return path
+}
+
+// IsTracked reports whether path (relative to repoDir) is tracked by git
+func IsTracked(repoDir, path string) bool {
+	return runQuiet(repoDir, "git", "ls-files", "--error-unmatch", "--", path) == nil
+}
@@ -0,0 +1,310 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// APIBaseURL is the GitHub REST API root
+const APIBaseURL = "https://api.github.com"
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// ErrNotFound is wrapped by API errors for a 404 response
+var ErrNotFound = errors.New("not found")
+
+// User is the subset of the GitHub user object spark-cli cares about
+type User struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// apiGet performs an authenticated GET against the GitHub API and decodes the JSON body into out
+func apiGet(token, path string, out interface{}) error {
+	return apiRequest(token, http.MethodGet, path, nil, out)
+}
+
+// apiRequest sends an authenticated request with an optional JSON body and decodes the
+// response into out (if non-nil)
+func apiRequest(token, method, path string, in, out interface{}) error {
+	var reqBody io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, APIBaseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read GitHub API response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = resp.Status
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("GitHub API %s: %s: %w", path, apiErr.Message, ErrNotFound)
+		}
+		return fmt.Errorf("GitHub API %s: %s", path, apiErr.Message)
+	}
+
+	if out == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse GitHub API response: %w", err)
+	}
+	return nil
+}
+
+// GetAuthenticatedUser returns the user the token belongs to
+func GetAuthenticatedUser(token string) (*User, error) {
+	if token == "" {
+		return nil, fmt.Errorf("no GitHub token — set GITHUB_TOKEN or run 'gh auth login'")
+	}
+	var u User
+	if err := apiGet(token, "/user", &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Repo is the subset of the GitHub repository object spark-cli cares about
+type Repo struct {
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Description   string `json:"description"`
+	Archived      bool   `json:"archived"`
+	Private       bool   `json:"private"`
+	Visibility    string `json:"visibility"`
+	Fork          bool   `json:"fork"`
+	DefaultBranch string `json:"default_branch"`
+	SSHURL        string `json:"ssh_url"`
+	CloneURL      string `json:"clone_url"`
+	HTMLURL       string `json:"html_url"`
+	PushedAt      string `json:"pushed_at"`
+}
+
+// ListOrgRepos returns every repository in an org visible to the token
+func ListOrgRepos(token, org string) ([]Repo, error) {
+	var all []Repo
+	for page := 1; ; page++ {
+		var batch []Repo
+		path := fmt.Sprintf("/orgs/%s/repos?per_page=100&page=%d&sort=full_name", org, page)
+		if err := apiGet(token, path, &batch); err != nil {
+			return nil, err
+		}
+		all = append(all, batch...)
+		if len(batch) < 100 {
+			break
+		}
+	}
+	return all, nil
+}
+
+// GetRepo returns repo ("owner/name"). GitHub redirects a renamed or transferred repo's
+// old name, so FullName is the name it has now.
+func GetRepo(token, repo string) (*Repo, error) {
+	var r Repo
+	if err := apiGet(token, "/repos/"+repo, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// CreateFork forks repo ("owner/name") into the token's account and returns the fork;
+// GitHub returns the existing fork if there is one. Forking finishes in the background,
+// so the fork may take a few seconds to clone.
+func CreateFork(token, repo string) (*Repo, error) {
+	var r Repo
+	if err := apiRequest(token, http.MethodPost, "/repos/"+repo+"/forks", map[string]interface{}{}, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Release is the subset of the GitHub release object spark-cli cares about
+type Release struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	HTMLURL string `json:"html_url"`
+}
+
+// CreateRelease publishes a release for an existing tag in repo ("owner/name"), with
+// GitHub-generated notes
+func CreateRelease(token, repo, tag string) (*Release, error) {
+	in := map[string]interface{}{
+		"tag_name":               tag,
+		"name":                   tag,
+		"generate_release_notes": true,
+	}
+	var r Release
+	if err := apiRequest(token, http.MethodPost, fmt.Sprintf("/repos/%s/releases", repo), in, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ReleaseByTag returns repo's ("owner/name") release for tag, or nil if it has none
+func ReleaseByTag(token, repo, tag string) (*Release, error) {
+	var r Release
+	err := apiGet(token, fmt.Sprintf("/repos/%s/releases/tags/%s", repo, url.PathEscape(tag)), &r)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// LatestRelease returns repo's ("owner/name") most recent non-prerelease, non-draft release
+func LatestRelease(token, repo string) (*Release, error) {
+	var r Release
+	if err := apiGet(token, fmt.Sprintf("/repos/%s/releases/latest", repo), &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// DispatchWorkflow triggers a workflow_dispatch run of workflow (file name or ID) on ref
+func DispatchWorkflow(token, repo, workflow, ref string) error {
+	in := map[string]string{"ref": ref}
+	return apiRequest(token, http.MethodPost, fmt.Sprintf("/repos/%s/actions/workflows/%s/dispatches", repo, workflow), in, nil)
+}
+
+// Label is a GitHub issue/PR label
+type Label struct {
+	Name string `json:"name"`
+}
+
+// PullRequest is the subset of a PR search result spark-cli cares about
+type PullRequest struct {
+	Number   int     `json:"number"`
+	Title    string  `json:"title"`
+	HTMLURL  string  `json:"html_url"`
+	Labels   []Label `json:"labels"`
+	ClosedAt string  `json:"closed_at"`
+	MergedAt string  `json:"merged_at"`
+	User     struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Head struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+}
+
+// SearchMergedPRs returns PRs in repo ("owner/name") merged in [since, until] (YYYY-MM-DD;
+// until may be empty)
+func SearchMergedPRs(token, repo, since, until string) ([]PullRequest, error) {
+	dates := ">=" + since
+	if until != "" {
+		dates = since + ".." + until
+	}
+	q := url.QueryEscape(fmt.Sprintf("repo:%s is:pr is:merged merged:%s", repo, dates))
+
+	var all []PullRequest
+	for page := 1; page <= 10; page++ {
+		var res struct {
+			Items []PullRequest `json:"items"`
+		}
+		path := fmt.Sprintf("/search/issues?q=%s&per_page=100&page=%d&sort=created&order=asc", q, page)
+		if err := apiGet(token, path, &res); err != nil {
+			return nil, err
+		}
+		all = append(all, res.Items...)
+		if len(res.Items) < 100 {
+			break
+		}
+	}
+	return all, nil
+}
+
+// MergedPullRequest returns the merged PR whose head is branch in repo ("owner/name"), or
+// nil if there is none
+func MergedPullRequest(token, repo, branch string) (*PullRequest, error) {
+	owner, _, _ := strings.Cut(repo, "/")
+	path := fmt.Sprintf("/repos/%s/pulls?state=closed&per_page=100&head=%s", repo, url.QueryEscape(owner+":"+branch))
+	var prs []PullRequest
+	if err := apiGet(token, path, &prs); err != nil {
+		return nil, err
+	}
+	for i := range prs {
+		if prs[i].MergedAt != "" {
+			return &prs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// OpenPullRequest returns the open PR whose head is branch in repo ("owner/name"), or nil
+// if there is none
+func OpenPullRequest(token, repo, branch string) (*PullRequest, error) {
+	owner, _, _ := strings.Cut(repo, "/")
+	path := fmt.Sprintf("/repos/%s/pulls?state=open&per_page=1&head=%s", repo, url.QueryEscape(owner+":"+branch))
+	var prs []PullRequest
+	if err := apiGet(token, path, &prs); err != nil {
+		return nil, err
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	return &prs[0], nil
+}
+
+// NewPullRequest is the body of a create-PR request
+type NewPullRequest struct {
+	Title string `json:"title"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Body  string `json:"body"`
+	Draft bool   `json:"draft"`
+}
+
+// CreatePullRequest opens a PR in repo ("owner/name")
+func CreatePullRequest(token, repo string, pr NewPullRequest) (*PullRequest, error) {
+	var out PullRequest
+	if err := apiRequest(token, http.MethodPost, fmt.Sprintf("/repos/%s/pulls", repo), pr, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdatePullRequestBody replaces a PR's description
+func UpdatePullRequestBody(token, repo string, number int, body string) error {
+	in := map[string]string{"body": body}
+	return apiRequest(token, http.MethodPatch, fmt.Sprintf("/repos/%s/pulls/%d", repo, number), in, nil)
+}
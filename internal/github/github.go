@@ -3,8 +3,12 @@ package github
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/retry"
 )
 
 type ssmParameter struct {
@@ -35,8 +39,7 @@ func FetchTokenFromSSM(profile, env, region string) (string, error) {
 		args = append(args, "--profile", profile)
 	}
 
-	cmd := exec.Command("aws", args...)
-	out, err := cmd.Output()
+	out, err := runSSM(args, "")
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			return "", fmt.Errorf("failed to fetch GitHub token: %s", string(exitErr.Stderr))
@@ -58,12 +61,58 @@ func FetchTokenFromSSM(profile, env, region string) (string, error) {
 	return "", fmt.Errorf("GitHub token not found at %s", paramName)
 }
 
+// permanentSSMErrors are AWS CLI error codes that retrying won't fix
+var permanentSSMErrors = []string{
+	"ExpiredToken",
+	"AccessDenied",
+	"UnrecognizedClientException",
+	"InvalidClientTokenId",
+	"Error loading SSO Token",
+	"The config profile",
+}
+
+// localCredentials are the dummy credentials LocalStack accepts
+var localCredentials = []string{"AWS_ACCESS_KEY_ID=test", "AWS_SECRET_ACCESS_KEY=test"}
+
+// runSSM runs an aws ssm command, retrying transient failures (throttling, network).
+// A non-empty endpoint targets LocalStack with dummy credentials.
+func runSSM(args []string, endpoint string) ([]byte, error) {
+	if endpoint != "" {
+		args = append(args, "--endpoint-url", endpoint)
+	}
+	var out []byte
+	err := retry.Do(func() error {
+		var err error
+		cmd := proc.Command("aws", args...)
+		if endpoint != "" {
+			cmd.Env = append(os.Environ(), localCredentials...)
+		}
+		out, err = cmd.Output()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr := string(exitErr.Stderr)
+			for _, code := range permanentSSMErrors {
+				if strings.Contains(stderr, code) {
+					return retry.Permanent(err)
+				}
+			}
+		}
+		return err
+	})
+	return out, err
+}
+
 // maxSSMParamsPerRequest is the AWS GetParameters limit (10 names per call)
 const maxSSMParamsPerRequest = 10
 
 // FetchMultipleFromSSM retrieves multiple parameters from AWS SSM, batching requests
 // (GetParameters allows at most 10 names per call).
 func FetchMultipleFromSSM(profile, env, region string, paramSuffixes []string) (map[string]string, error) {
+	return FetchMultipleFromSSMAt("", profile, env, region, paramSuffixes)
+}
+
+// FetchMultipleFromSSMAt is FetchMultipleFromSSM against a custom endpoint (LocalStack);
+// an empty endpoint means AWS
+func FetchMultipleFromSSMAt(endpoint, profile, env, region string, paramSuffixes []string) (map[string]string, error) {
 	if region == "" {
 		region = "us-east-1"
 	}
@@ -94,8 +143,7 @@ func FetchMultipleFromSSM(profile, env, region string, paramSuffixes []string) (
 			args = append(args, "--profile", profile)
 		}
 
-		cmd := exec.Command("aws", args...)
-		out, err := cmd.Output()
+		out, err := runSSM(args, endpoint)
 		if err != nil {
 			if exitErr, ok := err.(*exec.ExitError); ok {
 				return nil, fmt.Errorf("failed to fetch parameters: %s", string(exitErr.Stderr))
@@ -116,3 +164,17 @@ func FetchMultipleFromSSM(profile, env, region string, paramSuffixes []string) (
 
 	return result, nil
 }
+
+// PutSSMParameterAt writes a SecureString parameter, overwriting any existing value. Used to
+// seed LocalStack; an empty endpoint means AWS.
+func PutSSMParameterAt(endpoint, region, name, value string) error {
+	args := []string{"ssm", "put-parameter", "--name", name, "--value", value,
+		"--type", "SecureString", "--overwrite", "--region", region}
+	if _, err := runSSM(args, endpoint); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("failed to put %s: %s", name, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return fmt.Errorf("failed to put %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package gradle
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// projectPropertyPrefix makes Gradle expose an env var as a project property
+// (ORG_GRADLE_PROJECT_foo → findProperty("foo"))
+const projectPropertyPrefix = "ORG_GRADLE_PROJECT_"
+
+// LocalPropertiesFile is the untracked per-machine properties file Android builds read
+const LocalPropertiesFile = "local.properties"
+
+// IsProject reports whether dir holds a Gradle build
+func IsProject(dir string) bool {
+	for _, f := range []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectDir returns the Gradle root of a repo: the configured dir, the repo itself, or
+// android/ for React Native apps. Returns "" when there is no Gradle build.
+func ProjectDir(repoDir, configured string) string {
+	if configured != "" {
+		return filepath.Join(repoDir, configured)
+	}
+	if IsProject(repoDir) {
+		return repoDir
+	}
+	if android := filepath.Join(repoDir, "android"); IsProject(android) {
+		return android
+	}
+	return ""
+}
+
+// PropertyEnv returns the env var that sets Gradle project property name
+func PropertyEnv(name string) string {
+	return projectPropertyPrefix + name
+}
+
+// WriteLocalProperties sets keys in dir/local.properties, keeping every other line
+// (e.g. the sdk.dir Android Studio writes) as it is
+func WriteLocalProperties(dir string, values map[string]string) error {
+	path := filepath.Join(dir, LocalPropertiesFile)
+
+	var lines []string
+	seen := make(map[string]bool)
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if key, _, ok := strings.Cut(line, "="); ok && !strings.HasPrefix(strings.TrimSpace(line), "#") {
+				key = strings.TrimSpace(key)
+				if v, managed := values[key]; managed {
+					line = key + "=" + escape(v)
+					seen[key] = true
+				}
+			}
+			lines = append(lines, line)
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		lines = append(lines, k+"="+escape(values[k]))
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// escape writes backslashes (Windows paths) and leading spaces the way java.util.Properties
+// reads them
+func escape(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	if strings.HasPrefix(v, " ") {
+		v = `\` + v
+	}
+	return v
+}
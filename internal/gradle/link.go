@@ -0,0 +1,274 @@
+package gradle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LinksFile holds the substitutions the init script applies, in the Gradle user home
+const LinksFile = "spark-cli-links.json"
+
+// initScriptName is the init script spark-cli keeps in the Gradle user home's init.d/
+const initScriptName = "spark-cli-links.gradle"
+
+// initScript swaps a linked module for an included build of the workspace's model, or
+// pins it to a version published to mavenLocal. It runs for every Gradle build, so it only
+// touches builds whose root is listed in LinksFile.
+const initScript = `// Managed by spark-cli ('spark-cli link', 'spark-cli publish-local'). Do not edit: it
+// is rewritten as links change. Builds listed in ` + LinksFile + ` resolve their Spark
+// model modules from the model's local build (includeBuild) or from a version published
+// to mavenLocal, instead of the published Maven version.
+import groovy.json.JsonSlurper
+
+def linksFile = new File(gradle.gradleUserHomeDir, "` + LinksFile + `")
+if (!linksFile.exists()) {
+    return
+}
+def links = new JsonSlurper().parse(linksFile)
+
+settingsEvaluated { settings ->
+    def substitutions = links[settings.rootDir.canonicalPath]
+    if (!substitutions) {
+        return
+    }
+    def pinned = [:]
+    substitutions.each { coordinates, target ->
+        if (target.version) {
+            pinned[coordinates] = target.version
+            logger.lifecycle("spark-cli: ${coordinates} → ${target.version} (mavenLocal)")
+            return
+        }
+        settings.includeBuild(target.build) {
+            dependencySubstitution {
+                substitute(module(coordinates)).using(project(target.project))
+            }
+        }
+        logger.lifecycle("spark-cli: ${coordinates} → ${target.build}")
+    }
+    if (!pinned) {
+        return
+    }
+
+    settings.dependencyResolutionManagement.repositories { mavenLocal() }
+    def projectRepos = settings.dependencyResolutionManagement.repositoriesMode.get().name() == "PREFER_PROJECT"
+    settings.gradle.beforeProject { project ->
+        if (projectRepos) {
+            project.repositories { mavenLocal() }
+        }
+        project.configurations.configureEach {
+            resolutionStrategy.eachDependency { details ->
+                def version = pinned[(details.requested.group + ":" + details.requested.name).toString()]
+                if (version) {
+                    details.useVersion(version)
+                }
+            }
+        }
+    }
+}
+`
+
+// Substitution is the local build a linked module resolves to
+type Substitution struct {
+	Build   string `json:"build,omitempty"`   // included build dir
+	Project string `json:"project,omitempty"` // project in it that produces the module, e.g. ":" or ":client"
+	Version string `json:"version,omitempty"` // version in mavenLocal, instead of an included build
+}
+
+// IsModule reports whether s is a Maven module ("group:artifact") rather than an npm package
+func IsModule(s string) bool {
+	group, artifact, ok := strings.Cut(s, ":")
+	return ok && group != "" && artifact != "" && !strings.HasPrefix(s, "@") && !strings.Contains(artifact, ":")
+}
+
+// UserHome returns the Gradle user home: $GRADLE_USER_HOME, else ~/.gradle
+func UserHome() (string, error) {
+	if dir := os.Getenv("GRADLE_USER_HOME"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".gradle"), nil
+}
+
+// Link makes the Gradle build in consumerDir resolve module from the build in buildDir
+func Link(consumerDir, module, buildDir string) error {
+	project, err := substitutionProject(buildDir, module)
+	if err != nil {
+		return err
+	}
+	build, err := canonical(buildDir)
+	if err != nil {
+		return err
+	}
+	return updateLinks(consumerDir, func(subs map[string]Substitution) {
+		subs[module] = Substitution{Build: build, Project: project}
+	})
+}
+
+// LinkVersion makes the Gradle build in consumerDir resolve module at version, from
+// mavenLocal
+func LinkVersion(consumerDir, module, version string) error {
+	return updateLinks(consumerDir, func(subs map[string]Substitution) {
+		subs[module] = Substitution{Version: version}
+	})
+}
+
+// Unlink makes the Gradle build in consumerDir resolve module from its repositories again
+func Unlink(consumerDir, module string) error {
+	return updateLinks(consumerDir, func(subs map[string]Substitution) {
+		delete(subs, module)
+	})
+}
+
+// IsLinked reports whether the Gradle build in consumerDir has module substituted
+func IsLinked(consumerDir, module string) bool {
+	_, ok := LinkedTo(consumerDir, module)
+	return ok
+}
+
+// LinkedTo returns what module resolves to in the Gradle build in consumerDir, if it's linked
+func LinkedTo(consumerDir, module string) (Substitution, bool) {
+	links, _, err := readLinks()
+	if err != nil {
+		return Substitution{}, false
+	}
+	key, err := canonical(consumerDir)
+	if err != nil {
+		return Substitution{}, false
+	}
+	sub, ok := links[key][module]
+	return sub, ok
+}
+
+// PruneLinks drops the substitutions of consumer builds that no longer exist and of
+// included builds that are gone, and returns them as "consumerDir → module". dryRun only
+// reports them.
+func PruneLinks(dryRun bool) ([]string, error) {
+	links, _, err := readLinks()
+	if err != nil {
+		return nil, err
+	}
+	var pruned []string
+	for consumerDir, subs := range links {
+		_, consumerErr := os.Stat(consumerDir)
+		var gone []string
+		for module, sub := range subs {
+			if _, err := os.Stat(sub.Build); consumerErr == nil && (sub.Build == "" || err == nil) {
+				continue
+			}
+			gone = append(gone, module)
+			pruned = append(pruned, consumerDir+" → "+module)
+		}
+		if dryRun || len(gone) == 0 {
+			continue
+		}
+		err := updateLinks(consumerDir, func(subs map[string]Substitution) {
+			for _, module := range gone {
+				delete(subs, module)
+			}
+		})
+		if err != nil {
+			return pruned, err
+		}
+	}
+	return pruned, nil
+}
+
+// readLinks returns the substitutions by consumer build root and the file they're kept in
+func readLinks() (map[string]map[string]Substitution, string, error) {
+	home, err := UserHome()
+	if err != nil {
+		return nil, "", err
+	}
+	path := filepath.Join(home, LinksFile)
+	links := make(map[string]map[string]Substitution)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return links, path, nil
+		}
+		return nil, "", err
+	}
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, "", err
+	}
+	return links, path, nil
+}
+
+// updateLinks edits consumerDir's substitutions and rewrites the links file and init
+// script, removing both once nothing is linked
+func updateLinks(consumerDir string, edit func(map[string]Substitution)) error {
+	links, path, err := readLinks()
+	if err != nil {
+		return err
+	}
+	key, err := canonical(consumerDir)
+	if err != nil {
+		return err
+	}
+	subs := links[key]
+	if subs == nil {
+		subs = make(map[string]Substitution)
+	}
+	edit(subs)
+	if len(subs) == 0 {
+		delete(links, key)
+	} else {
+		links[key] = subs
+	}
+
+	scriptPath := filepath.Join(filepath.Dir(path), "init.d", initScriptName)
+	if len(links) == 0 {
+		for _, p := range []string{path, scriptPath} {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(scriptPath), 0755); err != nil {
+		return err
+	}
+	if current, err := os.ReadFile(scriptPath); err != nil || !bytes.Equal(current, []byte(initScript)) {
+		if err := os.WriteFile(scriptPath, []byte(initScript), 0644); err != nil {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(links, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// substitutionProject returns the project in buildDir that produces module: a
+// subproject named after its artifact when there is one, else the root project
+func substitutionProject(buildDir, module string) (string, error) {
+	if !IsProject(buildDir) {
+		return "", fmt.Errorf("%s isn't a Gradle build", buildDir)
+	}
+	_, artifact, _ := strings.Cut(module, ":")
+	if IsProject(filepath.Join(buildDir, artifact)) {
+		return ":" + artifact, nil
+	}
+	return ":", nil
+}
+
+// canonical resolves dir the way Gradle reports a build's rootDir (absolute, no symlinks)
+func canonical(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved, nil
+	}
+	return abs, nil
+}
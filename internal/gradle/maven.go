@@ -0,0 +1,90 @@
+package gradle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellquote"
+)
+
+// LocalVersionPrefix marks versions spark-cli publishes to mavenLocal, so they can't be
+// mistaken for (or collide with) released ones
+const LocalVersionPrefix = "0.0.0-spk-local-"
+
+// LocalVersion returns the marker version for a build of the model at commit
+func LocalVersion(commit string) string {
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	if commit == "" {
+		commit = "dev"
+	}
+	return LocalVersionPrefix + commit
+}
+
+// MavenLocalDir returns ~/.m2/repository
+func MavenLocalDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".m2", "repository"), nil
+}
+
+// moduleDir returns module's directory in mavenLocal, e.g. com/spark/client for
+// com.spark:client
+func moduleDir(module string) (string, error) {
+	repo, err := MavenLocalDir()
+	if err != nil {
+		return "", err
+	}
+	group, artifact, _ := strings.Cut(module, ":")
+	return filepath.Join(repo, filepath.FromSlash(strings.ReplaceAll(group, ".", "/")), artifact), nil
+}
+
+// InMavenLocal reports whether module is in mavenLocal at version
+func InMavenLocal(module, version string) bool {
+	dir, err := moduleDir(module)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, version, fmt.Sprintf("%s-%s.pom", filepath.Base(dir), version)))
+	return err == nil
+}
+
+// RemoveLocalVersions deletes module's marker versions from mavenLocal and returns them
+func RemoveLocalVersions(module string) ([]string, error) {
+	dir, err := moduleDir(module)
+	if err != nil {
+		return nil, err
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, LocalVersionPrefix+"*"))
+	var removed []string
+	for _, m := range matches {
+		if err := os.RemoveAll(m); err != nil {
+			return removed, err
+		}
+		removed = append(removed, filepath.Base(m))
+	}
+	return removed, nil
+}
+
+// PublishLocalCommand returns the command that publishes the Gradle build in dir to
+// mavenLocal at version, using the nearest gradlew between dir and root
+func PublishLocalCommand(dir, root, version string) string {
+	return fmt.Sprintf("%s -p %s publishToMavenLocal -Pversion=%s", wrapper(dir, root), shellquote.Quote(dir), shellquote.Quote(version))
+}
+
+// wrapper returns the nearest gradlew from dir up to root, else plain gradle
+func wrapper(dir, root string) string {
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, "gradlew")); err == nil {
+			return shellquote.Quote(filepath.Join(d, "gradlew"))
+		}
+		if rel, err := filepath.Rel(root, d); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			return "gradle"
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package gradle
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+)
+
+// Task is a task listed by `gradlew tasks --all`
+type Task struct {
+	Name        string `json:"name"`
+	Group       string `json:"group"`
+	Description string `json:"description,omitempty"`
+}
+
+// taskCache is the on-disk task list, keyed by a hash of the build scripts
+type taskCache struct {
+	Hash  string `json:"hash"`
+	Tasks []Task `json:"tasks"`
+}
+
+// buildFiles are the scripts whose changes can add or remove tasks
+var buildFiles = map[string]bool{
+	"build.gradle":        true,
+	"build.gradle.kts":    true,
+	"settings.gradle":     true,
+	"settings.gradle.kts": true,
+	"gradle.properties":   true,
+	"libs.versions.toml":  true,
+}
+
+// Tasks returns dir's tasks, running `./gradlew tasks --all -q` only when the build
+// scripts changed since the list cached at cachePath was written
+func Tasks(dir, cachePath string) ([]Task, error) {
+	hash := buildFilesHash(dir)
+	if c, err := readTaskCache(cachePath); err == nil && c.Hash == hash {
+		return c.Tasks, nil
+	}
+
+	cmd := proc.Command("./gradlew", "tasks", "--all", "-q")
+	cmd.Dir = dir
+	cmd.Stderr = io.Discard
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("./gradlew tasks failed in %s: %w", dir, err)
+	}
+	tasks := ParseTasks(string(out))
+
+	if data, err := json.MarshalIndent(taskCache{Hash: hash, Tasks: tasks}, "", "  "); err == nil {
+		if os.MkdirAll(filepath.Dir(cachePath), 0755) == nil {
+			os.WriteFile(cachePath, data, 0644)
+		}
+	}
+	return tasks, nil
+}
+
+// CachedTasks returns the task list cached at cachePath without running Gradle, or nil
+// if there is none
+func CachedTasks(cachePath string) []Task {
+	c, err := readTaskCache(cachePath)
+	if err != nil {
+		return nil
+	}
+	return c.Tasks
+}
+
+func readTaskCache(path string) (*taskCache, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c taskCache
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ParseTasks reads the report printed by `gradlew tasks --all -q`: groups titled by a
+// heading underlined with dashes, then "name - description" lines. The "Rules" section
+// describes task patterns, not tasks, and is skipped.
+func ParseTasks(out string) []Task {
+	var tasks []Task
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), " \r"))
+	}
+
+	group := ""
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if i+1 < len(lines) && line != "" && strings.Trim(lines[i+1], "-") == "" && strings.HasPrefix(lines[i+1], "---") {
+			group = strings.TrimSuffix(line, " tasks")
+			i++
+			continue
+		}
+		if line == "" || group == "" || group == "Rules" || strings.HasPrefix(line, " ") {
+			continue
+		}
+		name, desc, _ := strings.Cut(line, " - ")
+		if strings.ContainsAny(name, " \t") {
+			continue
+		}
+		tasks = append(tasks, Task{Name: name, Group: group, Description: desc})
+	}
+	return tasks
+}
+
+// buildFilesHash hashes the build scripts at the root and up to two levels down
+// (subprojects, buildSrc, gradle/libs.versions.toml)
+func buildFilesHash(dir string) string {
+	h := sha256.New()
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if d.IsDir() {
+			if path != dir && (strings.HasPrefix(d.Name(), ".") || d.Name() == "build" || strings.Count(rel, string(filepath.Separator)) >= 2) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !buildFiles[d.Name()] {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		io.WriteString(h, rel+"\x00")
+		h.Write(data)
+		return nil
+	})
+	return hex.EncodeToString(h.Sum(nil))
+}
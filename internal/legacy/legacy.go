@@ -0,0 +1,462 @@
+package legacy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Script is what a legacy sync.sh or .env template sets up, in workspace.json terms
+type Script struct {
+	// Params maps SSM parameter names under /app/<env>/ to the env key each is written as
+	Params map[string]string
+	// Environments maps environment names to the AWS profile and region used for them
+	Environments map[string]Environment
+	// Profile and Region are what the script used without naming an environment
+	Profile string
+	Region  string
+	// Env holds values exported as literals
+	Env map[string]string
+	// Skipped lists what couldn't be converted, and why
+	Skipped []string
+}
+
+// Environment is the AWS profile and region a script used for one environment
+type Environment struct {
+	Profile string
+	Region  string
+}
+
+var (
+	ssmPath     = regexp.MustCompile(`^/app/([^/]+)/(.+)$`)
+	assignment  = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=(.*)$`)
+	caseLabel   = regexp.MustCompile(`^\(?\s*([A-Za-z0-9_.*|"' -]+)\)\s*(.*)$`)
+	varRef      = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*|[0-9])(:?-([^}]*))?\}|\$([A-Za-z_][A-Za-z0-9_]*|[0-9])`)
+	unresolved  = regexp.MustCompile(`\$[{A-Za-z0-9_]`)
+	profileVars = regexp.MustCompile(`(^|_)PROFILE$`)
+	regionVars  = regexp.MustCompile(`(^|_)REGION$`)
+)
+
+// Parse reads the script or env template at path. Shell scripts (a .sh file, or one
+// with a #! line) contribute only exported variables to Env; in a template every
+// KEY=value line counts.
+func Parse(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	content := string(data)
+	p := &parser{
+		Script: &Script{
+			Params:       make(map[string]string),
+			Environments: make(map[string]Environment),
+			Env:          make(map[string]string),
+		},
+		shell:  filepath.Ext(path) == ".sh" || strings.HasPrefix(content, "#!"),
+		vars:   make(map[string]string),
+		perEnv: make(map[string]bool),
+	}
+	for _, line := range joinContinuations(content) {
+		p.line(line)
+	}
+	return p.Script, nil
+}
+
+type parser struct {
+	*Script
+	shell  bool
+	vars   map[string]string
+	perEnv map[string]bool // variables that depend on the environment the script is run for
+	inCase bool
+	labels []string // environments of the case branch being read
+}
+
+// line handles one logical line of the script
+func (p *parser) line(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+	if !p.shell {
+		p.statement(append([]string{"export"}, words(line)...))
+		return
+	}
+	switch first := strings.Fields(line)[0]; {
+	case first == "case":
+		p.inCase = true
+		return
+	case first == "esac":
+		p.inCase, p.labels = false, nil
+		return
+	}
+	if p.inCase && p.labels == nil {
+		if m := caseLabel.FindStringSubmatch(line); m != nil {
+			for _, l := range strings.Split(m[1], "|") {
+				if l = strings.Trim(strings.TrimSpace(l), `"'`); l != "" && l != "*" {
+					p.labels = append(p.labels, l)
+				}
+			}
+			if p.labels == nil {
+				p.labels = []string{}
+			}
+			line = m[2]
+		}
+	}
+
+	var stmt []string
+	for _, w := range append(words(line), ";") {
+		switch w {
+		case ";", "&&", "||", "|", ";;":
+			p.statement(stmt)
+			stmt = nil
+			if w == ";;" {
+				p.labels = nil
+			}
+		default:
+			stmt = append(stmt, w)
+		}
+	}
+}
+
+// statement handles one simple command
+func (p *parser) statement(ws []string) {
+	if len(ws) == 0 {
+		return
+	}
+	exported := false
+	if ws[0] == "export" {
+		exported, ws = true, ws[1:]
+	}
+	for len(ws) > 0 {
+		m := assignment.FindStringSubmatch(ws[0])
+		if m == nil {
+			break
+		}
+		p.assign(m[1], m[2], exported)
+		ws = ws[1:]
+	}
+	if len(ws) > 0 {
+		p.ssmCall(ws, "")
+	}
+}
+
+// assign records NAME=value: as a variable later lines expand, and, when exported, as a
+// workspace env value or the profile or region of the environment being read
+func (p *parser) assign(name, value string, exported bool) {
+	if inner, ok := substitution(value); ok {
+		if !p.ssmCall(words(inner), name) {
+			p.skip("%s: set by a command spark-cli can't translate (%s)", name, inner)
+		}
+		return
+	}
+	value, perEnv := p.expandEnv(value)
+	switch {
+	case profileVars.MatchString(name):
+		p.setAWS(value, "")
+	case regionVars.MatchString(name):
+		p.setAWS("", value)
+	}
+	if !p.inCase {
+		p.vars[name] = value
+		p.perEnv[name] = perEnv
+	}
+	if !exported || profileVars.MatchString(name) || regionVars.MatchString(name) {
+		return
+	}
+	switch {
+	case p.labels != nil || perEnv:
+		p.skip("%s: differs per environment — put it in SSM under /app/<env>/ instead", name)
+	case unresolved.MatchString(value):
+		p.skip("%s: built from variables spark-cli can't resolve (%s)", name, value)
+	case value == "":
+		p.skip("%s: no value — add it to workspace.json \"env\" or SSM", name)
+	default:
+		p.Env[name] = value
+	}
+}
+
+// ssmCall records the SSM parameters an `aws ssm get-parameter(s)` command reads, writing
+// a single parameter as target when set. It reports whether ws was such a command.
+func (p *parser) ssmCall(ws []string, target string) bool {
+	for j, w := range ws {
+		if w == ";" || w == "|" || w == "&&" || w == "||" {
+			ws = ws[:j]
+			break
+		}
+	}
+	i := indexOf(ws, "ssm")
+	if i < 1 || ws[i-1] != "aws" || i+1 >= len(ws) {
+		return false
+	}
+	sub := ws[i+1]
+	if sub != "get-parameter" && sub != "get-parameters" && sub != "get-parameters-by-path" {
+		return false
+	}
+	var names []string
+	var profile, region string
+	for j := i + 2; j < len(ws); j++ {
+		flag, value, hasValue := strings.Cut(ws[j], "=")
+		if !hasValue && j+1 < len(ws) {
+			value = ws[j+1]
+		}
+		switch flag {
+		case "--name", "--path":
+			names = append(names, value)
+		case "--names":
+			if hasValue {
+				names = append(names, value)
+				continue
+			}
+			for ; j+1 < len(ws) && !strings.HasPrefix(ws[j+1], "--"); j++ {
+				names = append(names, ws[j+1])
+			}
+		case "--profile":
+			profile = value
+		case "--region":
+			region = value
+		}
+	}
+	if sub == "get-parameters-by-path" {
+		p.skip("aws ssm get-parameters-by-path %s: list the parameters it reads in workspace.json \"ssm_params\"", strings.Join(names, " "))
+		return true
+	}
+
+	envs := map[string]bool{}
+	for _, name := range names {
+		name = p.expand(name)
+		m := ssmPath.FindStringSubmatch(name)
+		if m == nil || unresolved.MatchString(m[2]) {
+			p.skip("SSM parameter %s: not under /app/<env>/, where spark-cli reads from", name)
+			continue
+		}
+		key := m[2]
+		if target != "" && len(names) == 1 {
+			key = target
+		}
+		p.Params[m[2]] = key
+		if !unresolved.MatchString(m[1]) {
+			envs[m[1]] = true
+		}
+	}
+	profile, region = p.expand(profile), p.expand(region)
+	if len(envs) == 0 || p.labels != nil {
+		p.setAWS(profile, region)
+		return true
+	}
+	for env := range envs {
+		p.setEnvAWS(env, profile, region)
+	}
+	return true
+}
+
+// setAWS records a profile or region for the environments of the case branch being
+// read, or for the script as a whole
+func (p *parser) setAWS(profile, region string) {
+	if unresolved.MatchString(profile) {
+		profile = ""
+	}
+	if unresolved.MatchString(region) {
+		region = ""
+	}
+	if len(p.labels) > 0 {
+		for _, env := range p.labels {
+			p.setEnvAWS(env, profile, region)
+		}
+		return
+	}
+	if p.labels != nil {
+		return
+	}
+	if profile != "" {
+		p.Profile = profile
+	}
+	if region != "" {
+		p.Region = region
+	}
+}
+
+func (p *parser) setEnvAWS(env, profile, region string) {
+	if unresolved.MatchString(profile) {
+		profile = ""
+	}
+	if unresolved.MatchString(region) {
+		region = ""
+	}
+	if profile == "" && region == "" {
+		return
+	}
+	e := p.Environments[env]
+	if profile != "" {
+		e.Profile = profile
+	}
+	if region != "" {
+		e.Region = region
+	}
+	p.Environments[env] = e
+}
+
+// expand substitutes the variables the script set, and ${VAR:-default} defaults for
+// ones it didn't (like ${1:-beta}). Anything else is left as written.
+func (p *parser) expand(s string) string {
+	s, _ = p.expandEnv(s)
+	return s
+}
+
+// expandEnv is expand that also reports whether the result depends on the environment
+// the script is run for: on its arguments, directly or through another variable
+func (p *parser) expandEnv(s string) (string, bool) {
+	perEnv := false
+	s = varRef.ReplaceAllStringFunc(s, func(ref string) string {
+		m := varRef.FindStringSubmatch(ref)
+		name := m[1] + m[4]
+		if p.perEnv[name] || (name[0] >= '0' && name[0] <= '9') {
+			perEnv = true
+		}
+		if v, ok := p.vars[name]; ok && v != "" {
+			return v
+		}
+		if m[2] != "" {
+			return m[3]
+		}
+		return ref
+	})
+	return s, perEnv
+}
+
+func (p *parser) skip(format string, args ...any) {
+	p.Skipped = append(p.Skipped, fmt.Sprintf(format, args...))
+}
+
+// substitution returns the command inside a $(...) or `...` value
+func substitution(value string) (string, bool) {
+	switch {
+	case strings.HasPrefix(value, "$(") && strings.HasSuffix(value, ")"):
+		return value[2 : len(value)-1], true
+	case strings.HasPrefix(value, "`") && strings.HasSuffix(value, "`") && len(value) > 1:
+		return value[1 : len(value)-1], true
+	}
+	return "", false
+}
+
+// joinContinuations splits content into lines, joining those ending in a backslash
+func joinContinuations(content string) []string {
+	var lines []string
+	var cur strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if strings.HasSuffix(line, "\\") {
+			cur.WriteString(strings.TrimSuffix(line, "\\"))
+			cur.WriteString(" ")
+			continue
+		}
+		cur.WriteString(line)
+		lines = append(lines, cur.String())
+		cur.Reset()
+	}
+	return lines
+}
+
+// words splits a shell command line into words: quotes are removed, $(...) and `...`
+// stay whole, and the operators ; ;; | || && come out as words of their own. A # that
+// starts a word comments out the rest of the line.
+func words(line string) []string {
+	var out []string
+	var cur strings.Builder
+	inWord := false
+	flush := func() {
+		if inWord {
+			out = append(out, cur.String())
+			cur.Reset()
+			inWord = false
+		}
+	}
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == ' ' || c == '\t':
+			flush()
+		case c == '#' && !inWord:
+			flush()
+			return out
+		case c == ';' || c == '|' || c == '&':
+			flush()
+			op := string(c)
+			if i+1 < len(line) && (line[i+1] == c) {
+				op += string(c)
+				i++
+			}
+			if op != "&" {
+				out = append(out, op)
+			}
+		case c == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				end = len(line) - i - 1
+			}
+			cur.WriteString(line[i+1 : i+1+end])
+			inWord = true
+			i += end + 1
+		case c == '"':
+			inWord = true
+			for i++; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' && i+1 < len(line) {
+					i++
+				} else if line[i] == '$' && i+1 < len(line) && line[i+1] == '(' {
+					n := matchParen(line, i+1)
+					cur.WriteString(line[i:n])
+					i = n - 1
+					continue
+				}
+				cur.WriteByte(line[i])
+			}
+		case c == '$' && i+1 < len(line) && line[i+1] == '(':
+			n := matchParen(line, i+1)
+			cur.WriteString(line[i:n])
+			inWord = true
+			i = n - 1
+		case c == '`':
+			end := strings.IndexByte(line[i+1:], '`')
+			if end < 0 {
+				end = len(line) - i - 1
+			}
+			cur.WriteString("$(" + line[i+1:i+1+end] + ")")
+			inWord = true
+			i += end + 1
+		case c == '\\' && i+1 < len(line):
+			i++
+			cur.WriteByte(line[i])
+			inWord = true
+		default:
+			cur.WriteByte(c)
+			inWord = true
+		}
+	}
+	flush()
+	return out
+}
+
+// matchParen returns the index just past the parenthesis matching the one at open
+func matchParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(s)
+}
+
+func indexOf(ws []string, w string) int {
+	for i, x := range ws {
+		if x == w {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,185 @@
+package licenses
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Unknown is reported for packages that declare no license
+const Unknown = "UNKNOWN"
+
+// GradleReport is where the com.github.jk1.dependency-license-report plugin's JSON renderer
+// writes, relative to the repo
+const GradleReport = "build/reports/dependency-license/index.json"
+
+// Dependency is one production dependency and the repos that ship it
+type Dependency struct {
+	Name      string   `json:"name"`
+	Version   string   `json:"version"`
+	License   string   `json:"license"`
+	Ecosystem string   `json:"ecosystem"` // npm or maven
+	Repos     []string `json:"repos"`
+}
+
+// Node reads the production dependencies from package-lock.json, taking licenses from the
+// lockfile and falling back to each installed package.json
+func Node(repoDir string) ([]Dependency, error) {
+	data, err := os.ReadFile(filepath.Join(repoDir, "package-lock.json"))
+	if err != nil {
+		return nil, fmt.Errorf("no package-lock.json")
+	}
+	var lock struct {
+		Packages map[string]struct {
+			Name    string          `json:"name"`
+			Version string          `json:"version"`
+			License json.RawMessage `json:"license"`
+			Dev     bool            `json:"dev"`
+			Link    bool            `json:"link"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("invalid package-lock.json: %w", err)
+	}
+	if lock.Packages == nil {
+		return nil, fmt.Errorf("package-lock.json is lockfileVersion 1 — run npm install with npm 7+ to upgrade it")
+	}
+
+	var deps []Dependency
+	for path, p := range lock.Packages {
+		if path == "" || p.Dev || p.Link || !strings.Contains(path, "node_modules/") {
+			continue
+		}
+		name := p.Name
+		if name == "" {
+			name = path[strings.LastIndex(path, "node_modules/")+len("node_modules/"):]
+		}
+		license := parseLicense(p.License)
+		if license == Unknown {
+			license = installedLicense(filepath.Join(repoDir, path))
+		}
+		deps = append(deps, Dependency{Name: name, Version: p.Version, License: license, Ecosystem: "npm"})
+	}
+	return deps, nil
+}
+
+func installedLicense(pkgDir string) string {
+	data, err := os.ReadFile(filepath.Join(pkgDir, "package.json"))
+	if err != nil {
+		return Unknown
+	}
+	var pkg struct {
+		License  json.RawMessage   `json:"license"`
+		Licenses []json.RawMessage `json:"licenses"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return Unknown
+	}
+	if l := parseLicense(pkg.License); l != Unknown {
+		return l
+	}
+	var names []string
+	for _, raw := range pkg.Licenses {
+		if l := parseLicense(raw); l != Unknown {
+			names = append(names, l)
+		}
+	}
+	if len(names) == 0 {
+		return Unknown
+	}
+	return "(" + strings.Join(names, " OR ") + ")"
+}
+
+// parseLicense accepts the SPDX string form and the legacy {"type": ...} object form
+func parseLicense(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil && s != "" {
+		return s
+	}
+	var obj struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(raw, &obj) == nil && obj.Type != "" {
+		return obj.Type
+	}
+	return Unknown
+}
+
+// Gradle reads the report generated by the dependency-license-report plugin's JSON renderer
+func Gradle(repoDir string) ([]Dependency, error) {
+	data, err := os.ReadFile(filepath.Join(repoDir, GradleReport))
+	if err != nil {
+		return nil, fmt.Errorf("no %s — apply com.github.jk1.dependency-license-report with the JsonReportRenderer", GradleReport)
+	}
+	var report struct {
+		Dependencies []struct {
+			ModuleName     string `json:"moduleName"`
+			ModuleVersion  string `json:"moduleVersion"`
+			ModuleLicenses []struct {
+				ModuleLicense string `json:"moduleLicense"`
+			} `json:"moduleLicenses"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", GradleReport, err)
+	}
+
+	var deps []Dependency
+	for _, d := range report.Dependencies {
+		var names []string
+		for _, l := range d.ModuleLicenses {
+			if l.ModuleLicense != "" && !contains(names, l.ModuleLicense) {
+				names = append(names, l.ModuleLicense)
+			}
+		}
+		license := Unknown
+		if len(names) > 0 {
+			license = strings.Join(names, " OR ")
+		}
+		deps = append(deps, Dependency{Name: d.ModuleName, Version: d.ModuleVersion, License: license, Ecosystem: "maven"})
+	}
+	return deps, nil
+}
+
+// Merge dedupes dependencies by ecosystem, name, and version across repos, sorted by name
+func Merge(perRepo map[string][]Dependency) []Dependency {
+	byKey := make(map[string]*Dependency)
+	for repo, deps := range perRepo {
+		for _, d := range deps {
+			key := d.Ecosystem + ":" + d.Name + "@" + d.Version
+			m, ok := byKey[key]
+			if !ok {
+				m = &Dependency{Name: d.Name, Version: d.Version, License: d.License, Ecosystem: d.Ecosystem}
+				byKey[key] = m
+			}
+			if !contains(m.Repos, repo) {
+				m.Repos = append(m.Repos, repo)
+			}
+		}
+	}
+
+	merged := make([]Dependency, 0, len(byKey))
+	for _, d := range byKey {
+		sort.Strings(d.Repos)
+		merged = append(merged, *d)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Name != merged[j].Name {
+			return merged[i].Name < merged[j].Name
+		}
+		return merged[i].Version < merged[j].Version
+	})
+	return merged
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+)
+
+// Dir is the workspace metrics directory (.spk/metrics); nothing in it leaves the machine
+const Dir = "metrics"
+
+// historyFile holds one JSON entry per line, append-only
+const historyFile = "history.jsonl"
+
+// Entry is one timed operation
+type Entry struct {
+	Time       string `json:"time"`
+	Repo       string `json:"repo"`
+	Op         string `json:"op"`
+	DurationMs int64  `json:"duration_ms"`
+	OK         bool   `json:"ok"`
+}
+
+// Duration returns the entry's duration
+func (e Entry) Duration() time.Duration {
+	return time.Duration(e.DurationMs) * time.Millisecond
+}
+
+// HistoryPath returns .spk/metrics/history.jsonl for a workspace
+func HistoryPath(wsPath string) string {
+	return filepath.Join(wsPath, config.SparkDir, Dir, historyFile)
+}
+
+// Record appends an entry to the workspace history. Failures are returned but callers
+// generally ignore them — metrics must never break a build.
+func Record(wsPath string, e Entry) error {
+	if e.Time == "" {
+		e.Time = time.Now().UTC().Format(time.RFC3339)
+	}
+	path := HistoryPath(wsPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Time runs fn and records how long it took under repo/op
+func Time(wsPath, repo, op string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	Record(wsPath, Entry{
+		Repo:       repo,
+		Op:         op,
+		DurationMs: time.Since(start).Milliseconds(),
+		OK:         err == nil,
+	})
+	return err
+}
+
+// Load reads all recorded entries, oldest first. Malformed lines are skipped.
+func Load(wsPath string) ([]Entry, error) {
+	f, err := os.Open(HistoryPath(wsPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read metrics: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, sc.Err()
+}
+
+// Prune drops entries recorded before cutoff and returns how many it dropped. Entries
+// whose time can't be parsed are kept.
+func Prune(wsPath string, cutoff time.Time) (int, error) {
+	entries, err := Load(wsPath)
+	if err != nil {
+		return 0, err
+	}
+	var kept []byte
+	dropped := 0
+	for _, e := range entries {
+		if t, err := time.Parse(time.RFC3339, e.Time); err == nil && t.Before(cutoff) {
+			dropped++
+			continue
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			return 0, err
+		}
+		kept = append(append(kept, data...), '\n')
+	}
+	if dropped == 0 {
+		return 0, nil
+	}
+	return dropped, os.WriteFile(HistoryPath(wsPath), kept, 0644)
+}
@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Profile phases; scripts other than build are reported under their own name
+const (
+	PhaseGit     = "git"
+	PhaseInstall = "install"
+	PhaseCompile = "compile"
+	PhaseLink    = "link"
+)
+
+// barWidth is the width of the longest bar in the profile summary
+const barWidth = 40
+
+var (
+	profMu    sync.Mutex
+	profiling bool
+	profStart time.Time
+	spans     = make(map[string]map[string]time.Duration)
+)
+
+// EnableProfile turns on span collection for --profile
+func EnableProfile() {
+	profMu.Lock()
+	defer profMu.Unlock()
+	profiling = true
+	profStart = time.Now()
+}
+
+// Profiling reports whether --profile is active
+func Profiling() bool {
+	profMu.Lock()
+	defer profMu.Unlock()
+	return profiling
+}
+
+// Span starts timing a phase for a repo and returns the func that stops it.
+// It is a no-op unless profiling is enabled, so callers can use it unconditionally:
+//
+//	defer metrics.Span(name, metrics.PhaseGit)()
+func Span(repo, phase string) func() {
+	if !Profiling() {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		d := time.Since(start)
+		profMu.Lock()
+		defer profMu.Unlock()
+		if spans[repo] == nil {
+			spans[repo] = make(map[string]time.Duration)
+		}
+		spans[repo][phase] += d
+	}
+}
+
+// PrintProfile writes a flame-style breakdown of the collected spans: repos sorted by
+// total time, each with its phases as proportional bars
+func PrintProfile(w io.Writer) {
+	profMu.Lock()
+	defer profMu.Unlock()
+	if !profiling {
+		return
+	}
+
+	type phaseTime struct {
+		name string
+		d    time.Duration
+	}
+	type repoTime struct {
+		name   string
+		total  time.Duration
+		phases []phaseTime
+	}
+
+	var repos []repoTime
+	var longest time.Duration
+	for repo, phases := range spans {
+		rt := repoTime{name: repo}
+		for phase, d := range phases {
+			rt.total += d
+			rt.phases = append(rt.phases, phaseTime{phase, d})
+		}
+		if rt.total < time.Millisecond {
+			continue
+		}
+		sort.Slice(rt.phases, func(i, j int) bool { return rt.phases[i].d > rt.phases[j].d })
+		longest = max(longest, rt.total)
+		repos = append(repos, rt)
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].total > repos[j].total })
+
+	fmt.Fprintf(w, "\nProfile (wall %s)\n", time.Since(profStart).Round(time.Millisecond))
+	if len(repos) == 0 {
+		fmt.Fprintln(w, "  nothing recorded")
+		return
+	}
+	for _, rt := range repos {
+		fmt.Fprintf(w, "  %-25s %9s %s\n", rt.name, rt.total.Round(time.Millisecond), bar(rt.total, longest))
+		for _, p := range rt.phases {
+			pct := float64(p.d) / float64(rt.total) * 100
+			fmt.Fprintf(w, "    %-23s %9s %s %.0f%%\n", p.name, p.d.Round(time.Millisecond), bar(p.d, longest), pct)
+		}
+	}
+}
+
+func bar(d, longest time.Duration) string {
+	if longest <= 0 {
+		return ""
+	}
+	n := int(float64(d) / float64(longest) * barWidth)
+	if n == 0 && d > 0 {
+		n = 1
+	}
+	return strings.Repeat("█", n)
+}
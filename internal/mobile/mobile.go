@@ -0,0 +1,237 @@
+package mobile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+)
+
+// MetroPort is the default port the metro bundler listens on
+const MetroPort = 8081
+
+// Device is a simulator, emulator, or attached device an app can be launched on
+type Device struct {
+	Name   string
+	ID     string // simulator UDID or adb serial
+	Booted bool
+}
+
+// IsExpo reports whether the app is driven by the Expo CLI rather than the React Native CLI
+func IsExpo(repoDir string) bool {
+	data, err := os.ReadFile(filepath.Join(repoDir, "package.json"))
+	if err != nil {
+		return false
+	}
+	var pkg struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return false
+	}
+	_, ok := pkg.Dependencies["expo"]
+	return ok
+}
+
+// PodInstallNeeded reports whether ios/Pods is out of date with Podfile.lock — the same
+// check CocoaPods' "sandbox is not in sync" build phase does
+func PodInstallNeeded(iosDir string) (bool, string) {
+	if _, err := os.Stat(filepath.Join(iosDir, "Podfile")); err != nil {
+		return false, ""
+	}
+	lock, err := os.ReadFile(filepath.Join(iosDir, "Podfile.lock"))
+	if err != nil {
+		return true, "no Podfile.lock"
+	}
+	manifest, err := os.ReadFile(filepath.Join(iosDir, "Pods", "Manifest.lock"))
+	if err != nil {
+		return true, "Pods not installed"
+	}
+	if !bytes.Equal(lock, manifest) {
+		return true, "Podfile.lock changed"
+	}
+	return false, ""
+}
+
+// PodInstallCommand uses the repo's Gemfile (bundle exec) when it pins CocoaPods
+func PodInstallCommand(repoDir string) string {
+	if _, err := os.Stat(filepath.Join(repoDir, "Gemfile")); err == nil {
+		return "bundle exec pod install"
+	}
+	return "pod install"
+}
+
+// MetroRunning reports whether something is already listening on the metro port
+func MetroRunning(port int) bool {
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), 300*time.Millisecond)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// LinkedPackageDirs returns the real directories of @spark-rewards packages that are
+// symlinked into node_modules. Metro doesn't follow symlinks outside the project root,
+// so these have to be added to its watchFolders.
+func LinkedPackageDirs(repoDir string) []string {
+	scope := filepath.Join(repoDir, "node_modules", "@spark-rewards")
+	entries, err := os.ReadDir(scope)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.Type()&os.ModeSymlink == 0 {
+			continue
+		}
+		if real, err := filepath.EvalSymlinks(filepath.Join(scope, e.Name())); err == nil {
+			dirs = append(dirs, real)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// Simulators lists the available iOS simulators, booted ones first
+func Simulators() ([]Device, error) {
+	out, err := proc.Command("xcrun", "simctl", "list", "devices", "available", "-j").Output()
+	if err != nil {
+		return nil, fmt.Errorf("xcrun simctl failed — is Xcode installed?")
+	}
+	var list struct {
+		Devices map[string][]struct {
+			Name  string `json:"name"`
+			UDID  string `json:"udid"`
+			State string `json:"state"`
+		} `json:"devices"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, err
+	}
+
+	var devices []Device
+	for runtime, sims := range list.Devices {
+		if !strings.Contains(runtime, "iOS") {
+			continue
+		}
+		for _, s := range sims {
+			devices = append(devices, Device{Name: s.Name, ID: s.UDID, Booted: s.State == "Booted"})
+		}
+	}
+	sortDevices(devices)
+	return devices, nil
+}
+
+// AndroidDevices lists devices and running emulators known to adb
+func AndroidDevices() ([]Device, error) {
+	out, err := proc.Command("adb", "devices", "-l").Output()
+	if err != nil {
+		return nil, fmt.Errorf("adb failed — is the Android SDK platform-tools on PATH?")
+	}
+	var devices []Device
+	for _, line := range strings.Split(string(out), "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[1] != "device" {
+			continue
+		}
+		d := Device{Name: fields[0], ID: fields[0], Booted: true}
+		for _, f := range fields[2:] {
+			if model, ok := strings.CutPrefix(f, "model:"); ok {
+				d.Name = fmt.Sprintf("%s (%s)", model, fields[0])
+			}
+		}
+		devices = append(devices, d)
+	}
+	return devices, nil
+}
+
+func sortDevices(devices []Device) {
+	sort.SliceStable(devices, func(i, j int) bool {
+		if devices[i].Booted != devices[j].Booted {
+			return devices[i].Booted
+		}
+		return devices[i].Name < devices[j].Name
+	})
+}
+
+// CacheDirs returns the metro/haste caches, build outputs, and Xcode DerivedData that
+// go stale for the app at repoDir. deep adds the installed Pods and Gradle caches, which
+// are slow to rebuild. Only paths that exist are returned.
+func CacheDirs(repoDir string, deep bool) []string {
+	var candidates []string
+
+	tmp := os.TempDir()
+	for _, pattern := range []string{"metro-*", "haste-map-*", "react-native-packager-cache-*", "react-*"} {
+		matches, _ := filepath.Glob(filepath.Join(tmp, pattern))
+		candidates = append(candidates, matches...)
+	}
+	candidates = append(candidates,
+		filepath.Join(repoDir, "node_modules", ".cache"),
+		filepath.Join(repoDir, ".expo"),
+		filepath.Join(repoDir, "ios", "build"),
+		filepath.Join(repoDir, "android", "build"),
+		filepath.Join(repoDir, "android", "app", "build"),
+	)
+
+	if home, err := os.UserHomeDir(); err == nil {
+		derived := filepath.Join(home, "Library", "Developer", "Xcode", "DerivedData")
+		for _, name := range xcodeProjectNames(filepath.Join(repoDir, "ios")) {
+			matches, _ := filepath.Glob(filepath.Join(derived, name+"-*"))
+			candidates = append(candidates, matches...)
+		}
+	}
+
+	if deep {
+		candidates = append(candidates,
+			filepath.Join(repoDir, "ios", "Pods"),
+			filepath.Join(repoDir, "android", ".gradle"),
+		)
+	}
+
+	var dirs []string
+	for _, c := range candidates {
+		if _, err := os.Lstat(c); err == nil {
+			dirs = append(dirs, c)
+		}
+	}
+	return dirs
+}
+
+// xcodeProjectNames returns the workspace/project names under iosDir; DerivedData
+// folders are named <name>-<hash>
+func xcodeProjectNames(iosDir string) []string {
+	var names []string
+	for _, ext := range []string{".xcworkspace", ".xcodeproj"} {
+		matches, _ := filepath.Glob(filepath.Join(iosDir, "*"+ext))
+		for _, m := range matches {
+			name := strings.TrimSuffix(filepath.Base(m), ext)
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// ResetWatchman drops watchman's watch (and its cached crawl) for dir. Returns false
+// when watchman isn't installed.
+func ResetWatchman(dir string) (bool, error) {
+	if _, err := exec.LookPath("watchman"); err != nil {
+		return false, nil
+	}
+	out, err := proc.Command("watchman", "watch-del", dir).CombinedOutput()
+	if err != nil && !strings.Contains(string(out), "unable to resolve root") {
+		return true, fmt.Errorf("watchman watch-del failed: %s", strings.TrimSpace(string(out)))
+	}
+	return true, nil
+}
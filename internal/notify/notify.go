@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Methods accepted for the global "notify" setting
+const (
+	MethodBell    = "bell"
+	MethodDesktop = "desktop"
+	MethodBoth    = "both"
+)
+
+// Send rings the terminal bell and/or shows a desktop notification (osascript on macOS,
+// notify-send on Linux). A missing notifier is ignored.
+func Send(method, title, message string) {
+	if method == MethodBell || method == MethodBoth {
+		fmt.Fprint(os.Stderr, "\a")
+	}
+	if method != MethodDesktop && method != MethodBoth {
+		return
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		exec.Command("osascript", "-e", script).Run()
+	case "linux":
+		if _, err := exec.LookPath("notify-send"); err == nil {
+			exec.Command("notify-send", title, message).Run()
+		}
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,105 @@
+package npm
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Failure kinds Diagnose recognizes
+const (
+	FailureAuth     = "auth"
+	FailurePeerDeps = "peer-deps"
+	FailureEngine   = "engine"
+	FailureNetwork  = "network"
+	FailureLockfile = "lockfile"
+)
+
+// Diagnosis explains why an install failed
+type Diagnosis struct {
+	Kind string
+	// Detail is the line of npm's output that shows the cause, if one stands out
+	Detail string
+	// Transient failures may succeed if the install is simply run again
+	Transient bool
+}
+
+var (
+	peerDepsFailure = regexp.MustCompile(`ERESOLVE|unable to resolve dependency tree|[Cc]onflicting peer dependency|ERR_PNPM_PEER_DEP_ISSUES`)
+	engineFailure   = regexp.MustCompile(`EBADENGINE|[Uu]nsupported engine|ERR_PNPM_UNSUPPORTED_ENGINE|engine "node" is incompatible`)
+	networkFailure  = regexp.MustCompile(`ENOTFOUND|ETIMEDOUT|ECONNRESET|ECONNREFUSED|EAI_AGAIN|ENETUNREACH|socket hang up|network request to .* failed`)
+	lockfileFailure = regexp.MustCompile(`npm ci.* can only install packages when your package\.json and package-lock\.json|package-lock\.json.* (are|is) not in sync|ERR_PNPM_OUTDATED_LOCKFILE|Missing: .* from lock file`)
+
+	// Detail lines, most telling first
+	peerDepsDetail = []*regexp.Regexp{regexp.MustCompile(`^(peer \S+ from \S+|Conflicting peer dependency: .*)`), regexp.MustCompile(`^Found: .*`)}
+	engineDetail   = []*regexp.Regexp{regexp.MustCompile(`^required: \{.*\}`), regexp.MustCompile(`^Expected version: .*`), regexp.MustCompile(`engine "node" is incompatible.*`)}
+	networkDetail  = []*regexp.Regexp{regexp.MustCompile(`network request to .* failed.*`), regexp.MustCompile(`\b(ENOTFOUND|ETIMEDOUT|ECONNRESET|ECONNREFUSED|EAI_AGAIN|ENETUNREACH)\b.*`)}
+)
+
+// Diagnose recognizes common causes in a failed npm or pnpm install's output; it
+// returns nil when nothing matches. Auth failures win, since they often surface as
+// other errors too.
+func Diagnose(output string) *Diagnosis {
+	switch {
+	case AuthFailure(output):
+		return &Diagnosis{Kind: FailureAuth, Detail: firstLine(authFailure, output)}
+	case lockfileFailure.MatchString(output):
+		return &Diagnosis{Kind: FailureLockfile, Detail: firstLine(lockfileFailure, output)}
+	case peerDepsFailure.MatchString(output):
+		return &Diagnosis{Kind: FailurePeerDeps, Detail: detailLine(output, peerDepsDetail)}
+	case engineFailure.MatchString(output):
+		return &Diagnosis{Kind: FailureEngine, Detail: detailLine(output, engineDetail)}
+	case networkFailure.MatchString(output):
+		return &Diagnosis{Kind: FailureNetwork, Detail: detailLine(output, networkDetail), Transient: true}
+	}
+	return nil
+}
+
+// firstLine returns the output line holding re's first match
+func firstLine(re *regexp.Regexp, output string) string {
+	loc := re.FindStringIndex(output)
+	if loc == nil {
+		return ""
+	}
+	start := strings.LastIndexByte(output[:loc[0]], '\n') + 1
+	end := strings.IndexByte(output[loc[0]:], '\n')
+	if end < 0 {
+		return cleanLine(output[start:])
+	}
+	return cleanLine(output[start : loc[0]+end])
+}
+
+// detailLine returns the first output line matching the earliest pattern that matches any
+func detailLine(output string, patterns []*regexp.Regexp) string {
+	lines := strings.Split(output, "\n")
+	for _, re := range patterns {
+		for _, line := range lines {
+			if m := re.FindString(cleanLine(line)); m != "" {
+				return m
+			}
+		}
+	}
+	return ""
+}
+
+// npmPrefix is what npm puts before each line of an error or warning ("npm error",
+// "npm ERR!", "npm warn EBADENGINE")
+var npmPrefix = regexp.MustCompile(`^npm (ERR!|error|WARN|warn)( E[A-Z]+\b)?\s*`)
+
+// cleanLine drops npm's prefix from an output line
+func cleanLine(line string) string {
+	return strings.TrimSpace(npmPrefix.ReplaceAllString(strings.TrimSpace(line), ""))
+}
+
+// LastLines returns up to n trailing non-empty lines of output
+func LastLines(output string, n int) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
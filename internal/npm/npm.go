@@ -1,10 +1,16 @@
 package npm
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/shellquote"
 )
 
 const (
@@ -50,6 +56,46 @@ func Unlink(consumerDir, pkg string) error {
 	return os.Remove(target)
 }
 
+// asideDir is where SetAside keeps installed packages while a link replaces them
+const asideDir = ".spk-aside"
+
+// SetAside moves consumerDir's installed copy of pkg out of the way so a link can take
+// its place, and returns a func that removes the link and puts the copy back. When pkg
+// isn't installed (or is already a link) the func only removes the link.
+func SetAside(consumerDir, pkg string) (func() error, error) {
+	target := filepath.Join(consumerDir, "node_modules", pkg)
+	info, err := os.Lstat(target)
+	if err != nil || info.Mode()&os.ModeSymlink != 0 {
+		return func() error { return Unlink(consumerDir, pkg) }, nil
+	}
+
+	aside := filepath.Join(consumerDir, "node_modules", asideDir, pkg)
+	if err := os.RemoveAll(aside); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(aside), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(target, aside); err != nil {
+		return nil, err
+	}
+	return func() error {
+		if err := Unlink(consumerDir, pkg); err != nil {
+			return err
+		}
+		if err := os.Rename(aside, target); err != nil {
+			return err
+		}
+		// Drop the now-empty scope and aside dirs
+		for dir := filepath.Dir(aside); filepath.Base(dir) != "node_modules"; dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+		return nil
+	}, nil
+}
+
 // IsBuilt checks if a Smithy model directory has built artifacts
 func IsBuilt(modelDir string) bool {
 	buildDir := filepath.Join(modelDir, SmithyBuildPath)
@@ -93,7 +139,7 @@ func GetPackageName(dir string) (string, error) {
 		return "", fmt.Errorf("package.json not found in %s", dir)
 	}
 
-	cmd := exec.Command("node", "-p", "require('./package.json').name")
+	cmd := proc.Command("node", "-p", "require('./package.json').name")
 	cmd.Dir = dir
 	out, err := cmd.Output()
 	if err != nil {
@@ -117,6 +163,136 @@ func IsLinked(dir, pkg string) bool {
 	return info.Mode()&os.ModeSymlink != 0
 }
 
+// GitHubPackagesRegistry is where @spark-rewards packages are published
+const GitHubPackagesRegistry = "https://npm.pkg.github.com"
+
+// Whoami returns the username npm is authenticated as for registry, using the .npmrc
+// visible from dir
+func Whoami(dir, registry string) (string, error) {
+	args := []string{"whoami"}
+	if registry != "" {
+		args = append(args, "--registry", registry)
+	}
+	cmd := proc.Command("npm", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("not authenticated to %s", orRegistry(registry))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func orRegistry(registry string) string {
+	if registry == "" {
+		return "the default npm registry"
+	}
+	return registry
+}
+
+// HasVersion reports whether the registry dir's .npmrc routes pkg to has version of it
+// published. env is added to the environment.
+func HasVersion(dir, pkg, version string, env []string) bool {
+	cmd := proc.Command("npm", "view", pkg+"@"+version, "version", "--fetch-retries=0", "--fetch-timeout=15000")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(out)) == version
+}
+
+// Lockfile names for the supported package managers
+const (
+	LockfileName     = "package-lock.json"
+	PnpmLockfileName = "pnpm-lock.yaml"
+)
+
+// PackageManager returns "pnpm" if dir has a pnpm lockfile, otherwise "npm", along with
+// the lockfile name that manager uses
+func PackageManager(dir string) (manager, lockfile string) {
+	if _, err := os.Stat(filepath.Join(dir, PnpmLockfileName)); err == nil {
+		return "pnpm", PnpmLockfileName
+	}
+	return "npm", LockfileName
+}
+
+// LockfileHash returns the sha256 of dir's lockfile, or "" if there is none
+func LockfileHash(dir string) string {
+	_, lockfile := PackageManager(dir)
+	data, err := os.ReadFile(filepath.Join(dir, lockfile))
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Installed reports whether dir/node_modules holds a completed npm or pnpm install
+func Installed(dir string) bool {
+	for _, marker := range []string{".package-lock.json", ".modules.yaml"} {
+		if _, err := os.Stat(filepath.Join(dir, "node_modules", marker)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// InstallOptions controls the command InstallCommand picks
+type InstallOptions struct {
+	// Frozen never lets the install rewrite the lockfile and requires one to exist
+	Frozen bool
+	// StoreDir, if set, installs with pnpm against this shared content-addressable store.
+	// npm-locked repos get a throwaway pnpm lockfile imported from package-lock.json.
+	StoreDir string
+}
+
+// InstallCommand returns the shell command that installs dir's dependencies. With a
+// lockfile npm uses `npm ci`; Frozen makes pnpm refuse to update its lockfile.
+func InstallCommand(dir string, opts InstallOptions) (string, error) {
+	manager, lockfile := PackageManager(dir)
+	_, err := os.Stat(filepath.Join(dir, lockfile))
+	hasLock := err == nil
+	if !hasLock && opts.Frozen {
+		return "", fmt.Errorf("no %s in %s — frozen installs need a committed lockfile", lockfile, filepath.Base(dir))
+	}
+
+	if opts.StoreDir != "" {
+		if err := CheckPNPM(); err != nil {
+			return "", err
+		}
+		store := "--store-dir " + shellquote.Quote(opts.StoreDir)
+		switch {
+		case manager == "pnpm" && opts.Frozen:
+			return "pnpm install --frozen-lockfile " + store, nil
+		case manager == "pnpm":
+			return "pnpm install " + store, nil
+		case hasLock:
+			// Import package-lock.json so pnpm installs exactly the locked versions, then
+			// drop the generated lockfile so the repo stays clean and npm-managed
+			return fmt.Sprintf("pnpm import && pnpm install --frozen-lockfile %s; s=$?; rm -f %s; exit $s", store, PnpmLockfileName), nil
+		default:
+			return "pnpm install --no-lockfile " + store, nil
+		}
+	}
+
+	switch {
+	case manager == "pnpm" && opts.Frozen:
+		return "pnpm install --frozen-lockfile", nil
+	case manager == "pnpm":
+		return "pnpm install", nil
+	case hasLock:
+		return "npm ci", nil
+	default:
+		return "npm install", nil
+	}
+}
+
+// CheckPNPM verifies that pnpm is installed
+func CheckPNPM() error {
+	if _, err := exec.LookPath("pnpm"); err != nil {
+		return fmt.Errorf("pnpm not found — install with: npm install -g pnpm")
+	}
+	return nil
+}
+
 // CheckNPM verifies that npm is installed
 func CheckNPM() error {
 	_, err := exec.LookPath("npm")
@@ -0,0 +1,148 @@
+package npm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+)
+
+// Scope is the npm scope Spark packages are published under
+const Scope = "@spark-rewards"
+
+// TokenRef is the token a repo .npmrc uses: npm expands it from the environment, so the
+// file (often committed) never holds a secret and spark-cli's injected GITHUB_TOKEN applies
+const TokenRef = "${GITHUB_TOKEN}"
+
+// NpmrcName is npm's per-project and per-user config file
+const NpmrcName = ".npmrc"
+
+// UserNpmrcPath returns ~/.npmrc
+func UserNpmrcPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, NpmrcName), nil
+}
+
+// ConfigureAuth makes the .npmrc at path route Scope to registry and authenticate to it
+// with token, keeping every other line. A repo file whose auth line already reads the
+// token from an environment variable (${...}) is left alone when token is one too. It
+// reports whether the file changed.
+func ConfigureAuth(path, registry, token string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if !strings.HasPrefix(token, "${") {
+		perm = 0600 // holds a secret
+	}
+
+	scopeKey := Scope + ":registry="
+	authKey := authKey(registry)
+	scopeLine := scopeKey + registry
+	authLine := authKey + token
+
+	var lines []string
+	haveScope, haveAuth := false, false
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case line == "" && len(data) == 0:
+			continue
+		case strings.HasPrefix(trimmed, scopeKey):
+			if !haveScope {
+				lines = append(lines, scopeLine)
+			}
+			haveScope = true
+		case strings.HasPrefix(trimmed, authKey):
+			current := strings.TrimPrefix(trimmed, authKey)
+			keep := strings.HasPrefix(current, "${") && strings.HasPrefix(token, "${")
+			if !haveAuth {
+				if keep {
+					lines = append(lines, trimmed)
+				} else {
+					lines = append(lines, authLine)
+				}
+			}
+			haveAuth = true
+		default:
+			lines = append(lines, line)
+		}
+	}
+	if !haveScope {
+		lines = append(lines, scopeLine)
+	}
+	if !haveAuth {
+		lines = append(lines, authLine)
+	}
+
+	out := []byte(strings.Join(lines, "\n") + "\n")
+	if bytes.Equal(out, data) {
+		return false, nil
+	}
+	if err := os.WriteFile(path, out, perm); err != nil {
+		return false, err
+	}
+	return true, os.Chmod(path, perm)
+}
+
+// authKey returns the .npmrc key for registry's token, e.g. //npm.pkg.github.com/:_authToken=
+func authKey(registry string) string {
+	host := strings.TrimSuffix(registry, "/")
+	if u, err := url.Parse(registry); err == nil && u.Host != "" {
+		host = u.Host + strings.TrimSuffix(u.Path, "/")
+	}
+	return "//" + host + "/:_authToken="
+}
+
+// ErrAuthRejected means the registry answered 401/403 to the request's credentials
+var ErrAuthRejected = errors.New("rejected the token (401/403)")
+
+// authFailure matches npm's output when a registry rejects the request's credentials
+var authFailure = regexp.MustCompile(`(?i)\bE40[13]\b|40[13] (Unauthorized|Forbidden)|authentication token|code E401|code E403`)
+
+// AuthFailure reports whether npm output shows a 401/403 from the registry
+func AuthFailure(output string) bool {
+	return authFailure.MatchString(output)
+}
+
+// CheckAuth fetches pkg's metadata the way an install would (scope routing and all) from
+// dir, or asks registry who we are when pkg is empty. env is added to the environment.
+func CheckAuth(dir, registry, pkg string, env []string) error {
+	args := []string{"whoami", "--registry", registry}
+	if pkg != "" {
+		args = []string{"view", pkg, "name"}
+	}
+	// A check, not an install: fail fast rather than riding out npm's network retries
+	cmd := proc.Command("npm", append(args, "--fetch-retries=0", "--fetch-timeout=15000")...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if AuthFailure(stderr.String()) {
+			return fmt.Errorf("%s %w", registry, ErrAuthRejected)
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if i := strings.IndexByte(msg, '\n'); i >= 0 {
+			msg = msg[:i]
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("npm %s failed: %s", args[0], msg)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package proc
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// waitDelay is how long a cancelled child gets to exit before it is killed outright
+const waitDelay = 5 * time.Second
+
+var (
+	mu      sync.RWMutex
+	baseCtx = context.Background()
+)
+
+// SetContext sets the context that all commands started via this package are bound to.
+// The root command installs one that is cancelled on SIGINT/SIGTERM or --timeout.
+func SetContext(ctx context.Context) {
+	mu.Lock()
+	defer mu.Unlock()
+	baseCtx = ctx
+}
+
+// Context returns the shared command context
+func Context() context.Context {
+	mu.RLock()
+	defer mu.RUnlock()
+	return baseCtx
+}
+
+// Interrupted reports whether the shared context has been cancelled or timed out
+func Interrupted() bool {
+	return Context().Err() != nil
+}
+
+// TimedOut reports whether the shared context hit its deadline
+func TimedOut() bool {
+	return errors.Is(Context().Err(), context.DeadlineExceeded)
+}
+
+// Command returns an exec.Cmd bound to the shared context. The child runs in its own
+// process group so that cancellation kills everything it spawned (login shells, npm, node).
+// Use Foreground for commands that need to read from the terminal.
+func Command(name string, args ...string) *Cmd {
+	return CommandContext(Context(), name, args...)
+}
+
+// CommandContext is like Command but bound to ctx, which should be derived from Context()
+// (e.g. to put a per-step deadline on a child)
+func CommandContext(ctx context.Context, name string, args ...string) *Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+	}
+	cmd.WaitDelay = waitDelay
+	return &Cmd{Cmd: cmd}
+}
+
+// Foreground is like Command but for children that use the terminal (SSO login, prompts,
+// watch modes). The child still gets its own process group, which is handed the terminal
+// while it runs: Ctrl-C reaches everything it spawned and is passed on to spark-cli once
+// it exits. On timeout or SIGTERM the whole group is sent SIGTERM.
+func Foreground(name string, args ...string) *Cmd {
+	c := CommandContext(Context(), name, args...)
+	c.foreground = true
+	return c
+}
+
+// Cleanup returns a command that ignores cancellation, for restoring state after an
+// interrupt (e.g. git rebase --abort, switching back to the original branch)
+func Cleanup(name string, args ...string) *Cmd {
+	return &Cmd{Cmd: exec.Command(name, args...)}
+}
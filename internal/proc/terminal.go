@@ -0,0 +1,98 @@
+package proc
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	termMu    sync.Mutex
+	termOwner *Cmd // the foreground child holding the terminal, if any
+)
+
+// takeTerminal makes c's process group the terminal's foreground group, so keystrokes
+// and Ctrl-C go to it. Nothing changes when there is no terminal, spark-cli isn't in the
+// foreground, or another child already holds it.
+func (c *Cmd) takeTerminal() {
+	termMu.Lock()
+	defer termMu.Unlock()
+	if termOwner != nil {
+		return
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return
+	}
+	if pgrp, err := tcgetpgrp(tty); err != nil || pgrp != syscall.Getpgrp() {
+		tty.Close()
+		return
+	}
+	if err := tcsetpgrp(tty, c.Process.Pid); err != nil {
+		tty.Close()
+		return
+	}
+	// The child may have touched the terminal before it was handed over and been stopped
+	syscall.Kill(-c.Process.Pid, syscall.SIGCONT)
+	c.tty, termOwner = tty, c
+}
+
+// releaseTerminal takes the terminal back from c once it has exited. If the child was
+// stopped by Ctrl-C, spark-cli never saw it, so it is interrupted now.
+func (c *Cmd) releaseTerminal(err error) {
+	termMu.Lock()
+	defer termMu.Unlock()
+	if c.tty == nil {
+		return
+	}
+	// Setting the foreground group from the background raises SIGTTOU
+	signal.Ignore(syscall.SIGTTOU)
+	tcsetpgrp(c.tty, syscall.Getpgrp())
+	signal.Reset(syscall.SIGTTOU)
+	c.tty.Close()
+	c.tty, termOwner = nil, nil
+
+	if interruptedChild(err) && !Interrupted() {
+		syscall.Kill(os.Getpid(), syscall.SIGINT)
+		// Let the signal cancel the shared context before the caller looks at it
+		select {
+		case <-Context().Done():
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// interruptedChild reports whether a child ended because of SIGINT, directly or as a
+// shell reporting it (exit 130)
+func interruptedChild(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	if !ok {
+		return false
+	}
+	return status.Signaled() && status.Signal() == syscall.SIGINT || status.ExitStatus() == 130
+}
+
+func tcgetpgrp(tty *os.File) (int, error) {
+	var pgrp int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, tty.Fd(), syscall.TIOCGPGRP, uintptr(unsafe.Pointer(&pgrp))); errno != 0 {
+		return 0, errno
+	}
+	return int(pgrp), nil
+}
+
+func tcsetpgrp(tty *os.File, pgrp int) error {
+	p := int32(pgrp)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, tty.Fd(), syscall.TIOCSPGRP, uintptr(unsafe.Pointer(&p))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package proc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/binname"
+)
+
+// Cmd is an exec.Cmd whose runs are written to the trace log when tracing is on
+type Cmd struct {
+	*exec.Cmd
+	start      time.Time
+	foreground bool     // hand the terminal to the child's process group while it runs
+	tty        *os.File // the terminal, while the child holds it
+}
+
+// Run starts the command and waits for it to finish
+func (c *Cmd) Run() error {
+	if c.foreground {
+		if err := c.Start(); err != nil {
+			return err
+		}
+		return c.Wait()
+	}
+	c.start = time.Now()
+	err := c.Cmd.Run()
+	c.trace(err)
+	return err
+}
+
+// Output runs the command and returns its standard output
+func (c *Cmd) Output() ([]byte, error) {
+	if c.foreground {
+		var stdout, stderr bytes.Buffer
+		c.Stdout = &stdout
+		if c.Stderr == nil {
+			c.Stderr = &stderr
+		}
+		err := c.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitErr.Stderr = stderr.Bytes()
+		}
+		return stdout.Bytes(), err
+	}
+	c.start = time.Now()
+	out, err := c.Cmd.Output()
+	c.trace(err)
+	return out, err
+}
+
+// CombinedOutput runs the command and returns its standard output and standard error
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	if c.foreground {
+		var out bytes.Buffer
+		c.Stdout, c.Stderr = &out, &out
+		err := c.Run()
+		return out.Bytes(), err
+	}
+	c.start = time.Now()
+	out, err := c.Cmd.CombinedOutput()
+	c.trace(err)
+	return out, err
+}
+
+// Start starts the command; the trace entry is written by Wait
+func (c *Cmd) Start() error {
+	c.start = time.Now()
+	err := c.Cmd.Start()
+	if err != nil {
+		c.trace(err)
+		return err
+	}
+	if c.foreground {
+		c.takeTerminal()
+	}
+	return nil
+}
+
+// Wait waits for a command started with Start to exit
+func (c *Cmd) Wait() error {
+	err := c.Cmd.Wait()
+	if c.foreground {
+		c.releaseTerminal(err)
+	}
+	c.trace(err)
+	return err
+}
+
+var (
+	traceMu  sync.Mutex
+	traceOut io.WriteCloser
+)
+
+// EnableTrace appends a line for every command run through this package to the file at
+// path: when it started, how long it took, its exit code, directory, and command line
+func EnableTrace(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	if traceOut != nil {
+		traceOut.Close()
+	}
+	traceOut = f
+	fmt.Fprintf(f, "# %s %s %s\n", time.Now().Format(time.RFC3339), binname.Name(), strings.Join(os.Args[1:], " "))
+	return nil
+}
+
+// CloseTrace flushes and closes the trace log, if one is open
+func CloseTrace() {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	if traceOut != nil {
+		traceOut.Close()
+		traceOut = nil
+	}
+}
+
+func (c *Cmd) trace(err error) {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	if traceOut == nil {
+		return
+	}
+	exit := "0"
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		exit = fmt.Sprint(exitErr.ExitCode())
+	case err != nil:
+		exit = "error: " + err.Error()
+	}
+	dir := c.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	fmt.Fprintf(traceOut, "%s %8s exit=%-3s cwd=%s %s\n",
+		c.start.Format("15:04:05.000"), time.Since(c.start).Round(time.Millisecond), exit, dir, quoteArgs(c.Args))
+}
+
+// quoteArgs renders argv as a shell would need it typed, leaving out secrets: SSM
+// parameter values and auth headers
+func quoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if i > 0 && args[i-1] == "--value" {
+			a = "(redacted)"
+		} else if before, _, found := strings.Cut(a, "AUTHORIZATION: "); found {
+			a = before + "AUTHORIZATION: (redacted)"
+		}
+		if a == "" || strings.ContainsAny(a, " \t\n'\"$`\\|&;<>()*?[]{}~") {
+			a = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,98 @@
+package progress
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/ui"
+)
+
+// frames are the spinner animation
+var frames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Interactive is true when stdout is a terminal; otherwise spinners print plain lines
+var Interactive = isTerminal(os.Stdout)
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Spinner shows a label with elapsed time while quiet work runs. Anything else written to
+// the terminal meanwhile garbles its line, so only wrap steps that print nothing.
+type Spinner struct {
+	label string
+	start time.Time
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// Start shows label with a spinner (or prints "label..." when not a terminal)
+func Start(label string) *Spinner {
+	s := &Spinner{label: label, start: time.Now(), stop: make(chan struct{})}
+	if !Interactive {
+		fmt.Printf("%s...\n", label)
+		return s
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Printf("\r%s %s %s", frames[i%len(frames)], s.label, Elapsed(time.Since(s.start)))
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+// Stop ends the spinner with a ✓ or ✗ line (the error, if any, is appended)
+func (s *Spinner) Stop(err error) {
+	s.Clear()
+	if err != nil {
+		ui.Fail.Printf("%s (%s): %v\n", s.label, Elapsed(time.Since(s.start)), err)
+		return
+	}
+	ui.OK.Printf("%s (%s)\n", s.label, Elapsed(time.Since(s.start)))
+}
+
+// Clear removes the spinner line without printing an outcome
+func (s *Spinner) Clear() {
+	select {
+	case <-s.stop:
+		return
+	default:
+		close(s.stop)
+	}
+	s.wg.Wait()
+	if Interactive {
+		fmt.Print("\r\033[K")
+	}
+}
+
+// Step formats the "[i/n] " prefix for multi-repo loops; empty when there's only one
+func Step(i, n int) string {
+	if n <= 1 {
+		return ""
+	}
+	return fmt.Sprintf("[%d/%d] ", i, n)
+}
+
+// Elapsed renders d as "850ms", "4.2s", or "1m05s"
+func Elapsed(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	default:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+}
@@ -0,0 +1,248 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+var reader = bufio.NewReader(os.Stdin)
+
+// NonInteractive makes every prompt take its default without reading stdin (CI runs)
+var NonInteractive bool
+
+// readLine reads one trimmed line from stdin
+func readLine() string {
+	if NonInteractive {
+		fmt.Println()
+		return ""
+	}
+	input, _ := reader.ReadString('\n')
+	return strings.TrimSpace(input)
+}
+
+// Input asks for a free-form value, returning def when the user just presses Enter
+func Input(label, def string) string {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", label, def)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+	if v := readLine(); v != "" {
+		return v
+	}
+	return def
+}
+
+// Secret asks for a value without echoing it (passphrases, tokens)
+func Secret(label string) string {
+	fmt.Printf("%s: ", label)
+	if !NonInteractive && setEcho(false) {
+		defer func() {
+			setEcho(true)
+			fmt.Println()
+		}()
+	}
+	return readLine()
+}
+
+// setEcho turns terminal echo on or off, reporting whether it could
+func setEcho(on bool) bool {
+	arg := "-echo"
+	if on {
+		arg = "echo"
+	}
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run() == nil
+}
+
+// Confirm asks a yes/no question
+func Confirm(label string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	fmt.Printf("%s [%s]: ", label, hint)
+	switch strings.ToLower(readLine()) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	default:
+		return def
+	}
+}
+
+// Select shows a numbered list and returns the chosen index
+func Select(label string, options []string) (int, error) {
+	if len(options) == 0 {
+		return -1, fmt.Errorf("nothing to choose from")
+	}
+	if NonInteractive {
+		return -1, fmt.Errorf("%s — can't prompt in non-interactive mode", strings.TrimSuffix(label, ":"))
+	}
+	fmt.Println(label)
+	for i, o := range options {
+		fmt.Printf("  %d. %s\n", i+1, o)
+	}
+	fmt.Print("Enter a number: ")
+	idx, err := strconv.Atoi(readLine())
+	if err != nil || idx < 1 || idx > len(options) {
+		return -1, fmt.Errorf("invalid selection — enter a number between 1 and %d", len(options))
+	}
+	return idx - 1, nil
+}
+
+// MultiSelect shows a numbered list and returns the chosen indexes. Accepts
+// comma-separated numbers and ranges ("1,3,5-7"), "all", or "none"; Enter keeps the
+// preselected entries.
+func MultiSelect(label string, options []string, preselected []bool) ([]int, error) {
+	fmt.Println(label)
+	for i, o := range options {
+		mark := " "
+		if i < len(preselected) && preselected[i] {
+			mark = "*"
+		}
+		fmt.Printf(" %s%2d. %s\n", mark, i+1, o)
+	}
+	fmt.Print("Enter numbers (e.g. 1,3,5-7), 'all', 'none', or Enter for * entries: ")
+	input := strings.ToLower(readLine())
+
+	var picked []int
+	switch input {
+	case "":
+		for i := range options {
+			if i < len(preselected) && preselected[i] {
+				picked = append(picked, i)
+			}
+		}
+		return picked, nil
+	case "all":
+		for i := range options {
+			picked = append(picked, i)
+		}
+		return picked, nil
+	case "none":
+		return nil, nil
+	}
+	return ParseSelection(input, len(options))
+}
+
+// pickShown caps how many options Pick lists at once; typing narrows the rest
+const pickShown = 30
+
+// Pick lists options and lets the user narrow them by typing a filter (fuzzy: the
+// letters in order, so "apapi" matches AppAPI) before choosing by number. Enter picks
+// the only remaining option; with several left it picks nothing.
+func Pick(label string, options []string) ([]int, error) {
+	if len(options) == 0 {
+		return nil, fmt.Errorf("nothing to choose from")
+	}
+	if NonInteractive {
+		return nil, fmt.Errorf("%s — can't prompt in non-interactive mode", strings.TrimSuffix(label, ":"))
+	}
+	visible := Fuzzy(options, "")
+	fmt.Println(label)
+	for {
+		for i, idx := range visible {
+			if i == pickShown {
+				fmt.Printf("  ... %d more — type to filter\n", len(visible)-pickShown)
+				break
+			}
+			fmt.Printf("  %2d. %s\n", i+1, options[idx])
+		}
+		fmt.Print("Type to filter, or pick numbers (e.g. 1,3-4, 'all'): ")
+		input := readLine()
+
+		switch {
+		case input == "":
+			if len(visible) == 1 {
+				return visible, nil
+			}
+			return nil, nil
+		case strings.EqualFold(input, "all"):
+			return visible, nil
+		case strings.Trim(input, "0123456789,- ") == "":
+			picked, err := ParseSelection(input, len(visible))
+			if err != nil {
+				return nil, err
+			}
+			for i, p := range picked {
+				picked[i] = visible[p]
+			}
+			return picked, nil
+		}
+
+		matches := Fuzzy(options, input)
+		if len(matches) == 0 {
+			fmt.Printf("No matches for %q\n", input)
+			continue
+		}
+		visible = matches
+		fmt.Printf("Matching %q:\n", input)
+	}
+}
+
+// Fuzzy returns the indexes of options containing term's letters in order (case
+// insensitive): prefix matches first, then substring matches, then the rest
+func Fuzzy(options []string, term string) []int {
+	term = strings.ToLower(term)
+	var prefix, substr, subseq []int
+	for i, o := range options {
+		lower := strings.ToLower(o)
+		switch {
+		case strings.HasPrefix(lower, term):
+			prefix = append(prefix, i)
+		case strings.Contains(lower, term):
+			substr = append(substr, i)
+		case isSubsequence(term, lower):
+			subseq = append(subseq, i)
+		}
+	}
+	return append(append(prefix, substr...), subseq...)
+}
+
+func isSubsequence(needle, haystack string) bool {
+	rest := []rune(needle)
+	for _, r := range haystack {
+		if len(rest) == 0 {
+			break
+		}
+		if r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	return len(rest) == 0
+}
+
+// ParseSelection parses "1,3,5-7" into zero-based indexes within [0, n)
+func ParseSelection(input string, n int) ([]int, error) {
+	seen := make(map[int]bool)
+	var picked []int
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi := part, part
+		if i := strings.IndexByte(part, '-'); i > 0 {
+			lo, hi = part[:i], part[i+1:]
+		}
+		a, err1 := strconv.Atoi(strings.TrimSpace(lo))
+		b, err2 := strconv.Atoi(strings.TrimSpace(hi))
+		if err1 != nil || err2 != nil || a < 1 || b > n || a > b {
+			return nil, fmt.Errorf("invalid selection %q — use numbers between 1 and %d", part, n)
+		}
+		for i := a; i <= b; i++ {
+			if !seen[i-1] {
+				seen[i-1] = true
+				picked = append(picked, i-1)
+			}
+		}
+	}
+	return picked, nil
+}
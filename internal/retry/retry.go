@@ -0,0 +1,110 @@
+package retry
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/proc"
+)
+
+// Policy controls how many times a flaky operation is attempted and how long to wait between tries
+type Policy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultPolicy is used when the global config doesn't override it
+var DefaultPolicy = Policy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+var (
+	mu      sync.RWMutex
+	current = DefaultPolicy
+)
+
+// Configure overrides the shared policy; zero values keep the defaults
+func Configure(maxAttempts int, baseDelay time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = DefaultPolicy
+	if maxAttempts > 0 {
+		current.MaxAttempts = maxAttempts
+	}
+	if baseDelay > 0 {
+		current.BaseDelay = baseDelay
+	}
+}
+
+// Current returns the shared policy
+func Current() Policy {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error as not worth retrying (bad credentials, missing repo, ...)
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Do runs op with the shared policy
+func Do(op func() error) error {
+	return Current().Do(op)
+}
+
+// Do runs op until it succeeds, returns a permanent error, attempts run out, or the
+// command is interrupted. Waits grow exponentially with full jitter.
+func (p Policy) Do(op func() error) error {
+	attempts := p.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		err = op()
+		if err == nil {
+			return nil
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		if i == attempts-1 || proc.Interrupted() {
+			break
+		}
+
+		select {
+		case <-time.After(p.backoff(i)):
+		case <-proc.Context().Done():
+			return err
+		}
+	}
+	return err
+}
+
+// backoff returns a random wait in [0, min(MaxDelay, BaseDelay*2^attempt)]
+func (p Policy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << attempt
+	if p.MaxDelay > 0 && (d > p.MaxDelay || d <= 0) {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
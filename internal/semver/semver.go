@@ -0,0 +1,78 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Bump kinds
+const (
+	Major = "major"
+	Minor = "minor"
+	Patch = "patch"
+)
+
+// Version is a major.minor.patch release version (pre-release/build suffixes are dropped)
+type Version struct {
+	Major, Minor, Patch int
+}
+
+var versionRe = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)`)
+
+// Parse reads "1.2.3" or "v1.2.3"
+func Parse(s string) (Version, error) {
+	m := versionRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid version %q — expected MAJOR.MINOR.PATCH", s)
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	return Version{major, minor, patch}, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Tag returns the git tag for v ("v1.2.3")
+func (v Version) Tag() string {
+	return "v" + v.String()
+}
+
+// Bump returns v incremented by kind (major, minor, or patch)
+func (v Version) Bump(kind string) (Version, error) {
+	switch kind {
+	case Major:
+		return Version{v.Major + 1, 0, 0}, nil
+	case Minor:
+		return Version{v.Major, v.Minor + 1, 0}, nil
+	case Patch:
+		return Version{v.Major, v.Minor, v.Patch + 1}, nil
+	}
+	return v, fmt.Errorf("invalid bump %q — use major, minor, or patch", kind)
+}
+
+// breakingRe matches a conventional-commit header with "!" (e.g. "feat(api)!: drop v1")
+var breakingRe = regexp.MustCompile(`^\w+(\([^)]*\))?!:`)
+
+// featRe matches a conventional-commit feature header
+var featRe = regexp.MustCompile(`^feat(\([^)]*\))?:`)
+
+// Infer picks the bump implied by conventional commit messages: major for breaking
+// changes, minor for features, patch otherwise
+func Infer(messages []string) string {
+	kind := Patch
+	for _, msg := range messages {
+		header := strings.SplitN(strings.TrimSpace(msg), "\n", 2)[0]
+		if breakingRe.MatchString(header) || strings.Contains(msg, "BREAKING CHANGE") {
+			return Major
+		}
+		if featRe.MatchString(header) {
+			kind = Minor
+		}
+	}
+	return kind
+}
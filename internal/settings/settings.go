@@ -0,0 +1,357 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+)
+
+// Setting keys
+const (
+	AWSProfile  = "aws_profile"
+	AWSRegion   = "aws_region"
+	Env         = "env"
+	GitHubOrg   = "github_org"
+	LinkMode    = "link_mode"
+	Concurrency = "concurrency"
+	NudgeBehind = "nudge_behind"
+)
+
+// Link modes: how a consumer picks up a model built in the workspace
+const (
+	LinkSymlink   = "symlink"   // symlink the local build into node_modules
+	LinkPublished = "published" // always use the published package
+)
+
+// Source is the layer a setting's value came from, highest precedence first
+type Source string
+
+const (
+	SourceFlag      Source = "flag"
+	SourceEnv       Source = "env"
+	SourceWorkspace Source = "workspace"
+	SourceGlobal    Source = "global"
+	SourceDefault   Source = "default"
+)
+
+// Setting describes one key and where each layer stores it
+type Setting struct {
+	Key         string
+	Description string
+	Default     string
+
+	// workspace and global read the layer's value and the field it came from
+	workspace func(ws *workspace.Workspace, env string) (value, field string)
+	global    func(cfg *config.GlobalConfig) (value, field string)
+
+	setWorkspace func(ws *workspace.Workspace, value string)
+	setGlobal    func(cfg *config.GlobalConfig, value string)
+	validate     func(value string) error
+}
+
+// EnvVar is the environment variable that overrides the setting
+func (s Setting) EnvVar() string {
+	return "SPK_" + strings.ToUpper(s.Key)
+}
+
+// Settings lists every key in display order
+var Settings = []Setting{
+	{
+		Key:         AWSProfile,
+		Description: "AWS profile for SSM, CDK, and whoami",
+		workspace: func(ws *workspace.Workspace, env string) (string, string) {
+			if def, ok := ws.Environments[env]; ok && def.Profile != "" {
+				return def.Profile, "environments." + env + ".profile"
+			}
+			return ws.AWSProfile, "aws_profile"
+		},
+		global: func(cfg *config.GlobalConfig) (string, string) {
+			return cfg.DefaultAWSProfile, "default_aws_profile"
+		},
+		setWorkspace: func(ws *workspace.Workspace, v string) { ws.AWSProfile = v },
+		setGlobal:    func(cfg *config.GlobalConfig, v string) { cfg.DefaultAWSProfile = v },
+	},
+	{
+		Key:         AWSRegion,
+		Description: "AWS region",
+		Default:     "us-east-1",
+		workspace: func(ws *workspace.Workspace, env string) (string, string) {
+			if def, ok := ws.Environments[env]; ok && def.Region != "" {
+				return def.Region, "environments." + env + ".region"
+			}
+			return ws.AWSRegion, "aws_region"
+		},
+		global: func(cfg *config.GlobalConfig) (string, string) {
+			return cfg.DefaultAWSRegion, "default_aws_region"
+		},
+		setWorkspace: func(ws *workspace.Workspace, v string) { ws.AWSRegion = v },
+		setGlobal:    func(cfg *config.GlobalConfig, v string) { cfg.DefaultAWSRegion = v },
+	},
+	{
+		Key:         Env,
+		Description: "Environment whose SSM parameters fill .env (beta, prod, ...)",
+		Default:     "beta",
+		workspace: func(ws *workspace.Workspace, _ string) (string, string) {
+			return ws.SSMEnvPath, "ssm_env_path"
+		},
+		global: func(cfg *config.GlobalConfig) (string, string) {
+			return cfg.DefaultEnv, "default_env"
+		},
+		setWorkspace: func(ws *workspace.Workspace, v string) { ws.SSMEnvPath = v },
+		setGlobal:    func(cfg *config.GlobalConfig, v string) { cfg.DefaultEnv = v },
+	},
+	{
+		Key:         GitHubOrg,
+		Description: "GitHub org repos are cloned from and browsed in",
+		Default:     "Spark-Rewards",
+		workspace: func(ws *workspace.Workspace, _ string) (string, string) {
+			return ws.GithubOrg, "github_org"
+		},
+		global: func(cfg *config.GlobalConfig) (string, string) {
+			return cfg.DefaultGithubOrg, "default_github_org"
+		},
+		setWorkspace: func(ws *workspace.Workspace, v string) { ws.GithubOrg = v },
+		setGlobal:    func(cfg *config.GlobalConfig, v string) { cfg.DefaultGithubOrg = v },
+	},
+	{
+		Key:         LinkMode,
+		Description: "How consumers use models built in the workspace: symlink or published",
+		Default:     LinkSymlink,
+		workspace: func(ws *workspace.Workspace, _ string) (string, string) {
+			return ws.LinkMode, "link_mode"
+		},
+		global: func(cfg *config.GlobalConfig) (string, string) {
+			return cfg.DefaultLinkMode, "default_link_mode"
+		},
+		setWorkspace: func(ws *workspace.Workspace, v string) { ws.LinkMode = v },
+		setGlobal:    func(cfg *config.GlobalConfig, v string) { cfg.DefaultLinkMode = v },
+		validate: func(v string) error {
+			if v != LinkSymlink && v != LinkPublished {
+				return fmt.Errorf("must be %s or %s", LinkSymlink, LinkPublished)
+			}
+			return nil
+		},
+	},
+	{
+		Key:         Concurrency,
+		Description: "Repos fetched or inspected in parallel (sync, list, workspace)",
+		Default:     "8",
+		workspace: func(ws *workspace.Workspace, _ string) (string, string) {
+			return intString(ws.Concurrency), "concurrency"
+		},
+		global: func(cfg *config.GlobalConfig) (string, string) {
+			return intString(cfg.DefaultConcurrency), "default_concurrency"
+		},
+		setWorkspace: func(ws *workspace.Workspace, v string) { ws.Concurrency, _ = strconv.Atoi(v) },
+		setGlobal:    func(cfg *config.GlobalConfig, v string) { cfg.DefaultConcurrency, _ = strconv.Atoi(v) },
+		validate: func(v string) error {
+			if n, err := strconv.Atoi(v); err != nil || n < 1 {
+				return fmt.Errorf("must be a positive number")
+			}
+			return nil
+		},
+	},
+	{
+		Key:         NudgeBehind,
+		Description: "Commits a default branch can fall behind origin before commands suggest a sync (off to never)",
+		Default:     "20",
+		workspace: func(ws *workspace.Workspace, _ string) (string, string) {
+			return ws.NudgeBehind, "nudge_behind"
+		},
+		global: func(cfg *config.GlobalConfig) (string, string) {
+			return cfg.DefaultNudgeBehind, "default_nudge_behind"
+		},
+		setWorkspace: func(ws *workspace.Workspace, v string) { ws.NudgeBehind = v },
+		setGlobal:    func(cfg *config.GlobalConfig, v string) { cfg.DefaultNudgeBehind = v },
+		validate: func(v string) error {
+			if n, err := strconv.Atoi(v); v != "off" && (err != nil || n < 1) {
+				return fmt.Errorf("must be a positive number or off")
+			}
+			return nil
+		},
+	},
+}
+
+func intString(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// Lookup returns the setting named key
+func Lookup(key string) (Setting, error) {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+	keys := make([]string, len(Settings))
+	for i, s := range Settings {
+		keys[i] = s.Key
+	}
+	return Setting{}, errs.New(errs.KindUsage, "unknown setting %q (one of: %s)", key, strings.Join(keys, ", "))
+}
+
+// Value is a resolved setting and where it came from
+type Value struct {
+	Key    string
+	Value  string
+	Source Source
+	// Origin names the flag, variable, or field that supplied the value
+	Origin string
+}
+
+// Resolver answers settings from, highest precedence first: command-line flags, SPK_*
+// environment variables, workspace.json, ~/.spk/config.json, and built-in defaults
+type Resolver struct {
+	ws    *workspace.Workspace
+	cfg   *config.GlobalConfig
+	flags map[string]string
+}
+
+// New returns a Resolver over ws (nil outside a workspace) and cfg
+func New(ws *workspace.Workspace, cfg *config.GlobalConfig) *Resolver {
+	if cfg == nil {
+		cfg = &config.GlobalConfig{}
+	}
+	return &Resolver{ws: ws, cfg: cfg, flags: make(map[string]string)}
+}
+
+// Load returns a Resolver over ws and the global config; an unreadable global config
+// is treated as empty
+func Load(ws *workspace.Workspace) *Resolver {
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		cfg = nil
+	}
+	return New(ws, cfg)
+}
+
+// SetFlag records a command-line value for key; empty values are ignored so unset
+// flags fall through to the other layers
+func (r *Resolver) SetFlag(key, value string) *Resolver {
+	if value != "" {
+		r.flags[key] = value
+	}
+	return r
+}
+
+// Get resolves key. AWS settings follow the resolved env's entry in "environments".
+func (r *Resolver) Get(key string) (Value, error) {
+	s, err := Lookup(key)
+	if err != nil {
+		return Value{}, err
+	}
+	env := ""
+	if key == AWSProfile || key == AWSRegion {
+		env = r.String(Env)
+	}
+	return r.resolve(s, env), nil
+}
+
+// String returns key's resolved value ("" for unknown keys)
+func (r *Resolver) String(key string) string {
+	v, _ := r.Get(key)
+	return v.Value
+}
+
+// Int returns key's resolved value as a number (0 when it isn't one)
+func (r *Resolver) Int(key string) int {
+	n, _ := strconv.Atoi(r.String(key))
+	return n
+}
+
+// AWS returns the profile and region for env, which may differ from the resolved env
+func (r *Resolver) AWS(env string) (profile, region string) {
+	p, _ := Lookup(AWSProfile)
+	g, _ := Lookup(AWSRegion)
+	return r.resolve(p, env).Value, r.resolve(g, env).Value
+}
+
+// All resolves every setting
+func (r *Resolver) All() []Value {
+	values := make([]Value, 0, len(Settings))
+	for _, s := range Settings {
+		v, _ := r.Get(s.Key)
+		values = append(values, v)
+	}
+	return values
+}
+
+func (r *Resolver) resolve(s Setting, env string) Value {
+	v := Value{Key: s.Key}
+	if flag, ok := r.flags[s.Key]; ok {
+		v.Value, v.Source, v.Origin = flag, SourceFlag, "--"+strings.ReplaceAll(s.Key, "_", "-")
+		return v
+	}
+	if val := os.Getenv(s.EnvVar()); val != "" {
+		v.Value, v.Source, v.Origin = val, SourceEnv, "$"+s.EnvVar()
+		return v
+	}
+	if r.ws != nil {
+		if val, field := s.workspace(r.ws, env); val != "" {
+			v.Value, v.Source, v.Origin = val, SourceWorkspace, field
+			return v
+		}
+	}
+	if val, field := s.global(r.cfg); val != "" {
+		v.Value, v.Source, v.Origin = val, SourceGlobal, field
+		return v
+	}
+	v.Value, v.Source = s.Default, SourceDefault
+	return v
+}
+
+// Set stores value for key in the workspace (or, with global, the global config); an
+// empty value removes it so lower layers apply. The caller saves the file.
+func Set(ws *workspace.Workspace, cfg *config.GlobalConfig, key, value string, global bool) error {
+	s, err := Lookup(key)
+	if err != nil {
+		return err
+	}
+	if value != "" && s.validate != nil {
+		if err := s.validate(value); err != nil {
+			return errs.New(errs.KindUsage, "invalid %s %q: %v", key, value, err)
+		}
+	}
+	if global {
+		s.setGlobal(cfg, value)
+		return nil
+	}
+	if ws == nil {
+		return errs.New(errs.KindWorkspaceNotFound, "not inside a workspace — pass --global to set %s in ~/.spk/config.json", key)
+	}
+	s.setWorkspace(ws, value)
+	return nil
+}
+
+// Validate checks the values stored in ws and cfg (either may be nil)
+func Validate(ws *workspace.Workspace, cfg *config.GlobalConfig) error {
+	if cfg == nil {
+		cfg = &config.GlobalConfig{}
+	}
+	for _, s := range Settings {
+		if s.validate == nil {
+			continue
+		}
+		if ws != nil {
+			if v, field := s.workspace(ws, ""); v != "" {
+				if err := s.validate(v); err != nil {
+					return fmt.Errorf("%s %q: %v", field, v, err)
+				}
+			}
+		}
+		if v, field := s.global(cfg); v != "" {
+			if err := s.validate(v); err != nil {
+				return fmt.Errorf("%s %q: %v", field, v, err)
+			}
+		}
+	}
+	return nil
+}
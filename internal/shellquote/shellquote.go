@@ -0,0 +1,23 @@
+package shellquote
+
+import "strings"
+
+// Quote single-quotes s for the shell
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Join joins args into a shell command line, quoting the ones the shell would split
+// or interpret
+func Join(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = a
+		if a == "" || strings.ContainsFunc(a, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,@%+", r))
+		}) {
+			quoted[i] = Quote(a)
+		}
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,130 @@
+package shellrc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Begin and End mark the block spark-cli manages in a shell rc file. Everything between
+// them is rewritten on every install, so running it twice changes nothing.
+const (
+	Begin = "# >>> spark-cli >>>"
+	End   = "# <<< spark-cli <<<"
+)
+
+// Supported shells
+const (
+	Bash = "bash"
+	Zsh  = "zsh"
+	Fish = "fish"
+)
+
+// Shells lists the supported shells
+var Shells = []string{Bash, Zsh, Fish}
+
+// Detect returns the user's login shell from $SHELL, or "" if it isn't supported
+func Detect() string {
+	name := filepath.Base(os.Getenv("SHELL"))
+	for _, s := range Shells {
+		if name == s {
+			return s
+		}
+	}
+	return ""
+}
+
+// RCFile returns the startup file spark-cli writes its block to for shell
+func RCFile(shell string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	switch shell {
+	case Bash:
+		return filepath.Join(home, ".bashrc"), nil
+	case Zsh:
+		dir := os.Getenv("ZDOTDIR")
+		if dir == "" {
+			dir = home
+		}
+		return filepath.Join(dir, ".zshrc"), nil
+	case Fish:
+		dir := os.Getenv("XDG_CONFIG_HOME")
+		if dir == "" {
+			dir = filepath.Join(home, ".config")
+		}
+		return filepath.Join(dir, "fish", "conf.d", "spark-cli.fish"), nil
+	}
+	return "", fmt.Errorf("unsupported shell %q (supported: %s)", shell, strings.Join(Shells, ", "))
+}
+
+// Apply puts body between the markers in the file at path: it replaces the block from an
+// earlier install, or is appended. It reports whether the file changed.
+func Apply(path, body string) (bool, error) {
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	block := Begin + "\n" + strings.TrimRight(body, "\n") + "\n" + End + "\n"
+	rest, found := cut(string(current))
+	var updated string
+	switch {
+	case found:
+		updated = rest[0] + block + rest[1]
+	case len(current) == 0:
+		updated = block
+	default:
+		updated = strings.TrimRight(string(current), "\n") + "\n\n" + block
+	}
+	if updated == string(current) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, []byte(updated), 0644)
+}
+
+// Remove deletes the block from the file at path and reports whether there was one
+func Remove(path string) (bool, error) {
+	current, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	rest, found := cut(string(current))
+	if !found {
+		return false, nil
+	}
+	before := strings.TrimRight(rest[0], "\n")
+	if before != "" {
+		before += "\n"
+	}
+	after := strings.TrimLeft(rest[1], "\n")
+	if before != "" && after != "" {
+		before += "\n"
+	}
+	return true, os.WriteFile(path, []byte(before+after), 0644)
+}
+
+// cut splits content around the managed block (markers and the line break after End
+// included), reporting whether it has one
+func cut(content string) ([2]string, bool) {
+	start := strings.Index(content, Begin)
+	if start < 0 {
+		return [2]string{content, ""}, false
+	}
+	end := strings.Index(content[start:], End)
+	if end < 0 {
+		return [2]string{content, ""}, false
+	}
+	end += start + len(End)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return [2]string{content[:start], content[end:]}, true
+}
@@ -0,0 +1,90 @@
+package smoke
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// introspectionQuery is the smallest query that proves a GraphQL schema is served
+const introspectionQuery = `{"query":"{ __schema { queryType { name } } }"}`
+
+// maxBody caps how much of a response is read when looking for Contains
+const maxBody = 1 << 20
+
+// HTTP requests url and checks the status (0 means any 2xx) and, if set, that the body
+// contains want
+func HTTP(ctx context.Context, url string, status int, want string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	return do(req, status, want)
+}
+
+// GraphQL posts an introspection query to url and checks that a schema comes back
+func GraphQL(ctx context.Context, url, want string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(introspectionQuery))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Data struct {
+			Schema *struct {
+				QueryType struct {
+					Name string `json:"name"`
+				} `json:"queryType"`
+			} `json:"__schema"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("not a GraphQL response")
+	}
+	if out.Data.Schema == nil {
+		if len(out.Errors) > 0 {
+			return "", fmt.Errorf("introspection failed: %s", out.Errors[0].Message)
+		}
+		return "", fmt.Errorf("introspection returned no schema")
+	}
+	if want != "" && !bytes.Contains(body, []byte(want)) {
+		return "", fmt.Errorf("response doesn't contain %q", want)
+	}
+	return "schema " + out.Data.Schema.QueryType.Name, nil
+}
+
+func do(req *http.Request, status int, want string) (string, error) {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	detail := fmt.Sprintf("HTTP %d", resp.StatusCode)
+	if status == 0 && resp.StatusCode/100 != 2 || status != 0 && resp.StatusCode != status {
+		return "", fmt.Errorf("%s", detail)
+	}
+	if want != "" {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+		if !bytes.Contains(body, []byte(want)) {
+			return "", fmt.Errorf("%s, body doesn't contain %q", detail, want)
+		}
+	}
+	return detail, nil
+}
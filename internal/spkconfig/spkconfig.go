@@ -16,6 +16,8 @@ type ConsumesEntry struct {
 // Config is the per-repo spk.config.json (consumer-centric: repo lists what it consumes).
 type Config struct {
 	Consumes []ConsumesEntry `json:"consumes"`
+	// RequiredEnv are the variables the repo can't run without (checked by `spark-cli env validate`)
+	RequiredEnv []string `json:"required_env,omitempty"`
 }
 
 const ConfigFilename = "spk.config.json"
@@ -36,3 +38,72 @@ func Load(repoDir string) (*Config, error) {
 	}
 	return &c, nil
 }
+
+// AddConsumes records that the repo in repoDir consumes entry.Model, replacing any entry
+// for the same model. The file is created if needed; its other fields are kept.
+func AddConsumes(repoDir string, entry ConsumesEntry) error {
+	return updateConsumes(repoDir, func(consumes []ConsumesEntry) []ConsumesEntry {
+		for i, c := range consumes {
+			if c.Model == entry.Model {
+				consumes[i] = entry
+				return consumes
+			}
+		}
+		return append(consumes, entry)
+	})
+}
+
+// RemoveConsumes drops repoDir's entries for model, reporting whether there were any
+func RemoveConsumes(repoDir, model string) (bool, error) {
+	if _, err := os.Stat(filepath.Join(repoDir, ConfigFilename)); os.IsNotExist(err) {
+		return false, nil
+	}
+	removed := false
+	err := updateConsumes(repoDir, func(consumes []ConsumesEntry) []ConsumesEntry {
+		var out []ConsumesEntry
+		for _, c := range consumes {
+			if c.Model == model {
+				removed = true
+				continue
+			}
+			out = append(out, c)
+		}
+		return out
+	})
+	return removed, err
+}
+
+// updateConsumes rewrites the consumes list in place, leaving fields this package
+// doesn't know about untouched
+func updateConsumes(repoDir string, update func([]ConsumesEntry) []ConsumesEntry) error {
+	path := filepath.Join(repoDir, ConfigFilename)
+	raw := make(map[string]json.RawMessage)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	var consumes []ConsumesEntry
+	if c, ok := raw["consumes"]; ok {
+		if err := json.Unmarshal(c, &consumes); err != nil {
+			return err
+		}
+	}
+	consumes = update(consumes)
+	if consumes == nil {
+		consumes = []ConsumesEntry{}
+	}
+	if raw["consumes"], err = json.Marshal(consumes); err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0644)
+}
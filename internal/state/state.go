@@ -0,0 +1,218 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+)
+
+// FileName is the workspace state file inside .spk (machine-managed, not meant for hand edits)
+const FileName = "state.json"
+
+// StashSet is a group of per-repo git stashes created together by `spark-cli stash push`
+type StashSet struct {
+	ID        string   `json:"id"`
+	Message   string   `json:"message"`
+	CreatedAt string   `json:"created_at"`
+	Repos     []string `json:"repos"`
+}
+
+// WorkItem is the ticket being worked on, started with `spark-cli work start`
+type WorkItem struct {
+	Ticket    string   `json:"ticket"`
+	Title     string   `json:"title,omitempty"`
+	Branch    string   `json:"branch"`
+	Repos     []string `json:"repos"`
+	StartedAt string   `json:"started_at"`
+}
+
+// State is workspace-local bookkeeping that doesn't belong in workspace.json
+type State struct {
+	Stashes []StashSet `json:"stashes,omitempty"`
+	// Lockfiles maps repo name to the package-lock.json hash of its last successful install
+	Lockfiles map[string]string `json:"lockfiles,omitempty"`
+	// Work is the active ticket, if any
+	Work *WorkItem `json:"work,omitempty"`
+	// DefaultBranches caches each repo's detected default branch by workspace-relative path
+	DefaultBranches map[string]DefaultBranch `json:"default_branches,omitempty"`
+	// EnvRefresh records the last time the workspace .env was written from SSM
+	EnvRefresh *EnvRefresh `json:"env_refresh,omitempty"`
+	// OutdatedCheckedAt is when a command last checked for repos far behind origin
+	OutdatedCheckedAt string `json:"outdated_checked_at,omitempty"`
+	// RemotesCheckedAt is when sync last asked GitHub whether repos were renamed or moved
+	RemotesCheckedAt string `json:"remotes_checked_at,omitempty"`
+	// Links maps consumer repo name to whether it links models built in the workspace,
+	// as set by `spark-cli link enable/disable`; consumers without an entry follow link_mode
+	Links map[string]bool `json:"links,omitempty"`
+	// LinkPins maps consumer repo name and package to the model build it was linked to
+	LinkPins map[string]map[string]LinkPin `json:"link_pins,omitempty"`
+	// Trains maps model repo name to its release train in progress (`spark-cli train`)
+	Trains map[string]*Train `json:"trains,omitempty"`
+	// SyncOnto maps repo name to the branch sync rebases it onto instead of its default
+	// branch, as set by `spark-cli workspace sync <repo> --onto <branch>`
+	SyncOnto map[string]string `json:"sync_onto,omitempty"`
+}
+
+// Train is a model release being rolled out to its consumers by `spark-cli train`
+type Train struct {
+	Model string `json:"model"`
+	// Version is the model release the consumers are bumped to, recorded before it is
+	// tagged so a release that fails part-way can be finished on resume
+	Version string `json:"version,omitempty"`
+	// Tag is Version's release tag
+	Tag       string          `json:"tag,omitempty"`
+	Stage     string          `json:"stage"`
+	StartedAt string          `json:"started_at"`
+	Consumers []TrainConsumer `json:"consumers,omitempty"`
+}
+
+// TrainConsumer is one consumer's version bump in a train
+type TrainConsumer struct {
+	Repo    string `json:"repo"`
+	Package string `json:"package"`
+	Status  string `json:"status"`
+	PR      string `json:"pr,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// LinkPin is the model build a consumer's node_modules symlink pointed at when created
+type LinkPin struct {
+	Model string `json:"model"`
+	// Commit is the model's HEAD when the link was made
+	Commit string `json:"commit"`
+	// BuiltAt is when the linked codegen output was written
+	BuiltAt  string `json:"built_at,omitempty"`
+	LinkedAt string `json:"linked_at"`
+}
+
+// EnvRefresh is a write of the workspace .env
+type EnvRefresh struct {
+	Env       string `json:"env"`
+	WrittenAt string `json:"written_at"`
+}
+
+// DefaultBranch is a detected default branch and the fetch state it was detected in
+type DefaultBranch struct {
+	Branch string `json:"branch"`
+	// Key identifies the last fetch; the entry is stale once it changes
+	Key string `json:"key"`
+}
+
+// Path returns .spk/state.json for a workspace
+func Path(wsPath string) string {
+	return filepath.Join(wsPath, config.SparkDir, FileName)
+}
+
+// Load reads the workspace state; a missing file yields an empty state
+func Load(wsPath string) (*State, error) {
+	data, err := os.ReadFile(Path(wsPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{}, nil
+		}
+		return nil, fmt.Errorf("failed to read workspace state: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace state: %w", err)
+	}
+	return &s, nil
+}
+
+// Save writes the workspace state atomically
+func Save(wsPath string, s *State) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal workspace state: %w", err)
+	}
+	path := Path(wsPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Update loads the state, applies fn, and saves it if fn succeeds
+func Update(wsPath string, fn func(s *State) error) error {
+	s, err := Load(wsPath)
+	if err != nil {
+		return err
+	}
+	if err := fn(s); err != nil {
+		return err
+	}
+	return Save(wsPath, s)
+}
+
+// FindStash returns the stash set with the given id (or the most recent one if id is
+// empty) and its index, or -1 if not found
+func (s *State) FindStash(id string) (*StashSet, int) {
+	if len(s.Stashes) == 0 {
+		return nil, -1
+	}
+	if id == "" {
+		i := len(s.Stashes) - 1
+		return &s.Stashes[i], i
+	}
+	for i := range s.Stashes {
+		if s.Stashes[i].ID == id {
+			return &s.Stashes[i], i
+		}
+	}
+	return nil, -1
+}
+
+// SetDefaultBranch caches the default branch detected for the repo at path
+func (s *State) SetDefaultBranch(path, branch, key string) {
+	if s.DefaultBranches == nil {
+		s.DefaultBranches = make(map[string]DefaultBranch)
+	}
+	s.DefaultBranches[path] = DefaultBranch{Branch: branch, Key: key}
+}
+
+// SetLockfile records the lockfile hash a repo was last installed from
+func (s *State) SetLockfile(repo, hash string) {
+	if s.Lockfiles == nil {
+		s.Lockfiles = make(map[string]string)
+	}
+	s.Lockfiles[repo] = hash
+}
+
+// SetSyncOnto records the branch sync rebases repo onto; "" goes back to its default branch
+func (s *State) SetSyncOnto(repo, branch string) {
+	if branch == "" {
+		delete(s.SyncOnto, repo)
+		return
+	}
+	if s.SyncOnto == nil {
+		s.SyncOnto = make(map[string]string)
+	}
+	s.SyncOnto[repo] = branch
+}
+
+// SetLinkPin records the model build consumer's link to pkg points at
+func (s *State) SetLinkPin(consumer, pkg string, pin LinkPin) {
+	if s.LinkPins == nil {
+		s.LinkPins = make(map[string]map[string]LinkPin)
+	}
+	if s.LinkPins[consumer] == nil {
+		s.LinkPins[consumer] = make(map[string]LinkPin)
+	}
+	s.LinkPins[consumer][pkg] = pin
+}
+
+// RemoveLinkPin forgets consumer's link to pkg
+func (s *State) RemoveLinkPin(consumer, pkg string) {
+	delete(s.LinkPins[consumer], pkg)
+	if len(s.LinkPins[consumer]) == 0 {
+		delete(s.LinkPins, consumer)
+	}
+}
@@ -0,0 +1,68 @@
+package tunnel
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+)
+
+// Provider is a tunnelling tool
+type Provider string
+
+const (
+	Cloudflared Provider = "cloudflared"
+	Ngrok       Provider = "ngrok"
+)
+
+// Providers are the supported tools in order of preference (cloudflared quick tunnels
+// need no account)
+var Providers = []Provider{Cloudflared, Ngrok}
+
+// urlRes match the public URL each tool prints once the tunnel is up
+var urlRes = map[Provider]*regexp.Regexp{
+	Cloudflared: regexp.MustCompile(`https://[a-z0-9-]+\.trycloudflare\.com`),
+	Ngrok:       regexp.MustCompile(`https://[A-Za-z0-9.-]+\.ngrok(?:-free)?\.(?:app|dev|io)`),
+}
+
+// Detect returns the preferred provider if installed, otherwise the first installed one
+func Detect(preferred string) (Provider, error) {
+	if preferred != "" {
+		p := Provider(preferred)
+		if _, ok := urlRes[p]; !ok {
+			return "", fmt.Errorf("unknown tunnel provider %q — use cloudflared or ngrok", preferred)
+		}
+		if _, err := exec.LookPath(preferred); err != nil {
+			return "", fmt.Errorf("%s not found — install it (brew install %s)", preferred, installName(p))
+		}
+		return p, nil
+	}
+	for _, p := range Providers {
+		if _, err := exec.LookPath(string(p)); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("no tunnel tool found — install cloudflared (brew install cloudflared) or ngrok (brew install ngrok/ngrok/ngrok)")
+}
+
+// Args returns the command line that exposes localhost:port
+func Args(p Provider, port int) (string, []string) {
+	switch p {
+	case Ngrok:
+		return "ngrok", []string{"http", strconv.Itoa(port), "--log", "stdout"}
+	default:
+		return "cloudflared", []string{"tunnel", "--no-autoupdate", "--url", fmt.Sprintf("http://localhost:%d", port)}
+	}
+}
+
+// PublicURL extracts the tunnel's public URL from a line of the tool's output
+func PublicURL(p Provider, line string) string {
+	return urlRes[p].FindString(line)
+}
+
+func installName(p Provider) string {
+	if p == Ngrok {
+		return "ngrok/ngrok/ngrok"
+	}
+	return string(p)
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI styles
+const (
+	reset  = "\033[0m"
+	bold   = "\033[1m"
+	dim    = "\033[2m"
+	red    = "\033[31m"
+	green  = "\033[32m"
+	yellow = "\033[33m"
+)
+
+// colorEnabled is true when stdout is a terminal, NO_COLOR is unset, and TERM isn't dumb
+var colorEnabled = os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && IsTerminal(os.Stdout)
+
+// IsTerminal reports whether f is a terminal rather than a file or pipe
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// DisableColor turns off colored output (--no-color)
+func DisableColor() {
+	colorEnabled = false
+}
+
+// ColorEnabled reports whether output is colored
+func ColorEnabled() bool {
+	return colorEnabled
+}
+
+func style(code, s string) string {
+	if !colorEnabled || s == "" {
+		return s
+	}
+	return code + s + reset
+}
+
+// Green renders s in green
+func Green(s string) string { return style(green, s) }
+
+// Red renders s in red
+func Red(s string) string { return style(red, s) }
+
+// Yellow renders s in yellow
+func Yellow(s string) string { return style(yellow, s) }
+
+// Dim renders s faint
+func Dim(s string) string { return style(dim, s) }
+
+// Bold renders s bold
+func Bold(s string) string { return style(bold, s) }
+
+// Status is the outcome a line reports, shown as a colored mark
+type Status int
+
+const (
+	OK   Status = iota // ✓ done
+	Fail               // ✗ failed
+	Warn               // ⚠ needs attention
+	Skip               // ⏭ skipped
+)
+
+// String returns the status mark, colored when color is on
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return Green("✓")
+	case Fail:
+		return Red("✗")
+	case Warn:
+		return Yellow("⚠")
+	default:
+		return Dim("⏭")
+	}
+}
+
+// Printf prints the status mark, a space, and the formatted message to stdout
+func (s Status) Printf(format string, a ...any) {
+	s.Fprintf(os.Stdout, format, a...)
+}
+
+// Println prints the status mark followed by a to stdout
+func (s Status) Println(a ...any) {
+	fmt.Println(append([]any{s}, a...)...)
+}
+
+// Fprintf prints the status mark, a space, and the formatted message to w
+func (s Status) Fprintf(w io.Writer, format string, a ...any) {
+	fmt.Fprintf(w, "%s "+format, append([]any{s}, a...)...)
+}
+
+var ansiRe = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// Width returns how many columns s takes on screen, ignoring color codes
+func Width(s string) int {
+	return utf8.RuneCountInString(ansiRe.ReplaceAllString(s, ""))
+}
+
+// Pad right-pads s to width screen columns, for table cells that may be colored
+// (fmt's %-*s counts the color codes too)
+func Pad(s string, width int) string {
+	if n := Width(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
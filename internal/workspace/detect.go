@@ -0,0 +1,177 @@
+package workspace
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+)
+
+// DetectedDependency is a workspace repo whose package another repo uses
+type DetectedDependency struct {
+	Repo    string // the repo that publishes the package
+	Package string // npm package name or Gradle group:artifact
+	File    string // consumer file that references it, relative to the consumer
+}
+
+var (
+	gradleGroupRe       = regexp.MustCompile(`(?m)^\s*group\s*=?\s*["']([^"']+)["']`)
+	gradleRootProjectRe = regexp.MustCompile(`rootProject\.name\s*=\s*["']([^"']+)["']`)
+)
+
+// gradleScripts are the build scripts scanned for dependency coordinates
+var gradleScripts = []string{"build.gradle", "build.gradle.kts"}
+
+// DetectDependencies returns the other workspace repos that name uses, found by matching
+// its package.json dependencies and Gradle build scripts against the packages those repos
+// publish (their package.json name, model codegen packages, and Gradle group:artifact).
+// Repos it already depends on are left out.
+func DetectDependencies(wsPath string, ws *Workspace, name string) []DetectedDependency {
+	repo, ok := ws.Repos[name]
+	if !ok {
+		return nil
+	}
+	dir := filepath.Join(wsPath, repo.Path)
+	existing := make(map[string]bool)
+	for _, dep := range Dependencies(wsPath, ws, name) {
+		existing[dep] = true
+	}
+
+	npmDeps := packageJSONDependencies(dir)
+	gradleFiles := make(map[string]string)
+	for _, script := range gradleScripts {
+		matches, _ := filepath.Glob(filepath.Join(dir, script))
+		sub, _ := filepath.Glob(filepath.Join(dir, "*", script))
+		for _, path := range append(matches, sub...) {
+			if data, err := os.ReadFile(path); err == nil {
+				rel, _ := filepath.Rel(dir, path)
+				gradleFiles[rel] = string(data)
+			}
+		}
+	}
+
+	gradleNames := make([]string, 0, len(gradleFiles))
+	for file := range gradleFiles {
+		gradleNames = append(gradleNames, file)
+	}
+	sort.Strings(gradleNames)
+
+	others := make([]string, 0, len(ws.Repos))
+	for other := range ws.Repos {
+		if other != name && !existing[other] {
+			others = append(others, other)
+		}
+	}
+	sort.Strings(others)
+
+	var found []DetectedDependency
+	for _, other := range others {
+		otherDir := filepath.Join(wsPath, ws.Repos[other].Path)
+		npmPkgs, gradleCoords := publishedPackages(otherDir)
+		if d, ok := firstMatch(other, npmPkgs, func(pkg string) string {
+			if npmDeps[pkg] {
+				return "package.json"
+			}
+			return ""
+		}); ok {
+			found = append(found, d)
+			continue
+		}
+		if d, ok := firstMatch(other, gradleCoords, func(coord string) string {
+			for _, file := range gradleNames {
+				if strings.Contains(gradleFiles[file], coord) {
+					return file
+				}
+			}
+			return ""
+		}); ok {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+func firstMatch(repo string, pkgs []string, usedIn func(string) string) (DetectedDependency, bool) {
+	for _, pkg := range pkgs {
+		if file := usedIn(pkg); file != "" {
+			return DetectedDependency{Repo: repo, Package: pkg, File: file}, true
+		}
+	}
+	return DetectedDependency{}, false
+}
+
+// publishedPackages returns the npm packages and Gradle coordinates a repo produces
+func publishedPackages(dir string) (npmPkgs, gradleCoords []string) {
+	if name := packageJSONName(dir); name != "" {
+		npmPkgs = append(npmPkgs, name)
+	}
+	codegens, _ := filepath.Glob(filepath.Join(dir, npm.SmithyBuildBase, "*"))
+	for _, codegenDir := range codegens {
+		if name := packageJSONName(codegenDir); name != "" {
+			npmPkgs = append(npmPkgs, name)
+		}
+	}
+
+	group := ""
+	for _, script := range gradleScripts {
+		if data, err := os.ReadFile(filepath.Join(dir, script)); err == nil {
+			if m := gradleGroupRe.FindSubmatch(data); m != nil {
+				group = string(m[1])
+				break
+			}
+		}
+	}
+	if group != "" {
+		for _, settings := range []string{"settings.gradle", "settings.gradle.kts"} {
+			if data, err := os.ReadFile(filepath.Join(dir, settings)); err == nil {
+				if m := gradleRootProjectRe.FindSubmatch(data); m != nil {
+					gradleCoords = append(gradleCoords, group+":"+string(m[1]))
+				}
+			}
+		}
+	}
+	return npmPkgs, gradleCoords
+}
+
+func packageJSONName(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+	return pkg.Name
+}
+
+// packageJSONDependencies returns every package dir's package.json depends on, in any
+// dependency section
+func packageJSONDependencies(dir string) map[string]bool {
+	deps := make(map[string]bool)
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return deps
+	}
+	var pkg struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return deps
+	}
+	for _, section := range []map[string]string{pkg.Dependencies, pkg.DevDependencies, pkg.PeerDependencies, pkg.OptionalDependencies} {
+		for name := range section {
+			deps[name] = true
+		}
+	}
+	return deps
+}
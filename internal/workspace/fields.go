@@ -0,0 +1,125 @@
+package workspace
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+)
+
+// SetField sets the field of the struct v points to whose JSON name is key. Strings,
+// numbers, booleans, and string lists (comma-separated) can be set; an empty value
+// clears the field. Maps and nested objects need 'spark-cli config edit'.
+func SetField(v any, key, value string) error {
+	field, ok := jsonField(reflect.ValueOf(v).Elem(), key)
+	if !ok {
+		return errs.New(errs.KindUsage, "unknown field %q (settable: %s)", key, strings.Join(SettableFields(v), ", "))
+	}
+	if value == "" {
+		field.SetZero()
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errs.New(errs.KindUsage, "%s must be a number, got %q", key, value)
+		}
+		field.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errs.New(errs.KindUsage, "%s must be true or false, got %q", key, value)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return errs.New(errs.KindUsage, "%s can't be set from the command line — use 'spark-cli config edit'", key)
+	}
+	return nil
+}
+
+// SettableFields lists the JSON names SetField accepts for the struct v points to
+func SettableFields(v any) []string {
+	var names []string
+	rv := reflect.ValueOf(v).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		name := jsonName(rv.Type().Field(i))
+		if name != "" && settable(rv.Field(i)) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func jsonField(rv reflect.Value, key string) (reflect.Value, bool) {
+	for i := 0; i < rv.NumField(); i++ {
+		if jsonName(rv.Type().Field(i)) == key {
+			return rv.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func settable(f reflect.Value) bool {
+	switch f.Kind() {
+	case reflect.String, reflect.Int, reflect.Bool:
+		return true
+	case reflect.Slice:
+		return f.Type().Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// Validate checks the manifest for mistakes a hand edit can introduce: repos without a
+// path, two repos in one directory, dependencies on unknown repos, and cycles
+func Validate(wsPath string, ws *Workspace) error {
+	names := make([]string, 0, len(ws.Repos))
+	for name := range ws.Repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	paths := make(map[string]string)
+	for _, name := range names {
+		repo := ws.Repos[name]
+		if repo.Path == "" {
+			return fmt.Errorf("repo %s has no path", name)
+		}
+		if other, ok := paths[repo.Path]; ok {
+			return fmt.Errorf("repos %s and %s share the path %s", other, name, repo.Path)
+		}
+		paths[repo.Path] = name
+		for _, dep := range repo.Dependencies {
+			if _, ok := ws.Repos[dep]; !ok {
+				return fmt.Errorf("repo %s depends on %s, which isn't in the workspace", name, dep)
+			}
+			if dep == name {
+				return fmt.Errorf("repo %s depends on itself", name)
+			}
+		}
+	}
+	_, err := BuildOrder(wsPath, ws, nil)
+	return err
+}
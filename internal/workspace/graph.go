@@ -0,0 +1,122 @@
+package workspace
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/spkconfig"
+)
+
+// Dependencies returns the workspace repos that name depends on: the manifest's
+// dependencies plus any models the repo consumes in its spk.config.json
+func Dependencies(wsPath string, ws *Workspace, name string) []string {
+	repo, ok := ws.Repos[name]
+	if !ok {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var deps []string
+	add := func(dep string) {
+		if dep == name || seen[dep] {
+			return
+		}
+		if _, ok := ws.Repos[dep]; !ok {
+			return
+		}
+		seen[dep] = true
+		deps = append(deps, dep)
+	}
+
+	for _, dep := range repo.Dependencies {
+		add(dep)
+	}
+	if cfg, err := spkconfig.Load(filepath.Join(wsPath, repo.Path)); err == nil && cfg != nil {
+		for _, c := range cfg.Consumes {
+			add(c.Model)
+		}
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// Dependents returns names plus every repo that transitively depends on one of them
+func Dependents(wsPath string, ws *Workspace, names []string) []string {
+	reverse := make(map[string][]string)
+	for name := range ws.Repos {
+		for _, dep := range Dependencies(wsPath, ws, name) {
+			reverse[dep] = append(reverse[dep], name)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var out []string
+	queue := append([]string(nil), names...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+		queue = append(queue, reverse[name]...)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// BuildOrder returns names plus all their transitive dependencies, ordered so every
+// repo comes after the repos it depends on. Pass nil to order the whole workspace.
+func BuildOrder(wsPath string, ws *Workspace, names []string) ([]string, error) {
+	if names == nil {
+		for name := range ws.Repos {
+			names = append(names, name)
+		}
+	}
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var order []string
+	var stack []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s → %s", strings.Join(stack, " → "), name)
+		}
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range Dependencies(wsPath, ws, name) {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range sorted {
+		if _, ok := ws.Repos[name]; !ok {
+			return nil, errs.New(errs.KindRepoNotFound, "repo '%s' not found in workspace", name)
+		}
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
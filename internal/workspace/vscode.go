@@ -0,0 +1,217 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// VSCodeTaskPrefix starts the label of every task spark-cli generates; tasks without
+// it are the user's own and are kept when the file is regenerated
+const VSCodeTaskPrefix = "spark-cli: "
+
+// vscodeFolder is one root of the multi-root workspace
+type vscodeFolder struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// vscodeTask is a tasks.json task
+type vscodeTask struct {
+	Label          string            `json:"label"`
+	Type           string            `json:"type"`
+	Command        string            `json:"command"`
+	Options        map[string]string `json:"options,omitempty"`
+	Group          string            `json:"group,omitempty"`
+	IsBackground   bool              `json:"isBackground,omitempty"`
+	ProblemMatcher []string          `json:"problemMatcher"`
+}
+
+// GenerateVSCodeWorkspace creates/updates the .code-workspace file: a folder per repo
+// and spark-cli tasks (build, test, sync, dev) per repo and for the whole workspace.
+// Anything else in an existing file — settings, launch configs, the user's own tasks —
+// is kept.
+func GenerateVSCodeWorkspace(workspacePath string) error {
+	ws, err := Load(workspacePath)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(ws.Repos))
+	for name := range ws.Repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	folders := make([]vscodeFolder, 0, len(names))
+	for _, name := range names {
+		folders = append(folders, vscodeFolder{Name: name, Path: ws.Repos[name].Path})
+	}
+
+	wsFile := VSCodeWorkspacePath(workspacePath)
+	doc := make(map[string]json.RawMessage)
+	var userTasks []json.RawMessage
+	if data, err := os.ReadFile(wsFile); err == nil {
+		// A file VS Code wrote with comments isn't plain JSON; it is replaced like before
+		if json.Unmarshal(data, &doc) == nil {
+			userTasks = userVSCodeTasks(doc["tasks"])
+		} else {
+			doc = make(map[string]json.RawMessage)
+		}
+	}
+
+	tasks := make([]any, 0, len(userTasks))
+	for _, t := range userTasks {
+		tasks = append(tasks, t)
+	}
+	for _, t := range vscodeTasks(workspacePath, ws, names) {
+		tasks = append(tasks, t)
+	}
+
+	set := func(key string, v any) error {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal VS Code workspace: %w", err)
+		}
+		doc[key] = raw
+		return nil
+	}
+	if err := set("folders", folders); err != nil {
+		return err
+	}
+	if err := set("tasks", map[string]any{"version": "2.0.0", "tasks": tasks}); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(doc, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to marshal VS Code workspace: %w", err)
+	}
+	return os.WriteFile(wsFile, append(data, '\n'), 0644)
+}
+
+// userVSCodeTasks returns the tasks in an existing "tasks" block that spark-cli didn't
+// generate
+func userVSCodeTasks(raw json.RawMessage) []json.RawMessage {
+	var block struct {
+		Tasks []json.RawMessage `json:"tasks"`
+	}
+	if len(raw) == 0 || json.Unmarshal(raw, &block) != nil {
+		return nil
+	}
+	var kept []json.RawMessage
+	for _, t := range block.Tasks {
+		var task struct {
+			Label string `json:"label"`
+		}
+		if json.Unmarshal(t, &task) == nil && strings.HasPrefix(task.Label, VSCodeTaskPrefix) {
+			continue
+		}
+		kept = append(kept, t)
+	}
+	return kept
+}
+
+// vscodeTasks returns the workspace-wide tasks followed by each cloned repo's. Tasks run
+// in a repo folder so spark-cli finds the workspace from there.
+func vscodeTasks(workspacePath string, ws *Workspace, names []string) []vscodeTask {
+	var cloned []string
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(workspacePath, ws.Repos[name].Path)); err == nil {
+			cloned = append(cloned, name)
+		}
+	}
+	if len(cloned) == 0 {
+		return nil
+	}
+
+	task := func(label, command, folder, group string, matchers []string) vscodeTask {
+		if matchers == nil {
+			matchers = []string{}
+		}
+		return vscodeTask{
+			Label:          VSCodeTaskPrefix + label,
+			Type:           "shell",
+			Command:        command,
+			Options:        map[string]string{"cwd": "${workspaceFolder:" + folder + "}"},
+			Group:          group,
+			ProblemMatcher: matchers,
+		}
+	}
+
+	// Workspace-wide tasks can run from any repo folder
+	var allMatchers []string
+	for _, name := range cloned {
+		allMatchers = appendMissing(allMatchers, vscodeProblemMatchers(filepath.Join(workspacePath, ws.Repos[name].Path))...)
+	}
+	sort.Strings(allMatchers)
+	tasks := []vscodeTask{
+		task("build all", "spark-cli build --all", cloned[0], "build", allMatchers),
+		task("test all", "spark-cli test --all", cloned[0], "test", allMatchers),
+		task("sync", "spark-cli workspace sync", cloned[0], "", nil),
+	}
+
+	for _, name := range cloned {
+		dir := filepath.Join(workspacePath, ws.Repos[name].Path)
+		matchers := vscodeProblemMatchers(dir)
+		tasks = append(tasks,
+			task("build "+name, "spark-cli build "+name, name, "build", matchers),
+			task("test "+name, "spark-cli test "+name, name, "test", matchers),
+			task("sync "+name, "spark-cli workspace sync "+name, name, "", nil),
+		)
+		if HasNpmScript(dir, "dev") {
+			dev := task("dev "+name, "spark-cli run dev", name, "", nil)
+			dev.IsBackground = true
+			tasks = append(tasks, dev)
+		}
+	}
+	return tasks
+}
+
+// vscodeProblemMatchers picks VS Code's built-in problem matchers for a repo's toolchain
+func vscodeProblemMatchers(dir string) []string {
+	var matchers []string
+	if _, err := os.Stat(filepath.Join(dir, "tsconfig.json")); err == nil {
+		matchers = append(matchers, "$tsc")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		matchers = append(matchers, "$go")
+	}
+	return matchers
+}
+
+// HasNpmScript reports whether dir's package.json defines script
+func HasNpmScript(dir, script string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return false
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return false
+	}
+	_, ok := pkg.Scripts[script]
+	return ok
+}
+
+// appendMissing appends the values not already in list
+func appendMissing(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, have := range list {
+			if have == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -4,22 +4,193 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/errs"
 )
 
 const ManifestFile = "workspace.json"
 
 type RepoDef struct {
-	Remote        string   `json:"remote"`
-	Path          string   `json:"path"`
-	BuildCommand  string   `json:"build_command,omitempty"`
-	TestCommand   string   `json:"test_command,omitempty"`
-	Dependencies  []string `json:"dependencies,omitempty"`
-	DefaultBranch string   `json:"default_branch,omitempty"`
-	ModelFor      string   `json:"model_for,omitempty"`
+	Remote        string     `json:"remote"`
+	Path          string     `json:"path"`
+	BuildCommand  string     `json:"build_command,omitempty"`
+	TestCommand   string     `json:"test_command,omitempty"`
+	Dependencies  []string   `json:"dependencies,omitempty"`
+	DefaultBranch string     `json:"default_branch,omitempty"`
+	ModelFor      string     `json:"model_for,omitempty"`
+	CacheOutputs  []string   `json:"cache_outputs,omitempty"`
+	Gradle        *GradleDef `json:"gradle,omitempty"`
+	// Upstream is the repo a fork was made from: sync fetches it and rebases onto its
+	// branches, while pushes still go to Remote (origin)
+	Upstream string `json:"upstream,omitempty"`
+	// EnvKeys are the names or patterns (NEXT_PUBLIC_*) written to a generated env file
+	// for frameworks that get one (Next.js .env.local, default NEXT_PUBLIC_*; React
+	// Native .env, default client-side config only)
+	EnvKeys []string   `json:"env_keys,omitempty"`
+	Docker  *DockerDef `json:"docker,omitempty"`
+	// Smoke are the "is it alive" checks run by `spark-cli smoke`
+	Smoke []SmokeCheck `json:"smoke,omitempty"`
+	// EnvAllowlist and EnvDenylist limit which workspace env vars (names or path.Match
+	// patterns like STRIPE_*) reach the repo's commands and env files. The denylist wins.
+	EnvAllowlist []string `json:"env_allowlist,omitempty"`
+	EnvDenylist  []string `json:"env_denylist,omitempty"`
+	// ScriptArgs are extra args always passed to a script (e.g. "test": ["--runInBand"]),
+	// ahead of any given on the command line
+	ScriptArgs map[string][]string `json:"script_args,omitempty"`
+	// Tags group repos for --repos @tag (e.g. "api", "mobile")
+	Tags []string `json:"tags,omitempty"`
+	// CDKStacks are the CDK stacks that deploy the repo (e.g. "PipelineStack/beta/AppAPIStack"),
+	// listed by `spark-cli impact` when the repo is affected by a change
+	CDKStacks []string `json:"cdk_stacks,omitempty"`
+}
+
+// UpstreamRemote is the git remote a fork's upstream is fetched from
+const UpstreamRemote = "upstream"
+
+// SyncRemote returns the git remote the repo is synced from: upstream for a fork,
+// otherwise origin
+func (r RepoDef) SyncRemote() string {
+	if r.Upstream != "" {
+		return UpstreamRemote
+	}
+	return "origin"
+}
+
+// HasTag reports whether the repo is tagged tag
+func (r RepoDef) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ArgsFor returns the repo's default args for script followed by the command-line args
+func (r RepoDef) ArgsFor(script string, args []string) []string {
+	defaults := r.ScriptArgs[script]
+	if len(defaults) == 0 {
+		return args
+	}
+	return append(append([]string(nil), defaults...), args...)
+}
+
+// EnvAllowed reports whether the workspace env var key may reach the repo's commands
+func (r RepoDef) EnvAllowed(key string) bool {
+	if matchAny(r.EnvDenylist, key) {
+		return false
+	}
+	return len(r.EnvAllowlist) == 0 || matchAny(r.EnvAllowlist, key)
+}
+
+// ScopesEnv reports whether the repo limits the workspace env it sees
+func (r RepoDef) ScopesEnv() bool {
+	return len(r.EnvAllowlist) > 0 || len(r.EnvDenylist) > 0
+}
+
+// ScopeEnv returns the part of env the repo is allowed to see
+func (r RepoDef) ScopeEnv(env map[string]string) map[string]string {
+	if !r.ScopesEnv() {
+		return env
+	}
+	scoped := make(map[string]string, len(env))
+	for k, v := range env {
+		if r.EnvAllowed(k) {
+			scoped[k] = v
+		}
+	}
+	return scoped
+}
+
+func matchAny(patterns []string, key string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SmokeCheck is one liveness check. Exactly one of HTTP, GraphQL, or Command is set.
+// URLs and commands may use ${VAR} from the workspace env and ${ENV} for the target
+// environment name.
+type SmokeCheck struct {
+	Name string `json:"name,omitempty"`
+	// HTTP is a URL that must answer with Status (default: any 2xx)
+	HTTP   string `json:"http,omitempty"`
+	Status int    `json:"status,omitempty"`
+	// GraphQL is an endpoint that must answer an introspection query
+	GraphQL string `json:"graphql,omitempty"`
+	// Command runs in the repo and must exit 0 (e.g. "./bin/cli --version")
+	Command string `json:"command,omitempty"`
+	// Contains, if set, must appear in the response body or command output
+	Contains string `json:"contains,omitempty"`
+}
+
+// DockerDef configures `spark-cli docker` for a repo
+type DockerDef struct {
+	// Image is the image name (default: the lowercased repo name)
+	Image string `json:"image,omitempty"`
+	// Dockerfile is relative to the repo (default: Dockerfile)
+	Dockerfile string `json:"dockerfile,omitempty"`
+	// Ports are published by `docker run` and compose stacks (host:container)
+	Ports []string `json:"ports,omitempty"`
+	// Secrets are env keys passed as BuildKit secrets (--secret id=KEY,env=KEY) rather
+	// than build args, so they never end up in image layers
+	Secrets []string `json:"secrets,omitempty"`
+}
+
+// GradleDef controls how workspace env reaches a repo's Gradle build. Values are
+// workspace env keys; a value that is a path starting with ./ or ~/ (e.g. a keystore)
+// is made absolute, relative to the workspace root or home directory.
+type GradleDef struct {
+	// Dir is the Gradle root relative to the repo (default: the repo, or android/)
+	Dir string `json:"dir,omitempty"`
+	// Properties maps Gradle project properties to env keys, passed as
+	// ORG_GRADLE_PROJECT_<property>. Without it every env key is passed under its own name.
+	Properties map[string]string `json:"properties,omitempty"`
+	// LocalProperties maps local.properties keys to env keys (e.g. "sdk.dir": "ANDROID_HOME")
+	LocalProperties map[string]string `json:"local_properties,omitempty"`
+}
+
+// EnvTypeLocal marks an environment served by LocalStack instead of an AWS account
+const EnvTypeLocal = "local"
+
+// DefaultLocalEndpoint is LocalStack's edge port
+const DefaultLocalEndpoint = "http://localhost:4566"
+
+// EnvironmentDef maps a named environment (beta, prod, ...) to the AWS account it lives in
+type EnvironmentDef struct {
+	Profile string `json:"profile,omitempty"`
+	Account string `json:"account,omitempty"`
+	Region  string `json:"region,omitempty"`
+	// Type is "local" for LocalStack; empty means a real AWS account
+	Type string `json:"type,omitempty"`
+	// Endpoint overrides the LocalStack URL for local environments
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// ServiceDef is a service's URL per environment. When the workspace env is written,
+// the URL for the active environment is exported as Env (default: <NAME>_URL).
+type ServiceDef struct {
+	Env  string            `json:"env,omitempty"`
+	URLs map[string]string `json:"urls"`
+}
+
+// BuildCacheDef points at the team's shared remote build cache (an S3 bucket)
+type BuildCacheDef struct {
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix,omitempty"`
+	// Profile is a workspace environment name or AWS profile with access to the bucket
+	Profile string `json:"profile,omitempty"`
+	Region  string `json:"region,omitempty"`
+	// Push uploads artifacts after local builds; usually only CI turns this on
+	Push bool `json:"push,omitempty"`
 }
 
 type Workspace struct {
@@ -31,6 +202,132 @@ type Workspace struct {
 	Env           map[string]string  `json:"env,omitempty"`
 	DefaultBranch string             `json:"default_branch,omitempty"`
 	SSMEnvPath    string             `json:"ssm_env_path,omitempty"`
+
+	// SSMParams adds SSM parameters to the workspace env: the name under /app/<env>/ maps
+	// to the env key it's written as (see `spark-cli migrate legacy`)
+	SSMParams map[string]string `json:"ssm_params,omitempty"`
+
+	Environments map[string]EnvironmentDef `json:"environments,omitempty"`
+
+	// FrozenLockfile makes every install use the lockfile as-is (npm ci / pnpm --frozen-lockfile)
+	FrozenLockfile bool `json:"frozen_lockfile,omitempty"`
+	// SharedStore installs Node repos with pnpm against one store under .spk (see `spark-cli store`)
+	SharedStore bool `json:"shared_store,omitempty"`
+
+	BuildCache *BuildCacheDef `json:"build_cache,omitempty"`
+
+	// Hooks maps git hook names (pre-commit, commit-msg, pre-push, ...) to the shell script
+	// installed in every repo by `spark-cli hooks install`
+	Hooks map[string]string `json:"hooks,omitempty"`
+
+	// Stacks are named groups of repos brought up together by `spark-cli docker compose`
+	Stacks map[string][]string `json:"stacks,omitempty"`
+
+	// Services maps logical service names to their URL in each environment (see `spark-cli url`)
+	Services map[string]ServiceDef `json:"services,omitempty"`
+
+	// Layout is where `spark-cli use` clones new repos: "flat" (<repo>, the default),
+	// "org" (<org>/<repo>), or a template using {org} and {repo} (e.g. "services/{repo}")
+	Layout string `json:"layout,omitempty"`
+
+	// GithubOrg, LinkMode, Concurrency, and NudgeBehind override the global defaults
+	// for this workspace (see `spark-cli config`)
+	GithubOrg   string `json:"github_org,omitempty"`
+	LinkMode    string `json:"link_mode,omitempty"`
+	Concurrency int    `json:"concurrency,omitempty"`
+	NudgeBehind string `json:"nudge_behind,omitempty"`
+}
+
+// Clone layouts (see Workspace.Layout)
+const (
+	LayoutFlat = "flat"
+	LayoutOrg  = "org"
+)
+
+// RepoPath returns the workspace-relative directory a new clone of org/repo goes in
+func (ws *Workspace) RepoPath(org, repo string) string {
+	switch ws.Layout {
+	case "", LayoutFlat:
+		return repo
+	case LayoutOrg:
+		if org == "" {
+			return repo
+		}
+		return filepath.Join(org, repo)
+	default:
+		path := strings.NewReplacer("{org}", org, "{repo}", repo).Replace(ws.Layout)
+		if !strings.Contains(ws.Layout, "{repo}") {
+			path = filepath.Join(path, repo)
+		}
+		return filepath.Clean(strings.TrimLeft(path, "/")) // an empty {org} mustn't make it absolute
+	}
+}
+
+// ActiveEnv returns the workspace's selected environment name (default: beta)
+func (ws *Workspace) ActiveEnv() string {
+	if ws.SSMEnvPath != "" {
+		return ws.SSMEnvPath
+	}
+	return "beta"
+}
+
+// ResolveEnv returns the AWS profile and region for a named environment. Values from
+// the environments map win; otherwise the workspace defaults are used.
+func (ws *Workspace) ResolveEnv(name string) (profile, region string) {
+	profile = ws.AWSProfile
+	region = ws.AWSRegion
+	if def, ok := ws.Environments[name]; ok {
+		if def.Profile != "" {
+			profile = def.Profile
+		}
+		if def.Region != "" {
+			region = def.Region
+		}
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	return profile, region
+}
+
+// LocalEndpoint returns the LocalStack URL for a local environment, or "" when the
+// environment is a real AWS account
+func (ws *Workspace) LocalEndpoint(name string) string {
+	def, ok := ws.Environments[name]
+	if !ok || def.Type != EnvTypeLocal {
+		return ""
+	}
+	if def.Endpoint != "" {
+		return def.Endpoint
+	}
+	return DefaultLocalEndpoint
+}
+
+// ServiceVar returns the env var a service's URL is exported as
+func (ws *Workspace) ServiceVar(name string) string {
+	if def := ws.Services[name]; def.Env != "" {
+		return def.Env
+	}
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String() + "_URL"
+}
+
+// ServiceEnv returns the env vars for every service that has a URL in env
+func (ws *Workspace) ServiceEnv(env string) map[string]string {
+	vars := make(map[string]string)
+	for name, def := range ws.Services {
+		if u, ok := def.URLs[env]; ok {
+			vars[ws.ServiceVar(name)] = u
+		}
+	}
+	return vars
 }
 
 // SparkDir returns the .spark directory path within a workspace
@@ -99,27 +396,94 @@ func Save(workspacePath string, ws *Workspace) error {
 	return os.WriteFile(path, data, 0644)
 }
 
-// Find walks up from the current directory to find a workspace root
+// EnvVar selects a workspace (path or registered name) from outside it
+const EnvVar = "SPK_WORKSPACE"
+
+// override is the workspace selected by --workspace
+var override string
+
+// SetOverride makes Find return the workspace ref names (a path or registered name)
+func SetOverride(ref string) {
+	override = ref
+}
+
+// Find returns the workspace root: the --workspace or $SPK_WORKSPACE selection, else the
+// nearest workspace above the current directory, else the one chosen with `workspace switch`
 func Find() (string, error) {
+	ref := override
+	if ref == "" {
+		ref = os.Getenv(EnvVar)
+	}
+	if ref != "" {
+		return Resolve(ref)
+	}
+
 	dir, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current directory: %w", err)
 	}
+	if root, ok := FindFrom(dir); ok {
+		return root, nil
+	}
 
-	for {
-		manifest := ManifestPath(dir)
-		if _, err := os.Stat(manifest); err == nil {
-			return dir, nil
+	if cfg, err := config.LoadGlobal(); err == nil && cfg.CurrentWorkspace != "" {
+		if _, err := os.Stat(ManifestPath(cfg.CurrentWorkspace)); err == nil {
+			return cfg.CurrentWorkspace, nil
 		}
+	}
+
+	return "", errs.New(errs.KindWorkspaceNotFound, "not inside a spark-cli workspace (no .spk/workspace.json found) — cd into one, pass --workspace, or run 'spark-cli workspace switch <name>'")
+}
 
+// FindFrom returns the root of the workspace containing dir, if any
+func FindFrom(dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(ManifestPath(dir)); err == nil {
+			return dir, true
+		}
 		parent := filepath.Dir(dir)
 		if parent == dir {
-			break
+			return "", false
 		}
 		dir = parent
 	}
+}
+
+// Resolve returns the root of the workspace ref names: a path to a workspace, or the
+// name (or directory name) of one registered in ~/.spk/config.json
+func Resolve(ref string) (string, error) {
+	if abs, err := filepath.Abs(ref); err == nil {
+		if _, err := os.Stat(ManifestPath(abs)); err == nil {
+			return abs, nil
+		}
+	}
 
-	return "", fmt.Errorf("not inside a spark-cli workspace (no .spk/workspace.json found)")
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return "", err
+	}
+	var matches, names []string
+	for _, path := range cfg.Workspaces {
+		ws, err := Load(path)
+		if err != nil {
+			continue
+		}
+		names = append(names, ws.Name)
+		if ws.Name == ref || filepath.Base(path) == ref {
+			matches = append(matches, path)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		if len(names) == 0 {
+			return "", errs.New(errs.KindWorkspaceNotFound, "no workspace at or named %q (no workspaces registered)", ref)
+		}
+		return "", errs.New(errs.KindWorkspaceNotFound, "no workspace at or named %q — registered: %s", ref, strings.Join(names, ", "))
+	default:
+		return "", errs.New(errs.KindWorkspaceNotFound, "%q matches several workspaces (%s) — pass the path instead", ref, strings.Join(matches, ", "))
+	}
 }
 
 // AddRepo registers a repo in the workspace manifest
@@ -157,36 +521,6 @@ func VSCodeWorkspacePath(workspacePath string) string {
 	return filepath.Join(workspacePath, ws.Name+".code-workspace")
 }
 
-// GenerateVSCodeWorkspace creates/updates the .code-workspace file
-func GenerateVSCodeWorkspace(workspacePath string) error {
-	ws, err := Load(workspacePath)
-	if err != nil {
-		return err
-	}
-
-	type folder struct {
-		Path string `json:"path"`
-	}
-	type vscodeWorkspace struct {
-		Folders []folder `json:"folders"`
-	}
-
-	var folders []folder
-	for _, repo := range ws.Repos {
-		folders = append(folders, folder{Path: repo.Path})
-	}
-
-	vscWs := vscodeWorkspace{Folders: folders}
-
-	data, err := json.MarshalIndent(vscWs, "", "\t")
-	if err != nil {
-		return fmt.Errorf("failed to marshal VS Code workspace: %w", err)
-	}
-
-	wsFile := VSCodeWorkspacePath(workspacePath)
-	return os.WriteFile(wsFile, data, 0644)
-}
-
 // GlobalEnvPath returns the path to the workspace's global .env file
 func GlobalEnvPath(workspacePath string) string {
 	return filepath.Join(workspacePath, ".env")
@@ -204,9 +538,32 @@ func WriteGlobalEnv(workspacePath string, vars map[string]string) error {
 	for k, v := range vars {
 		existing[k] = v
 	}
+	return writeEnvFile(envPath, existing)
+}
+
+// UnsetGlobalEnv removes keys from the workspace .env, but only where the file still
+// holds the given value — a value the user changed by hand is kept
+func UnsetGlobalEnv(workspacePath string, vars map[string]string) error {
+	existing, err := ReadGlobalEnv(workspacePath)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for k, v := range vars {
+		if cur, ok := existing[k]; ok && cur == v {
+			delete(existing, k)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return writeEnvFile(GlobalEnvPath(workspacePath), existing)
+}
 
+func writeEnvFile(envPath string, vars map[string]string) error {
 	var lines []string
-	for k, v := range existing {
+	for k, v := range vars {
 		lines = append(lines, fmt.Sprintf("%s=%s", k, v))
 	}
 
@@ -215,7 +572,13 @@ func WriteGlobalEnv(workspacePath string, vars map[string]string) error {
 		content += line + "\n"
 	}
 
-	return os.WriteFile(envPath, []byte(content), 0644)
+	// Write to a temp file and rename so an interrupted sync never leaves a half-written .env
+	tmp := envPath + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, envPath)
 }
 
 // ReadGlobalEnv reads the workspace's global .env file into a map